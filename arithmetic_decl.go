@@ -7,89 +7,111 @@ import (
 	"golang.org/x/sys/cpu"
 )
 
-func init() {
-	if !cpu.X86.HasADX || !cpu.X86.HasBMI2 {
-		mul = mulNoADX
-		wmul = wmulNoADX
-		fromWide = montRedNoADX
-		mulFR = mulNoADXFR
-		wmulFR = wmulNoADXFR
-		wfp2Mul = wfp2MulGeneric
-		wfp2Square = wfp2SquareGeneric
+// hasADX selects the MULX/ADX variants of multiplication routines.
+// Dispatch is done with direct calls rather than function values so that
+// arguments do not escape to heap.
+var hasADX = cpu.X86.HasADX && cpu.X86.HasBMI2
+
+func mul(c, a, b *Fp) {
+	if hasADX {
+		mulADX(c, a, b)
+		return
 	}
+	mulNoADX(c, a, b)
 }
 
-var mul func(c, a, b *Fe) = mulADX
-var wmul func(c *wfe, a, b *Fe) = wmulADX
-var fromWide func(c *Fe, w *wfe) = montRedADX
-var wfp2Mul func(c *wfe2, a, b *fe2) = wfp2MulADX
-var wfp2Square func(c *wfe2, b *fe2) = wfp2SquareADX
+func wmul(c *wfe, a, b *Fp) {
+	if hasADX {
+		wmulADX(c, a, b)
+		return
+	}
+	wmulNoADX(c, a, b)
+}
 
-func square(c, a *Fe) {
-	mul(c, a, a)
+func fromWide(c *Fp, w *wfe) {
+	if hasADX {
+		montRedADX(c, w)
+		return
+	}
+	montRedNoADX(c, w)
 }
 
-func neg(c, a *Fe) {
-	if a.isZero() {
-		c.set(a)
-	} else {
-		_neg(c, a)
+func wfp2Mul(c *wfe2, a, b *fe2) {
+	if hasADX {
+		wfp2MulADX(c, a, b)
+		return
 	}
+	wfp2MulGeneric(c, a, b)
+}
+
+func wfp2Square(c *wfe2, a *fe2) {
+	if hasADX {
+		wfp2SquareADX(c, a)
+		return
+	}
+	wfp2SquareGeneric(c, a)
+}
+
+func square(c, a *Fp) {
+	mul(c, a, a)
 }
 
 //go:noescape
-func add(c, a, b *Fe)
+func add(c, a, b *Fp)
 
 //go:noescape
-func addAssign(a, b *Fe)
+func addAssign(a, b *Fp)
 
 //go:noescape
-func ladd(c, a, b *Fe)
+func ladd(c, a, b *Fp)
 
 //go:noescape
-func laddAssign(a, b *Fe)
+func laddAssign(a, b *Fp)
 
 //go:noescape
-func double(c, a *Fe)
+func double(c, a *Fp)
 
 //go:noescape
-func doubleAssign(a *Fe)
+func doubleAssign(a *Fp)
 
 //go:noescape
-func ldouble(c, a *Fe)
+func ldouble(c, a *Fp)
 
 //go:noescape
-func ldoubleAssign(a *Fe)
+func ldoubleAssign(a *Fp)
 
 //go:noescape
-func sub(c, a, b *Fe)
+func sub(c, a, b *Fp)
 
 //go:noescape
-func subAssign(a, b *Fe)
+func subAssign(a, b *Fp)
 
 //go:noescape
-func lsubAssign(a, b *Fe)
+func lsubAssign(a, b *Fp)
 
 //go:noescape
-func _neg(c, a *Fe)
+func neg(c, a *Fp)
 
 //go:noescape
-func mulNoADX(c, a, b *Fe)
+func cmov(c, a *Fp, cond uint64)
 
 //go:noescape
-func mulADX(c, a, b *Fe)
+func mulNoADX(c, a, b *Fp)
 
 //go:noescape
-func wmulNoADX(c *wfe, a, b *Fe)
+func mulADX(c, a, b *Fp)
 
 //go:noescape
-func wmulADX(c *wfe, a, b *Fe)
+func wmulNoADX(c *wfe, a, b *Fp)
 
 //go:noescape
-func montRedNoADX(a *Fe, w *wfe)
+func wmulADX(c *wfe, a, b *Fp)
 
 //go:noescape
-func montRedADX(a *Fe, w *wfe)
+func montRedNoADX(a *Fp, w *wfe)
+
+//go:noescape
+func montRedADX(a *Fp, w *wfe)
 
 //go:noescape
 func lwadd(c, a, b *wfe)
@@ -193,8 +215,21 @@ func wfp2SquareADX(c *wfe2, a *fe2)
 //go:noescape
 func wfp2MulADX(c *wfe2, a, b *fe2)
 
-var mulFR func(c, a, b *Fr) = mulADXFR
-var wmulFR func(c *wideFr, a, b *Fr) = wmulADXFR
+func mulFR(c, a, b *Fr) {
+	if hasADX {
+		mulADXFR(c, a, b)
+		return
+	}
+	mulNoADXFR(c, a, b)
+}
+
+func wmulFR(c *wideFr, a, b *Fr) {
+	if hasADX {
+		wmulADXFR(c, a, b)
+		return
+	}
+	wmulNoADXFR(c, a, b)
+}
 
 func squareFR(c, a *Fr) {
 	mulFR(c, a, a)
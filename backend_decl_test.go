@@ -0,0 +1,83 @@
+//go:build amd64 && !generic
+// +build amd64,!generic
+
+package bls12381
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"golang.org/x/sys/cpu"
+)
+
+// testBackends returns arithmetic backends linked into assembly builds.
+// Multiplication routines without MULX/ADX run on every amd64 CPU while the
+// ADX variants are only available on capable ones. Pure Go fallback cannot be
+// linked together with assembly and is covered by the generic build tag.
+func testBackends() []testBackend {
+	backends := []testBackend{{"noadx", func() { hasADX = false }}}
+	if cpu.X86.HasADX && cpu.X86.HasBMI2 {
+		backends = append(backends, testBackend{"adx", func() { hasADX = true }})
+	}
+	return backends
+}
+
+func restoreBackend() {
+	hasADX = cpu.X86.HasADX && cpu.X86.HasBMI2
+}
+
+func TestBackendEquivalence(t *testing.T) {
+	if !(cpu.X86.HasADX && cpu.X86.HasBMI2) {
+		t.Skip("ADX is not supported")
+	}
+	for i := 0; i < fuz*10; i++ {
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		c0, c1 := new(Fp), new(Fp)
+		mulADX(c0, a, b)
+		mulNoADX(c1, a, b)
+		if !c0.equal(c1) {
+			t.Fatal("mul", a, b)
+		}
+		w0, w1 := new(wfe), new(wfe)
+		wmulADX(w0, a, b)
+		wmulNoADX(w1, a, b)
+		if *w0 != *w1 {
+			t.Fatal("wide mul", a, b)
+		}
+		montRedADX(c0, w0)
+		montRedNoADX(c1, w0)
+		if !c0.equal(c1) {
+			t.Fatal("montgomery reduction", a, b)
+		}
+
+		x, _ := new(fe2).rand(rand.Reader)
+		y, _ := new(fe2).rand(rand.Reader)
+		v0, v1 := new(wfe2), new(wfe2)
+		wfp2MulADX(v0, x, y)
+		wfp2MulGeneric(v1, x, y)
+		if *v0 != *v1 {
+			t.Fatal("fp2 wide mul", x, y)
+		}
+		wfp2SquareADX(v0, x)
+		wfp2SquareGeneric(v1, x)
+		if *v0 != *v1 {
+			t.Fatal("fp2 wide square", x)
+		}
+
+		e, _ := new(Fr).Rand(rand.Reader)
+		f, _ := new(Fr).Rand(rand.Reader)
+		r0, r1 := new(Fr), new(Fr)
+		mulADXFR(r0, e, f)
+		mulNoADXFR(r1, e, f)
+		if !r0.Equal(r1) {
+			t.Fatal("scalar mul", e, f)
+		}
+		s0, s1 := new(wideFr), new(wideFr)
+		wmulADXFR(s0, e, f)
+		wmulNoADXFR(s1, e, f)
+		if *s0 != *s1 {
+			t.Fatal("scalar wide mul", e, f)
+		}
+	}
+}
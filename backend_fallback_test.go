@@ -0,0 +1,11 @@
+//go:build !amd64 || generic
+// +build !amd64 generic
+
+package bls12381
+
+// testBackends returns the only backend of pure Go builds.
+func testBackends() []testBackend {
+	return []testBackend{{"generic", func() {}}}
+}
+
+func restoreBackend() {}
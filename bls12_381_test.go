@@ -5,18 +5,52 @@ import (
 	"encoding/hex"
 	"errors"
 	"flag"
+	"fmt"
+	"go/build"
 	"math/big"
 	"os"
+	"strings"
 	"testing"
 )
 
 var fuz int
 
+// testBackend is an arithmetic backend that can be selected at run time.
+type testBackend struct {
+	name   string
+	enable func()
+}
+
 func TestMain(m *testing.M) {
 	_fuz := flag.Int("fuzz", 10, "# of iterations")
+	_backends := flag.Bool("backends", false, "run tests once for each arithmetic backend in the binary")
 	flag.Parse()
 	fuz = *_fuz
-	os.Exit(m.Run())
+	if !*_backends {
+		os.Exit(m.Run())
+	}
+	code := 0
+	for _, b := range testBackends() {
+		fmt.Println("backend:", b.name)
+		b.enable()
+		if c := m.Run(); c != 0 {
+			code = c
+		}
+	}
+	restoreBackend()
+	os.Exit(code)
+}
+
+func TestCoreDoesNotImportProtocols(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, imp := range pkg.Imports {
+		if strings.HasPrefix(imp, "github.com/kilic/bls12-381/") {
+			t.Fatal("core package must not import sub packages", imp)
+		}
+	}
 }
 
 func randScalar(max *big.Int) *big.Int {
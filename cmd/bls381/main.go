@@ -0,0 +1,472 @@
+// Command bls381 is a thin command line wrapper around sig package and point
+// encodings of this library.
+//
+// Usage:
+//
+//	bls381 keygen [-sk-file <path>]
+//	bls381 pubkey [-sk-file <path>]
+//	bls381 sign [-sk-file <path>] -msg <message>
+//	bls381 verify -pk <public key> -sig <signature> -msg <message>
+//	bls381 aggregate <signature>...
+//	bls381 aggregate -pk <public key>...
+//	bls381 inspect <point>
+//	bls381 hash -group g2 -dst <tag> -msg <message>
+//
+// Secret keys are 32 byte big endian hex strings. They are never taken as
+// arguments, which are visible to other processes and kept in shell history:
+// pubkey and sign read the key from the file given with -sk-file, or from
+// standard input if the flag is empty or "-". Keygen prints the new key
+// before the public key, or writes it to the -sk-file path with owner only
+// permissions. Public keys and signatures are compressed points written as
+// hex, or with -format as bech32m or base64 text of sig package. Messages are
+// taken as raw strings, or as hex with -hex. Verify exits with status 1 if
+// the signature is invalid, usage errors exit with status 2.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/sig"
+)
+
+// command runs a subcommand with its arguments, reading secret keys from in
+// and writing results to out.
+type command func(args []string, in io.Reader, out io.Writer) error
+
+// usageError is returned by commands for invalid flags, it holds the error
+// and usage of flags as reported by the flag set.
+type usageError string
+
+func (e usageError) Error() string {
+	return strings.TrimSuffix(string(e), "\n")
+}
+
+var commands = map[string]command{
+	"keygen":    keygen,
+	"pubkey":    pubkey,
+	"sign":      sign,
+	"verify":    verify,
+	"aggregate": aggregate,
+	"inspect":   inspect,
+	"hash":      hash,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		return usage(stderr)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return usage(stderr)
+	}
+	err := cmd(args[1:], stdin, stdout)
+	if _, ok := err.(usageError); ok {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) int {
+	fmt.Fprintln(w, "usage: bls381 keygen|pubkey|sign|verify|aggregate|inspect|hash [flags]")
+	return 2
+}
+
+// newFlagSet returns a flag set of a command, errors are returned by parse.
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+// parse parses flags of a command and returns failures as usageError.
+func parse(fs *flag.FlagSet, args []string) error {
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	if err := fs.Parse(args); err != nil {
+		return usageError(buf.String())
+	}
+	return nil
+}
+
+// textFlags are flags shared by commands that read or write keys and
+// signatures.
+type textFlags struct {
+	format string
+	hrp    string
+}
+
+func (f *textFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "hex", "text format of keys and signatures: hex, bech32m or base64")
+	fs.StringVar(&f.hrp, "hrp", "", "bech32m human readable part, blspk or blssig if empty")
+}
+
+// textFormat returns the text format for the given default human readable
+// part, or nil for hex.
+func (f *textFlags) textFormat(hrp string) (sig.TextFormat, error) {
+	switch f.format {
+	case "hex":
+		return nil, nil
+	case "bech32m":
+		if f.hrp != "" {
+			hrp = f.hrp
+		}
+		return sig.Bech32m(hrp), nil
+	case "base64":
+		return sig.Base64, nil
+	}
+	return nil, errors.New("unknown format " + f.format)
+}
+
+func (f *textFlags) publicKey(s string) (*sig.PublicKey, error) {
+	tf, err := f.textFormat(sig.PublicKeyHRP)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil {
+		return sig.PublicKeyFromText(s, tf)
+	}
+	in, err := decodeHex(s)
+	if err != nil {
+		return nil, err
+	}
+	return sig.PublicKeyFromBytes(in)
+}
+
+func (f *textFlags) signature(s string) (*sig.Signature, error) {
+	tf, err := f.textFormat(sig.SignatureHRP)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil {
+		return sig.SignatureFromText(s, tf)
+	}
+	in, err := decodeHex(s)
+	if err != nil {
+		return nil, err
+	}
+	return sig.SignatureFromBytes(in)
+}
+
+func (f *textFlags) printPublicKey(out io.Writer, pk *sig.PublicKey) error {
+	tf, err := f.textFormat(sig.PublicKeyHRP)
+	if err != nil {
+		return err
+	}
+	if tf != nil {
+		fmt.Fprintln(out, pk.Text(tf))
+		return nil
+	}
+	fmt.Fprintln(out, hex.EncodeToString(pk.Bytes()))
+	return nil
+}
+
+func (f *textFlags) printSignature(out io.Writer, s *sig.Signature) error {
+	tf, err := f.textFormat(sig.SignatureHRP)
+	if err != nil {
+		return err
+	}
+	if tf != nil {
+		fmt.Fprintln(out, s.Text(tf))
+		return nil
+	}
+	fmt.Fprintln(out, hex.EncodeToString(s.Bytes()))
+	return nil
+}
+
+// messageFlags are flags of commands that take a message.
+type messageFlags struct {
+	msg   string
+	isHex bool
+}
+
+func (f *messageFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.msg, "msg", "", "message")
+	fs.BoolVar(&f.isHex, "hex", false, "message is hex encoded")
+}
+
+func (f *messageFlags) message() ([]byte, error) {
+	if f.isHex {
+		return decodeHex(f.msg)
+	}
+	return []byte(f.msg), nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// readSecretKey reads a hex encoded secret key from the file at path, or from
+// in if path is empty or "-". Surrounding white space is ignored.
+func readSecretKey(path string, in io.Reader) (*sig.SecretKey, error) {
+	var data []byte
+	var err error
+	if path == "" || path == "-" {
+		data, err = ioutil.ReadAll(in)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "" {
+		return nil, errors.New("secret key is required")
+	}
+	b, err := decodeHex(s)
+	if err != nil {
+		return nil, err
+	}
+	return sig.SecretKeyFromBytes(b)
+}
+
+func keygen(args []string, _ io.Reader, out io.Writer) error {
+	fs := newFlagSet("keygen")
+	skFile := fs.String("sk-file", "", "write secret key to this file instead of standard output")
+	var tf textFlags
+	tf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if _, err := tf.textFormat(sig.PublicKeyHRP); err != nil {
+		return err
+	}
+	sk, err := sig.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	skHex := hex.EncodeToString(sk.Bytes())
+	if *skFile != "" {
+		if err := ioutil.WriteFile(*skFile, []byte(skHex+"\n"), 0600); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(out, skHex)
+	}
+	return tf.printPublicKey(out, sk.PublicKey())
+}
+
+func pubkey(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlagSet("pubkey")
+	skFile := fs.String("sk-file", "", "file with the secret key, standard input if empty or -")
+	var tf textFlags
+	tf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	sk, err := readSecretKey(*skFile, in)
+	if err != nil {
+		return err
+	}
+	return tf.printPublicKey(out, sk.PublicKey())
+}
+
+func sign(args []string, in io.Reader, out io.Writer) error {
+	fs := newFlagSet("sign")
+	skFile := fs.String("sk-file", "", "file with the secret key, standard input if empty or -")
+	var tf textFlags
+	var mf messageFlags
+	tf.register(fs)
+	mf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	sk, err := readSecretKey(*skFile, in)
+	if err != nil {
+		return err
+	}
+	msg, err := mf.message()
+	if err != nil {
+		return err
+	}
+	s, err := sk.Sign(msg)
+	if err != nil {
+		return err
+	}
+	return tf.printSignature(out, s)
+}
+
+func verify(args []string, _ io.Reader, out io.Writer) error {
+	fs := newFlagSet("verify")
+	pkText := fs.String("pk", "", "public key")
+	sigText := fs.String("sig", "", "signature")
+	var tf textFlags
+	var mf messageFlags
+	tf.register(fs)
+	mf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	pk, err := tf.publicKey(*pkText)
+	if err != nil {
+		return err
+	}
+	s, err := tf.signature(*sigText)
+	if err != nil {
+		return err
+	}
+	msg, err := mf.message()
+	if err != nil {
+		return err
+	}
+	if !s.Verify(pk, msg) {
+		return errors.New("invalid signature")
+	}
+	fmt.Fprintln(out, "valid")
+	return nil
+}
+
+func aggregate(args []string, _ io.Reader, out io.Writer) error {
+	fs := newFlagSet("aggregate")
+	keys := fs.Bool("pk", false, "aggregate public keys instead of signatures")
+	var tf textFlags
+	tf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *keys {
+		pks := make([]*sig.PublicKey, fs.NArg())
+		for i, s := range fs.Args() {
+			pk, err := tf.publicKey(s)
+			if err != nil {
+				return fmt.Errorf("public key %d: %v", i, err)
+			}
+			pks[i] = pk
+		}
+		agg, err := sig.AggregatePublicKeys(pks...)
+		if err != nil {
+			return err
+		}
+		return tf.printPublicKey(out, agg)
+	}
+	sigs := make([]*sig.Signature, fs.NArg())
+	for i, s := range fs.Args() {
+		sg, err := tf.signature(s)
+		if err != nil {
+			return fmt.Errorf("signature %d: %v", i, err)
+		}
+		sigs[i] = sg
+	}
+	agg, err := sig.AggregateSignatures(sigs...)
+	if err != nil {
+		return err
+	}
+	return tf.printSignature(out, agg)
+}
+
+// inspect decodes a compressed or uncompressed point, which must be on the
+// curve and in the correct subgroup, and prints both encodings with affine
+// coordinates.
+func inspect(args []string, _ io.Reader, out io.Writer) error {
+	fs := newFlagSet("inspect")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("a single hex encoded point is required")
+	}
+	in, err := decodeHex(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(in) == 0 {
+		return bls.ErrInvalidLength
+	}
+	compressed := in[0]&bls.CompressionFlag != 0
+	switch {
+	case compressed && len(in) == bls.G1CompressedSize, !compressed && len(in) == bls.G1UncompressedSize:
+		g := bls.NewG1()
+		var p *bls.PointG1
+		if compressed {
+			p, err = g.FromCompressed(in)
+		} else {
+			p, err = g.FromUncompressed(in)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "group:", "g1")
+		fmt.Fprintln(out, "compressed:", hex.EncodeToString(g.ToCompressed(p)))
+		fmt.Fprintln(out, "uncompressed:", hex.EncodeToString(g.ToUncompressed(p)))
+		if g.IsZero(p) {
+			fmt.Fprintln(out, "infinity: true")
+			return nil
+		}
+		fmt.Fprintln(out, "x:", "0x"+p[0].Big().Text(16))
+		fmt.Fprintln(out, "y:", "0x"+p[1].Big().Text(16))
+	case compressed && len(in) == bls.G2CompressedSize, !compressed && len(in) == bls.G2UncompressedSize:
+		g := bls.NewG2()
+		var p *bls.PointG2
+		if compressed {
+			p, err = g.FromCompressed(in)
+		} else {
+			p, err = g.FromUncompressed(in)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "group:", "g2")
+		fmt.Fprintln(out, "compressed:", hex.EncodeToString(g.ToCompressed(p)))
+		fmt.Fprintln(out, "uncompressed:", hex.EncodeToString(g.ToUncompressed(p)))
+		if g.IsZero(p) {
+			fmt.Fprintln(out, "infinity: true")
+			return nil
+		}
+		fmt.Fprintln(out, "x:", "0x"+p[0][0].Big().Text(16), "0x"+p[0][1].Big().Text(16))
+		fmt.Fprintln(out, "y:", "0x"+p[1][0].Big().Text(16), "0x"+p[1][1].Big().Text(16))
+	default:
+		return bls.ErrInvalidLength
+	}
+	return nil
+}
+
+func hash(args []string, _ io.Reader, out io.Writer) error {
+	fs := newFlagSet("hash")
+	group := fs.String("group", "g2", "target group: g1 or g2")
+	dst := fs.String("dst", sig.DST, "domain separation tag")
+	var mf messageFlags
+	mf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	msg, err := mf.message()
+	if err != nil {
+		return err
+	}
+	switch *group {
+	case "g1":
+		g := bls.NewG1()
+		p, err := g.HashToCurve(msg, []byte(*dst))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, hex.EncodeToString(g.ToCompressed(p)))
+	case "g2":
+		g := bls.NewG2()
+		p, err := g.HashToCurve(msg, []byte(*dst))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, hex.EncodeToString(g.ToCompressed(p)))
+	default:
+		return errors.New("unknown group " + *group)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// exec runs the command line with given standard input and returns exit
+// status and lines of standard output.
+func exec(t *testing.T, stdin string, args ...string) (int, []string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, strings.Fields(stdout.String())
+}
+
+func TestKeygenSignVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bls381")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	skFile := filepath.Join(dir, "key")
+
+	code, out := exec(t, "", "keygen", "-sk-file", skFile)
+	if code != 0 || len(out) != 1 {
+		t.Fatal("keygen failed", code, out)
+	}
+	pk := out[0]
+	info, err := os.Stat(skFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatal("secret key file must be readable by owner only", info.Mode())
+	}
+	code, out = exec(t, "", "pubkey", "-sk-file", skFile)
+	if code != 0 || len(out) != 1 || out[0] != pk {
+		t.Fatal("pubkey must match keygen", code, out)
+	}
+	code, out = exec(t, "", "sign", "-sk-file", skFile, "-msg", "hello")
+	if code != 0 || len(out) != 1 {
+		t.Fatal("sign failed", code, out)
+	}
+	signature := out[0]
+	code, _ = exec(t, "", "verify", "-pk", pk, "-sig", signature, "-msg", "hello")
+	if code != 0 {
+		t.Fatal("signature must verify", code)
+	}
+	code, _ = exec(t, "", "verify", "-pk", pk, "-sig", signature, "-msg", "bye")
+	if code != 1 {
+		t.Fatal("signature of another message must not verify", code)
+	}
+
+	// secret key from standard input
+	code, out = exec(t, "", "keygen", "-format", "bech32m")
+	if code != 0 || len(out) != 2 {
+		t.Fatal("keygen failed", code, out)
+	}
+	sk, pk := out[0], out[1]
+	code, out = exec(t, sk+"\n", "sign", "-sk-file", "-", "-format", "bech32m", "-hex", "-msg", "0xabcd")
+	if code != 0 || len(out) != 1 {
+		t.Fatal("sign failed", code, out)
+	}
+	code, _ = exec(t, "", "verify", "-format", "bech32m", "-pk", pk, "-sig", out[0], "-hex", "-msg", "abcd")
+	if code != 0 {
+		t.Fatal("signature must verify", code)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bls381")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	badKey := filepath.Join(dir, "bad")
+	if err := ioutil.WriteFile(badKey, []byte("zz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range []struct {
+		stdin string
+		args  []string
+		code  int
+	}{
+		{"", nil, 2},
+		{"", []string{"unknown"}, 2},
+		{"", []string{"sign", "-sk", "01"}, 2},
+		{"", []string{"keygen", "-format"}, 2},
+		{"", []string{"sign", "-msg", "hello"}, 1},
+		{"", []string{"pubkey", "-sk-file", filepath.Join(dir, "missing")}, 1},
+		{"", []string{"pubkey", "-sk-file", badKey}, 1},
+		{"00", []string{"pubkey"}, 1},
+		{"", []string{"keygen", "-format", "base58"}, 1},
+		{"", []string{"verify", "-pk", "00", "-sig", "00"}, 1},
+		{"", []string{"inspect", "0102"}, 1},
+		{"", []string{"hash", "-group", "gt"}, 1},
+	} {
+		if code, _ := exec(t, c.stdin, c.args...); code != c.code {
+			t.Fatal("bad exit status", i, code)
+		}
+	}
+}
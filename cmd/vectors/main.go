@@ -0,0 +1,329 @@
+// Command vectors generates test vectors from this implementation as JSON so
+// that other implementations can cross test against it.
+//
+// Usage:
+//
+//	go run ./cmd/vectors -n 16 -seed 1 -out vectors.json
+//
+// Randomness is derived from the given seed, so the same seed always yields
+// the same set of vectors. Field elements and scalars are encoded as 32 byte
+// big endian hex strings, points are encoded in compressed form and target
+// group elements follow GT.ToBytes encoding. Hashing vectors use the suite
+// identifier followed by VECTORS as domain separation tag.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// hashTag follows suite identifiers in domain separation tags of hashing
+// vectors, as TESTGEN does in vectors of the hash to curve draft, so that
+// every suite is hashed under its own tag.
+const hashTag = "VECTORS"
+
+type fieldVector struct {
+	Op  string `json:"op"`
+	A   string `json:"a"`
+	B   string `json:"b,omitempty"`
+	Out string `json:"out"`
+}
+
+type groupVector struct {
+	Op     string `json:"op"`
+	P      string `json:"p"`
+	Q      string `json:"q,omitempty"`
+	Scalar string `json:"scalar,omitempty"`
+	Out    string `json:"out"`
+}
+
+type pairingVector struct {
+	P   string `json:"p"`
+	Q   string `json:"q"`
+	Out string `json:"out"`
+}
+
+type hashVector struct {
+	Msg    string `json:"msg"`
+	Domain string `json:"dst"`
+	Out    string `json:"out"`
+}
+
+type vectors struct {
+	Seed       int64           `json:"seed"`
+	Fr         []fieldVector   `json:"fr"`
+	G1         []groupVector   `json:"g1"`
+	G2         []groupVector   `json:"g2"`
+	Pairing    []pairingVector `json:"pairing"`
+	HashToG1   []hashVector    `json:"hash_to_g1"`
+	HashToG2   []hashVector    `json:"hash_to_g2"`
+	EncodeToG1 []hashVector    `json:"encode_to_g1"`
+	EncodeToG2 []hashVector    `json:"encode_to_g2"`
+}
+
+func main() {
+	n := flag.Int("n", 8, "number of vectors per operation")
+	seed := flag.Int64("seed", 1, "seed for deterministic randomness")
+	out := flag.String("out", "", "output file, standard output if empty")
+	flag.Parse()
+
+	v, err := generate(rand.New(rand.NewSource(*seed)), *n)
+	if err != nil {
+		fatal(err)
+	}
+	v.Seed = *seed
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+func generate(r io.Reader, n int) (*vectors, error) {
+	v := &vectors{}
+	var err error
+	if v.Fr, err = frVectors(r, n); err != nil {
+		return nil, err
+	}
+	if v.G1, err = g1Vectors(r, n); err != nil {
+		return nil, err
+	}
+	if v.G2, err = g2Vectors(r, n); err != nil {
+		return nil, err
+	}
+	if v.Pairing, err = pairingVectors(r, n); err != nil {
+		return nil, err
+	}
+	if v.HashToG1, v.EncodeToG1, err = hashG1Vectors(r, n); err != nil {
+		return nil, err
+	}
+	if v.HashToG2, v.EncodeToG2, err = hashG2Vectors(r, n); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func frVectors(r io.Reader, n int) ([]fieldVector, error) {
+	vectors := []fieldVector{}
+	c := bls.NewFr()
+	for i := 0; i < n; i++ {
+		a, err := bls.NewFr().Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		b, err := bls.NewFr().Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		c.Add(a, b)
+		vectors = append(vectors, fieldVector{"add", frHex(a), frHex(b), frHex(c)})
+		c.Sub(a, b)
+		vectors = append(vectors, fieldVector{"sub", frHex(a), frHex(b), frHex(c)})
+		c.Mul(a, b)
+		vectors = append(vectors, fieldVector{"mul", frHex(a), frHex(b), frHex(c)})
+		c.Neg(a)
+		vectors = append(vectors, fieldVector{"neg", frHex(a), "", frHex(c)})
+		c.Square(a)
+		vectors = append(vectors, fieldVector{"square", frHex(a), "", frHex(c)})
+		c.Inverse(a)
+		vectors = append(vectors, fieldVector{"inverse", frHex(a), "", frHex(c)})
+	}
+	return vectors, nil
+}
+
+func g1Vectors(r io.Reader, n int) ([]groupVector, error) {
+	vectors := []groupVector{}
+	g := bls.NewG1()
+	c := g.New()
+	for i := 0; i < n; i++ {
+		p, err := randG1(g, r)
+		if err != nil {
+			return nil, err
+		}
+		q, err := randG1(g, r)
+		if err != nil {
+			return nil, err
+		}
+		s, err := bls.NewFr().Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		pHex, qHex := hex.EncodeToString(g.ToCompressed(p)), hex.EncodeToString(g.ToCompressed(q))
+		g.Add(c, p, q)
+		vectors = append(vectors, groupVector{Op: "add", P: pHex, Q: qHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.Double(c, p)
+		vectors = append(vectors, groupVector{Op: "double", P: pHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.Neg(c, p)
+		vectors = append(vectors, groupVector{Op: "neg", P: pHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.MulScalar(c, p, s)
+		vectors = append(vectors, groupVector{Op: "mul", P: pHex, Scalar: frHex(s), Out: hex.EncodeToString(g.ToCompressed(c))})
+	}
+	return vectors, nil
+}
+
+func g2Vectors(r io.Reader, n int) ([]groupVector, error) {
+	vectors := []groupVector{}
+	g := bls.NewG2()
+	c := g.New()
+	for i := 0; i < n; i++ {
+		p, err := randG2(g, r)
+		if err != nil {
+			return nil, err
+		}
+		q, err := randG2(g, r)
+		if err != nil {
+			return nil, err
+		}
+		s, err := bls.NewFr().Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		pHex, qHex := hex.EncodeToString(g.ToCompressed(p)), hex.EncodeToString(g.ToCompressed(q))
+		g.Add(c, p, q)
+		vectors = append(vectors, groupVector{Op: "add", P: pHex, Q: qHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.Double(c, p)
+		vectors = append(vectors, groupVector{Op: "double", P: pHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.Neg(c, p)
+		vectors = append(vectors, groupVector{Op: "neg", P: pHex, Out: hex.EncodeToString(g.ToCompressed(c))})
+		g.MulScalar(c, p, s)
+		vectors = append(vectors, groupVector{Op: "mul", P: pHex, Scalar: frHex(s), Out: hex.EncodeToString(g.ToCompressed(c))})
+	}
+	return vectors, nil
+}
+
+func pairingVectors(r io.Reader, n int) ([]pairingVector, error) {
+	vectors := []pairingVector{}
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	for i := 0; i < n; i++ {
+		p, err := randG1(g1, r)
+		if err != nil {
+			return nil, err
+		}
+		q, err := randG2(g2, r)
+		if err != nil {
+			return nil, err
+		}
+		e := bls.NewEngine()
+		e.AddPair(p, q)
+		out := e.Result()
+		vectors = append(vectors, pairingVector{
+			P:   hex.EncodeToString(g1.ToCompressed(p)),
+			Q:   hex.EncodeToString(g2.ToCompressed(q)),
+			Out: hex.EncodeToString(e.GT().ToBytes(out)),
+		})
+	}
+	return vectors, nil
+}
+
+func hashG1Vectors(r io.Reader, n int) ([]hashVector, []hashVector, error) {
+	hash, encode := []hashVector{}, []hashVector{}
+	g := bls.NewG1()
+	for i := 0; i < n; i++ {
+		msg, err := randMessage(r, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		h, e, err := hashG1(g, msg, bls.AlgorithmHashToG1+hashTag, bls.AlgorithmEncodeToG1+hashTag)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash, encode = append(hash, h), append(encode, e)
+	}
+	return hash, encode, nil
+}
+
+// hashG1 hashes and encodes a message to G1 under given tags.
+func hashG1(g *bls.G1, msg []byte, hashDST, encodeDST string) (hashVector, hashVector, error) {
+	p, err := g.HashToCurve(msg, []byte(hashDST))
+	if err != nil {
+		return hashVector{}, hashVector{}, err
+	}
+	h := hashVector{hex.EncodeToString(msg), hashDST, hex.EncodeToString(g.ToCompressed(p))}
+	p, err = g.EncodeToCurve(msg, []byte(encodeDST))
+	if err != nil {
+		return hashVector{}, hashVector{}, err
+	}
+	return h, hashVector{hex.EncodeToString(msg), encodeDST, hex.EncodeToString(g.ToCompressed(p))}, nil
+}
+
+func hashG2Vectors(r io.Reader, n int) ([]hashVector, []hashVector, error) {
+	hash, encode := []hashVector{}, []hashVector{}
+	g := bls.NewG2()
+	for i := 0; i < n; i++ {
+		msg, err := randMessage(r, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		h, e, err := hashG2(g, msg, bls.AlgorithmHashToG2+hashTag, bls.AlgorithmEncodeToG2+hashTag)
+		if err != nil {
+			return nil, nil, err
+		}
+		hash, encode = append(hash, h), append(encode, e)
+	}
+	return hash, encode, nil
+}
+
+// hashG2 hashes and encodes a message to G2 under given tags.
+func hashG2(g *bls.G2, msg []byte, hashDST, encodeDST string) (hashVector, hashVector, error) {
+	p, err := g.HashToCurve(msg, []byte(hashDST))
+	if err != nil {
+		return hashVector{}, hashVector{}, err
+	}
+	h := hashVector{hex.EncodeToString(msg), hashDST, hex.EncodeToString(g.ToCompressed(p))}
+	p, err = g.EncodeToCurve(msg, []byte(encodeDST))
+	if err != nil {
+		return hashVector{}, hashVector{}, err
+	}
+	return h, hashVector{hex.EncodeToString(msg), encodeDST, hex.EncodeToString(g.ToCompressed(p))}, nil
+}
+
+func randG1(g *bls.G1, r io.Reader) (*bls.PointG1, error) {
+	s, err := bls.NewFr().Rand(r)
+	if err != nil {
+		return nil, err
+	}
+	return g.MulScalar(g.New(), g.One(), s), nil
+}
+
+func randG2(g *bls.G2, r io.Reader) (*bls.PointG2, error) {
+	s, err := bls.NewFr().Rand(r)
+	if err != nil {
+		return nil, err
+	}
+	return g.MulScalar(g.New(), g.One(), s), nil
+}
+
+// randMessage returns a message of varying length, first one is always empty.
+func randMessage(r io.Reader, i int) ([]byte, error) {
+	msg := make([]byte, (i*17)%129)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func frHex(e *bls.Fr) string {
+	return hex.EncodeToString(e.ToBytes())
+}
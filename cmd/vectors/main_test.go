@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestHashVectors(t *testing.T) {
+	// message abc under TESTGEN tags, as expected by hashing tests of the
+	// root package
+	msg := []byte("abc")
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	hash, encode, err := hashG1(g1, msg, bls.AlgorithmHashToG1+"TESTGEN", bls.AlgorithmEncodeToG1+"TESTGEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []struct {
+		have     hashVector
+		expected string
+	}{
+		{hash, "061daf0cc00d8912dac1d4cf5a7c32fca97f8b3bf3f805121888e5eb89f77f9a9f406569027ac6d0e61b1229f42c43d6" +
+			"0de1601e5ba02cb637c1d35266f5700acee9850796dc88e860d022d7b9e7e3dce5950952e97861e5bb16d215c87f030d"},
+		{encode, "179d3fd0b4fb1da43aad06cea1fb3f828806ddb1b1fa9424b1e3944dfdbab6e763c42636404017da03099af0dcca0fd6" +
+			"0d037cb1c6d495c0f5f22b061d23f1be3d7fe64d3c6820cfcd99b6b36fa69f7b4c1f4addba2ae7aa46fb25901ab483e4"},
+	} {
+		p, err := g1.FromBytes(fromHex(t, v.expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.have.Msg != hex.EncodeToString(msg) || v.have.Out != hex.EncodeToString(g1.ToCompressed(p)) {
+			t.Fatal("bad g1 hashing vector", i)
+		}
+	}
+	hash, encode, err = hashG2(g2, msg, bls.AlgorithmHashToG2+"TESTGEN", bls.AlgorithmEncodeToG2+"TESTGEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range []struct {
+		have     hashVector
+		expected string
+	}{
+		{hash, "03578447618463deb106b60e609c6f7cc446dc6035f84a72801ba17c94cd800583b493b948eff0033f09086fdd7f6175" +
+			"1953ce6d4267939c7360756d9cca8eb34aac4633ef35369a7dc249445069888e7d1b3f9d2e75fbd468fbcbba7110ea02" +
+			"0184d26779ae9d4670aca9b267dbd4d3b30443ad05b8546d36a195686e1ccc3a59194aea05ed5bce7c3144a29ec047c4" +
+			"0882ab045b8fe4d7d557ebb59a63a35ac9f3d312581b509af0f8eaa2960cbc5e1e36bb969b6e22980b5cbdd0787fcf4e"},
+		{encode, "18f0f87b40af67c056915dbaf48534c592524e82c1c2b50c3734d02c0172c80df780a60b5683759298a3303c5d942778" +
+			"09349f1cb5b2e55489dcd45a38545343451cc30a1681c57acd4fb0a6db125f8352c09f4a67eb7d1d8242cb7d3405f97b" +
+			"10a2ba341bc689ab947b7941ce6ef39be17acaab067bd32bd652b471ab0792c53a2bd03bdac47f96aaafe96e441f63c0" +
+			"02f2d9deb2c7742512f5b8230bf0fd83ea42279d7d39779543c1a43b61c885982b611f6a7a24b514995e8a098496b811"},
+	} {
+		p, err := g2.FromBytes(fromHex(t, v.expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.have.Msg != hex.EncodeToString(msg) || v.have.Out != hex.EncodeToString(g2.ToCompressed(p)) {
+			t.Fatal("bad g2 hashing vector", i)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	v0, err := generate(rand.New(rand.NewSource(1)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1, _ := generate(rand.New(rand.NewSource(1)), 2)
+	if v0.Pairing[1] != v1.Pairing[1] || v0.HashToG2[1] != v1.HashToG2[1] {
+		t.Fatal("vectors must be deterministic for a seed")
+	}
+	// every suite is hashed under its own tag
+	seen := map[string]bool{}
+	for _, vs := range [][]hashVector{v0.HashToG1, v0.EncodeToG1, v0.HashToG2, v0.EncodeToG2} {
+		dst := vs[0].Domain
+		if seen[dst] || vs[1].Domain != dst {
+			t.Fatal("bad domain separation tag", dst)
+		}
+		seen[dst] = true
+	}
+	if v0.HashToG1[0].Domain != "BLS12381G1_XMD:SHA-256_SSWU_RO_VECTORS" || v0.EncodeToG2[0].Domain != "BLS12381G2_XMD:SHA-256_SSWU_NU_VECTORS" {
+		t.Fatal("unexpected domain separation tags")
+	}
+}
@@ -0,0 +1,142 @@
+package bls12381
+
+import (
+	"io"
+	"math/big"
+)
+
+// Compile time check that exported API of upstream kilic/bls12-381, which this
+// package is derived from, is kept intact. Importers of upstream can switch to
+// this module without code changes. Entries must not be removed or changed.
+var (
+	_ PointG1                                                       = G1One
+	_ PointG2                                                       = G2One
+	_ func() *Engine                                                = NewEngine
+	_ func() *Fr                                                    = NewFr
+	_ func() *G1                                                    = NewG1
+	_ func() *G2                                                    = NewG2
+	_ func() *GT                                                    = NewGT
+	_ func(*E) *E                                                   = (*E).One
+	_ func(*E) bool                                                 = (*E).IsOne
+	_ func(*E, *E) *E                                               = (*E).Set
+	_ func(*E, *E) bool                                             = (*E).Equal
+	_ func(*Engine) *E                                              = (*Engine).Result
+	_ func(*Engine) *Engine                                         = (*Engine).Reset
+	_ func(*Engine) *GT                                             = (*Engine).GT
+	_ func(*Engine) bool                                            = (*Engine).Check
+	_ func(*Engine, *PointG1, *PointG2) *Engine                     = (*Engine).AddPair
+	_ func(*Engine, *PointG1, *PointG2) *Engine                     = (*Engine).AddPairInv
+	_ func(*Fe) *big.Int                                            = ToBig
+	_ func(*Fe) string                                              = ToString
+	_ func(*Fr) *Fr                                                 = (*Fr).One
+	_ func(*Fr) *Fr                                                 = (*Fr).RedOne
+	_ func(*Fr) *Fr                                                 = (*Fr).Zero
+	_ func(*Fr) *big.Int                                            = (*Fr).RedToBig
+	_ func(*Fr) *big.Int                                            = (*Fr).ToBig
+	_ func(*Fr)                                                     = (*Fr).FromRed
+	_ func(*Fr)                                                     = (*Fr).ToRed
+	_ func(*Fr) []byte                                              = (*Fr).RedToBytes
+	_ func(*Fr) []byte                                              = (*Fr).ToBytes
+	_ func(*Fr) bool                                                = (*Fr).IsOne
+	_ func(*Fr) bool                                                = (*Fr).IsRedOne
+	_ func(*Fr) bool                                                = (*Fr).IsZero
+	_ func(*Fr, *Fr) *Fr                                            = (*Fr).Set
+	_ func(*Fr, *Fr)                                                = (*Fr).Double
+	_ func(*Fr, *Fr)                                                = (*Fr).Inverse
+	_ func(*Fr, *Fr)                                                = (*Fr).Neg
+	_ func(*Fr, *Fr)                                                = (*Fr).RedInverse
+	_ func(*Fr, *Fr)                                                = (*Fr).RedSquare
+	_ func(*Fr, *Fr)                                                = (*Fr).Square
+	_ func(*Fr, *Fr) bool                                           = (*Fr).Equal
+	_ func(*Fr, *Fr) int                                            = (*Fr).Cmp
+	_ func(*Fr, *Fr, *Fr)                                           = (*Fr).Add
+	_ func(*Fr, *Fr, *Fr)                                           = (*Fr).Mul
+	_ func(*Fr, *Fr, *Fr)                                           = (*Fr).RedMul
+	_ func(*Fr, *Fr, *Fr)                                           = (*Fr).Sub
+	_ func(*Fr, *Fr, *big.Int)                                      = (*Fr).Exp
+	_ func(*Fr, *Fr, *big.Int)                                      = (*Fr).RedExp
+	_ func(*Fr, []byte) *Fr                                         = (*Fr).FromBytes
+	_ func(*Fr, []byte) *Fr                                         = (*Fr).RedFromBytes
+	_ func(*Fr, int) bool                                           = (*Fr).Bit
+	_ func(*Fr, io.Reader) (*Fr, error)                             = (*Fr).Rand
+	_ func(*G1) *PointG1                                            = (*G1).New
+	_ func(*G1) *PointG1                                            = (*G1).One
+	_ func(*G1) *PointG1                                            = (*G1).Zero
+	_ func(*G1) *big.Int                                            = (*G1).Q
+	_ func(*G1, *PointG1) *PointG1                                  = (*G1).Affine
+	_ func(*G1, *PointG1) *PointG1                                  = (*G1).ClearCofactor
+	_ func(*G1, *PointG1) []byte                                    = (*G1).ToBytes
+	_ func(*G1, *PointG1) []byte                                    = (*G1).ToCompressed
+	_ func(*G1, *PointG1) []byte                                    = (*G1).ToUncompressed
+	_ func(*G1, *PointG1) bool                                      = (*G1).InCorrectSubgroup
+	_ func(*G1, *PointG1) bool                                      = (*G1).IsAffine
+	_ func(*G1, *PointG1) bool                                      = (*G1).IsOnCurve
+	_ func(*G1, *PointG1) bool                                      = (*G1).IsZero
+	_ func(*G1, *PointG1, *PointG1) *PointG1                        = (*G1).Double
+	_ func(*G1, *PointG1, *PointG1) *PointG1                        = (*G1).Neg
+	_ func(*G1, *PointG1, *PointG1) bool                            = (*G1).Equal
+	_ func(*G1, *PointG1, *PointG1, *Fr) *PointG1                   = (*G1).MulScalar
+	_ func(*G1, *PointG1, *PointG1, *PointG1) *PointG1              = (*G1).Add
+	_ func(*G1, *PointG1, *PointG1, *PointG1) *PointG1              = (*G1).AddMixed
+	_ func(*G1, *PointG1, *PointG1, *PointG1) *PointG1              = (*G1).Sub
+	_ func(*G1, *PointG1, *PointG1, *big.Int) *PointG1              = (*G1).MulScalarBig
+	_ func(*G1, *PointG1, []*PointG1, []*Fr) (*PointG1, error)      = (*G1).MultiExp
+	_ func(*G1, *PointG1, []*PointG1, []*big.Int) (*PointG1, error) = (*G1).MultiExpBig
+	_ func(*G1, []*PointG1)                                         = (*G1).AffineBatch
+	_ func(*G1, []byte) (*PointG1, error)                           = (*G1).FromBytes
+	_ func(*G1, []byte) (*PointG1, error)                           = (*G1).FromCompressed
+	_ func(*G1, []byte) (*PointG1, error)                           = (*G1).FromUncompressed
+	_ func(*G1, []byte) (*PointG1, error)                           = (*G1).MapToCurve
+	_ func(*G1, []byte, []byte) (*PointG1, error)                   = (*G1).EncodeToCurve
+	_ func(*G1, []byte, []byte) (*PointG1, error)                   = (*G1).HashToCurve
+	_ func(*G2) *PointG2                                            = (*G2).New
+	_ func(*G2) *PointG2                                            = (*G2).One
+	_ func(*G2) *PointG2                                            = (*G2).Zero
+	_ func(*G2) *big.Int                                            = (*G2).Q
+	_ func(*G2, *PointG2) *PointG2                                  = (*G2).Affine
+	_ func(*G2, *PointG2) *PointG2                                  = (*G2).ClearCofactor
+	_ func(*G2, *PointG2) []byte                                    = (*G2).ToBytes
+	_ func(*G2, *PointG2) []byte                                    = (*G2).ToCompressed
+	_ func(*G2, *PointG2) []byte                                    = (*G2).ToUncompressed
+	_ func(*G2, *PointG2) bool                                      = (*G2).InCorrectSubgroup
+	_ func(*G2, *PointG2) bool                                      = (*G2).IsAffine
+	_ func(*G2, *PointG2) bool                                      = (*G2).IsOnCurve
+	_ func(*G2, *PointG2) bool                                      = (*G2).IsZero
+	_ func(*G2, *PointG2, *PointG2) *PointG2                        = (*G2).Double
+	_ func(*G2, *PointG2, *PointG2) *PointG2                        = (*G2).Neg
+	_ func(*G2, *PointG2, *PointG2) bool                            = (*G2).Equal
+	_ func(*G2, *PointG2, *PointG2, *Fr) *PointG2                   = (*G2).MulScalar
+	_ func(*G2, *PointG2, *PointG2, *PointG2) *PointG2              = (*G2).Add
+	_ func(*G2, *PointG2, *PointG2, *PointG2) *PointG2              = (*G2).AddMixed
+	_ func(*G2, *PointG2, *PointG2, *PointG2) *PointG2              = (*G2).Sub
+	_ func(*G2, *PointG2, *PointG2, *big.Int) *PointG2              = (*G2).MulScalarBig
+	_ func(*G2, *PointG2, []*PointG2, []*Fr) (*PointG2, error)      = (*G2).MultiExp
+	_ func(*G2, *PointG2, []*PointG2, []*big.Int) (*PointG2, error) = (*G2).MultiExpBig
+	_ func(*G2, []*PointG2)                                         = (*G2).AffineBatch
+	_ func(*G2, []byte) (*PointG2, error)                           = (*G2).FromBytes
+	_ func(*G2, []byte) (*PointG2, error)                           = (*G2).FromCompressed
+	_ func(*G2, []byte) (*PointG2, error)                           = (*G2).FromUncompressed
+	_ func(*G2, []byte) (*PointG2, error)                           = (*G2).MapToCurve
+	_ func(*G2, []byte, []byte) (*PointG2, error)                   = (*G2).EncodeToCurve
+	_ func(*G2, []byte, []byte) (*PointG2, error)                   = (*G2).HashToCurve
+	_ func(*GT) *E                                                  = (*GT).New
+	_ func(*GT) *big.Int                                            = (*GT).Q
+	_ func(*GT, *E) []byte                                          = (*GT).ToBytes
+	_ func(*GT, *E) bool                                            = (*GT).IsValid
+	_ func(*GT, *E, *E)                                             = (*GT).Inverse
+	_ func(*GT, *E, *E)                                             = (*GT).Square
+	_ func(*GT, *E, *E, *E)                                         = (*GT).Add
+	_ func(*GT, *E, *E, *E)                                         = (*GT).Mul
+	_ func(*GT, *E, *E, *E)                                         = (*GT).Sub
+	_ func(*GT, *E, *E, *big.Int)                                   = (*GT).Exp
+	_ func(*GT, []byte) (*E, error)                                 = (*GT).FromBytes
+	_ func(*PointG1) *PointG1                                       = (*PointG1).Zero
+	_ func(*PointG1) bool                                           = (*PointG1).IsAffine
+	_ func(*PointG1, *PointG1) *PointG1                             = (*PointG1).Set
+	_ func(*PointG2) *PointG2                                       = (*PointG2).Zero
+	_ func(*PointG2) bool                                           = (*PointG2).IsAffine
+	_ func(*PointG2, *PointG2) *PointG2                             = (*PointG2).Set
+	_ func([]Fr)                                                    = InverseBatchFr
+	_ func([]Fr)                                                    = RedInverseBatchFr
+	_ func([]byte, []byte, int) ([]*Fe, error)                      = HashToFpXMDSHA256
+)
@@ -0,0 +1,151 @@
+// Package delegation outsources pairing computation to an untrusted party.
+package delegation
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// ErrInconsistent is returned if results of a delegated pairing fail
+// consistency checks.
+var ErrInconsistent = errors.New("delegated pairing results are inconsistent")
+
+var (
+	generatorPairingOnce sync.Once
+	generatorPairing     *bls.E
+)
+
+// pairingOfGenerators returns e(g1, g2), which is computed once.
+func pairingOfGenerators() *bls.E {
+	generatorPairingOnce.Do(func() {
+		e := bls.NewEngine()
+		generatorPairing = e.AddPair(e.G1.One(), e.G2.One()).Result()
+	})
+	return generatorPairing
+}
+
+type delegatedPair struct {
+	p *bls.PointG1
+	q *bls.PointG2
+	// unblind is the inverse of the blinding factor for the target pair
+	unblind *big.Int
+	// expected is the known result for a test pair
+	expected *bls.E
+}
+
+// Pairing outsources computation of e(P, Q) to an untrusted party.
+//
+// Target pair is sent twice as (aP, bQ) and (cP, dQ) with fresh random
+// scalars, so that neither request reveals P or Q. Requests are shuffled
+// together with test pairs (xG1, yG2) of which results are known from e(G1, G2)
+// and which are indistinguishable from blinded requests. Results are
+// accepted if both unblinded target results agree and every test result is
+// correct, thus a server that alters some results is caught unless it
+// guesses exactly which requests are the target ones. P and Q are expected
+// to be in correct subgroups. Points at infinity stay at infinity under
+// blinding and are not hidden.
+//
+// Verifying results costs a few exponentiations in the target group instead
+// of a pairing, and computation of e(G1, G2) once per process.
+type Pairing struct {
+	pairs []delegatedPair
+}
+
+// NewPairing prepares requests for e(p, q) with given number of test pairs,
+// which must be at least one.
+func NewPairing(r io.Reader, p *bls.PointG1, q *bls.PointG2, tests int) (*Pairing, error) {
+	if tests < 1 {
+		return nil, errors.New("at least one test pair is required")
+	}
+	g1, g2, gt := bls.NewG1(), bls.NewG2(), bls.NewGT()
+	pairs := make([]delegatedPair, 0, tests+2)
+	for i := 0; i < 2; i++ {
+		a, err := new(bls.Fr).RandNonZero(r)
+		if err != nil {
+			return nil, err
+		}
+		b, err := new(bls.Fr).RandNonZero(r)
+		if err != nil {
+			return nil, err
+		}
+		ab := new(bls.Fr)
+		ab.Mul(a, b)
+		ab.Inverse(ab)
+		pairs = append(pairs, delegatedPair{
+			p:       g1.Affine(g1.MulScalar(g1.New(), p, a)),
+			q:       g2.Affine(g2.MulScalar(g2.New(), q, b)),
+			unblind: ab.ToBig(),
+		})
+	}
+	for i := 0; i < tests; i++ {
+		x, err := new(bls.Fr).RandNonZero(r)
+		if err != nil {
+			return nil, err
+		}
+		y, err := new(bls.Fr).RandNonZero(r)
+		if err != nil {
+			return nil, err
+		}
+		xy := new(bls.Fr)
+		xy.Mul(x, y)
+		expected := gt.New()
+		gt.Exp(expected, pairingOfGenerators(), xy.ToBig())
+		pairs = append(pairs, delegatedPair{
+			p:        g1.Affine(g1.MulScalar(g1.New(), g1.One(), x)),
+			q:        g2.Affine(g2.MulScalar(g2.New(), g2.One(), y)),
+			expected: expected,
+		})
+	}
+	// Fisher-Yates shuffle
+	for i := len(pairs) - 1; i > 0; i-- {
+		j, err := rand.Int(r, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		pairs[i], pairs[j.Int64()] = pairs[j.Int64()], pairs[i]
+	}
+	return &Pairing{pairs}, nil
+}
+
+// Requests returns pairs of which pairings are to be computed by the
+// delegate, result of i-th pairing is expected at i-th index.
+func (d *Pairing) Requests() ([]*bls.PointG1, []*bls.PointG2) {
+	ps, qs := make([]*bls.PointG1, len(d.pairs)), make([]*bls.PointG2, len(d.pairs))
+	for i := range d.pairs {
+		ps[i], qs[i] = d.pairs[i].p, d.pairs[i].q
+	}
+	return ps, qs
+}
+
+// Result checks results computed by the delegate and returns e(p, q).
+func (d *Pairing) Result(results []*bls.E) (*bls.E, error) {
+	if len(results) != len(d.pairs) {
+		return nil, ErrInconsistent
+	}
+	gt := bls.NewGT()
+	var result *bls.E
+	for i := range d.pairs {
+		if !gt.IsValid(results[i]) {
+			return nil, ErrInconsistent
+		}
+		if d.pairs[i].expected != nil {
+			if !results[i].Equal(d.pairs[i].expected) {
+				return nil, ErrInconsistent
+			}
+			continue
+		}
+		u := gt.New()
+		gt.Exp(u, results[i], d.pairs[i].unblind)
+		if result == nil {
+			result = u
+		} else if !result.Equal(u) {
+			return nil, ErrInconsistent
+		}
+	}
+	return result, nil
+}
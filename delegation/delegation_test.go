@@ -0,0 +1,71 @@
+package delegation
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func delegate(ps []*bls.PointG1, qs []*bls.PointG2) []*bls.E {
+	results := make([]*bls.E, len(ps))
+	for i := range ps {
+		results[i] = bls.NewEngine().AddPair(ps[i], qs[i]).Result()
+	}
+	return results
+}
+
+func TestPairing(t *testing.T) {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	a, err := bls.NewFr().Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, q := g1.MulScalar(g1.New(), g1.One(), a), g2.MulScalar(g2.New(), g2.One(), a)
+	expected := bls.NewEngine().AddPair(p, q).Result()
+	d, err := NewPairing(rand.Reader, p, q, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, qs := d.Requests()
+	if len(ps) != 4 || len(qs) != 4 {
+		t.Fatal("bad number of requests")
+	}
+	for i := range ps {
+		if g1.Equal(ps[i], p) || g2.Equal(qs[i], q) {
+			t.Fatal("target pair must be blinded")
+		}
+	}
+	results := delegate(ps, qs)
+	r, err := d.Result(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Equal(expected) {
+		t.Fatal("bad delegated pairing")
+	}
+
+	gt := bls.NewGT()
+	// every altered result must be caught
+	for i := range results {
+		tampered := delegate(ps, qs)
+		gt.Square(tampered[i], tampered[i])
+		if _, err := d.Result(tampered); err != ErrInconsistent {
+			t.Fatal("altered result must be rejected", i)
+		}
+	}
+	// raising all results to the same power must be caught by test pairs
+	tampered := delegate(ps, qs)
+	for i := range tampered {
+		gt.Square(tampered[i], tampered[i])
+	}
+	if _, err := d.Result(tampered); err != ErrInconsistent {
+		t.Fatal("altered results must be rejected")
+	}
+	if _, err := d.Result(results[1:]); err != ErrInconsistent {
+		t.Fatal("missing results must be rejected")
+	}
+	if _, err := NewPairing(rand.Reader, p, q, 0); err == nil {
+		t.Fatal("test pairs must be required")
+	}
+}
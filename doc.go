@@ -0,0 +1,17 @@
+// Package bls12381 implements base and scalar field arithmetic, G1 and G2
+// groups, hashing to curve and the optimal ate pairing over BLS12-381.
+//
+// Protocols are built in sub packages on top of this package, so importing
+// the core does not link any protocol code:
+//
+//	sig         BLS signatures
+//	kzg         KZG polynomial commitments and data availability sampling helpers
+//	eth         Ethereum consensus layer domains, signing roots and deposits
+//	nizk        non interactive proofs of knowledge
+//	delegation  outsourcing pairing computation to an untrusted party
+//	legendre    Legendre pseudo random function for proof of custody
+//
+// Field, group and pairing code is kept in a single package since groups and
+// the pairing engine share unexported, assembly backed field arithmetic and
+// temporaries. Splitting them would require exporting those internals.
+package bls12381
@@ -0,0 +1,164 @@
+package bls12381
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EncodingVersion identifies the wire format of point and field element
+// encodings described by the sizes and flags below. Encodings do not carry it
+// and serializers do not check it. It is incremented on any change to the
+// format, so that callers persisting encoded values can store it alongside
+// them and detect format drift between releases.
+const EncodingVersion = 1
+
+// Encoded sizes in bytes.
+const (
+	FpSize             = fpByteSize
+	FrSize             = frByteSize
+	G1CompressedSize   = fpByteSize
+	G1UncompressedSize = 2 * fpByteSize
+	G2CompressedSize   = 2 * fpByteSize
+	G2UncompressedSize = 4 * fpByteSize
+	GTSize             = 12 * fpByteSize
+	GTCompressedSize   = 8 * fpByteSize
+)
+
+// Flags stored in the three most significant bits of the first byte of
+// compressed and uncompressed point encodings, in line with zcash serialization.
+const (
+	// CompressionFlag is set if the point is in compressed form.
+	CompressionFlag byte = 1 << 7
+	// InfinityFlag is set if the point is the point at infinity.
+	InfinityFlag byte = 1 << 6
+	// SignFlag is set in compressed form if y coordinate is lexicographically largest.
+	SignFlag byte = 1 << 5
+	// FlagMask covers all flag bits.
+	FlagMask = CompressionFlag | InfinityFlag | SignFlag
+)
+
+// PointFormat selects byte ordering and flag conventions of compressed point
+// encodings.
+type PointFormat int
+
+const (
+	// FormatZcash is the default format. Coordinates are big endian, fp2
+	// elements are ordered as c1 || c0 and flags are stored in the three most
+	// significant bits of the first byte.
+	FormatZcash PointFormat = iota
+	// FormatMCL is the native serialization of mcl and herumi bls libraries,
+	// used when Ethereum mode is not enabled. Coordinates are little endian,
+	// fp2 elements are ordered as c0 || c1, the most significant bit of the
+	// last byte is set if y is odd and the point at infinity is all zeros.
+	// Parity of an fp2 element is parity of c0.
+	FormatMCL
+)
+
+// Fp2Order selects order of coefficients in big endian encodings of fp2
+// elements.
+type Fp2Order int
+
+const (
+	// Fp2C1C0 is the default order c1 || c0, used by zcash serialization.
+	Fp2C1C0 Fp2Order = iota
+	// Fp2C0C1 is the order c0 || c1, used by Ethereum precompiles of EIP-2537,
+	// whose 64 byte zero padded coefficients are not applied here.
+	Fp2C0C1
+)
+
+// swapFp2Order converts concatenated fp2 encodings between the two orders in
+// place.
+func swapFp2Order(in []byte) {
+	var t [fpByteSize]byte
+	for i := 0; i+2*fpByteSize <= len(in); i += 2 * fpByteSize {
+		copy(t[:], in[i:i+fpByteSize])
+		copy(in[i:i+fpByteSize], in[i+fpByteSize:i+2*fpByteSize])
+		copy(in[i+fpByteSize:i+2*fpByteSize], t[:])
+	}
+}
+
+// mclOddFlag is set in the last byte of mcl encoding if y is odd.
+const mclOddFlag byte = 1 << 7
+
+// Errors returned by decoders. They are allocated once so that failing to
+// decode an input does not allocate either.
+var (
+	ErrInvalidLength    = errors.New("invalid input length")
+	ErrCompressionFlag  = errors.New("unexpected compression flag")
+	ErrSortFlag         = errors.New("sort flag must be zero")
+	ErrInfinityEncoding = errors.New("input string must be zero when infinity flag is set")
+	ErrNonCanonical     = errors.New("must be less than modulus")
+	ErrNotOnCurve       = errors.New("point is not on curve")
+	ErrNotInSubgroup    = errors.New("point is not on correct subgroup")
+	ErrUnknownFormat    = errors.New("unknown point format")
+	ErrUnknownOrder     = errors.New("unknown fp2 coefficient order")
+)
+
+// Errors of decoders that predate the Into variants, which keep their
+// original messages for callers matching on error text.
+var (
+	errLength48           = errors.New("input string length must be equal to 48 bytes")
+	errLength96           = errors.New("input string length must be equal to 96 bytes")
+	errLength192          = errors.New("input string length must be equal to 192 bytes")
+	errCompressionFlagSet = errors.New("compression flag must be set")
+	errCompressionFlagOff = errors.New("compression flag must be zero")
+)
+
+// legacyError maps length and compression flag errors of an Into decoder to
+// the original errors of its allocating counterpart.
+func legacyError(err, length, flag error) error {
+	switch err {
+	case ErrInvalidLength:
+		return length
+	case ErrCompressionFlag:
+		return flag
+	}
+	return err
+}
+
+// BatchError is returned by batch validations and reports indices of all
+// invalid elements in ascending order.
+type BatchError struct {
+	Err     error
+	Indices []int
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Indices) == 0 {
+		return fmt.Sprintf("%v", e.Err)
+	}
+	return fmt.Sprintf("%v: index %d, %d invalid elements in total", e.Err, e.Indices[0], len(e.Indices))
+}
+
+// Unwrap returns the reason of the failure.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// First returns index of the first invalid element, or -1 if no index is
+// reported.
+func (e *BatchError) First() int {
+	if len(e.Indices) == 0 {
+		return -1
+	}
+	return e.Indices[0]
+}
+
+// IsCompressedEncoding returns true if the compression flag of an encoded
+// point is set. Input is not decoded or validated.
+func IsCompressedEncoding(in []byte) bool {
+	return len(in) > 0 && in[0]&CompressionFlag != 0
+}
+
+// IsInfinityEncoding returns true if the infinity flag of an encoded point is
+// set. Input is not decoded or validated.
+func IsInfinityEncoding(in []byte) bool {
+	return len(in) > 0 && in[0]&InfinityFlag != 0
+}
+
+// EncodedSignBit returns the sign flag of an encoded point, which is set in
+// compressed form if y coordinate is the lexicographically largest root.
+// Input is not decoded or validated.
+func EncodedSignBit(in []byte) bool {
+	return len(in) > 0 && in[0]&SignFlag != 0
+}
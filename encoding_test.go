@@ -0,0 +1,632 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEncodingSizes(t *testing.T) {
+	g1, g2, gt := NewG1(), NewG2(), NewGT()
+	p1, p2 := g1.randCorrect(), g2.randCorrect()
+	for _, c := range []struct {
+		name     string
+		size     int
+		expected int
+	}{
+		{"fp", FpSize, len(toBytes(new(Fp).one()))},
+		{"fr", FrSize, len(new(Fr).One().ToBytes())},
+		{"g1 compressed", G1CompressedSize, len(g1.ToCompressed(p1))},
+		{"g1 uncompressed", G1UncompressedSize, len(g1.ToUncompressed(p1))},
+		{"g2 compressed", G2CompressedSize, len(g2.ToCompressed(p2))},
+		{"g2 uncompressed", G2UncompressedSize, len(g2.ToUncompressed(p2))},
+		{"gt", GTSize, len(gt.ToBytes(new(E).One()))},
+		{"gt compressed", GTCompressedSize, len(gt.ToCompressedBytes(new(E).One()))},
+		{"g1 raw", G1RawSize, len(g1.ToRawBytes(p1))},
+		{"g2 raw", G2RawSize, len(g2.ToRawBytes(p2))},
+	} {
+		if c.size != c.expected {
+			t.Fatalf("bad %s size, have %d want %d", c.name, c.size, c.expected)
+		}
+	}
+	if g1.ToCompressed(g1.Zero())[0] != CompressionFlag|InfinityFlag {
+		t.Fatal("bad flags for compressed infinity")
+	}
+	if g2.ToUncompressed(g2.Zero())[0] != InfinityFlag {
+		t.Fatal("bad flags for uncompressed infinity")
+	}
+}
+
+func TestEncodingFlags(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	for i := 0; i < fuz; i++ {
+		p1, p2 := g1.randCorrect(), g2.randCorrect()
+		for _, in := range [][]byte{g1.ToCompressed(p1), g2.ToCompressed(p2)} {
+			if !IsCompressedEncoding(in) || IsInfinityEncoding(in) {
+				t.Fatal("bad flags for compressed point")
+			}
+		}
+		for _, in := range [][]byte{g1.ToUncompressed(p1), g2.ToUncompressed(p2)} {
+			if IsCompressedEncoding(in) || IsInfinityEncoding(in) || EncodedSignBit(in) {
+				t.Fatal("bad flags for uncompressed point")
+			}
+		}
+		// sign bit must flip with negation
+		in := g1.ToCompressed(p1)
+		if EncodedSignBit(in) == EncodedSignBit(g1.ToCompressed(g1.Neg(p1, p1))) {
+			t.Fatal("sign flag must differ for negated point")
+		}
+		in = g2.ToCompressed(p2)
+		if EncodedSignBit(in) == EncodedSignBit(g2.ToCompressed(g2.Neg(p2, p2))) {
+			t.Fatal("sign flag must differ for negated point")
+		}
+	}
+	for _, in := range [][]byte{g1.ToCompressed(g1.Zero()), g2.ToCompressed(g2.Zero())} {
+		if !IsCompressedEncoding(in) || !IsInfinityEncoding(in) || EncodedSignBit(in) {
+			t.Fatal("bad flags for compressed infinity")
+		}
+	}
+	for _, in := range [][]byte{g1.ToUncompressed(g1.Zero()), g2.ToUncompressed(g2.Zero())} {
+		if IsCompressedEncoding(in) || !IsInfinityEncoding(in) {
+			t.Fatal("bad flags for uncompressed infinity")
+		}
+	}
+	if IsCompressedEncoding(nil) || IsInfinityEncoding(nil) || EncodedSignBit(nil) {
+		t.Fatal("empty input must have no flags")
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	p1, p2 := g1.randCorrect(), g2.randCorrect()
+	r1, r2 := new(PointG1), new(PointG2)
+	for _, c := range []struct {
+		name   string
+		decode func() error
+		check  func() bool
+	}{
+		{"g1 compressed", func() error { return g1.FromCompressedInto(r1, g1.ToCompressed(p1)) }, func() bool { return g1.Equal(r1, p1) }},
+		{"g1 uncompressed", func() error { return g1.FromUncompressedInto(r1, g1.ToUncompressed(p1)) }, func() bool { return g1.Equal(r1, p1) }},
+		{"g1 bytes", func() error { return g1.FromBytesInto(r1, g1.ToBytes(p1)) }, func() bool { return g1.Equal(r1, p1) }},
+		{"g1 infinity", func() error { return g1.FromCompressedInto(r1, g1.ToCompressed(g1.Zero())) }, func() bool { return g1.IsZero(r1) }},
+		{"g2 compressed", func() error { return g2.FromCompressedInto(r2, g2.ToCompressed(p2)) }, func() bool { return g2.Equal(r2, p2) }},
+		{"g2 uncompressed", func() error { return g2.FromUncompressedInto(r2, g2.ToUncompressed(p2)) }, func() bool { return g2.Equal(r2, p2) }},
+		{"g2 bytes", func() error { return g2.FromBytesInto(r2, g2.ToBytes(p2)) }, func() bool { return g2.Equal(r2, p2) }},
+		{"g2 infinity", func() error { return g2.FromCompressedInto(r2, g2.ToCompressed(g2.Zero())) }, func() bool { return g2.IsZero(r2) }},
+	} {
+		if err := c.decode(); err != nil {
+			t.Fatal(c.name, err)
+		}
+		if !c.check() {
+			t.Fatal(c.name, "decoding failed")
+		}
+	}
+
+	if err := g1.FromCompressedInto(r1, make([]byte, G1CompressedSize)); err != ErrCompressionFlag {
+		t.Fatal("expect compression flag error", err)
+	}
+	if err := g2.FromCompressedInto(r2, make([]byte, G1CompressedSize)); err != ErrInvalidLength {
+		t.Fatal("expect length error", err)
+	}
+	if err := g1.FromUncompressedInto(r1, g1.ToUncompressed(g1.rand())); err != ErrNotInSubgroup {
+		t.Fatal("expect subgroup error", err)
+	}
+	if err := g2.FromUncompressedInto(r2, g2.ToUncompressed(g2.rand())); err != ErrNotInSubgroup {
+		t.Fatal("expect subgroup error", err)
+	}
+}
+
+func TestDecodeErrorMessages(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	short := make([]byte, 10)
+	uncompressed1, uncompressed2 := g1.ToUncompressed(g1.One()), g2.ToUncompressed(g2.One())
+	uncompressed1[0] |= CompressionFlag
+	uncompressed2[0] |= CompressionFlag
+	errs := func(_ interface{}, err error) error { return err }
+	for _, c := range []struct {
+		err  error
+		want string
+	}{
+		{errs(g1.FromCompressed(short)), "input string length must be equal to 48 bytes"},
+		{errs(g1.FromUncompressed(short)), "input string length must be equal to 96 bytes"},
+		{errs(g1.FromBytes(short)), "input string length must be equal to 96 bytes"},
+		{errs(g2.FromCompressed(short)), "input string length must be equal to 96 bytes"},
+		{errs(g2.FromUncompressed(short)), "input string length must be equal to 192 bytes"},
+		{errs(g2.FromBytes(short)), "input string length must be equal to 192 bytes"},
+		{errs(g1.FromCompressed(make([]byte, G1CompressedSize))), "compression flag must be set"},
+		{errs(g2.FromCompressed(make([]byte, G2CompressedSize))), "compression flag must be set"},
+		{errs(g1.FromUncompressed(uncompressed1)), "compression flag must be zero"},
+		{errs(g2.FromUncompressed(uncompressed2)), "compression flag must be zero"},
+		{errs(g1.FromUncompressed(g1.ToUncompressed(g1.rand()))), ErrNotInSubgroup.Error()},
+	} {
+		if c.err == nil || c.err.Error() != c.want {
+			t.Fatalf("expected %q, got %v", c.want, c.err)
+		}
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	err := &BatchError{Err: ErrZeroInversion, Indices: []int{1, 3}}
+	if err.Error() != "inversion of zero: index 1, 2 invalid elements in total" || err.First() != 1 {
+		t.Fatal("bad batch error", err)
+	}
+	// caller built errors may carry no indices
+	err = &BatchError{Err: ErrZeroInversion}
+	if err.Error() != "inversion of zero" || err.First() != -1 {
+		t.Fatal("bad batch error without indices", err)
+	}
+}
+
+func TestDecodeIntoAllocations(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	p1, p2 := g1.randCorrect(), g2.randCorrect()
+	r1, r2 := new(PointG1), new(PointG2)
+	in1c, in1u := g1.ToCompressed(p1), g1.ToUncompressed(p1)
+	in2c, in2u := g2.ToCompressed(p2), g2.ToUncompressed(p2)
+	in1r, in2r := g1.ToRawBytes(p1), g2.ToRawBytes(p2)
+	invalid := make([]byte, G1CompressedSize)
+	for _, c := range []struct {
+		name   string
+		decode func()
+	}{
+		{"g1 compressed", func() { _ = g1.FromCompressedInto(r1, in1c) }},
+		{"g1 uncompressed", func() { _ = g1.FromUncompressedInto(r1, in1u) }},
+		{"g1 bytes", func() { _ = g1.FromBytesInto(r1, in1u) }},
+		{"g1 invalid", func() { _ = g1.FromCompressedInto(r1, invalid) }},
+		{"g2 compressed", func() { _ = g2.FromCompressedInto(r2, in2c) }},
+		{"g2 uncompressed", func() { _ = g2.FromUncompressedInto(r2, in2u) }},
+		{"g2 bytes", func() { _ = g2.FromBytesInto(r2, in2u) }},
+		{"g2 invalid", func() { _ = g2.FromCompressedInto(r2, invalid) }},
+		{"g1 raw", func() { _ = g1.FromRawBytesInto(r1, in1r) }},
+		{"g2 raw", func() { _ = g2.FromRawBytesInto(r2, in2r) }},
+	} {
+		if n := testing.AllocsPerRun(10, c.decode); n != 0 {
+			t.Fatalf("%s decoding allocates %v times", c.name, n)
+		}
+	}
+}
+
+func TestRawEncoding(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	// jacobian points with z not equal to one are encoded as they are
+	p1 := g1.Double(g1.New(), g1.randCorrect())
+	p2 := g2.Double(g2.New(), g2.randCorrect())
+	for _, p := range []*PointG1{p1, g1.Zero()} {
+		r := new(PointG1)
+		if err := g1.FromRawBytesInto(r, g1.ToRawBytes(p)); err != nil {
+			t.Fatal(err)
+		}
+		if *r != *p {
+			t.Fatal("bad g1 raw encoding")
+		}
+	}
+	for _, p := range []*PointG2{p2, g2.Zero()} {
+		r := new(PointG2)
+		if err := g2.FromRawBytesInto(r, g2.ToRawBytes(p)); err != nil {
+			t.Fatal(err)
+		}
+		if *r != *p {
+			t.Fatal("bad g2 raw encoding")
+		}
+	}
+	if err := g1.FromRawBytesInto(new(PointG1), make([]byte, G1RawSize-1)); err != ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+	if err := g2.FromRawBytesInto(new(PointG2), make([]byte, G1RawSize)); err != ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+}
+
+func TestFp2Order(t *testing.T) {
+	tw, g2 := NewTower(), NewG2()
+	a, _ := tw.Fp2Rand(rand.Reader)
+	b, err := tw.Fp2ToBytesOrder(a, Fp2C0C1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:fpByteSize], a[0].Bytes()) || !bytes.Equal(b[fpByteSize:], a[1].Bytes()) {
+		t.Fatal("bad c0 || c1 layout")
+	}
+	for _, order := range []Fp2Order{Fp2C1C0, Fp2C0C1} {
+		b, _ := tw.Fp2ToBytesOrder(a, order)
+		c, err := tw.Fp2FromBytesOrder(b, order)
+		if err != nil || !c.Equal(a) {
+			t.Fatal("bad fp2 round trip", order)
+		}
+		for _, p := range []*PointG2{g2.randCorrect(), g2.Zero()} {
+			b, _ := g2.ToBytesOrder(p, order)
+			q, err := g2.FromBytesOrder(b, order)
+			if err != nil || !g2.Equal(p, q) {
+				t.Fatal("bad g2 round trip", order)
+			}
+		}
+	}
+	p := g2.randCorrect()
+	b, _ = g2.ToBytesOrder(p, Fp2C0C1)
+	if !bytes.Equal(b[:fpByteSize], p[0][0].Bytes()) || !bytes.Equal(b[3*fpByteSize:], p[1][1].Bytes()) {
+		t.Fatal("bad g2 c0 || c1 layout")
+	}
+	if _, err := tw.Fp2FromBytesOrder(b[:2*fpByteSize], Fp2Order(2)); err != ErrUnknownOrder {
+		t.Fatal("unknown order must be rejected")
+	}
+	if _, err := g2.FromBytesOrder(b[1:], Fp2C0C1); err != ErrInvalidLength {
+		t.Fatal("input of wrong length must be rejected")
+	}
+}
+
+func TestMCLFormat(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	for i := 0; i < fuz; i++ {
+		p1 := g1.randCorrect()
+		b, err := g1.ToCompressedFormat(p1, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// x in little endian and parity of y
+		x := append([]byte{}, b...)
+		x[G1CompressedSize-1] &^= mclOddFlag
+		reverseBytes(x)
+		if !bytes.Equal(x, toBytes(&p1[0])) {
+			t.Fatal("bad g1 mcl layout")
+		}
+		if (b[G1CompressedSize-1]&mclOddFlag != 0) != (p1[1].Sgn0() == 1) {
+			t.Fatal("bad g1 mcl parity flag")
+		}
+		q1, err := g1.FromCompressedFormat(b, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(p1, q1) {
+			t.Fatal("bad g1 mcl round trip")
+		}
+		b[G1CompressedSize-1] ^= mclOddFlag
+		q1, err = g1.FromCompressedFormat(b, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(g1.Neg(q1, q1), p1) {
+			t.Fatal("parity flag must select the other root")
+		}
+
+		p2 := g2.randCorrect()
+		b, err = g2.ToCompressedFormat(p2, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x = append([]byte{}, b...)
+		x[G2CompressedSize-1] &^= mclOddFlag
+		reverseBytes(x[:fpByteSize])
+		reverseBytes(x[fpByteSize:])
+		if !bytes.Equal(x[:fpByteSize], toBytes(&p2[0][0])) || !bytes.Equal(x[fpByteSize:], toBytes(&p2[0][1])) {
+			t.Fatal("bad g2 mcl layout")
+		}
+		if (b[G2CompressedSize-1]&mclOddFlag != 0) != (p2[1][0].Sgn0() == 1) {
+			t.Fatal("bad g2 mcl parity flag")
+		}
+		q2, err := g2.FromCompressedFormat(b, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(p2, q2) {
+			t.Fatal("bad g2 mcl round trip")
+		}
+	}
+
+	b, _ := g1.ToCompressedFormat(g1.Zero(), FormatMCL)
+	if !isAllZero(b) {
+		t.Fatal("g1 infinity must be all zeros")
+	}
+	if p, err := g1.FromCompressedFormat(b, FormatMCL); err != nil || !g1.IsZero(p) {
+		t.Fatal("bad g1 infinity decoding")
+	}
+	b, _ = g2.ToCompressedFormat(g2.Zero(), FormatMCL)
+	if !isAllZero(b) {
+		t.Fatal("g2 infinity must be all zeros")
+	}
+	if p, err := g2.FromCompressedFormat(b, FormatMCL); err != nil || !g2.IsZero(p) {
+		t.Fatal("bad g2 infinity decoding")
+	}
+
+	// x = modulus is not canonical
+	x := modulus.bytes()
+	reverseBytes(x)
+	if _, err := g1.FromCompressedFormat(x, FormatMCL); err != ErrNonCanonical {
+		t.Fatal("non canonical x must be rejected", err)
+	}
+	if _, err := g1.FromCompressedFormat(x[1:], FormatMCL); err != ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+	if _, err := g1.ToCompressedFormat(g1.One(), PointFormat(-1)); err != ErrUnknownFormat {
+		t.Fatal("unknown format must be rejected")
+	}
+	if _, err := g2.FromCompressedFormat(make([]byte, G2CompressedSize), PointFormat(-1)); err != ErrUnknownFormat {
+		t.Fatal("unknown format must be rejected")
+	}
+	// zcash format is the default
+	p := g1.randCorrect()
+	if b, _ := g1.ToCompressedFormat(p, FormatZcash); !bytes.Equal(b, g1.ToCompressed(p)) {
+		t.Fatal("zcash format must match default encoding")
+	}
+}
+
+func TestMCLLayoutGenerators(t *testing.T) {
+	// Generators and their doubles laid out as read from mcl sources: little
+	// endian x, c0 || c1 for fp2 and top bit of the last byte set if y, or
+	// c0 of y, is odd. Expected bytes are computed from published generator
+	// coordinates with big integers rather than taken from an mcl build, so
+	// they pin the layout but do not prove interoperability.
+	g1, g2 := NewG1(), NewG2()
+	for i, v := range []struct {
+		p        *PointG1
+		expected []byte
+	}{
+		{g1.One(), fromHex(-1, "bbc622db0af03afbef1a7af93fe8556c58ac1b173f3a4ea105b974974f8c68c30faca94f8c63952694d79731a7d3f197")},
+		{g1.Double(g1.New(), g1.One()), fromHex(-1, "4e0fbf29558c9ac3427c1c8fbb758fe22aa658c30a2d90432501289130db21970c45a950ebc8088846674d90eacb7205")},
+	} {
+		b, err := g1.ToCompressedFormat(v.p, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, v.expected) {
+			t.Fatal("bad g1 mcl encoding", i)
+		}
+		p, err := g1.FromCompressedFormat(v.expected, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(p, v.p) {
+			t.Fatal("bad g1 mcl decoding", i)
+		}
+	}
+	for i, v := range []struct {
+		p        *PointG2
+		expected []byte
+	}{
+		{g2.One(), fromHex(-1, "b8bd21c1c85680d4efbb05a82603ac0b77d1e37a640b51b4023b40fad47ae4c65110c52d27050826910a8ff0b2a24a02", "7e2b045d057dace5575d941312f14c3349507fdcbb61dab51ab62099d0d06b59654f2788a0d3ac7d609f7152602be093")},
+		{g2.Double(g2.New(), g2.One()), fromHex(-1, "53a027b8caaa52c9781b61f30b4bf181aedb004d1e1eeae10e5e82b895b9c03b86d57ecc170f37d2a940d55739533816", "7735c3478c2878612ac77eb5f686c8c672151e03d11481727410ba04a96206d74f120a73470e529f727fedc1f9de4e8a")},
+	} {
+		b, err := g2.ToCompressedFormat(v.p, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, v.expected) {
+			t.Fatal("bad g2 mcl encoding", i)
+		}
+		p, err := g2.FromCompressedFormat(v.expected, FormatMCL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(p, v.p) {
+			t.Fatal("bad g2 mcl decoding", i)
+		}
+	}
+}
+
+func TestNonCanonicalCoordinates(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	p := modulus.bytes()
+	if _, err := NewFp().SetBytesCanonical(p); err != ErrNonCanonical {
+		t.Fatal("modulus must be rejected")
+	}
+	if _, err := NewFp().SetBytesCanonical(p[1:]); err != ErrInvalidLength {
+		t.Fatal("short input must be rejected")
+	}
+	// p - 1 is the largest canonical value
+	pMinus1 := new(Fp).set(&modulus)
+	pMinus1[0]--
+	if _, err := NewFp().SetBytesCanonical(pMinus1.bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	one1, one2 := g1.ToUncompressed(g1.One()), g2.ToUncompressed(g2.One())
+	for i, c := range []struct {
+		in     []byte
+		decode func([]byte) error
+	}{
+		// x coordinate of g1 replaced by p, compressed flag set
+		{append([]byte{p[0] | CompressionFlag}, p[1:]...), func(in []byte) error { _, err := g1.FromCompressed(in); return err }},
+		// x and y coordinates of g1 replaced by p
+		{append(append([]byte{}, p...), one1[fpByteSize:]...), func(in []byte) error { _, err := g1.FromUncompressed(in); return err }},
+		{append(append([]byte{}, one1[:fpByteSize]...), p...), func(in []byte) error { _, err := g1.FromUncompressed(in); return err }},
+		// both fp2 components of x coordinate of g2
+		{append(append([]byte{p[0] | CompressionFlag}, p[1:]...), make([]byte, fpByteSize)...), func(in []byte) error { _, err := g2.FromCompressed(in); return err }},
+		{append(append([]byte{CompressionFlag}, make([]byte, fpByteSize-1)...), p...), func(in []byte) error { _, err := g2.FromCompressed(in); return err }},
+		{append(append(append([]byte{}, one2[:fpByteSize]...), p...), one2[2*fpByteSize:]...), func(in []byte) error { _, err := g2.FromUncompressed(in); return err }},
+	} {
+		if err := c.decode(c.in); err != ErrNonCanonical {
+			t.Fatal("non canonical coordinate must be rejected", i, err)
+		}
+	}
+}
+
+func TestEncodingRegistry(t *testing.T) {
+	for _, name := range []string{EncodingZcash, EncodingMCL} {
+		if _, err := LookupEncoding(name); err != nil {
+			t.Fatal("built in encoding must be registered", name)
+		}
+	}
+	if _, err := LookupEncoding("unknown"); err != ErrUnknownEncoding {
+		t.Fatal("unknown encoding must be rejected")
+	}
+	base, _ := LookupEncoding(EncodingZcash)
+	enc := WrapEncoding("hex-test", base,
+		func(in []byte) ([]byte, error) { return []byte(hex.EncodeToString(in)), nil },
+		func(in []byte) ([]byte, error) { return hex.DecodeString(string(in)) })
+	if err := RegisterEncoding(enc); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterEncoding(enc); err != ErrDuplicateEncoding {
+		t.Fatal("duplicate encoding must be rejected")
+	}
+	if _, err := LookupEncoding("hex-test"); err != nil {
+		t.Fatal(err)
+	}
+	g1, g2 := NewG1(), NewG2()
+	p1, p2 := g1.randCorrect(), g2.randCorrect()
+	s, _ := NewFr().Rand(rand.Reader)
+	for _, name := range []string{EncodingZcash, EncodingMCL, "hex-test"} {
+		e, _ := LookupEncoding(name)
+		b1, err := e.EncodeG1(p1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q1, err := e.DecodeG1(b1); err != nil || !g1.Equal(q1, p1) {
+			t.Fatal("bad g1 round trip", name)
+		}
+		b2, err := e.EncodeG2(p2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q2, err := e.DecodeG2(b2); err != nil || !g2.Equal(q2, p2) {
+			t.Fatal("bad g2 round trip", name)
+		}
+		bs, err := e.EncodeFr(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s2, err := e.DecodeFr(bs); err != nil || !s2.Equal(s) {
+			t.Fatal("bad scalar round trip", name)
+		}
+	}
+	// wrapped decoders reuse validation of the base encoding
+	notInSubgroup := g1.ToCompressed(g1.rand())
+	if _, err := enc.DecodeG1([]byte(hex.EncodeToString(notInSubgroup))); err != ErrNotInSubgroup {
+		t.Fatal("point out of subgroup must be rejected", err)
+	}
+	if _, err := enc.DecodeFr([]byte(hex.EncodeToString(qBig.Bytes()))); err != ErrNonCanonical {
+		t.Fatal("non canonical scalar must be rejected")
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	fpOne := "0x" + hex.EncodeToString(new(Fp).one().Bytes())
+	for _, c := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%v", new(Fp).one(), fpOne},
+		{"%s", new(Fp).one(), fpOne},
+		{"%x", new(Fp).one(), fpOne[2:]},
+		{"%#x", new(Fp).one(), fpOne},
+		{"%d", new(Fp).one(), "1"},
+		{"%v", new(Fp2).one(), "0x" + hex.EncodeToString(NewTower().Fp2ToBytes(new(Fp2).one()))},
+		{"%v", new(Fp6).one(), "0x" + hex.EncodeToString(NewTower().Fp6ToBytes(new(Fp6).one()))},
+		{"%v", new(Fp12).one(), "0x" + hex.EncodeToString(NewGT().ToBytes(new(Fp12).one()))},
+		{"%v", NewG1().One(), "0x" + hex.EncodeToString(NewG1().ToCompressed(NewG1().One()))},
+		{"%X", NewG2().Zero(), "C" + strings.Repeat("0", 2*G2CompressedSize-1)},
+		{"%q", NewG1().One(), "%!q(" + NewG1().One().String() + ")"},
+	} {
+		if have := fmt.Sprintf(c.format, c.arg); have != c.want {
+			t.Fatal("bad formatting", c.format, have, c.want)
+		}
+	}
+	g := NewG1()
+	p := g.Double(g.New(), g.One())
+	p0 := *p
+	_ = p.String()
+	if p0 != *p {
+		t.Fatal("formatting must not modify the point")
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	type payload struct {
+		A  *Fp      `json:"a"`
+		B  *Fp2     `json:"b"`
+		C  *Fp6     `json:"c"`
+		D  *Fp12    `json:"d"`
+		S  *Fr      `json:"s"`
+		P  *PointG1 `json:"p"`
+		Q  *PointG2 `json:"q"`
+		P0 PointG1  `json:"p0"`
+	}
+	tw, g1, g2 := NewTower(), NewG1(), NewG2()
+	a, _ := new(Fp).rand(rand.Reader)
+	b, _ := tw.Fp2Rand(rand.Reader)
+	c, _ := tw.Fp6Rand(rand.Reader)
+	d, _ := tw.Fp12Rand(rand.Reader)
+	s, _ := new(Fr).Rand(rand.Reader)
+	p, q := g1.randCorrect(), g2.randCorrect()
+	in := &payload{a, b, c, d, s, p, q, *g1.Zero()}
+	enc, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(payload)
+	if err := json.Unmarshal(enc, out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.A.Equal(a) || !out.B.Equal(b) || !out.C.Equal(c) || !out.D.Equal(d) || !out.S.Equal(s) {
+		t.Fatal("bad field element round trip")
+	}
+	if !g1.Equal(out.P, p) || !g2.Equal(out.Q, q) || !g1.IsZero(&out.P0) {
+		t.Fatal("bad point round trip")
+	}
+	if enc, _ := json.Marshal(new(Fp).one()); string(enc) != `"`+new(Fp).one().String()+`"` {
+		t.Fatal("json encoding must match string form", string(enc))
+	}
+	// prefix is optional
+	x := new(Fp)
+	if err := json.Unmarshal([]byte(`"`+new(Fp).one().String()[2:]+`"`), x); err != nil || !x.isOne() {
+		t.Fatal("hex string without prefix must be accepted")
+	}
+	for _, c := range []struct {
+		in  string
+		err error
+	}{
+		{`"0x` + hex.EncodeToString(modulus.bytes()) + `"`, ErrNonCanonical},
+		{`"0x00"`, ErrInvalidLength},
+		{`"0xzz"`, errJSONString},
+		{`1`, errJSONString},
+	} {
+		if err := json.Unmarshal([]byte(c.in), new(Fp)); err != c.err {
+			t.Fatal("invalid input must be rejected", c.in, err)
+		}
+	}
+	notInSubgroup := `"0x` + hex.EncodeToString(g1.ToCompressed(g1.rand())) + `"`
+	if err := json.Unmarshal([]byte(notInSubgroup), new(PointG1)); err != ErrNotInSubgroup {
+		t.Fatal("point out of subgroup must be rejected", err)
+	}
+	if err := json.Unmarshal([]byte(`"0x`+hex.EncodeToString(qBig.Bytes())+`"`), new(Fr)); err != ErrNonCanonical {
+		t.Fatal("non canonical scalar must be rejected", err)
+	}
+}
+
+func TestRawLimbs(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp).Rand(rand.Reader)
+		b, err := new(Fp).SetRawLimbs(a.RawLimbs())
+		if err != nil || !a.Equal(b) {
+			t.Fatal("raw limbs must round trip")
+		}
+	}
+	// raw limbs are in montgomery form
+	if new(Fp).One().RawLimbs() != [fpNumberOfLimbs]uint64(*r1) {
+		t.Fatal("raw limbs of one must be r1")
+	}
+	if _, err := new(Fp).SetRawLimbs(modulus); err != ErrNonCanonical {
+		t.Fatal("non canonical limbs must be rejected", err)
+	}
+	g1, g2 := NewG1(), NewG2()
+	// jacobian points with z != 1
+	p1 := g1.Double(g1.New(), g1.randCorrect())
+	q1, err := new(PointG1).SetRawLimbs(p1.RawLimbs())
+	if err != nil || !g1.Equal(p1, q1) || q1[2] != p1[2] {
+		t.Fatal("raw limbs of g1 point must round trip")
+	}
+	p2 := g2.Double(g2.New(), g2.randCorrect())
+	q2, err := new(PointG2).SetRawLimbs(p2.RawLimbs())
+	if err != nil || !g2.Equal(p2, q2) || q2[2] != p2[2] {
+		t.Fatal("raw limbs of g2 point must round trip")
+	}
+	limbs := p2.RawLimbs()
+	limbs[1][1] = modulus
+	if _, err := q2.SetRawLimbs(limbs); err != ErrNonCanonical || !g2.Equal(p2, q2) {
+		t.Fatal("non canonical coordinate must be rejected", err)
+	}
+}
@@ -0,0 +1,120 @@
+package eth
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/kilic/bls12-381/internal/ssz"
+	"github.com/kilic/bls12-381/sig"
+)
+
+// Withdrawal credential prefixes.
+const (
+	BLSWithdrawalPrefix       byte = 0x00
+	ExecutionWithdrawalPrefix byte = 0x01
+)
+
+// DepositMessage is the signed part of a validator deposit.
+type DepositMessage struct {
+	PublicKey             [sig.PublicKeySize]byte
+	WithdrawalCredentials [32]byte
+	// Amount is in Gwei.
+	Amount uint64
+}
+
+// DepositData is a validator deposit as submitted to the deposit contract.
+type DepositData struct {
+	PublicKey             [sig.PublicKeySize]byte
+	WithdrawalCredentials [32]byte
+	// Amount is in Gwei.
+	Amount    uint64
+	Signature [sig.SignatureSize]byte
+}
+
+// BLSWithdrawalCredentials returns withdrawal credentials committing to a BLS
+// withdrawal public key.
+func BLSWithdrawalCredentials(pk *sig.PublicKey) [32]byte {
+	wc := sha256.Sum256(pk.Bytes())
+	wc[0] = BLSWithdrawalPrefix
+	return wc
+}
+
+// ExecutionWithdrawalCredentials returns withdrawal credentials committing to
+// an execution layer address.
+func ExecutionWithdrawalCredentials(address [20]byte) [32]byte {
+	var wc [32]byte
+	wc[0] = ExecutionWithdrawalPrefix
+	copy(wc[12:], address[:])
+	return wc
+}
+
+// HashTreeRoot returns SSZ hash tree root of the deposit message.
+func (m *DepositMessage) HashTreeRoot() ([32]byte, error) {
+	return containerRoot(ssz.Merkleize(m.PublicKey[:]), m.WithdrawalCredentials, uint64Root(m.Amount)), nil
+}
+
+// HashTreeRoot returns SSZ hash tree root of the deposit data, which is the
+// deposit data root argument of the deposit contract.
+func (d *DepositData) HashTreeRoot() ([32]byte, error) {
+	return containerRoot(ssz.Merkleize(d.PublicKey[:]), d.WithdrawalCredentials, uint64Root(d.Amount), ssz.Merkleize(d.Signature[:])), nil
+}
+
+// Message returns the signed part of the deposit.
+func (d *DepositData) Message() *DepositMessage {
+	return &DepositMessage{d.PublicKey, d.WithdrawalCredentials, d.Amount}
+}
+
+// DepositSigningRoot returns the message signed for a deposit. Deposits are
+// valid across forks, so domain is computed from genesis fork version of the
+// network and an empty genesis validators root.
+func DepositSigningRoot(m *DepositMessage, genesisForkVersion Version) Root {
+	root, _ := m.HashTreeRoot()
+	return ComputeSigningRoot(root, ComputeDomain(DomainDeposit, genesisForkVersion, Root{}))
+}
+
+// SignDeposit signs the deposit message with the validator key, public key of
+// the message is set from the secret key.
+func SignDeposit(sk *sig.SecretKey, withdrawalCredentials [32]byte, amount uint64, genesisForkVersion Version) (*DepositData, error) {
+	m := &DepositMessage{WithdrawalCredentials: withdrawalCredentials, Amount: amount}
+	copy(m.PublicKey[:], sk.PublicKey().Bytes())
+	root := DepositSigningRoot(m, genesisForkVersion)
+	s, err := sk.Sign(root[:])
+	if err != nil {
+		return nil, err
+	}
+	d := &DepositData{PublicKey: m.PublicKey, WithdrawalCredentials: m.WithdrawalCredentials, Amount: m.Amount}
+	copy(d.Signature[:], s.Bytes())
+	return d, nil
+}
+
+// VerifyDeposit returns true if the deposit is signed by its public key.
+// Deposits with undecodable keys or signatures are invalid.
+func VerifyDeposit(d *DepositData, genesisForkVersion Version) bool {
+	pk, err := sig.PublicKeyFromBytes(d.PublicKey[:])
+	if err != nil {
+		return false
+	}
+	s, err := sig.SignatureFromBytes(d.Signature[:])
+	if err != nil {
+		return false
+	}
+	root := DepositSigningRoot(d.Message(), genesisForkVersion)
+	return s.Verify(pk, root[:])
+}
+
+// containerRoot returns hash tree root of a container from roots of its
+// fields.
+func containerRoot(fields ...[32]byte) [32]byte {
+	chunks := make([]byte, 0, len(fields)*32)
+	for i := range fields {
+		chunks = append(chunks, fields[i][:]...)
+	}
+	return ssz.Merkleize(chunks)
+}
+
+// uint64Root returns hash tree root of an uint64.
+func uint64Root(v uint64) [32]byte {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:], v)
+	return chunk
+}
@@ -0,0 +1,112 @@
+package eth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/kilic/bls12-381/sig"
+)
+
+// Deposit of 32 ETH on mainnet by interop validator 0 with BLS withdrawal
+// credentials of the same key. Roots are computed with a separate SSZ
+// implementation and signature with gnark-crypto. Deposit domain is the one
+// used by the mainnet deposit tooling.
+func TestDepositVector(t *testing.T) {
+	sk, err := sig.SecretKeyFromBytes(fromHex(t, "25295f0d1d592a90b333e26e85149708208e9f8e8bc18f6c77bd62f8ad7a6866"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain := ComputeDomain(DomainDeposit, Version{}, Root{})
+	if !bytes.Equal(domain[:], fromHex(t, "03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9")) {
+		t.Fatal("bad deposit domain")
+	}
+	wc := BLSWithdrawalCredentials(sk.PublicKey())
+	if !bytes.Equal(wc[:], fromHex(t, "00fad2a6bfb0e7f1f0f45460944fbd8dfa7f37da06a4d13b3983cc90bb46963b")) {
+		t.Fatal("bad withdrawal credentials")
+	}
+	d, err := SignDeposit(sk, wc, 32000000000, Version{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.PublicKey[:], fromHex(t, "a99a76ed7796f7be22d5b7e85deeb7c5677e88e511e0b337618f8c4eb61349b4bf2d153f649f7b53359fe8b94a38e44c")) {
+		t.Fatal("bad public key")
+	}
+	root, _ := d.Message().HashTreeRoot()
+	if !bytes.Equal(root[:], fromHex(t, "139b510ea7f2788ab82da1f427d6cbe1db147c15a053db738ad5500cd83754a6")) {
+		t.Fatal("bad deposit message root")
+	}
+	signingRoot := DepositSigningRoot(d.Message(), Version{})
+	if !bytes.Equal(signingRoot[:], fromHex(t, "ddd8883f4b4ef567801cf85501c1015d7bb8f64bf151b8c187876e147dbef4bb")) {
+		t.Fatal("bad deposit signing root")
+	}
+	if !bytes.Equal(d.Signature[:], fromHex(t, "a9ac65fdd32e9ea916127b5c307a4abde9bde12e751f372c5f0aa84f62f09eba673b25949673c5c5d01527ecff90205e02389d709a74715b5f3f30d3defd0fc559e9480eae522463d7c9e6b77649132ba1fa3b4b33f7b1f471d22829df9f9416")) {
+		t.Fatal("bad deposit signature")
+	}
+	root, _ = d.HashTreeRoot()
+	if !bytes.Equal(root[:], fromHex(t, "97f892cc0b7e6ac39e28c650ea91c06c32ffcf6a37f9fffd30998d1faf7767d3")) {
+		t.Fatal("bad deposit data root")
+	}
+}
+
+func TestWithdrawalCredentials(t *testing.T) {
+	sk, err := sig.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := BLSWithdrawalCredentials(sk.PublicKey())
+	h := sha256.Sum256(sk.PublicKey().Bytes())
+	if wc[0] != BLSWithdrawalPrefix || !bytes.Equal(wc[1:], h[1:]) {
+		t.Fatal("bad bls withdrawal credentials")
+	}
+	var address [20]byte
+	for i := range address {
+		address[i] = byte(i + 1)
+	}
+	wc = ExecutionWithdrawalCredentials(address)
+	if wc[0] != ExecutionWithdrawalPrefix || !bytes.Equal(wc[1:12], make([]byte, 11)) || !bytes.Equal(wc[12:], address[:]) {
+		t.Fatal("bad execution withdrawal credentials")
+	}
+}
+
+func TestSignVerifyDeposit(t *testing.T) {
+	sk, err := sig.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := BLSWithdrawalCredentials(sk.PublicKey())
+	d, err := SignDeposit(sk, wc, 32000000000, Version{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.PublicKey[:], sk.PublicKey().Bytes()) {
+		t.Fatal("public key of the deposit must be the signer")
+	}
+	if !VerifyDeposit(d, Version{}) {
+		t.Fatal("deposit must verify")
+	}
+	if VerifyDeposit(d, Version{0x01, 0x01, 0x70, 0x00}) {
+		t.Fatal("deposit must not verify on another network")
+	}
+	tampered := *d
+	tampered.Amount++
+	if VerifyDeposit(&tampered, Version{}) {
+		t.Fatal("tampered amount must not verify")
+	}
+	tampered = *d
+	tampered.WithdrawalCredentials[31] ^= 1
+	if VerifyDeposit(&tampered, Version{}) {
+		t.Fatal("tampered credentials must not verify")
+	}
+	tampered = *d
+	tampered.Signature[10] ^= 1
+	if VerifyDeposit(&tampered, Version{}) {
+		t.Fatal("corrupt signature must not verify")
+	}
+	tampered = *d
+	tampered.PublicKey = [sig.PublicKeySize]byte{}
+	if VerifyDeposit(&tampered, Version{}) {
+		t.Fatal("invalid public key must not verify")
+	}
+}
@@ -0,0 +1,72 @@
+// Package eth provides Ethereum consensus layer glue for BLS signatures such
+// as domain, signing root and deposit data computation.
+//
+// Domains and signing roots follow phase0 beacon chain specification at
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/phase0/beacon-chain.md
+package eth
+
+import "crypto/sha256"
+
+// Version is a fork version.
+type Version [4]byte
+
+// DomainType identifies the purpose of a signature.
+type DomainType [4]byte
+
+// Domain is a signature domain which is mixed into messages before signing.
+type Domain [32]byte
+
+// Root is an SSZ hash tree root.
+type Root [32]byte
+
+// Domain types defined by consensus layer specifications.
+var (
+	DomainBeaconProposer              = DomainType{0x00, 0x00, 0x00, 0x00}
+	DomainBeaconAttester              = DomainType{0x01, 0x00, 0x00, 0x00}
+	DomainRandao                      = DomainType{0x02, 0x00, 0x00, 0x00}
+	DomainDeposit                     = DomainType{0x03, 0x00, 0x00, 0x00}
+	DomainVoluntaryExit               = DomainType{0x04, 0x00, 0x00, 0x00}
+	DomainSelectionProof              = DomainType{0x05, 0x00, 0x00, 0x00}
+	DomainAggregateAndProof           = DomainType{0x06, 0x00, 0x00, 0x00}
+	DomainSyncCommittee               = DomainType{0x07, 0x00, 0x00, 0x00}
+	DomainSyncCommitteeSelectionProof = DomainType{0x08, 0x00, 0x00, 0x00}
+	DomainContributionAndProof        = DomainType{0x09, 0x00, 0x00, 0x00}
+	DomainBLSToExecutionChange        = DomainType{0x0a, 0x00, 0x00, 0x00}
+)
+
+// ComputeForkDataRoot returns hash tree root of ForkData container.
+func ComputeForkDataRoot(version Version, genesisValidatorsRoot Root) Root {
+	var chunks [64]byte
+	copy(chunks[:4], version[:])
+	copy(chunks[32:], genesisValidatorsRoot[:])
+	return sha256.Sum256(chunks[:])
+}
+
+// ComputeForkDigest returns the first four bytes of fork data root, which is
+// used to distinguish networks and forks in gossip topics.
+func ComputeForkDigest(version Version, genesisValidatorsRoot Root) [4]byte {
+	var digest [4]byte
+	root := ComputeForkDataRoot(version, genesisValidatorsRoot)
+	copy(digest[:], root[:4])
+	return digest
+}
+
+// ComputeDomain returns the domain for given domain type, fork version and
+// genesis validators root. Deposits and other fork agnostic messages use
+// genesis fork version and zero genesis validators root.
+func ComputeDomain(domainType DomainType, version Version, genesisValidatorsRoot Root) Domain {
+	var domain Domain
+	root := ComputeForkDataRoot(version, genesisValidatorsRoot)
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], root[:28])
+	return domain
+}
+
+// ComputeSigningRoot returns hash tree root of SigningData container, which
+// is the message that is signed for an object with given hash tree root.
+func ComputeSigningRoot(objectRoot Root, domain Domain) Root {
+	var chunks [64]byte
+	copy(chunks[:32], objectRoot[:])
+	copy(chunks[32:], domain[:])
+	return sha256.Sum256(chunks[:])
+}
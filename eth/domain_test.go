@@ -0,0 +1,59 @@
+package eth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// mainnetGenesisValidatorsRoot is genesis validators root of Ethereum mainnet.
+const mainnetGenesisValidatorsRoot = "4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95"
+
+func TestComputeForkDigest(t *testing.T) {
+	var root Root
+	copy(root[:], fromHex(t, mainnetGenesisValidatorsRoot))
+	digest := ComputeForkDigest(Version{}, root)
+	if !bytes.Equal(digest[:], fromHex(t, "b5303f2a")) {
+		t.Fatal("bad mainnet phase0 fork digest")
+	}
+}
+
+func TestComputeDomain(t *testing.T) {
+	domain := ComputeDomain(DomainDeposit, Version{}, Root{})
+	expected := fromHex(t, "03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9")
+	if !bytes.Equal(domain[:], expected) {
+		t.Fatal("bad mainnet deposit domain")
+	}
+	var root Root
+	copy(root[:], fromHex(t, mainnetGenesisValidatorsRoot))
+	domain = ComputeDomain(DomainBeaconAttester, Version{}, root)
+	expected = fromHex(t, "01000000b5303f2ad2010d699a76c8e62350947421a3e4a979779642cfdb0f66")
+	if !bytes.Equal(domain[:], expected) {
+		t.Fatal("bad mainnet phase0 attester domain")
+	}
+}
+
+func TestComputeSigningRoot(t *testing.T) {
+	var objectRoot Root
+	for i := range objectRoot {
+		objectRoot[i] = byte(i)
+	}
+	domain := ComputeDomain(DomainRandao, Version{1, 0, 0, 0}, Root{})
+	expected := sha256.Sum256(append(objectRoot[:], domain[:]...))
+	if ComputeSigningRoot(objectRoot, domain) != expected {
+		t.Fatal("bad signing root")
+	}
+	if ComputeSigningRoot(objectRoot, ComputeDomain(DomainRandao, Version{2, 0, 0, 0}, Root{})) == expected {
+		t.Fatal("signing root must depend on fork version")
+	}
+}
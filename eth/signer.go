@@ -0,0 +1,142 @@
+package eth
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/kilic/bls12-381/sig"
+)
+
+// Errors returned by slashing protection.
+var (
+	ErrSlashableBlock       = errors.New("block proposal is slashable")
+	ErrSlashableAttestation = errors.New("attestation is slashable")
+)
+
+// SlashingProtector is a slashing protection database. Check methods must
+// atomically check a message against signing history of the key and record
+// it if it is safe to sign, since Signer signs right after a check succeeds.
+// Signing the same signing root again must be allowed, BLS signatures are
+// deterministic so that it yields the same signature.
+type SlashingProtector interface {
+	CheckBlock(pubkey [sig.PublicKeySize]byte, slot uint64, signingRoot Root) error
+	CheckAttestation(pubkey [sig.PublicKeySize]byte, sourceEpoch, targetEpoch uint64, signingRoot Root) error
+}
+
+// Signer signs consensus messages with a validator key and consults a
+// slashing protection database before signing blocks and attestations.
+type Signer struct {
+	sk        *sig.SecretKey
+	pubkey    [sig.PublicKeySize]byte
+	protector SlashingProtector
+}
+
+// NewSigner returns a signer for the key protected with given database.
+func NewSigner(sk *sig.SecretKey, protector SlashingProtector) *Signer {
+	s := &Signer{sk: sk, protector: protector}
+	copy(s.pubkey[:], sk.PublicKey().Bytes())
+	return s
+}
+
+// PublicKey returns public key of the signer.
+func (s *Signer) PublicKey() *sig.PublicKey {
+	return s.sk.PublicKey()
+}
+
+// SignBlock signs a block with given hash tree root if protection database
+// accepts the proposal.
+func (s *Signer) SignBlock(slot uint64, blockRoot Root, domain Domain) (*sig.Signature, error) {
+	signingRoot := ComputeSigningRoot(blockRoot, domain)
+	if err := s.protector.CheckBlock(s.pubkey, slot, signingRoot); err != nil {
+		return nil, err
+	}
+	return s.sk.Sign(signingRoot[:])
+}
+
+// SignAttestation signs attestation data with given hash tree root if
+// protection database accepts the vote.
+func (s *Signer) SignAttestation(sourceEpoch, targetEpoch uint64, dataRoot Root, domain Domain) (*sig.Signature, error) {
+	signingRoot := ComputeSigningRoot(dataRoot, domain)
+	if err := s.protector.CheckAttestation(s.pubkey, sourceEpoch, targetEpoch, signingRoot); err != nil {
+		return nil, err
+	}
+	return s.sk.Sign(signingRoot[:])
+}
+
+// Sign signs an object that is not subject to slashing such as randao
+// reveals, exits and selection proofs.
+func (s *Signer) Sign(objectRoot Root, domain Domain) (*sig.Signature, error) {
+	signingRoot := ComputeSigningRoot(objectRoot, domain)
+	return s.sk.Sign(signingRoot[:])
+}
+
+type signingHistory struct {
+	hasBlock    bool
+	blockSlot   uint64
+	blockRoot   Root
+	hasVote     bool
+	sourceEpoch uint64
+	targetEpoch uint64
+	voteRoot    Root
+}
+
+// MemoryProtector is an in memory slashing protection database. It applies
+// minimal strategy of EIP-3076 where only the highest signed slot, source and
+// target epochs are kept. Blocks must be at a higher slot than the last one,
+// attestations must have a source not lower and a target higher than the
+// last one, which rules out double and surround votes.
+type MemoryProtector struct {
+	mu      sync.Mutex
+	history map[[sig.PublicKeySize]byte]*signingHistory
+}
+
+// NewMemoryProtector returns an empty in memory protection database.
+func NewMemoryProtector() *MemoryProtector {
+	return &MemoryProtector{history: make(map[[sig.PublicKeySize]byte]*signingHistory)}
+}
+
+func (m *MemoryProtector) get(pubkey [sig.PublicKeySize]byte) *signingHistory {
+	h, ok := m.history[pubkey]
+	if !ok {
+		h = &signingHistory{}
+		m.history[pubkey] = h
+	}
+	return h
+}
+
+// CheckBlock implements SlashingProtector.
+func (m *MemoryProtector) CheckBlock(pubkey [sig.PublicKeySize]byte, slot uint64, signingRoot Root) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.get(pubkey)
+	if h.hasBlock {
+		if slot == h.blockSlot && signingRoot == h.blockRoot {
+			return nil
+		}
+		if slot <= h.blockSlot {
+			return ErrSlashableBlock
+		}
+	}
+	h.hasBlock, h.blockSlot, h.blockRoot = true, slot, signingRoot
+	return nil
+}
+
+// CheckAttestation implements SlashingProtector.
+func (m *MemoryProtector) CheckAttestation(pubkey [sig.PublicKeySize]byte, sourceEpoch, targetEpoch uint64, signingRoot Root) error {
+	if sourceEpoch > targetEpoch {
+		return ErrSlashableAttestation
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.get(pubkey)
+	if h.hasVote {
+		if sourceEpoch == h.sourceEpoch && targetEpoch == h.targetEpoch && signingRoot == h.voteRoot {
+			return nil
+		}
+		if sourceEpoch < h.sourceEpoch || targetEpoch <= h.targetEpoch {
+			return ErrSlashableAttestation
+		}
+	}
+	h.hasVote, h.sourceEpoch, h.targetEpoch, h.voteRoot = true, sourceEpoch, targetEpoch, signingRoot
+	return nil
+}
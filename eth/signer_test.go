@@ -0,0 +1,80 @@
+package eth
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kilic/bls12-381/sig"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	sk, err := sig.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSigner(sk, NewMemoryProtector())
+}
+
+func TestSignerBlocks(t *testing.T) {
+	s := newTestSigner(t)
+	domain := ComputeDomain(DomainBeaconProposer, Version{}, Root{})
+	s0, err := s.SignBlock(10, Root{1}, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot := ComputeSigningRoot(Root{1}, domain)
+	if !s0.Verify(s.PublicKey(), signingRoot[:]) {
+		t.Fatal("block signature must verify")
+	}
+	s1, err := s.SignBlock(10, Root{1}, domain)
+	if err != nil {
+		t.Fatal("signing the same block again must be allowed", err)
+	}
+	if !s0.Equal(s1) {
+		t.Fatal("signatures must be deterministic")
+	}
+	if _, err := s.SignBlock(10, Root{2}, domain); err != ErrSlashableBlock {
+		t.Fatal("double proposal must be rejected")
+	}
+	if _, err := s.SignBlock(9, Root{3}, domain); err != ErrSlashableBlock {
+		t.Fatal("proposal below the last slot must be rejected")
+	}
+	if _, err := s.SignBlock(11, Root{2}, domain); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignerAttestations(t *testing.T) {
+	s := newTestSigner(t)
+	domain := ComputeDomain(DomainBeaconAttester, Version{}, Root{})
+	if _, err := s.SignAttestation(5, 4, Root{1}, domain); err != ErrSlashableAttestation {
+		t.Fatal("source after target must be rejected")
+	}
+	if _, err := s.SignAttestation(2, 5, Root{1}, domain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignAttestation(2, 5, Root{1}, domain); err != nil {
+		t.Fatal("signing the same attestation again must be allowed", err)
+	}
+	for _, c := range []struct {
+		source, target uint64
+		desc           string
+	}{
+		{2, 5, "double vote"},
+		{3, 4, "surrounded vote"},
+		{1, 6, "surrounding vote"},
+		{3, 5, "vote for the same target"},
+	} {
+		if _, err := s.SignAttestation(c.source, c.target, Root{2}, domain); err != ErrSlashableAttestation {
+			t.Fatalf("%s must be rejected", c.desc)
+		}
+	}
+	if _, err := s.SignAttestation(5, 6, Root{2}, domain); err != nil {
+		t.Fatal(err)
+	}
+	// history is kept per key
+	other := NewSigner(newTestSigner(t).sk, s.protector)
+	if _, err := other.SignAttestation(2, 5, Root{2}, domain); err != nil {
+		t.Fatal(err)
+	}
+}
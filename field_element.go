@@ -6,14 +6,20 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"math/bits"
 )
 
-// Fe is base field element representation
-type Fe /***			***/ [fpNumberOfLimbs]uint64
+// Fp is base field element representation
+type Fp /***			***/ [fpNumberOfLimbs]uint64
+
+// Fe is the former name of Fp and is kept so that existing importers compile.
+//
+// Deprecated: use Fp.
+type Fe = Fp
 
 // fe2 is element representation of 'fp2' which is quadratic extention of base field 'fp'
 // Representation follows c[0] + c[1] * u encoding order.
-type fe2 /**			***/ [2]Fe
+type fe2 /**			***/ [2]Fp
 
 // fe6 is element representation of 'fp6' field which is cubic extention of 'fp2'
 // Representation follows c[0] + c[1] * v + c[2] * v^2 encoding order.
@@ -23,11 +29,16 @@ type fe6 /**			***/ [3]fe2
 // Representation follows c[0] + c[1] * w encoding order.
 type fe12 /**			***/ [2]fe6
 
+// wfe, wfe2 and wfe6 hold double width unreduced products for lazy reduction.
+// Multiplications in fp2, fp6 and fp12 accumulate products in wide form and
+// reduce once per output coefficient, that is 2, 6 and 12 Montgomery
+// reductions, which is the least possible at each level. Squarings in fp12
+// reduce the two fp6 products of complex squaring, also 12 reductions.
 type wfe /***			***/ [fpNumberOfLimbs * 2]uint64
 type wfe2 /**			***/ [2]wfe
 type wfe6 /**			***/ [3]wfe2
 
-func (Fe *Fe) setBytes(in []byte) *Fe {
+func (e *Fp) setBytes(in []byte) *Fp {
 	l := len(in)
 	if l >= fpByteSize {
 		l = fpByteSize
@@ -37,19 +48,19 @@ func (Fe *Fe) setBytes(in []byte) *Fe {
 	var a int
 	for i := 0; i < fpNumberOfLimbs; i++ {
 		a = fpByteSize - i*8
-		Fe[i] = uint64(padded[a-1]) | uint64(padded[a-2])<<8 |
+		e[i] = uint64(padded[a-1]) | uint64(padded[a-2])<<8 |
 			uint64(padded[a-3])<<16 | uint64(padded[a-4])<<24 |
 			uint64(padded[a-5])<<32 | uint64(padded[a-6])<<40 |
 			uint64(padded[a-7])<<48 | uint64(padded[a-8])<<56
 	}
-	return Fe
+	return e
 }
 
-func (Fe *Fe) setBig(a *big.Int) *Fe {
-	return Fe.setBytes(a.Bytes())
+func (e *Fp) setBig(a *big.Int) *Fp {
+	return e.setBytes(a.Bytes())
 }
 
-func (Fe *Fe) setString(s string) (*Fe, error) {
+func (e *Fp) setString(s string) (*Fp, error) {
 	if s[:2] == "0x" {
 		s = s[2:]
 	}
@@ -57,120 +68,152 @@ func (Fe *Fe) setString(s string) (*Fe, error) {
 	if err != nil {
 		return nil, err
 	}
-	return Fe.setBytes(bytes), nil
+	return e.setBytes(bytes), nil
 }
 
-func (Fe *Fe) set(fe2 *Fe) *Fe {
-	Fe[0] = fe2[0]
-	Fe[1] = fe2[1]
-	Fe[2] = fe2[2]
-	Fe[3] = fe2[3]
-	Fe[4] = fe2[4]
-	Fe[5] = fe2[5]
-	return Fe
+func (e *Fp) set(fe2 *Fp) *Fp {
+	e[0] = fe2[0]
+	e[1] = fe2[1]
+	e[2] = fe2[2]
+	e[3] = fe2[3]
+	e[4] = fe2[4]
+	e[5] = fe2[5]
+	return e
 }
 
-func (Fe *Fe) bytes() []byte {
+func (e *Fp) bytes() []byte {
 	out := make([]byte, fpByteSize)
 	var a int
 	for i := 0; i < fpNumberOfLimbs; i++ {
 		a = fpByteSize - i*8
-		out[a-1] = byte(Fe[i])
-		out[a-2] = byte(Fe[i] >> 8)
-		out[a-3] = byte(Fe[i] >> 16)
-		out[a-4] = byte(Fe[i] >> 24)
-		out[a-5] = byte(Fe[i] >> 32)
-		out[a-6] = byte(Fe[i] >> 40)
-		out[a-7] = byte(Fe[i] >> 48)
-		out[a-8] = byte(Fe[i] >> 56)
+		out[a-1] = byte(e[i])
+		out[a-2] = byte(e[i] >> 8)
+		out[a-3] = byte(e[i] >> 16)
+		out[a-4] = byte(e[i] >> 24)
+		out[a-5] = byte(e[i] >> 32)
+		out[a-6] = byte(e[i] >> 40)
+		out[a-7] = byte(e[i] >> 48)
+		out[a-8] = byte(e[i] >> 56)
 	}
 	return out
 }
 
-func (Fe *Fe) big() *big.Int {
-	return new(big.Int).SetBytes(Fe.bytes())
+func (e *Fp) big() *big.Int {
+	return new(big.Int).SetBytes(e.bytes())
 }
 
-func (Fe *Fe) string() (s string) {
+func (e *Fp) string() (s string) {
 	for i := fpNumberOfLimbs - 1; i >= 0; i-- {
-		s = fmt.Sprintf("%s%16.16x", s, Fe[i])
+		s = fmt.Sprintf("%s%16.16x", s, e[i])
 	}
 	return "0x" + s
 }
 
-func (Fe *Fe) zero() *Fe {
-	Fe[0] = 0
-	Fe[1] = 0
-	Fe[2] = 0
-	Fe[3] = 0
-	Fe[4] = 0
-	Fe[5] = 0
-	return Fe
+func (e *Fp) zero() *Fp {
+	e[0] = 0
+	e[1] = 0
+	e[2] = 0
+	e[3] = 0
+	e[4] = 0
+	e[5] = 0
+	return e
 }
 
-func (Fe *Fe) one() *Fe {
-	return Fe.set(r1)
+func (e *Fp) one() *Fp {
+	return e.set(r1)
 }
 
-func (Fe *Fe) rand(r io.Reader) (*Fe, error) {
+func (e *Fp) rand(r io.Reader) (*Fp, error) {
 	bi, err := rand.Int(r, modulus.big())
 	if err != nil {
 		return nil, err
 	}
-	return Fe.setBig(bi), nil
+	return e.setBig(bi), nil
 }
 
-func (Fe *Fe) isValid() bool {
-	return Fe.cmp(&modulus) == -1
+func (e *Fp) isValid() bool {
+	return e.CmpCT(&modulus) == -1
 }
 
-func (Fe *Fe) isOdd() bool {
+func (e *Fp) isOdd() bool {
 	var mask uint64 = 1
-	return Fe[0]&mask != 0
+	return e[0]&mask != 0
 }
 
-func (Fe *Fe) isEven() bool {
+func (e *Fp) isEven() bool {
 	var mask uint64 = 1
-	return Fe[0]&mask == 0
+	return e[0]&mask == 0
 }
 
-func (Fe *Fe) isZero() bool {
-	return (Fe[5] | Fe[4] | Fe[3] | Fe[2] | Fe[1] | Fe[0]) == 0
+func (e *Fp) isZero() bool {
+	return (e[5] | e[4] | e[3] | e[2] | e[1] | e[0]) == 0
 }
 
-func (Fe *Fe) isOne() bool {
-	return Fe.equal(r1)
+func (e *Fp) isOne() bool {
+	return e.equal(r1)
 }
 
-func (Fe *Fe) cmp(fe2 *Fe) int {
+func (e *Fp) cmp(fe2 *Fp) int {
 	for i := fpNumberOfLimbs - 1; i >= 0; i-- {
-		if Fe[i] > fe2[i] {
+		if e[i] > fe2[i] {
 			return 1
-		} else if Fe[i] < fe2[i] {
+		} else if e[i] < fe2[i] {
 			return -1
 		}
 	}
 	return 0
 }
 
-func (Fe *Fe) equal(fe2 *Fe) bool {
-	return fe2[0] == Fe[0] && fe2[1] == Fe[1] && fe2[2] == Fe[2] && fe2[3] == Fe[3] && fe2[4] == Fe[4] && fe2[5] == Fe[5]
-}
-
-func (e *Fe) signBE() bool {
-	negZ, z := new(Fe), new(Fe)
+// CmpCT compares two field elements limb by limb without early exit.
+// Returns 1 if e > e2, -1 if e < e2 and 0 if they are equal.
+func (e *Fp) CmpCT(fe2 *Fp) int {
+	var lt, gt uint64
+	_, lt = bits.Sub64(e[0], fe2[0], 0)
+	_, lt = bits.Sub64(e[1], fe2[1], lt)
+	_, lt = bits.Sub64(e[2], fe2[2], lt)
+	_, lt = bits.Sub64(e[3], fe2[3], lt)
+	_, lt = bits.Sub64(e[4], fe2[4], lt)
+	_, lt = bits.Sub64(e[5], fe2[5], lt)
+	_, gt = bits.Sub64(fe2[0], e[0], 0)
+	_, gt = bits.Sub64(fe2[1], e[1], gt)
+	_, gt = bits.Sub64(fe2[2], e[2], gt)
+	_, gt = bits.Sub64(fe2[3], e[3], gt)
+	_, gt = bits.Sub64(fe2[4], e[4], gt)
+	_, gt = bits.Sub64(fe2[5], e[5], gt)
+	return int(gt) - int(lt)
+}
+
+func (e *Fp) equal(fe2 *Fp) bool {
+	return fe2[0] == e[0] && fe2[1] == e[1] && fe2[2] == e[2] && fe2[3] == e[3] && fe2[4] == e[4] && fe2[5] == e[5]
+}
+
+// signBE returns false if e is lexicographically larger than -e. Compressed
+// encodings set their sign flag for the larger y coordinate, a convention
+// different from Sgn0 that is fixed by the encoding format.
+func (e *Fp) signBE() bool {
+	negZ, z := new(Fp), new(Fp)
 	fromMont(z, e)
 	neg(negZ, z)
-	return negZ.cmp(z) > -1
-}
-
-func (e *Fe) sign() bool {
-	r := new(Fe)
-	fromMont(r, e)
-	return r[0]&1 == 0
-}
-
-func (e *Fe) div2(u uint64) {
+	return negZ.CmpCT(z) > -1
+}
+
+// halve computes c = a / 2 in constant time by adding the modulus to odd
+// values before shifting. Division by two commutes with Montgomery form.
+func halve(c, a *Fp) {
+	mask := -(a[0] & 1)
+	t := new(Fp)
+	var carry uint64
+	t[0], carry = bits.Add64(a[0], modulus[0]&mask, 0)
+	t[1], carry = bits.Add64(a[1], modulus[1]&mask, carry)
+	t[2], carry = bits.Add64(a[2], modulus[2]&mask, carry)
+	t[3], carry = bits.Add64(a[3], modulus[3]&mask, carry)
+	t[4], carry = bits.Add64(a[4], modulus[4]&mask, carry)
+	t[5], carry = bits.Add64(a[5], modulus[5]&mask, carry)
+	t.div2(carry)
+	c.set(t)
+}
+
+func (e *Fp) div2(u uint64) {
 	e[0] = e[0]>>1 | e[1]<<63
 	e[1] = e[1]>>1 | e[2]<<63
 	e[2] = e[2]>>1 | e[3]<<63
@@ -179,7 +222,7 @@ func (e *Fe) div2(u uint64) {
 	e[5] = e[5]>>1 | u<<63
 }
 
-func (e *Fe) mul2() uint64 {
+func (e *Fp) mul2() uint64 {
 	u := e[5] >> 63
 	e[5] = e[5]<<1 | e[4]>>63
 	e[4] = e[4]<<1 | e[3]>>63
@@ -219,12 +262,12 @@ func (e *fe2) fromWide(w *wfe2) {
 }
 
 func (e *fe2) rand(r io.Reader) (*fe2, error) {
-	a0, err := new(Fe).rand(r)
+	a0, err := new(Fp).rand(r)
 	if err != nil {
 		return nil, err
 	}
 	e[0].set(a0)
-	a1, err := new(Fe).rand(r)
+	a1, err := new(Fp).rand(r)
 	if err != nil {
 		return nil, err
 	}
@@ -244,6 +287,7 @@ func (e *fe2) equal(e2 *fe2) bool {
 	return e[0].equal(&e2[0]) && e[1].equal(&e2[1])
 }
 
+// signBE is signBE of c1, or of c0 if c1 is zero.
 func (e *fe2) signBE() bool {
 	if !e[1].isZero() {
 		return e[1].signBE()
@@ -251,16 +295,6 @@ func (e *fe2) signBE() bool {
 	return e[0].signBE()
 }
 
-func (e *fe2) sign() bool {
-	r := new(Fe)
-	if !e[0].isZero() {
-		fromMont(r, &e[0])
-		return r[0]&1 == 0
-	}
-	fromMont(r, &e[1])
-	return r[0]&1 == 0
-}
-
 func (e *fe6) zero() *fe6 {
 	e[0].zero()
 	e[1].zero()
@@ -374,31 +408,31 @@ func (e *fe12) equal(e2 *fe12) bool {
 	return e[0].equal(&e2[0]) && e[1].equal(&e2[1])
 }
 
-func (Fe *wfe) set(fe2 *wfe) *wfe {
-	Fe[0] = fe2[0]
-	Fe[1] = fe2[1]
-	Fe[2] = fe2[2]
-	Fe[3] = fe2[3]
-	Fe[4] = fe2[4]
-	Fe[5] = fe2[5]
-	Fe[6] = fe2[6]
-	Fe[7] = fe2[7]
-	Fe[8] = fe2[8]
-	Fe[9] = fe2[9]
-	Fe[10] = fe2[10]
-	Fe[11] = fe2[11]
-	return Fe
-}
-
-func (Fe *wfe2) set(fe2 *wfe2) *wfe2 {
-	Fe[0].set(&fe2[0])
-	Fe[1].set(&fe2[1])
-	return Fe
-}
-
-func (Fe *wfe6) set(fe2 *wfe6) *wfe6 {
-	Fe[0].set(&fe2[0])
-	Fe[1].set(&fe2[1])
-	Fe[2].set(&fe2[2])
-	return Fe
+func (Fp *wfe) set(fe2 *wfe) *wfe {
+	Fp[0] = fe2[0]
+	Fp[1] = fe2[1]
+	Fp[2] = fe2[2]
+	Fp[3] = fe2[3]
+	Fp[4] = fe2[4]
+	Fp[5] = fe2[5]
+	Fp[6] = fe2[6]
+	Fp[7] = fe2[7]
+	Fp[8] = fe2[8]
+	Fp[9] = fe2[9]
+	Fp[10] = fe2[10]
+	Fp[11] = fe2[11]
+	return Fp
+}
+
+func (Fp *wfe2) set(fe2 *wfe2) *wfe2 {
+	Fp[0].set(&fe2[0])
+	Fp[1].set(&fe2[1])
+	return Fp
+}
+
+func (Fp *wfe6) set(fe2 *wfe6) *wfe6 {
+	Fp[0].set(&fe2[0])
+	Fp[1].set(&fe2[1])
+	Fp[2].set(&fe2[2])
+	return Fp
 }
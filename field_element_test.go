@@ -3,17 +3,18 @@ package bls12381
 import (
 	"bytes"
 	"crypto/rand"
+	"io"
 	"math/big"
 	"testing"
 )
 
 func TestFieldElementValidation(t *testing.T) {
-	// Fe
-	zero := new(Fe).zero()
+	// Fp
+	zero := new(Fp).zero()
 	if !zero.isValid() {
 		t.Fatal("zero must be valid")
 	}
-	one := new(Fe).one()
+	one := new(Fp).one()
 	if !one.isValid() {
 		t.Fatal("one must be valid")
 	}
@@ -22,26 +23,26 @@ func TestFieldElementValidation(t *testing.T) {
 	}
 	n := modulus.big()
 	n.Add(n, big.NewInt(1))
-	if new(Fe).setBig(n).isValid() {
+	if new(Fp).setBig(n).isValid() {
 		t.Fatal("number greater than modulus must be invalid")
 	}
 }
 
 func TestFieldElementEquality(t *testing.T) {
-	// Fe
-	zero := new(Fe).zero()
+	// Fp
+	zero := new(Fp).zero()
 	if !zero.equal(zero) {
 		t.Fatal("0 == 0")
 	}
-	one := new(Fe).one()
+	one := new(Fp).one()
 	if !one.equal(one) {
 		t.Fatal("1 == 1")
 	}
-	a, _ := new(Fe).rand(rand.Reader)
+	a, _ := new(Fp).rand(rand.Reader)
 	if !a.equal(a) {
 		t.Fatal("a == a")
 	}
-	b := new(Fe)
+	b := new(Fp)
 	add(b, a, one)
 	if a.equal(b) {
 		t.Fatal("a != a + 1")
@@ -104,23 +105,23 @@ func TestFieldElementEquality(t *testing.T) {
 }
 
 func TestFieldElementHelpers(t *testing.T) {
-	// Fe
-	zero := new(Fe).zero()
+	// Fp
+	zero := new(Fp).zero()
 	if !zero.isZero() {
 		t.Fatal("'zero' is not zero")
 	}
-	one := new(Fe).one()
+	one := new(Fp).one()
 	if !one.isOne() {
 		t.Fatal("'one' is not one")
 	}
-	odd := new(Fe).setBig(big.NewInt(1))
+	odd := new(Fp).setBig(big.NewInt(1))
 	if !odd.isOdd() {
 		t.Fatal("1 must be odd")
 	}
 	if odd.isEven() {
 		t.Fatal("1 must not be even")
 	}
-	even := new(Fe).setBig(big.NewInt(2))
+	even := new(Fp).setBig(big.NewInt(2))
 	if !even.isEven() {
 		t.Fatal("2 must be even")
 	}
@@ -159,18 +160,18 @@ func TestFieldElementHelpers(t *testing.T) {
 func TestFieldElementSerialization(t *testing.T) {
 	t.Run("zero", func(t *testing.T) {
 		in := make([]byte, fpByteSize)
-		Fe := new(Fe).setBytes(in)
-		if !Fe.isZero() {
+		Fp := new(Fp).setBytes(in)
+		if !Fp.isZero() {
 			t.Fatal("serialization failed")
 		}
-		if !bytes.Equal(in, Fe.bytes()) {
+		if !bytes.Equal(in, Fp.bytes()) {
 			t.Fatal("serialization failed")
 		}
 	})
 	t.Run("bytes", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
-			b := new(Fe).setBytes(a.bytes())
+			a, _ := new(Fp).rand(rand.Reader)
+			b := new(Fp).setBytes(a.bytes())
 			if !a.equal(b) {
 				t.Fatal("serialization failed")
 			}
@@ -178,8 +179,8 @@ func TestFieldElementSerialization(t *testing.T) {
 	})
 	t.Run("big", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
-			b := new(Fe).setBig(a.big())
+			a, _ := new(Fp).rand(rand.Reader)
+			b := new(Fp).setBig(a.big())
 			if !a.equal(b) {
 				t.Fatal("encoding or decoding failed")
 			}
@@ -187,8 +188,8 @@ func TestFieldElementSerialization(t *testing.T) {
 	})
 	t.Run("string", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
-			b, err := new(Fe).setString(a.string())
+			a, _ := new(Fp).rand(rand.Reader)
+			b, err := new(Fp).setString(a.string())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -200,34 +201,34 @@ func TestFieldElementSerialization(t *testing.T) {
 }
 
 func TestFieldElementByteInputs(t *testing.T) {
-	zero := new(Fe).zero()
+	zero := new(Fp).zero()
 	in := make([]byte, 0)
-	a := new(Fe).setBytes(in)
+	a := new(Fp).setBytes(in)
 	if !a.equal(zero) {
 		t.Fatal("serialization failed")
 	}
 	in = make([]byte, fpByteSize)
-	a = new(Fe).setBytes(in)
+	a = new(Fp).setBytes(in)
 	if !a.equal(zero) {
 		t.Fatal("serialization failed")
 	}
 	in = make([]byte, fpByteSize+200)
-	a = new(Fe).setBytes(in)
+	a = new(Fp).setBytes(in)
 	if !a.equal(zero) {
 		t.Fatal("serialization failed")
 	}
 	in = make([]byte, fpByteSize+1)
 	in[fpByteSize-1] = 1
-	normalOne := &Fe{1, 0, 0, 0, 0, 0}
-	a = new(Fe).setBytes(in)
+	normalOne := &Fp{1, 0, 0, 0, 0, 0}
+	a = new(Fp).setBytes(in)
 	if !a.equal(normalOne) {
 		t.Fatal("serialization failed")
 	}
 }
 
 func TestFieldElementCopy(t *testing.T) {
-	a, _ := new(Fe).rand(rand.Reader)
-	b := new(Fe).set(a)
+	a, _ := new(Fp).rand(rand.Reader)
+	b := new(Fp).set(a)
 	if !a.equal(b) {
 		t.Fatal("copy failed")
 	}
@@ -247,3 +248,79 @@ func TestFieldElementCopy(t *testing.T) {
 		t.Fatal("copy failed2")
 	}
 }
+
+func TestDeprecatedAliases(t *testing.T) {
+	// Fe must stay interchangeable with Fp.
+	var a Fe
+	b := &a
+	var c *Fp = b
+	c.one()
+	if !a.isOne() {
+		t.Fatal("Fe must be an alias of Fp")
+	}
+}
+
+func TestSgn0(t *testing.T) {
+	one, two, minusOne := new(Fp).One(), new(Fp).SetUint64(2), new(Fp).SetInt64(-1)
+	for _, c := range []struct {
+		e    *Fp
+		sgn0 int
+	}{
+		{new(Fp), 0}, {one, 1}, {two, 0}, {minusOne, 0},
+	} {
+		if c.e.Sgn0() != c.sgn0 {
+			t.Fatal("bad sgn0", c.e)
+		}
+	}
+	for _, c := range []struct {
+		e    *Fp2
+		sgn0 int
+	}{
+		{&Fp2{}, 0},
+		{&Fp2{*one, Fp{}}, 1},
+		{&Fp2{*two, *one}, 0},
+		// zero c0 defers to c1
+		{&Fp2{Fp{}, *one}, 1},
+		{&Fp2{Fp{}, *minusOne}, 0},
+	} {
+		if c.e.Sgn0() != c.sgn0 {
+			t.Fatal("bad sgn0", c.e)
+		}
+	}
+	// sgn0(-e) = 1 - sgn0(e) for non zero e
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp2).rand(rand.Reader)
+		b := new(Fp2)
+		fp2Neg(b, a)
+		if a.Sgn0()+b.Sgn0() != 1 || a[0].Sgn0()+b[0].Sgn0() != 1 {
+			t.Fatal("negation must flip sgn0")
+		}
+	}
+}
+
+func TestRandNonZero(t *testing.T) {
+	// first draw of each reader is zero
+	zeroFirst := func(n int) io.Reader {
+		return io.MultiReader(bytes.NewReader(make([]byte, n)), rand.Reader)
+	}
+	if a, err := new(Fp).RandNonZero(zeroFirst(fpByteSize)); err != nil || a.IsZero() {
+		t.Fatal("zero base field element must be rejected", err)
+	}
+	if a, err := NewTower().Fp2RandNonZero(zeroFirst(2 * fpByteSize)); err != nil || a.IsZero() {
+		t.Fatal("zero fp2 element must be rejected", err)
+	}
+	if a, err := new(Fr).RandNonZero(zeroFirst(FrSize)); err != nil || a.IsZero() {
+		t.Fatal("zero scalar must be rejected", err)
+	}
+	// reader errors are returned
+	empty := bytes.NewReader(nil)
+	if _, err := new(Fp).RandNonZero(empty); err == nil {
+		t.Fatal("reader error must be returned")
+	}
+	if _, err := NewTower().Fp2RandNonZero(empty); err == nil {
+		t.Fatal("reader error must be returned")
+	}
+	if _, err := new(Fr).RandNonZero(empty); err == nil {
+		t.Fatal("reader error must be returned")
+	}
+}
@@ -0,0 +1,259 @@
+// Package fixture defines a JSON interchange format for scalars, points and
+// pairing triples with integer coordinates, so that values printed by Sage or
+// Python scripts, such as ones used during audits, can be checked against this
+// library without byte level encodings.
+//
+// Integers are written as hex strings with 0x prefix. Decoding also accepts
+// decimal strings and plain JSON numbers, which is what Python json module
+// outputs for int values. Extension field elements are lists of coefficients
+// in the tower
+//
+//	Fp2  = Fp[u] / (u^2 + 1)
+//	Fp6  = Fp2[v] / (v^3 - (u + 1))
+//	Fp12 = Fp6[w] / (w^2 - v)
+//
+// with constant coefficients first. An example pairing triple is
+//
+//	{
+//	  "p": {"x": "0x17f1...", "y": "0x08b3..."},
+//	  "q": {"x": ["0x24aa...", "0x13e0..."], "y": ["0x0ce5...", "0x0606..."]},
+//	  "e": ["0x1250...", ...]
+//	}
+//
+// and points at infinity are written as {"infinity": true}.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var (
+	ErrMissingCoordinate  = errors.New("missing coordinate")
+	ErrNonCanonicalScalar = errors.New("scalar must be less than group order")
+	ErrInvalidGT          = errors.New("invalid target group element")
+	ErrPairingMismatch    = errors.New("pairing result mismatch")
+)
+
+// Int is a non negative integer with JSON encoding described in package
+// documentation.
+type Int big.Int
+
+// NewInt returns a copy of a as Int.
+func NewInt(a *big.Int) *Int {
+	return (*Int)(new(big.Int).Set(a))
+}
+
+// Big returns the integer as big.Int sharing the same memory.
+func (i *Int) Big() *big.Int {
+	return (*big.Int)(i)
+}
+
+// MarshalJSON encodes the integer as a hex string with 0x prefix.
+func (i *Int) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + i.Big().Text(16))
+}
+
+// UnmarshalJSON decodes a hex or decimal string or a JSON number.
+func (i *Int) UnmarshalJSON(in []byte) error {
+	if bytes.Equal(in, []byte("null")) {
+		return nil
+	}
+	s, base := string(in), 10
+	if len(in) > 0 && in[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+		base = 0
+	}
+	a, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return errors.New("invalid integer " + strconv.Quote(s))
+	}
+	if a.Sign() < 0 {
+		return errors.New("negative integer " + strconv.Quote(s))
+	}
+	*i = Int(*a)
+	return nil
+}
+
+// FromScalar returns the scalar as Int.
+func FromScalar(s *bls.Fr) *Int {
+	return (*Int)(s.ToBig())
+}
+
+// Scalar returns the integer as a scalar. Integer must be less than the group
+// order.
+func (i *Int) Scalar() (*bls.Fr, error) {
+	if i.Big().Cmp(bls.NewG1().Q()) >= 0 {
+		return nil, ErrNonCanonicalScalar
+	}
+	in := make([]byte, bls.FrSize)
+	b := i.Big().Bytes()
+	copy(in[len(in)-len(b):], b)
+	return bls.NewFr().FromBytes(in), nil
+}
+
+// Fp2 is an element c0 + c1 * u given as [c0, c1].
+type Fp2 [2]*Int
+
+func fromFp2(e *bls.Fp2) *Fp2 {
+	return &Fp2{(*Int)(e[0].Big()), (*Int)(e[1].Big())}
+}
+
+func (e *Fp2) ints() []*Int {
+	if e == nil {
+		return []*Int{nil, nil}
+	}
+	return e[:]
+}
+
+// G1 is a point in affine coordinates.
+type G1 struct {
+	X        *Int `json:"x,omitempty"`
+	Y        *Int `json:"y,omitempty"`
+	Infinity bool `json:"infinity,omitempty"`
+}
+
+// FromG1 returns affine coordinates of the point.
+func FromG1(p *bls.PointG1) *G1 {
+	g := bls.NewG1()
+	if g.IsZero(p) {
+		return &G1{Infinity: true}
+	}
+	a := g.Affine(new(bls.PointG1).Set(p))
+	return &G1{X: (*Int)(a[0].Big()), Y: (*Int)(a[1].Big())}
+}
+
+// Point returns the point checking that it is on the curve and in the
+// correct subgroup.
+func (p *G1) Point() (*bls.PointG1, error) {
+	if p.Infinity {
+		if p.X != nil || p.Y != nil {
+			return nil, errors.New("point at infinity must not have coordinates")
+		}
+		return bls.NewG1().Zero(), nil
+	}
+	if p.X == nil || p.Y == nil {
+		return nil, ErrMissingCoordinate
+	}
+	return bls.PointG1FromBig(p.X.Big(), p.Y.Big())
+}
+
+// G2 is a point in affine coordinates.
+type G2 struct {
+	X        *Fp2 `json:"x,omitempty"`
+	Y        *Fp2 `json:"y,omitempty"`
+	Infinity bool `json:"infinity,omitempty"`
+}
+
+// FromG2 returns affine coordinates of the point.
+func FromG2(p *bls.PointG2) *G2 {
+	g := bls.NewG2()
+	if g.IsZero(p) {
+		return &G2{Infinity: true}
+	}
+	a := g.Affine(new(bls.PointG2).Set(p))
+	return &G2{X: fromFp2(&a[0]), Y: fromFp2(&a[1])}
+}
+
+// Point returns the point checking that it is on the curve and in the
+// correct subgroup.
+func (p *G2) Point() (*bls.PointG2, error) {
+	if p.Infinity {
+		if p.X != nil || p.Y != nil {
+			return nil, errors.New("point at infinity must not have coordinates")
+		}
+		return bls.NewG2().Zero(), nil
+	}
+	coords := append(p.X.ints(), p.Y.ints()...)
+	for _, c := range coords {
+		if c == nil {
+			return nil, ErrMissingCoordinate
+		}
+	}
+	return bls.PointG2FromBig(coords[0].Big(), coords[1].Big(), coords[2].Big(), coords[3].Big())
+}
+
+// GT is a target group element given by its twelve Fp coefficients, where
+// coefficient of u^k v^j w^i is at index 6i + 2j + k.
+type GT [12]*Int
+
+// FromGT returns coefficients of the element.
+func FromGT(e *bls.E) *GT {
+	out := new(GT)
+	for i := range out {
+		out[i] = (*Int)(e[i/6][i/2%3][i%2].Big())
+	}
+	return out
+}
+
+// Element returns the target group element checking that it is in the
+// correct subgroup.
+func (g *GT) Element() (*bls.E, error) {
+	e := new(bls.E)
+	for i, c := range g {
+		if c == nil {
+			return nil, ErrMissingCoordinate
+		}
+		if _, err := e[i/6][i/2%3][i%2].SetBig(c.Big()); err != nil {
+			return nil, err
+		}
+	}
+	if !bls.NewGT().IsValid(e) {
+		return nil, ErrInvalidGT
+	}
+	return e, nil
+}
+
+// Pairing is a triple of points and their pairing e(P, Q).
+type Pairing struct {
+	P *G1 `json:"p"`
+	Q *G2 `json:"q"`
+	E *GT `json:"e"`
+}
+
+// NewPairing computes the pairing of given points and returns the triple.
+func NewPairing(p *bls.PointG1, q *bls.PointG2) *Pairing {
+	e := bls.NewEngine().AddPair(p, q).Result()
+	return &Pairing{P: FromG1(p), Q: FromG2(q), E: FromGT(e)}
+}
+
+// Verify decodes the triple and checks that the pairing of the points equals
+// to the given result.
+func (t *Pairing) Verify() error {
+	if t.P == nil || t.Q == nil || t.E == nil {
+		return ErrMissingCoordinate
+	}
+	p, err := t.P.Point()
+	if err != nil {
+		return err
+	}
+	q, err := t.Q.Point()
+	if err != nil {
+		return err
+	}
+	e, err := t.E.Element()
+	if err != nil {
+		return err
+	}
+	if !bls.NewEngine().AddPair(p, q).Result().Equal(e) {
+		return ErrPairingMismatch
+	}
+	return nil
+}
+
+// Fixtures is a collection of values to be exchanged as a single JSON
+// document.
+type Fixtures struct {
+	Scalars  []*Int     `json:"scalars,omitempty"`
+	G1       []*G1      `json:"g1,omitempty"`
+	G2       []*G2      `json:"g2,omitempty"`
+	Pairings []*Pairing `json:"pairings,omitempty"`
+}
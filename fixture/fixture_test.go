@@ -0,0 +1,103 @@
+package fixture
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestRoundTrip(t *testing.T) {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	s, _ := bls.NewFr().Rand(rand.Reader)
+	p := g1.MulScalar(g1.New(), g1.One(), s)
+	q := g2.MulScalar(g2.New(), g2.One(), s)
+	in := &Fixtures{
+		Scalars:  []*Int{FromScalar(s)},
+		G1:       []*G1{FromG1(p), FromG1(g1.Zero())},
+		G2:       []*G2{FromG2(q), FromG2(g2.Zero())},
+		Pairings: []*Pairing{NewPairing(p, q)},
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := new(Fixtures)
+	if err := json.Unmarshal(b, out); err != nil {
+		t.Fatal(err)
+	}
+	s2, err := out.Scalars[0].Scalar()
+	if err != nil || !s2.Equal(s) {
+		t.Fatal("bad scalar round trip")
+	}
+	for i, want := range []*bls.PointG1{p, g1.Zero()} {
+		have, err := out.G1[i].Point()
+		if err != nil || !g1.Equal(have, want) {
+			t.Fatal("bad g1 round trip")
+		}
+	}
+	for i, want := range []*bls.PointG2{q, g2.Zero()} {
+		have, err := out.G2[i].Point()
+		if err != nil || !g2.Equal(have, want) {
+			t.Fatal("bad g2 round trip")
+		}
+	}
+	if err := out.Pairings[0].Verify(); err != nil {
+		t.Fatal(err)
+	}
+	out.Pairings[0].P = FromG1(g1.Double(g1.New(), p))
+	if err := out.Pairings[0].Verify(); err != ErrPairingMismatch {
+		t.Fatal("wrong pairing result must be rejected")
+	}
+}
+
+func TestPythonIntegers(t *testing.T) {
+	// json.dumps output of generator coordinates as Python ints
+	in := []byte(`{
+		"x": 3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507,
+		"y": 1339506544944476473020471379941921221584933875938349620426543736416511423956333506472724655353366534992391756441569
+	}`)
+	p := new(G1)
+	if err := json.Unmarshal(in, p); err != nil {
+		t.Fatal(err)
+	}
+	g, err := p.Point()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.NewG1().Equal(g, bls.NewG1().One()) {
+		t.Fatal("bad generator")
+	}
+	b, _ := json.Marshal(p)
+	want := `{"x":"0x17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",` +
+		`"y":"0x8b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1"}`
+	if string(b) != want {
+		t.Fatal("bad encoding", string(b))
+	}
+}
+
+func TestInvalidFixtures(t *testing.T) {
+	for _, in := range []string{`"-1"`, `"0xzz"`, `1.5`, `true`} {
+		if err := json.Unmarshal([]byte(in), new(Int)); err == nil {
+			t.Fatal("invalid integer must be rejected", in)
+		}
+	}
+	if _, err := (&G1{X: NewInt(bls.NewG1().Q())}).Point(); err != ErrMissingCoordinate {
+		t.Fatal("missing coordinate must be rejected")
+	}
+	if _, err := (&G1{X: NewInt(bls.NewG1().Q()), Infinity: true}).Point(); err == nil {
+		t.Fatal("point at infinity with coordinates must be rejected")
+	}
+	if _, err := (&G2{X: &Fp2{}, Y: &Fp2{}}).Point(); err != ErrMissingCoordinate {
+		t.Fatal("missing coordinate must be rejected")
+	}
+	if _, err := NewInt(bls.NewG1().Q()).Scalar(); err != ErrNonCanonicalScalar {
+		t.Fatal("scalar not less than group order must be rejected")
+	}
+	e := FromGT(new(bls.E).One())
+	e[1] = NewInt(bls.NewG1().Q())
+	if _, err := e.Element(); err != ErrInvalidGT {
+		t.Fatal("element out of subgroup must be rejected")
+	}
+}
@@ -0,0 +1,102 @@
+package bls12381
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Field elements and points print as hex strings of their canonical byte
+// encodings with 0x prefix, that is big endian values of field elements not in
+// Montgomery form and compressed encodings of points. Format supports %v and
+// %s, which are same as String, and %x and %X, which omit the prefix unless
+// the # flag is given. Base field elements are also printed in decimal with %d.
+
+// formatBytes writes the encoding in given verb.
+func formatBytes(f fmt.State, verb rune, b []byte) {
+	s := hex.EncodeToString(b)
+	switch verb {
+	case 'v', 's':
+		_, _ = io.WriteString(f, "0x"+s)
+	case 'x':
+		if f.Flag('#') {
+			s = "0x" + s
+		}
+		_, _ = io.WriteString(f, s)
+	case 'X':
+		s = strings.ToUpper(s)
+		if f.Flag('#') {
+			s = "0X" + s
+		}
+		_, _ = io.WriteString(f, s)
+	default:
+		fmt.Fprintf(f, "%%!%c(0x%s)", verb, s)
+	}
+}
+
+// String returns the element as hex string, same as ToString.
+func (e *Fp) String() string {
+	return ToString(e)
+}
+
+// Format implements fmt.Formatter.
+func (e *Fp) Format(f fmt.State, verb rune) {
+	if verb == 'd' {
+		_, _ = io.WriteString(f, e.Big().String())
+		return
+	}
+	formatBytes(f, verb, e.Bytes())
+}
+
+// String returns Tower.Fp2ToBytes encoding of the element as hex string.
+func (e *Fp2) String() string {
+	return "0x" + hex.EncodeToString(newFp2().toBytes(e))
+}
+
+// Format implements fmt.Formatter.
+func (e *Fp2) Format(f fmt.State, verb rune) {
+	formatBytes(f, verb, newFp2().toBytes(e))
+}
+
+// String returns Tower.Fp6ToBytes encoding of the element as hex string.
+func (e *Fp6) String() string {
+	return "0x" + hex.EncodeToString(newFp6(nil).toBytes(e))
+}
+
+// Format implements fmt.Formatter.
+func (e *Fp6) Format(f fmt.State, verb rune) {
+	formatBytes(f, verb, newFp6(nil).toBytes(e))
+}
+
+// String returns Tower.Fp12ToBytes encoding of the element as hex string.
+func (e *Fp12) String() string {
+	return "0x" + hex.EncodeToString(newFp12(nil).toBytes(e))
+}
+
+// Format implements fmt.Formatter.
+func (e *Fp12) Format(f fmt.State, verb rune) {
+	formatBytes(f, verb, newFp12(nil).toBytes(e))
+}
+
+// String returns compressed encoding of the point as hex string. The point
+// is not modified.
+func (p *PointG1) String() string {
+	return "0x" + hex.EncodeToString(NewG1().ToCompressed(new(PointG1).Set(p)))
+}
+
+// Format implements fmt.Formatter.
+func (p *PointG1) Format(f fmt.State, verb rune) {
+	formatBytes(f, verb, NewG1().ToCompressed(new(PointG1).Set(p)))
+}
+
+// String returns compressed encoding of the point as hex string. The point
+// is not modified.
+func (p *PointG2) String() string {
+	return "0x" + hex.EncodeToString(NewG2().ToCompressed(new(PointG2).Set(p)))
+}
+
+// Format implements fmt.Formatter.
+func (p *PointG2) Format(f fmt.State, verb rune) {
+	formatBytes(f, verb, NewG2().ToCompressed(new(PointG2).Set(p)))
+}
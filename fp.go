@@ -2,103 +2,93 @@ package bls12381
 
 import (
 	"errors"
+	"io"
 	"math/big"
 )
 
-func fromBytes(in []byte) (*Fe, error) {
-	Fe := &Fe{}
+func fromBytes(in []byte) (*Fp, error) {
+	Fp := &Fp{}
 	if len(in) != fpByteSize {
 		return nil, errors.New("input string must be equal 48 bytes")
 	}
-	Fe.setBytes(in)
-	if !Fe.isValid() {
-		return nil, errors.New("must be less than modulus")
+	if err := fromBytesInto(Fp, in); err != nil {
+		return nil, err
 	}
-	toMont(Fe, Fe)
-	return Fe, nil
+	return Fp, nil
 }
 
-func from64Bytes(in []byte) (*Fe, error) {
-	if len(in) != 32*2 {
-		return nil, errors.New("input string must be equal 64 bytes")
-	}
-	a0 := make([]byte, fpByteSize)
-	copy(a0[fpByteSize-32:fpByteSize], in[:32])
-	a1 := make([]byte, fpByteSize)
-	copy(a1[fpByteSize-32:fpByteSize], in[32:])
-	e0, err := fromBytes(a0)
-	if err != nil {
-		return nil, err
-	}
-	e1, err := fromBytes(a1)
-	if err != nil {
-		return nil, err
-	}
-	// F = 2 ^ 256 * R
-	F := Fe{
-		0x75b3cd7c5ce820f,
-		0x3ec6ba621c3edb0b,
-		0x168a13d82bff6bce,
-		0x87663c4bf8c449d2,
-		0x15f34c83ddc8d830,
-		0xf9628b49caa2e85,
+// fromBytesInto decodes 48 bytes big endian input into given element in Montgomery form.
+// Input length must be checked by the caller.
+func fromBytesInto(c *Fp, in []byte) error {
+	c.setBytes(in)
+	if !c.isValid() {
+		return ErrNonCanonical
 	}
-
-	mul(e0, e0, &F)
-	add(e1, e1, e0)
-	return e1, nil
+	toMont(c, c)
+	return nil
 }
 
-func fromBig(in *big.Int) (*Fe, error) {
-	Fe := new(Fe).setBig(in)
-	if !Fe.isValid() {
+func fromBig(in *big.Int) (*Fp, error) {
+	Fp := new(Fp).setBig(in)
+	if !Fp.isValid() {
 		return nil, errors.New("invalid input string")
 	}
-	toMont(Fe, Fe)
-	return Fe, nil
+	toMont(Fp, Fp)
+	return Fp, nil
 }
 
-func fromString(in string) (*Fe, error) {
-	Fe, err := new(Fe).setString(in)
+func fromString(in string) (*Fp, error) {
+	Fp, err := new(Fp).setString(in)
 	if err != nil {
 		return nil, err
 	}
-	if !Fe.isValid() {
+	if !Fp.isValid() {
 		return nil, errors.New("invalid input string")
 	}
-	toMont(Fe, Fe)
-	return Fe, nil
+	toMont(Fp, Fp)
+	return Fp, nil
 }
 
-func toBytes(e *Fe) []byte {
-	e2 := new(Fe)
+func toBytes(e *Fp) []byte {
+	e2 := new(Fp)
 	fromMont(e2, e)
 	return e2.bytes()
 }
 
-func ToBig(e *Fe) *big.Int {
-	e2 := new(Fe)
+func ToBig(e *Fp) *big.Int {
+	e2 := new(Fp)
 	fromMont(e2, e)
 	return e2.big()
 }
 
-func ToString(e *Fe) (s string) {
-	e2 := new(Fe)
+func ToString(e *Fp) (s string) {
+	e2 := new(Fp)
 	fromMont(e2, e)
 	return e2.string()
 }
 
-func toMont(c, a *Fe) {
+func toMont(c, a *Fp) {
 	mul(c, a, r2)
 }
 
-func fromMont(c, a *Fe) {
-	mul(c, a, &Fe{1})
+func fromMont(c, a *Fp) {
+	mul(c, a, &Fp{1})
+}
+
+// NormalizeBatchFp converts elements from Montgomery form to standard form in
+// place, for exporting large vectors to external representations. Limbs of
+// resulting elements are little endian words of the integer value, and they
+// must not be used in field operations afterwards.
+func NormalizeBatchFp(in []Fp) {
+	one := &Fp{1}
+	for i := range in {
+		mul(&in[i], &in[i], one)
+	}
 }
 
 func wfp2MulGeneric(c *wfe2, a, b *fe2) {
 	wt0, wt1 := new(wfe), new(wfe)
-	t0, t1 := new(Fe), new(Fe)
+	t0, t1 := new(Fp), new(Fp)
 	wmul(wt0, &a[0], &b[0])
 	wmul(wt1, &a[1], &b[1])
 	wsub(&c[0], wt0, wt1)
@@ -110,7 +100,7 @@ func wfp2MulGeneric(c *wfe2, a, b *fe2) {
 }
 
 func wfp2SquareGeneric(c *wfe2, a *fe2) {
-	t0, t1, t2 := new(Fe), new(Fe), new(Fe)
+	t0, t1, t2 := new(Fp), new(Fp), new(Fp)
 	ladd(t0, &a[0], &a[1])
 	sub(t1, &a[0], &a[1])
 	ldouble(t2, &a[0])
@@ -118,79 +108,92 @@ func wfp2SquareGeneric(c *wfe2, a *fe2) {
 	wmul(&c[1], t2, &a[1])
 }
 
-func exp(c, a *Fe, e *big.Int) {
-	z := new(Fe).set(r1)
-	for i := e.BitLen(); i >= 0; i-- {
-		mul(z, z, z)
-		if e.Bit(i) == 1 {
-			mul(z, z, a)
-		}
+func exp(c, a *Fp, e *big.Int) {
+	expLimbs(c, a, bigToLimbs(e))
+}
+
+// bigToLimbs returns absolute value of e in 64 bit little endian limbs.
+func bigToLimbs(e *big.Int) []uint64 {
+	b := e.Bytes()
+	limbs := make([]uint64, (len(b)+7)/8)
+	for i := range b {
+		limbs[i/8] |= uint64(b[len(b)-1-i]) << (8 * uint(i%8))
 	}
-	c.set(z)
+	return limbs
 }
 
-func inverse(inv, e *Fe) {
-	if e.isZero() {
-		inv.zero()
-		return
+// expLimbs computes c = a^s where s is given in 64 bit little endian limbs,
+// with 4 bit fixed windows. Leading zero windows are skipped, so running time
+// depends on bit length of the exponent but not on the base.
+func expLimbs(c, a *Fp, s []uint64) {
+	var table [16]Fp
+	table[0].set(r1)
+	table[1].set(a)
+	for i := 2; i < 16; i++ {
+		mul(&table[i], &table[i-1], a)
 	}
-	u := new(Fe).set(&modulus)
-	v := new(Fe).set(e)
-	s := &Fe{1}
-	r := &Fe{0}
-	var k int
-	var z uint64
-	var found = false
-	// Phase 1
-	for i := 0; i < sixWordBitSize*2; i++ {
-		if v.isZero() {
-			found = true
-			break
-		}
-		if u.isEven() {
-			u.div2(0)
-			s.mul2()
-		} else if v.isEven() {
-			v.div2(0)
-			z += r.mul2()
-		} else if u.cmp(v) == 1 {
-			lsubAssign(u, v)
-			u.div2(0)
-			laddAssign(r, s)
-			s.mul2()
-		} else {
-			lsubAssign(v, u)
-			v.div2(0)
-			laddAssign(s, r)
-			z += r.mul2()
+	z := new(Fp).set(r1)
+	started := false
+	for i := len(s) - 1; i >= 0; i-- {
+		for j := 60; j >= 0; j -= 4 {
+			w := (s[i] >> uint(j)) & 0xf
+			if !started {
+				if w == 0 {
+					continue
+				}
+				z.set(&table[w])
+				started = true
+				continue
+			}
+			square(z, z)
+			square(z, z)
+			square(z, z)
+			square(z, z)
+			mul(z, z, &table[w])
 		}
-		k += 1
 	}
+	c.set(z)
+}
 
-	if !found {
-		inv.zero()
-		return
-	}
+// ErrZeroInversion is returned when an element or a point coordinate that is
+// expected to be invertible is zero.
+var ErrZeroInversion = errors.New("inversion of zero")
+
+// inverse computes inv = e^-1 in constant time with safegcd. It returns false
+// if e is zero, in which case inv is set to zero.
+func inverse(inv, e *Fp) bool {
+	ok := !e.isZero()
+	t := new(Fp)
+	fromMont(t, e)
+	fpSafegcd.inverse(t[:], t[:])
+	toMont(inv, t)
+	return ok
+}
 
-	if k < fpBitSize || k > fpBitSize+sixWordBitSize {
+// inverseVarTime computes inv = e^-1 with safegcd in variable time, which
+// stops as soon as the gcd is found instead of running the worst case number
+// of divsteps. Running time depends on e, so it must only be used for public
+// values. It returns false if e is zero, in which case inv is set to zero.
+func inverseVarTime(inv, e *Fp) bool {
+	if e.isZero() {
 		inv.zero()
-		return
-	}
-
-	if r.cmp(&modulus) != -1 || z > 0 {
-		lsubAssign(r, &modulus)
+		return false
 	}
-	u.set(&modulus)
-	lsubAssign(u, r)
+	t := new(Fp)
+	fromMont(t, e)
+	fpSafegcd.inverseVarTime(t[:], t[:])
+	toMont(inv, t)
+	return true
+}
 
-	// Phase 2
-	for i := k; i < 2*sixWordBitSize; i++ {
-		double(u, u)
-	}
-	inv.set(u)
+// BatchInverse inverts elements in place using Montgomery's trick, which costs
+// a single inversion and 3N multiplications. Zero elements are skipped and
+// stay zero.
+func BatchInverse(in []Fp) {
+	inverseBatch(in)
 }
 
-func inverseBatch(in []Fe) {
+func inverseBatch(in []Fp) {
 
 	n, N, setFirst := 0, len(in), false
 
@@ -203,8 +206,8 @@ func inverseBatch(in []Fe) {
 		return
 	}
 
-	tA := make([]Fe, n)
-	tB := make([]Fe, n)
+	tA := make([]Fp, n)
+	tB := make([]Fp, n)
 
 	for i, j := 0, 0; i < N; i++ {
 		if !in[i].isZero() {
@@ -239,8 +242,8 @@ func inverseBatch(in []Fe) {
 	}
 }
 
-func rsqrt(c, a *Fe) bool {
-	t0, t1 := new(Fe), new(Fe)
+func rsqrt(c, a *Fp) bool {
+	t0, t1 := new(Fp), new(Fp)
 	sqrtAddchain(t0, a)
 	mul(t1, t0, a)
 	square(t1, t1)
@@ -249,8 +252,8 @@ func rsqrt(c, a *Fe) bool {
 	return ret
 }
 
-func sqrt(c, a *Fe) bool {
-	u, v := new(Fe).set(a), new(Fe)
+func sqrt(c, a *Fp) bool {
+	u, v := new(Fp).set(a), new(Fp)
 	// a ^ (p - 3) / 4
 	sqrtAddchain(c, a)
 	// a ^ (p + 1) / 4
@@ -260,22 +263,22 @@ func sqrt(c, a *Fe) bool {
 	return u.equal(v)
 }
 
-func _sqrt(c, a *Fe) bool {
-	u, v := new(Fe).set(a), new(Fe)
+func _sqrt(c, a *Fp) bool {
+	u, v := new(Fp).set(a), new(Fp)
 	exp(c, a, pPlus1Over4)
 	square(v, c)
 	return u.equal(v)
 }
 
-func sqrtAddchain(c, a *Fe) {
-	chain := func(c *Fe, n int, a *Fe) {
+func sqrtAddchain(c, a *Fp) {
+	chain := func(c *Fp, n int, a *Fp) {
 		for i := 0; i < n; i++ {
 			square(c, c)
 		}
 		mul(c, c, a)
 	}
 
-	t := make([]Fe, 16)
+	t := make([]Fp, 16)
 	t[13].set(a)
 	square(&t[0], &t[13])
 	mul(&t[8], &t[0], &t[13])
@@ -364,9 +367,367 @@ func sqrtAddchain(c, a *Fe) {
 	square(c, &t[0])
 }
 
-func isQuadraticNonResidue(a *Fe) bool {
+func isQuadraticNonResidue(a *Fp) bool {
 	if a.isZero() {
 		return true
 	}
-	return !sqrt(new(Fe), a)
+	return !sqrt(new(Fp), a)
+}
+
+// Exported base field API. Elements are kept in Montgomery form internally:
+// SetBytes, SetBig, SetBigReduce, SetUint64 and SetInt64 convert into
+// Montgomery form and Bytes and Big convert back, so callers never see
+// Montgomery representation unless they access limbs directly or through
+// RawLimbs and SetRawLimbs. Arithmetic methods set the receiver and accept
+// aliased arguments. None of them allocate; Assign variants update the
+// receiver in place and use two operand routines where available.
+
+// NewFp returns zero element of the base field.
+func NewFp() *Fp {
+	return new(Fp)
+}
+
+// Rand sets the element to a uniformly random value.
+func (e *Fp) Rand(r io.Reader) (*Fp, error) {
+	return e.rand(r)
+}
+
+// RandNonZero sets the element to a uniformly random non zero value, for
+// blinding factors and other values that must be invertible.
+func (e *Fp) RandNonZero(r io.Reader) (*Fp, error) {
+	for {
+		if _, err := e.rand(r); err != nil {
+			return nil, err
+		}
+		if !e.isZero() {
+			return e, nil
+		}
+	}
+}
+
+// Set sets the element to the value of a.
+func (e *Fp) Set(a *Fp) *Fp {
+	return e.set(a)
+}
+
+// Zero sets the element to zero.
+func (e *Fp) Zero() *Fp {
+	return e.zero()
+}
+
+// One sets the element to one.
+func (e *Fp) One() *Fp {
+	return e.one()
+}
+
+// SetUint64 sets the element to the value of n.
+func (e *Fp) SetUint64(n uint64) *Fp {
+	*e = Fp{n}
+	toMont(e, e)
+	return e
+}
+
+// SetInt64 sets the element to the value of n, which is p - |n| for negative
+// values.
+func (e *Fp) SetInt64(n int64) *Fp {
+	if n >= 0 {
+		return e.SetUint64(uint64(n))
+	}
+	// magnitude in two's complement, also correct for math.MinInt64
+	e.SetUint64(-uint64(n))
+	neg(e, e)
+	return e
+}
+
+// SetBytes sets the element to 48 bytes big endian input, which must be
+// less than the modulus. It is same as SetBytesCanonical.
+func (e *Fp) SetBytes(in []byte) (*Fp, error) {
+	return e.SetBytesCanonical(in)
+}
+
+// SetBytesCanonical sets the element to 48 bytes big endian input. It returns
+// ErrInvalidLength for inputs of other lengths and ErrNonCanonical for values
+// not less than the modulus, unlike internal decoding that silently keeps such
+// values until they are validated. Point decoders apply the same checks to
+// every coordinate.
+func (e *Fp) SetBytesCanonical(in []byte) (*Fp, error) {
+	if len(in) != fpByteSize {
+		return nil, ErrInvalidLength
+	}
+	if err := fromBytesInto(e, in); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// SetBytesLE sets the element to 48 bytes little endian input, as exchanged
+// by arkworks and other Rust libraries. It applies the same checks as
+// SetBytesCanonical.
+func (e *Fp) SetBytesLE(in []byte) (*Fp, error) {
+	if len(in) != fpByteSize {
+		return nil, ErrInvalidLength
+	}
+	var b [fpByteSize]byte
+	copy(b[:], in)
+	reverseBytes(b[:])
+	return e.SetBytesCanonical(b[:])
+}
+
+// SetBytesWide sets the element to 64 or 96 bytes big endian input reduced
+// modulo p, as required by hash to field. Input is split into 32 byte chunks
+// which are multiplied by precomputed powers of 2^256 in Montgomery form and
+// accumulated in double width, so a single Montgomery reduction is needed.
+func (e *Fp) SetBytesWide(in []byte) (*Fp, error) {
+	if len(in) != 64 && len(in) != 96 {
+		return nil, ErrInvalidLength
+	}
+	w, t := new(wfe), new(wfe)
+	a := new(Fp)
+	for i := 0; i < len(in)/32; i++ {
+		end := len(in) - 32*i
+		// a < 2^256 < p
+		a.setBytes(in[end-32 : end])
+		wmul(t, a, &wideReduction[i])
+		// at most three terms less than 2^256 * p, no overflow
+		lwaddAssign(w, t)
+	}
+	fromWide(e, w)
+	return e, nil
+}
+
+// SetBig sets the element to the value of a, which must be non negative and
+// less than the modulus.
+func (e *Fp) SetBig(a *big.Int) (*Fp, error) {
+	if a.Sign() < 0 || a.Cmp(modulus.big()) >= 0 {
+		return nil, ErrNonCanonical
+	}
+	e.setBig(a)
+	toMont(e, e)
+	return e, nil
+}
+
+// SetBigReduce sets the element to a mod p for any integer a, so that
+// negative values are mapped to p - (|a| mod p). Unlike SetBig it never fails.
+func (e *Fp) SetBigReduce(a *big.Int) *Fp {
+	e.setBig(new(big.Int).Mod(a, modulus.big()))
+	toMont(e, e)
+	return e
+}
+
+// Bytes returns 48 bytes big endian encoding of the element.
+func (e *Fp) Bytes() []byte {
+	return toBytes(e)
+}
+
+// BytesLE returns 48 bytes little endian encoding of the element.
+func (e *Fp) BytesLE() []byte {
+	out := toBytes(e)
+	reverseBytes(out)
+	return out
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the encoding of
+// Bytes.
+func (e *Fp) MarshalBinary() ([]byte, error) {
+	return e.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Input is decoded as
+// in SetBytesCanonical.
+func (e *Fp) UnmarshalBinary(in []byte) error {
+	_, err := e.SetBytesCanonical(in)
+	return err
+}
+
+// Big returns value of the element.
+func (e *Fp) Big() *big.Int {
+	return ToBig(e)
+}
+
+// IsZero returns true if the element is zero.
+func (e *Fp) IsZero() bool {
+	return e.isZero()
+}
+
+// IsOne returns true if the element is one.
+func (e *Fp) IsOne() bool {
+	return e.isOne()
+}
+
+// Equal returns true if elements are equal.
+func (e *Fp) Equal(a *Fp) bool {
+	return e.equal(a)
+}
+
+// EqualCT returns 1 if elements are equal and 0 otherwise. Unlike Equal it
+// compares all limbs without early exit, so that timing does not depend on
+// the values.
+func (e *Fp) EqualCT(a *Fp) int {
+	var d uint64
+	for i := 0; i < fpNumberOfLimbs; i++ {
+		d |= e[i] ^ a[i]
+	}
+	return int(1 ^ ((d | -d) >> 63))
+}
+
+// CMov sets e = a if cond is non zero and leaves e unchanged otherwise, in
+// constant time.
+func (e *Fp) CMov(a *Fp, cond int) *Fp {
+	cmov(e, a, uint64(cond))
+	return e
+}
+
+// Select sets e = a if cond is non zero and e = b otherwise, in constant time.
+func (e *Fp) Select(cond int, a, b *Fp) *Fp {
+	t := *b
+	cmov(&t, a, uint64(cond))
+	*e = t
+	return e
+}
+
+// Add sets e = a + b.
+func (e *Fp) Add(a, b *Fp) {
+	add(e, a, b)
+}
+
+// Double sets e = 2 * a.
+func (e *Fp) Double(a *Fp) {
+	double(e, a)
+}
+
+// Halve sets e = a / 2 in constant time.
+func (e *Fp) Halve(a *Fp) {
+	halve(e, a)
+}
+
+// Sub sets e = a - b.
+func (e *Fp) Sub(a, b *Fp) {
+	sub(e, a, b)
+}
+
+// Neg sets e = -a.
+func (e *Fp) Neg(a *Fp) {
+	neg(e, a)
+}
+
+// Mul sets e = a * b.
+func (e *Fp) Mul(a, b *Fp) {
+	mul(e, a, b)
+}
+
+// Square sets e = a^2.
+func (e *Fp) Square(a *Fp) {
+	square(e, a)
+}
+
+// AddAssign sets e = e + a.
+func (e *Fp) AddAssign(a *Fp) {
+	addAssign(e, a)
+}
+
+// SubAssign sets e = e - a.
+func (e *Fp) SubAssign(a *Fp) {
+	subAssign(e, a)
+}
+
+// MulAssign sets e = e * a.
+func (e *Fp) MulAssign(a *Fp) {
+	mul(e, e, a)
+}
+
+// SquareAssign sets e = e^2.
+func (e *Fp) SquareAssign() {
+	square(e, e)
+}
+
+// Inverse sets e = a^-1 in constant time, so that it is safe for secret
+// values. It returns false if a is zero, in which case e is set to zero.
+func (e *Fp) Inverse(a *Fp) bool {
+	return inverse(e, a)
+}
+
+// InverseVarTime is Inverse in variable time, which is faster on average. It
+// must only be used for public values such as point coordinates under
+// verification.
+func (e *Fp) InverseVarTime(a *Fp) bool {
+	return inverseVarTime(e, a)
+}
+
+// Exp sets e = a^s with 4 bit windows. Sign of s is ignored.
+func (e *Fp) Exp(a *Fp, s *big.Int) {
+	exp(e, a, s)
+}
+
+// ExpLimbs sets e = a^s where exponent is given in 64 bit little endian limbs,
+// for exponents kept as machine words, such as exponents derived from field
+// elements or the modulus.
+func (e *Fp) ExpLimbs(a *Fp, s []uint64) {
+	expLimbs(e, a, s)
+}
+
+// SqrtFp returns a square root of `a` and true if `a` is a quadratic
+// residue, otherwise it returns nil and false. Since p = 3 mod 4 the root is
+// computed as a^((p+1)/4).
+func SqrtFp(a *Fp) (*Fp, bool) {
+	c := new(Fp)
+	if !sqrt(c, a) {
+		return nil, false
+	}
+	return c, true
+}
+
+// legendre returns Legendre symbol of a computed with Euler's criterion
+// a^((p-1)/2). It is 1 for quadratic residues, -1 for non residues and 0 for
+// zero.
+func legendre(a *Fp) int {
+	c := new(Fp)
+	exp(c, a, pMinus1Over2)
+	if c.isZero() {
+		return 0
+	}
+	if c.isOne() {
+		return 1
+	}
+	return -1
+}
+
+// IsQuadraticResidue returns true if a is a non zero square in the base field.
+// Input is in Montgomery form as every other element and it is not modified.
+// Zero is not a quadratic residue, IsSquare accepts it as well.
+func IsQuadraticResidue(a *Fp) bool {
+	return legendre(a) == 1
+}
+
+// IsQuadraticResidueFp2 returns true if a is a non zero square in Fp2. An
+// element of Fp2 is a square if and only if its norm a0^2 + a1^2 is a square
+// in the base field, so a single exponentiation by (p-1)/2 is used.
+func IsQuadraticResidueFp2(a *Fp2) bool {
+	n := new(Fp)
+	newFp2().norm(n, a)
+	return legendre(n) == 1
+}
+
+// IsSquare returns true if the element is a quadratic residue or zero.
+func (e *Fp) IsSquare() bool {
+	return e.isZero() || !isQuadraticNonResidue(e)
+}
+
+// Sgn0 returns sgn0 of the element as defined in RFC 9380 section 4.1, that
+// is parity of its canonical value. Hashing to curve selects y coordinates
+// with it. Point compression uses a different, lexicographic sign instead.
+func (e *Fp) Sgn0() int {
+	r := new(Fp)
+	fromMont(r, e)
+	return int(r[0] & 1)
+}
+
+// Sqrt sets e to a square root of a and returns true if a is a square,
+// otherwise e is left unchanged and false is returned.
+func (e *Fp) Sqrt(a *Fp) bool {
+	r := new(Fp)
+	if !sqrt(r, a) {
+		return false
+	}
+	e.set(r)
+	return true
 }
@@ -50,7 +50,7 @@ func (e *fp12) fp2() *fp2 {
 }
 
 func (e *fp12) fromBytes(in []byte) (*fe12, error) {
-	if len(in) != 576 {
+	if len(in) != GTSize {
 		return nil, errors.New("input string length must be equal to 576 bytes")
 	}
 	fp6 := e.fp6
@@ -162,6 +162,11 @@ func (e *fp12) square(c, a *fe12) {
 	// Multiplication and Squaring on Pairing-Friendly Fields
 	// Complex squaring algorithm
 	// https://eprint.iacr.org/2006/471
+	//
+	// Chung-Hasan SQR3 over fp4 = fp2[w^3], and Karatsuba over wide fp6
+	// squaring, take as many fp2 multiplications and did not run faster.
+	// Final exponentiation uses compressed squaring and target group
+	// exponentiation uses cyclotomic squaring instead.
 
 	fp6Add(t[0], &a[0], &a[1])
 	e.fp6.mul(t[2], &a[0], &a[1])
@@ -217,6 +222,10 @@ func (e *fp12) exp(c, a *fe12, s *big.Int) {
 	c.set(z)
 }
 
+// cyclotomicExp sets c = a^s for an element a of the cyclotomic subgroup. It
+// squares the full element, since for a dense exponent decompressing a power
+// for each set bit costs more than compressed squaring saves, see
+// cyclotomicExpBits.
 func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
 	z := e.one()
 	for i := s.BitLen() - 1; i >= 0; i-- {
@@ -228,6 +237,36 @@ func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
 	c.set(z)
 }
 
+// cyclotomicExpBits sets c = a^s for an element a of the cyclotomic subgroup
+// and an exponent s given by positions of its set bits in increasing order.
+// Powers a^(2^i) are computed with compressed squaring and decompressed at
+// the end sharing a single inversion, which pays off for sparse exponents
+// such as the curve parameter in final exponentiation.
+func (e *fp12) cyclotomicExpBits(c, a *fe12, bits []int) {
+	n := len(bits)
+	if n == 0 {
+		c.one()
+		return
+	}
+	parts, powers := make([]fe12c, n), make([]fe12, n)
+	z := new(fe12c)
+	e.compress(z, a)
+	for i, k := 0, 0; ; i++ {
+		if bits[k] == i {
+			parts[k] = *z
+			if k++; k == n {
+				break
+			}
+		}
+		e.compressedSquare(z)
+	}
+	e.decompressBatch(powers, parts)
+	c.set(&powers[0])
+	for k := 1; k < n; k++ {
+		e.mulAssign(c, &powers[k])
+	}
+}
+
 func (e *fp12) cyclotomicSquare(a *fe12) {
 	t := e.t2
 	// Guide to Pairing Based Cryptography
@@ -275,6 +314,146 @@ func (e *fp12) fp4Square(c0, c1, a0, a1 *fe2) {
 	c1.fromWide(wt[2])
 }
 
+// fe12c is Karabina compressed form of a cyclotomic subgroup element. It
+// keeps coefficients a01, a02, a10 and a12, where aij is coefficient of v^j w^i,
+// and a00 and a11 are recovered with decompression.
+//
+// Squaring and Cyclotomic Subgroups in Fp12, K. Karabina
+// https://eprint.iacr.org/2010/542.pdf
+type fe12c [4]fe2
+
+// compress sets c to the compressed form of a cyclotomic subgroup element a.
+func (e *fp12) compress(c *fe12c, a *fe12) {
+	c[0].set(&a[0][1])
+	c[1].set(&a[0][2])
+	c[2].set(&a[1][0])
+	c[3].set(&a[1][2])
+}
+
+// compressedSquare squares a compressed element in place with six fp2
+// squarings and four reductions of their lazy sums, which is cheaper than
+// cyclotomic squaring of the full element.
+func (e *fp12) compressedSquare(a *fe12c) {
+	wt, t := e.wt2, e.t2
+	a01, a02, a10, a12 := &a[0], &a[1], &a[2], &a[3]
+
+	wfp2Square(wt[0], a01)
+	wfp2Square(wt[1], a12)
+	fp2Add(t[0], a01, a12)
+	wfp2Square(wt[2], t[0])
+	wfp2SubAssign(wt[2], wt[0])
+	wfp2SubAssign(wt[2], wt[1])
+	t[1].fromWide(wt[2]) // 2 * a01 * a12
+	wfp2MulByNonResidue(wt[2], wt[1])
+	wfp2AddAssign(wt[2], wt[0])
+	t[2].fromWide(wt[2]) // nr * a12^2 + a01^2
+
+	wfp2Square(wt[0], a10)
+	wfp2Square(wt[1], a02)
+	fp2Add(t[0], a10, a02)
+	wfp2Square(wt[2], t[0])
+	wfp2SubAssign(wt[2], wt[0])
+	wfp2SubAssign(wt[2], wt[1])
+	t[3].fromWide(wt[2]) // 2 * a10 * a02
+	wfp2MulByNonResidue(wt[2], wt[1])
+	wfp2AddAssign(wt[2], wt[0])
+	t[4].fromWide(wt[2]) // a10^2 + nr * a02^2
+
+	// a10 = 6 * nr * a01 * a12 + 2 * a10
+	mulByNonResidue(t[0], t[1])
+	fp2Add(t[5], t[0], a10)
+	fp2DoubleAssign(t[5])
+	fp2Add(a10, t[5], t[0])
+
+	// a02 = 3 * (nr * a12^2 + a01^2) - 2 * a02
+	fp2Sub(t[5], t[2], a02)
+	fp2DoubleAssign(t[5])
+	fp2Add(a02, t[5], t[2])
+
+	// a01 = 3 * (a10^2 + nr * a02^2) - 2 * a01
+	fp2Sub(t[5], t[4], a01)
+	fp2DoubleAssign(t[5])
+	fp2Add(a01, t[5], t[4])
+
+	// a12 = 6 * a10 * a02 + 2 * a12
+	fp2Add(t[5], t[3], a12)
+	fp2DoubleAssign(t[5])
+	fp2Add(a12, t[5], t[3])
+}
+
+// decompress recovers the cyclotomic subgroup element c from its compressed
+// form a.
+func (e *fp12) decompress(c *fe12, a *fe12c) {
+	out, in := [1]fe12{}, [1]fe12c{*a}
+	e.decompressBatch(out[:], in[:])
+	c.set(&out[0])
+}
+
+// decompressBatch recovers cyclotomic subgroup elements c[i] from compressed
+// forms a[i], sharing a single inversion across all elements.
+func (e *fp12) decompressBatch(c []fe12, a []fe12c) {
+	fp2, t0, t1, one := e.fp2(), e.t2[0], e.t2[1], e.t2[2].one()
+	num := make([]fe2, len(a))
+	den := make([]fe2, len(a))
+	for i := range a {
+		a01, a02, a10, a12 := &a[i][0], &a[i][1], &a[i][2], &a[i][3]
+		switch {
+		case !a10.isZero():
+			// a11 = (nr * a12^2 + 3 * a01^2 - 2 * a02) / (4 * a10)
+			fp2.square(t0, a01)
+			fp2Sub(&num[i], t0, a02)
+			fp2DoubleAssign(&num[i])
+			fp2AddAssign(&num[i], t0)
+			fp2.square(t0, a12)
+			mulByNonResidueAssign(t0)
+			fp2AddAssign(&num[i], t0)
+			fp2Double(&den[i], a10)
+			fp2DoubleAssign(&den[i])
+		case !a02.isZero():
+			// a11 = 2 * a01 * a12 / a02
+			fp2.mul(&num[i], a01, a12)
+			fp2DoubleAssign(&num[i])
+			den[i].set(a02)
+		}
+		// a10 and a02 are zero only for one, for which denominator is left
+		// zero and skipped by batch inversion
+	}
+	fp2.inverseBatch(den)
+	for i := range a {
+		a01, a02, a10, a12 := &a[i][0], &a[i][1], &a[i][2], &a[i][3]
+		if den[i].isZero() {
+			c[i].one()
+			continue
+		}
+		a11 := &c[i][1][1]
+		fp2.mul(a11, &num[i], &den[i])
+
+		// a00 = nr * (2 * a11^2 + a10 * a12 - 3 * a02 * a01) + 1
+		fp2.mul(t0, a02, a01)
+		fp2.square(t1, a11)
+		fp2SubAssign(t1, t0)
+		fp2DoubleAssign(t1)
+		fp2SubAssign(t1, t0)
+		fp2.mul(t0, a10, a12)
+		fp2AddAssign(t1, t0)
+		mulByNonResidue(&c[i][0][0], t1)
+		fp2AddAssign(&c[i][0][0], one)
+		c[i][0][1].set(a01)
+		c[i][0][2].set(a02)
+		c[i][1][0].set(a10)
+		c[i][1][2].set(a12)
+	}
+}
+
+func (e *fp12) frobeniusMap(a *fe12, power int) {
+	fp6, fp2 := e.fp6, e.fp6.fp2
+	fp6.frobeniusMap(&a[0], power)
+	fp6.frobeniusMap(&a[1], power)
+	fp2.mulAssign(&a[1][0], &frobeniusCoeffs12[power%12])
+	fp2.mulAssign(&a[1][1], &frobeniusCoeffs12[power%12])
+	fp2.mulAssign(&a[1][2], &frobeniusCoeffs12[power%12])
+}
+
 func (e *fp12) frobeniusMap1(a *fe12) {
 	fp6, fp2 := e.fp6, e.fp6.fp2
 	fp6.frobeniusMap1(&a[0])
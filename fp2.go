@@ -6,7 +6,7 @@ import (
 )
 
 type fp2Temp struct {
-	t [3]*Fe
+	t [3]*Fp
 	w *wfe2
 }
 
@@ -15,9 +15,9 @@ type fp2 struct {
 }
 
 func newFp2Temp() fp2Temp {
-	t := [3]*Fe{}
+	t := [3]*Fp{}
 	for i := 0; i < len(t); i++ {
-		t[i] = &Fe{}
+		t[i] = &Fp{}
 	}
 	return fp2Temp{t, &wfe2{}}
 }
@@ -31,15 +31,20 @@ func (e *fp2) fromBytes(in []byte) (*fe2, error) {
 	if len(in) != 2*fpByteSize {
 		return nil, errors.New("input string must be equal to 96 bytes")
 	}
-	c1, err := fromBytes(in[:fpByteSize])
-	if err != nil {
+	c := new(fe2)
+	if err := e.fromBytesInto(c, in); err != nil {
 		return nil, err
 	}
-	c0, err := fromBytes(in[fpByteSize:])
-	if err != nil {
-		return nil, err
+	return c, nil
+}
+
+// fromBytesInto decodes 96 bytes input into given element.
+// Input length must be checked by the caller.
+func (e *fp2) fromBytesInto(c *fe2, in []byte) error {
+	if err := fromBytesInto(&c[1], in[:fpByteSize]); err != nil {
+		return err
 	}
-	return &fe2{*c0, *c1}, nil
+	return fromBytesInto(&c[0], in[fpByteSize:])
 }
 
 func (e *fp2) toBytes(a *fe2) []byte {
@@ -71,6 +76,25 @@ func fp2Conjugate(c, a *fe2) {
 	neg(&c[1], &a[1])
 }
 
+// wfp2Conjugate conjugates an element in wide form.
+func wfp2Conjugate(c, a *wfe2) {
+	c[0].set(&a[0])
+	wsub(&c[1], &wfe{}, &a[1])
+}
+
+// norm calculates a0^2 + a1^2 with a single reduction.
+func (e *fp2) norm(c *Fp, a *fe2) {
+	e.wnorm(&e.w[0], a)
+	fromWide(c, &e.w[0])
+}
+
+// wnorm calculates a0^2 + a1^2 in wide form.
+func (e *fp2) wnorm(c *wfe, a *fe2) {
+	wmul(c, &a[0], &a[0])
+	wmul(&e.w[1], &a[1], &a[1])
+	wadd(c, c, &e.w[1])
+}
+
 func (e *fp2) mul(c, a, b *fe2) {
 	wfp2Mul(e.w, b, a)
 	c.fromWide(e.w)
@@ -102,12 +126,12 @@ func (e *fp2) squareAssign(a *fe2) {
 	mul(&a[1], t[2], &a[1])
 }
 
-func (e *fp2) mul0(c, a *fe2, b *Fe) {
+func (e *fp2) mul0(c, a *fe2, b *Fp) {
 	mul(&c[0], &a[0], b)
 	mul(&c[1], &a[1], b)
 }
 
-func (e *fp2) mul0Assign(a *fe2, b *Fe) {
+func (e *fp2) mul0Assign(a *fe2, b *Fp) {
 	mul(&a[0], &a[0], b)
 	mul(&a[1], &a[1], b)
 }
@@ -129,9 +153,7 @@ func (e *fp2) inverse(c, a *fe2) {
 	// Guide to Pairing Based Cryptography
 	// Algorithm 5.16
 
-	square(t[0], &a[0])     // a0^2
-	square(t[1], &a[1])     // a1^2
-	addAssign(t[0], t[1])   // a0^2 + a1^2
+	e.norm(t[0], a)         // a0^2 + a1^2
 	inverse(t[0], t[0])     // (a0^2 + a1^2)^-1
 	mul(&c[0], &a[0], t[0]) // c0 = a0(a0^2 + a1^2)^-1
 	mul(t[0], t[0], &a[1])  // a1(a0^2 + a1^2)^-1
@@ -232,8 +254,82 @@ func (e *fp2) sqrt(c, a *fe2) bool {
 	return alpha.equal(u)
 }
 
+// sqrtComplex calculates a square root with the complex method. Since
+// u^2 = -1, a root of a0 + a1*u is x0 + x1*u where x0^2 = (a0 ± sqrt(norm(a))) / 2
+// and x1 = a1 / 2x0, which costs two base field square roots and an inversion.
+// Square root computation over even extension fields, Adj and Rodríguez-Henríquez
+// https://eprint.iacr.org/2012/685.pdf Algorithm 8
+func (e *fp2) sqrtComplex(c, a *fe2) bool {
+	t := e.t
+	if a[1].isZero() {
+		// a0 or -a0 is a residue since -1 is not
+		if sqrt(t[0], &a[0]) {
+			c[0].set(t[0])
+			c[1].zero()
+			return true
+		}
+		neg(t[0], &a[0])
+		sqrt(&c[1], t[0])
+		c[0].zero()
+		return true
+	}
+	e.norm(t[0], a)
+	if !sqrt(t[0], t[0]) {
+		return false
+	}
+	add(t[1], &a[0], t[0])
+	mul(t[1], t[1], twoInv)
+	if !sqrt(t[2], t[1]) {
+		sub(t[1], &a[0], t[0])
+		mul(t[1], t[1], twoInv)
+		sqrt(t[2], t[1])
+	}
+	double(t[1], t[2])
+	inverse(t[1], t[1])
+	mul(&c[1], &a[1], t[1])
+	c[0].set(t[2])
+	return true
+}
+
+// sqrtTonelliShanks calculates a square root with Tonelli-Shanks algorithm
+// over p^2 - 1 = 2^3 * t, as Fr.RedSqrt does over r - 1. It is slower than
+// sqrtComplex and is kept as an independent method to cross check the others.
+func (e *fp2) sqrtTonelliShanks(c, a *fe2) bool {
+	if a.isZero() {
+		c.zero()
+		return true
+	}
+	w, x, b, z, t := &fe2{}, &fe2{}, &fe2{}, &fe2{}, &fe2{}
+	z.set(p2RootOfUnity)
+	// x = a^((t + 1) / 2), b = a^t
+	e.exp(w, a, p2SqrtExp)
+	e.mul(x, a, w)
+	e.mul(b, x, w)
+	m := p2TwoAdicity
+	for !b.isOne() {
+		// find least i such that b^(2^i) = 1
+		i := 0
+		for t.set(b); !t.isOne(); i++ {
+			e.square(t, t)
+		}
+		if i == m {
+			return false
+		}
+		t.set(z)
+		for j := 0; j < m-i-1; j++ {
+			e.square(t, t)
+		}
+		e.square(z, t)
+		e.mul(x, x, t)
+		e.mul(b, b, z)
+		m = i
+	}
+	c.set(x)
+	return true
+}
+
 func (e *fp2) isQuadraticNonResidue(a *fe2) bool {
-	c0, c1 := new(Fe), new(Fe)
+	c0, c1 := new(Fp), new(Fp)
 	square(c0, &a[0])
 	square(c1, &a[1])
 	add(c1, c1, c0)
@@ -244,7 +340,7 @@ func (e *fp2) isQuadraticNonResidue(a *fe2) bool {
 // https://github.com/supranational/blst/blob/master/src/sqrt.c
 
 func (e *fp2) sqrtBLST(out, inp *fe2) bool {
-	aa, bb := new(Fe), new(Fe)
+	aa, bb := new(Fp), new(Fp)
 	ret := new(fe2)
 	square(aa, &inp[0])
 	square(bb, &inp[1])
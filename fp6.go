@@ -370,6 +370,10 @@ func (e *fp6) mulAssign(a, b *fe6) {
 
 func (e *fp6) square(c, a *fe6) {
 	wt, t := e.wt, e.t
+	// Asymmetric Squaring Formulae, Chung and Hasan
+	// SQR2
+	// http://cacr.uwaterloo.ca/techreports/2006/cacr2006-24.pdf
+
 	wfp2Square(wt[0], &a[0])
 	wfp2Mul(wt[1], &a[0], &a[1])
 	wfp2DoubleAssign(wt[1])
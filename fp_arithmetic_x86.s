@@ -482,7 +482,10 @@ TEXT ·ldouble(SB), NOSPLIT, $0-16
 /*	 | end													*/
 
 
-TEXT ·_neg(SB), NOSPLIT, $0-16
+// negation with modular reduction
+// c = (p - a) % p
+// result is masked to zero without branching if a is zero
+TEXT ·neg(SB), NOSPLIT, $0-16
 	// |
 	MOVQ a+8(FP), DI
 
@@ -500,8 +503,59 @@ TEXT ·_neg(SB), NOSPLIT, $0-16
 	SBBQ 32(DI), R12
 	SBBQ 40(DI), R13
 
+	// | a == 0 ?
+	XORQ BX, BX
+	MOVQ (DI), AX
+	ORQ 8(DI), AX
+	ORQ 16(DI), AX
+	ORQ 24(DI), AX
+	ORQ 32(DI), AX
+	ORQ 40(DI), AX
+	CMOVQEQ BX, R8
+	CMOVQEQ BX, R9
+	CMOVQEQ BX, R10
+	CMOVQEQ BX, R11
+	CMOVQEQ BX, R12
+	CMOVQEQ BX, R13
+
+	// |
+	MOVQ c+0(FP), DI
+	MOVQ R8, (DI)
+	MOVQ R9, 8(DI)
+	MOVQ R10, 16(DI)
+	MOVQ R11, 24(DI)
+	MOVQ R12, 32(DI)
+	MOVQ R13, 40(DI)
+	RET
+/*	 | end													*/
+
+
+// conditional move
+// c = a if cond != 0, otherwise c is unchanged
+TEXT ·cmov(SB), NOSPLIT, $0-24
 	// |
 	MOVQ c+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ cond+16(FP), AX
+
+	// |
+	MOVQ (DI), R8
+	MOVQ 8(DI), R9
+	MOVQ 16(DI), R10
+	MOVQ 24(DI), R11
+	MOVQ 32(DI), R12
+	MOVQ 40(DI), R13
+
+	// |
+	TESTQ AX, AX
+	CMOVQNE (SI), R8
+	CMOVQNE 8(SI), R9
+	CMOVQNE 16(SI), R10
+	CMOVQNE 24(SI), R11
+	CMOVQNE 32(SI), R12
+	CMOVQNE 40(SI), R13
+
+	// |
 	MOVQ R8, (DI)
 	MOVQ R9, 8(DI)
 	MOVQ R10, 16(DI)
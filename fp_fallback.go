@@ -27,7 +27,7 @@ package bls12381
 
 import "math/bits"
 
-func add(z, x, y *Fe) {
+func add(z, x, y *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(x[0], y[0], 0)
@@ -37,20 +37,10 @@ func add(z, x, y *Fe) {
 	z[4], carry = bits.Add64(x[4], y[4], carry)
 	z[5], _ = bits.Add64(x[5], y[5], carry)
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
-func addAssign(z, y *Fe) {
+func addAssign(z, y *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(z[0], y[0], 0)
@@ -60,20 +50,10 @@ func addAssign(z, y *Fe) {
 	z[4], carry = bits.Add64(z[4], y[4], carry)
 	z[5], _ = bits.Add64(z[5], y[5], carry)
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
-func ladd(z, x, y *Fe) {
+func ladd(z, x, y *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(x[0], y[0], 0)
@@ -84,7 +64,7 @@ func ladd(z, x, y *Fe) {
 	z[5], _ = bits.Add64(x[5], y[5], carry)
 }
 
-func laddAssign(z, y *Fe) {
+func laddAssign(z, y *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(z[0], y[0], 0)
@@ -95,7 +75,7 @@ func laddAssign(z, y *Fe) {
 	z[5], _ = bits.Add64(z[5], y[5], carry)
 }
 
-func double(z, x *Fe) {
+func double(z, x *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(x[0], x[0], 0)
@@ -105,20 +85,10 @@ func double(z, x *Fe) {
 	z[4], carry = bits.Add64(x[4], x[4], carry)
 	z[5], _ = bits.Add64(x[5], x[5], carry)
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
-func doubleAssign(z *Fe) {
+func doubleAssign(z *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(z[0], z[0], 0)
@@ -128,20 +98,10 @@ func doubleAssign(z *Fe) {
 	z[4], carry = bits.Add64(z[4], z[4], carry)
 	z[5], _ = bits.Add64(z[5], z[5], carry)
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
-func ldouble(z, x *Fe) {
+func ldouble(z, x *Fp) {
 	var carry uint64
 
 	z[0], carry = bits.Add64(x[0], x[0], 0)
@@ -152,7 +112,7 @@ func ldouble(z, x *Fe) {
 	z[5], _ = bits.Add64(x[5], x[5], carry)
 }
 
-func sub(z, x, y *Fe) {
+func sub(z, x, y *Fp) {
 	var b uint64
 	z[0], b = bits.Sub64(x[0], y[0], 0)
 	z[1], b = bits.Sub64(x[1], y[1], b)
@@ -160,18 +120,19 @@ func sub(z, x, y *Fe) {
 	z[3], b = bits.Sub64(x[3], y[3], b)
 	z[4], b = bits.Sub64(x[4], y[4], b)
 	z[5], b = bits.Sub64(x[5], y[5], b)
-	if b != 0 {
-		var c uint64
-		z[0], c = bits.Add64(z[0], 13402431016077863595, 0)
-		z[1], c = bits.Add64(z[1], 2210141511517208575, c)
-		z[2], c = bits.Add64(z[2], 7435674573564081700, c)
-		z[3], c = bits.Add64(z[3], 7239337960414712511, c)
-		z[4], c = bits.Add64(z[4], 5412103778470702295, c)
-		z[5], _ = bits.Add64(z[5], 1873798617647539866, c)
-	}
+
+	// z += q & -b
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(z[0], 13402431016077863595&mask, 0)
+	z[1], c = bits.Add64(z[1], 2210141511517208575&mask, c)
+	z[2], c = bits.Add64(z[2], 7435674573564081700&mask, c)
+	z[3], c = bits.Add64(z[3], 7239337960414712511&mask, c)
+	z[4], c = bits.Add64(z[4], 5412103778470702295&mask, c)
+	z[5], _ = bits.Add64(z[5], 1873798617647539866&mask, c)
 }
 
-func subAssign(z, y *Fe) {
+func subAssign(z, y *Fp) {
 	var b uint64
 	z[0], b = bits.Sub64(z[0], y[0], 0)
 	z[1], b = bits.Sub64(z[1], y[1], b)
@@ -179,18 +140,19 @@ func subAssign(z, y *Fe) {
 	z[3], b = bits.Sub64(z[3], y[3], b)
 	z[4], b = bits.Sub64(z[4], y[4], b)
 	z[5], b = bits.Sub64(z[5], y[5], b)
-	if b != 0 {
-		var c uint64
-		z[0], c = bits.Add64(z[0], 13402431016077863595, 0)
-		z[1], c = bits.Add64(z[1], 2210141511517208575, c)
-		z[2], c = bits.Add64(z[2], 7435674573564081700, c)
-		z[3], c = bits.Add64(z[3], 7239337960414712511, c)
-		z[4], c = bits.Add64(z[4], 5412103778470702295, c)
-		z[5], _ = bits.Add64(z[5], 1873798617647539866, c)
-	}
+
+	// z += q & -b
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(z[0], 13402431016077863595&mask, 0)
+	z[1], c = bits.Add64(z[1], 2210141511517208575&mask, c)
+	z[2], c = bits.Add64(z[2], 7435674573564081700&mask, c)
+	z[3], c = bits.Add64(z[3], 7239337960414712511&mask, c)
+	z[4], c = bits.Add64(z[4], 5412103778470702295&mask, c)
+	z[5], _ = bits.Add64(z[5], 1873798617647539866&mask, c)
 }
 
-func lsubAssign(z, y *Fe) {
+func lsubAssign(z, y *Fp) {
 	var b uint64
 	z[0], b = bits.Sub64(z[0], y[0], 0)
 	z[1], b = bits.Sub64(z[1], y[1], b)
@@ -200,11 +162,10 @@ func lsubAssign(z, y *Fe) {
 	z[5], b = bits.Sub64(z[5], y[5], b)
 }
 
-func neg(z, x *Fe) {
-	if x.isZero() {
-		z.zero()
-		return
-	}
+func neg(z, x *Fp) {
+	// z = (q - x) & -(x != 0)
+	nz := x[0] | x[1] | x[2] | x[3] | x[4] | x[5]
+	mask := -((nz | -nz) >> 63)
 	var borrow uint64
 	z[0], borrow = bits.Sub64(13402431016077863595, x[0], 0)
 	z[1], borrow = bits.Sub64(2210141511517208575, x[1], borrow)
@@ -212,9 +173,48 @@ func neg(z, x *Fe) {
 	z[3], borrow = bits.Sub64(7239337960414712511, x[3], borrow)
 	z[4], borrow = bits.Sub64(5412103778470702295, x[4], borrow)
 	z[5], _ = bits.Sub64(1873798617647539866, x[5], borrow)
+	z[0] &= mask
+	z[1] &= mask
+	z[2] &= mask
+	z[3] &= mask
+	z[4] &= mask
+	z[5] &= mask
+}
+
+// reduce subtracts the modulus from z if z >= q, given z < 2q.
+// Subtraction is always performed and the result is selected with a mask
+// derived from the final borrow so that no branch depends on the value of z.
+func reduce(z *Fp) {
+	var t Fp
+	var b uint64
+	t[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
+	t[1], b = bits.Sub64(z[1], 2210141511517208575, b)
+	t[2], b = bits.Sub64(z[2], 7435674573564081700, b)
+	t[3], b = bits.Sub64(z[3], 7239337960414712511, b)
+	t[4], b = bits.Sub64(z[4], 5412103778470702295, b)
+	t[5], b = bits.Sub64(z[5], 1873798617647539866, b)
+
+	// keep z if subtraction borrowed, otherwise take z - q
+	mask := b - 1
+	z[0] ^= (z[0] ^ t[0]) & mask
+	z[1] ^= (z[1] ^ t[1]) & mask
+	z[2] ^= (z[2] ^ t[2]) & mask
+	z[3] ^= (z[3] ^ t[3]) & mask
+	z[4] ^= (z[4] ^ t[4]) & mask
+	z[5] ^= (z[5] ^ t[5]) & mask
+}
+
+func cmov(z, x *Fp, cond uint64) {
+	mask := -((cond | -cond) >> 63)
+	z[0] ^= (z[0] ^ x[0]) & mask
+	z[1] ^= (z[1] ^ x[1]) & mask
+	z[2] ^= (z[2] ^ x[2]) & mask
+	z[3] ^= (z[3] ^ x[3]) & mask
+	z[4] ^= (z[4] ^ x[4]) & mask
+	z[5] ^= (z[5] ^ x[5]) & mask
 }
 
-func mul(z, x, y *Fe) {
+func mul(z, x, y *Fp) {
 
 	var t [6]uint64
 	var c [3]uint64
@@ -321,20 +321,10 @@ func mul(z, x, y *Fe) {
 		z[5], z[4] = madd3(m, 1873798617647539866, c[0], c[2], c[1])
 	}
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
-func square(z, x *Fe) {
+func square(z, x *Fp) {
 
 	var t [6]uint64
 	var c [3]uint64
@@ -441,17 +431,7 @@ func square(z, x *Fe) {
 		z[5], z[4] = madd3(m, 1873798617647539866, c[0], c[2], c[1])
 	}
 
-	// if z > q --> z -= q
-	// note: this is NOT constant time
-	if !(z[5] < 1873798617647539866 || (z[5] == 1873798617647539866 && (z[4] < 5412103778470702295 || (z[4] == 5412103778470702295 && (z[3] < 7239337960414712511 || (z[3] == 7239337960414712511 && (z[2] < 7435674573564081700 || (z[2] == 7435674573564081700 && (z[1] < 2210141511517208575 || (z[1] == 2210141511517208575 && (z[0] < 13402431016077863595))))))))))) {
-		var b uint64
-		z[0], b = bits.Sub64(z[0], 13402431016077863595, 0)
-		z[1], b = bits.Sub64(z[1], 2210141511517208575, b)
-		z[2], b = bits.Sub64(z[2], 7435674573564081700, b)
-		z[3], b = bits.Sub64(z[3], 7239337960414712511, b)
-		z[4], b = bits.Sub64(z[4], 5412103778470702295, b)
-		z[5], _ = bits.Sub64(z[5], 1873798617647539866, b)
-	}
+	reduce(z)
 }
 
 func wadd(z, x, y *wfe) {
@@ -603,11 +583,11 @@ func lwdouble(z, x *wfe) {
 	z[11], _ = bits.Add64(x[11], x[11], carry)
 }
 
-func fromWide(c *Fe, w *wfe) {
+func fromWide(c *Fp, w *wfe) {
 	montRed(c, w)
 }
 
-func wmul(w *wfe, a, b *Fe) {
+func wmul(w *wfe, a, b *Fp) {
 
 	var w0, w1, w2, w3, w4, w5, w6, w7, w8, w9, w10, w11 uint64
 	var a0 = a[0]
@@ -884,7 +864,7 @@ func wmul(w *wfe, a, b *Fe) {
 	w[11] = w11
 }
 
-func montRed(c *Fe, w *wfe) {
+func montRed(c *Fp, w *wfe) {
 
 	// Reduces T as T (R^-1) modp
 	// Handbook of Applied Cryptography
@@ -1281,14 +1261,14 @@ func fp2SubAssign(c, a *fe2) {
 }
 
 func mulByNonResidue(c, a *fe2) {
-	t := new(Fe)
+	t := new(Fp)
 	sub(t, &a[0], &a[1])
 	add(&c[1], &a[0], &a[1])
 	c[0].set(t)
 }
 
 func mulByNonResidueAssign(a *fe2) {
-	t := new(Fe)
+	t := new(Fp)
 	sub(t, &a[0], &a[1])
 	add(&a[1], &a[0], &a[1])
 	a[0].set(t)
@@ -1368,5 +1348,10 @@ func wfp2MulByNonResidueAssign(a *wfe2) {
 	a[1].set(wt0)
 }
 
-var wfp2Mul func(c *wfe2, a, b *fe2) = wfp2MulGeneric
-var wfp2Square func(c *wfe2, a *fe2) = wfp2SquareGeneric
+func wfp2Mul(c *wfe2, a, b *fe2) {
+	wfp2MulGeneric(c, a, b)
+}
+
+func wfp2Square(c *wfe2, a *fe2) {
+	wfp2SquareGeneric(c, a)
+}
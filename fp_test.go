@@ -3,6 +3,7 @@ package bls12381
 import (
 	"bytes"
 	"crypto/rand"
+	"math"
 	"math/big"
 	"testing"
 )
@@ -10,20 +11,20 @@ import (
 func TestFpSerialization(t *testing.T) {
 	t.Run("zero", func(t *testing.T) {
 		in := make([]byte, fpByteSize)
-		Fe, err := fromBytes(in)
+		Fp, err := fromBytes(in)
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !Fe.isZero() {
+		if !Fp.isZero() {
 			t.Fatal("serialization failed")
 		}
-		if !bytes.Equal(in, toBytes(Fe)) {
+		if !bytes.Equal(in, toBytes(Fp)) {
 			t.Fatal("serialization failed")
 		}
 	})
 	t.Run("bytes", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
+			a, _ := new(Fp).rand(rand.Reader)
 			b, err := fromBytes(toBytes(a))
 			if err != nil {
 				t.Fatal(err)
@@ -35,7 +36,7 @@ func TestFpSerialization(t *testing.T) {
 	})
 	t.Run("string", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
+			a, _ := new(Fp).rand(rand.Reader)
 			b, err := fromString(ToString(a))
 			if err != nil {
 				t.Fatal(err)
@@ -47,7 +48,7 @@ func TestFpSerialization(t *testing.T) {
 	})
 	t.Run("big", func(t *testing.T) {
 		for i := 0; i < fuz; i++ {
-			a, _ := new(Fe).rand(rand.Reader)
+			a, _ := new(Fp).rand(rand.Reader)
 			b, err := fromBig(ToBig(a))
 			if err != nil {
 				t.Fatal(err)
@@ -61,9 +62,9 @@ func TestFpSerialization(t *testing.T) {
 
 func TestFpAdditionCrossAgainstBigInt(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
-		c := new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		c := new(Fp)
 		big_a := a.big()
 		big_b := b.big()
 		big_c := new(big.Int)
@@ -96,8 +97,8 @@ func TestFpAdditionCrossAgainstBigInt(t *testing.T) {
 
 func TestFpAdditionCrossAgainstBigIntAssigned(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
 		big_a, big_b := a.big(), b.big()
 		addAssign(a, b)
 		out_1 := a.bytes()
@@ -105,7 +106,7 @@ func TestFpAdditionCrossAgainstBigIntAssigned(t *testing.T) {
 		if !bytes.Equal(out_1, out_2) {
 			t.Fatal("cross test against big.Int is failed A")
 		}
-		a, _ = new(Fe).rand(rand.Reader)
+		a, _ = new(Fp).rand(rand.Reader)
 		big_a = a.big()
 		doubleAssign(a)
 		out_1 = a.bytes()
@@ -113,8 +114,8 @@ func TestFpAdditionCrossAgainstBigIntAssigned(t *testing.T) {
 		if !bytes.Equal(out_1, out_2) {
 			t.Fatal("cross test against big.Int is failed B")
 		}
-		a, _ = new(Fe).rand(rand.Reader)
-		b, _ = new(Fe).rand(rand.Reader)
+		a, _ = new(Fp).rand(rand.Reader)
+		b, _ = new(Fp).rand(rand.Reader)
 		big_a, big_b = a.big(), b.big()
 		subAssign(a, b)
 		out_1 = a.bytes()
@@ -128,10 +129,10 @@ func TestFpAdditionCrossAgainstBigIntAssigned(t *testing.T) {
 func TestFpAdditionProperties(t *testing.T) {
 	for i := 0; i < fuz; i++ {
 
-		zero := new(Fe).zero()
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
-		c1, c2 := new(Fe), new(Fe)
+		zero := new(Fp).zero()
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		c1, c2 := new(Fp), new(Fp)
 		add(c1, a, zero)
 		if !c1.equal(a) {
 			t.Fatal("a + 0 == a")
@@ -169,7 +170,7 @@ func TestFpAdditionProperties(t *testing.T) {
 		if !c1.equal(c2) {
 			t.Fatal("a - b = - ( b - a )")
 		}
-		cx, _ := new(Fe).rand(rand.Reader)
+		cx, _ := new(Fp).rand(rand.Reader)
 		add(c1, a, b)
 		add(c1, c1, cx)
 		add(c2, a, cx)
@@ -189,8 +190,8 @@ func TestFpAdditionProperties(t *testing.T) {
 
 func TestFpAdditionPropertiesAssigned(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		zero := new(Fe).zero()
-		a, b := new(Fe), new(Fe)
+		zero := new(Fp).zero()
+		a, b := new(Fp), new(Fp)
 		_, _ = a.rand(rand.Reader)
 		b.set(a)
 		addAssign(a, zero)
@@ -221,7 +222,7 @@ func TestFpAdditionPropertiesAssigned(t *testing.T) {
 		}
 		_, _ = a.rand(rand.Reader)
 		_, _ = b.rand(rand.Reader)
-		c1, c2 := new(Fe).set(a), new(Fe).set(b)
+		c1, c2 := new(Fp).set(a), new(Fp).set(b)
 		addAssign(c1, b)
 		addAssign(c2, a)
 		if !c1.equal(c2) {
@@ -239,8 +240,8 @@ func TestFpAdditionPropertiesAssigned(t *testing.T) {
 		}
 		_, _ = a.rand(rand.Reader)
 		_, _ = b.rand(rand.Reader)
-		c, _ := new(Fe).rand(rand.Reader)
-		a0 := new(Fe).set(a)
+		c, _ := new(Fp).rand(rand.Reader)
+		a0 := new(Fp).set(a)
 		addAssign(a, b)
 		addAssign(a, c)
 		addAssign(b, c)
@@ -264,11 +265,11 @@ func TestFpAdditionPropertiesAssigned(t *testing.T) {
 
 func TestFpLazyOperations(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
-		c, _ := new(Fe).rand(rand.Reader)
-		c0 := new(Fe)
-		c1 := new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		c, _ := new(Fp).rand(rand.Reader)
+		c0 := new(Fp)
+		c1 := new(Fp)
 		ladd(c0, a, b)
 		add(c1, a, b)
 		mul(c0, c0, c)
@@ -286,7 +287,7 @@ func TestFpLazyOperations(t *testing.T) {
 		_, _ = a.rand(rand.Reader)
 		_, _ = b.rand(rand.Reader)
 		_, _ = c.rand(rand.Reader)
-		a0 := new(Fe).set(a)
+		a0 := new(Fp).set(a)
 		lsubAssign(a, b)
 		laddAssign(a, &modulus)
 		mul(a, a, c)
@@ -298,11 +299,120 @@ func TestFpLazyOperations(t *testing.T) {
 	}
 }
 
+func TestFpComparison(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		if a.CmpCT(b) != a.cmp(b) || b.CmpCT(a) != b.cmp(a) {
+			t.Fatal("constant time comparison failed")
+		}
+		if a.CmpCT(a) != 0 {
+			t.Fatal("constant time comparison failed")
+		}
+		b.set(a)
+		b[0] ^= 1
+		if a.CmpCT(b) != a.cmp(b) {
+			t.Fatal("constant time comparison failed")
+		}
+	}
+	if !new(Fp).isValid() || modulus.isValid() {
+		t.Fatal("canonical check failed")
+	}
+}
+
+func TestFpReductionBoundaries(t *testing.T) {
+	one, two := &Fp{1}, &Fp{2}
+	qMinusOne := new(Fp).set(&modulus)
+	lsubAssign(qMinusOne, one)
+	qMinusTwo := new(Fp).set(&modulus)
+	lsubAssign(qMinusTwo, two)
+	c := new(Fp)
+	add(c, qMinusOne, one)
+	if !c.isZero() {
+		t.Fatal("(q - 1) + 1 == 0")
+	}
+	add(c, qMinusOne, qMinusOne)
+	if !c.equal(qMinusTwo) {
+		t.Fatal("(q - 1) + (q - 1) == q - 2")
+	}
+	double(c, qMinusOne)
+	if !c.equal(qMinusTwo) {
+		t.Fatal("2 * (q - 1) == q - 2")
+	}
+	add(c, qMinusTwo, one)
+	if !c.equal(qMinusOne) {
+		t.Fatal("(q - 2) + 1 == q - 1")
+	}
+	sub(c, new(Fp), one)
+	if !c.equal(qMinusOne) {
+		t.Fatal("0 - 1 == q - 1")
+	}
+	sub(c, one, one)
+	if !c.isZero() {
+		t.Fatal("1 - 1 == 0")
+	}
+}
+
+func TestFpHalve(t *testing.T) {
+	p := modulus.big()
+	twoInv := new(big.Int).ModInverse(big.NewInt(2), p)
+	inputs := []*Fp{new(Fp), new(Fp).One(), new(Fp).SetInt64(-1), new(Fp).SetUint64(2)}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp).Rand(rand.Reader)
+		inputs = append(inputs, a)
+	}
+	for _, a := range inputs {
+		c := new(Fp)
+		c.Halve(a)
+		expected := new(big.Int).Mul(a.Big(), twoInv)
+		if c.Big().Cmp(expected.Mod(expected, p)) != 0 {
+			t.Fatal("bad halving", a)
+		}
+		// in place
+		c.Double(c)
+		c.Halve(c)
+		c.Double(c)
+		if !c.Equal(a) {
+			t.Fatal("2(a/2) == a", a)
+		}
+	}
+}
+
+func TestFpNegationAndConditionalMove(t *testing.T) {
+	zero := new(Fp).zero()
+	c := new(Fp)
+	neg(c, zero)
+	if !c.isZero() {
+		t.Fatal("-0 == 0")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		neg(c, a)
+		add(c, c, a)
+		if !c.isZero() {
+			t.Fatal("a + (-a) == 0")
+		}
+		c.set(a)
+		cmov(c, b, 0)
+		if !c.equal(a) {
+			t.Fatal("move with zero condition")
+		}
+		for _, cond := range []uint64{1, 2, 1 << 63, ^uint64(0)} {
+			c.set(a)
+			cmov(c, b, cond)
+			if !c.equal(b) {
+				t.Fatal("move with non zero condition")
+			}
+		}
+	}
+}
+
 func TestFpMultiplicationCrossAgainstBigInt(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
-		c := new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		c := new(Fp)
 		big_a := ToBig(a)
 		big_b := ToBig(b)
 		big_c := new(big.Int)
@@ -317,10 +427,10 @@ func TestFpMultiplicationCrossAgainstBigInt(t *testing.T) {
 
 func TestFpMultiplicationProperties(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
-		zero, one := new(Fe).zero(), new(Fe).one()
-		c1, c2 := new(Fe), new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
+		zero, one := new(Fp).zero(), new(Fp).one()
+		c1, c2 := new(Fp), new(Fp)
 		mul(c1, a, zero)
 		if !c1.equal(zero) {
 			t.Fatal("a * 0 == 0")
@@ -334,7 +444,7 @@ func TestFpMultiplicationProperties(t *testing.T) {
 		if !c1.equal(c2) {
 			t.Fatal("a * b == b * a")
 		}
-		cx, _ := new(Fe).rand(rand.Reader)
+		cx, _ := new(Fp).rand(rand.Reader)
 		mul(c1, a, b)
 		mul(c1, c1, cx)
 		mul(c2, cx, b)
@@ -361,8 +471,8 @@ func TestFpMultiplicationProperties(t *testing.T) {
 
 func TestFpExponentiation(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		u := new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		u := new(Fp)
 		exp(u, a, big.NewInt(0))
 		if !u.isOne() {
 			t.Fatal("a^0 == 1")
@@ -371,7 +481,7 @@ func TestFpExponentiation(t *testing.T) {
 		if !u.equal(a) {
 			t.Fatal("a^1 == a")
 		}
-		v := new(Fe)
+		v := new(Fp)
 		mul(u, a, a)
 		mul(u, u, u)
 		mul(u, u, u)
@@ -391,10 +501,46 @@ func TestFpExponentiation(t *testing.T) {
 	}
 }
 
+func TestFpExpLimbs(t *testing.T) {
+	one := big.NewInt(1)
+	p := modulus.big()
+	exponents := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(16),
+		new(big.Int).Lsh(one, 64),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 64), one),
+		new(big.Int).Sub(p, one),
+		new(big.Int).Lsh(p, 200),
+	}
+	for i := 0; i < fuz; i++ {
+		exponents = append(exponents, randScalar(new(big.Int).Lsh(one, 512)))
+	}
+	a, _ := NewFp().Rand(rand.Reader)
+	for _, s := range exponents {
+		expected := new(big.Int).Exp(a.Big(), s, p)
+		u := NewFp()
+		u.Exp(a, s)
+		if u.Big().Cmp(expected) != 0 {
+			t.Fatal("exponentiation failed", s)
+		}
+		u.ExpLimbs(a, bigToLimbs(s))
+		if u.Big().Cmp(expected) != 0 {
+			t.Fatal("exponentiation by limbs failed", s)
+		}
+	}
+	u, aa := NewFp(), NewFp()
+	u.ExpLimbs(a, []uint64{2, 0, 0})
+	aa.Square(a)
+	if !u.Equal(aa) {
+		t.Fatal("leading zero limbs must be ignored")
+	}
+}
+
 func TestFpInversion(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		u := new(Fe)
-		zero, one := new(Fe).zero(), new(Fe).one()
+		u := new(Fp)
+		zero, one := new(Fp).zero(), new(Fp).one()
 		inverse(u, zero)
 		if !u.equal(zero) {
 			t.Fatal("(0^-1) == 0)")
@@ -403,13 +549,13 @@ func TestFpInversion(t *testing.T) {
 		if !u.equal(one) {
 			t.Fatal("(1^-1) == 1)")
 		}
-		a, _ := new(Fe).rand(rand.Reader)
+		a, _ := new(Fp).rand(rand.Reader)
 		inverse(u, a)
 		mul(u, u, a)
 		if !u.equal(one) {
 			t.Fatal("(r*a) * r*(a^-1) == r)")
 		}
-		v := new(Fe)
+		v := new(Fp)
 		p := modulus.big()
 		exp(u, a, p.Sub(p, big.NewInt(2)))
 		inverse(v, a)
@@ -422,11 +568,11 @@ func TestFpInversion(t *testing.T) {
 func TestFpBatchInversion(t *testing.T) {
 	n := 20
 	for i := 0; i < n; i++ {
-		e0 := make([]Fe, n)
-		e1 := make([]Fe, n)
+		e0 := make([]Fp, n)
+		e1 := make([]Fp, n)
 		for j := 0; j < n; j++ {
 			if j != i {
-				e, err := new(Fe).rand(rand.Reader)
+				e, err := new(Fp).rand(rand.Reader)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -444,13 +590,349 @@ func TestFpBatchInversion(t *testing.T) {
 	}
 }
 
+func TestFpInverseVarTime(t *testing.T) {
+	p := modulus.big()
+	for _, a := range []*Fp{
+		new(Fp).One(), new(Fp).SetUint64(2), new(Fp).SetInt64(-1), new(Fp).SetBigReduce(new(big.Int).Rsh(p, 1)),
+	} {
+		c := new(Fp)
+		if !c.InverseVarTime(a) || c.Big().Cmp(new(big.Int).ModInverse(a.Big(), p)) != 0 {
+			t.Fatal("bad inverse", a)
+		}
+		// in place
+		c.Set(a)
+		c.InverseVarTime(c)
+		c.InverseVarTime(c)
+		if !c.Equal(a) {
+			t.Fatal("double inversion must be identity", a)
+		}
+	}
+}
+
+func TestFpBatchInverse(t *testing.T) {
+	n := fuz + 2
+	in, expected := make([]Fp, n), make([]Fp, n)
+	for i := 1; i < n; i++ {
+		e, _ := NewFp().Rand(rand.Reader)
+		in[i].Set(e)
+		expected[i].Inverse(e)
+	}
+	in[n-1].Zero()
+	expected[n-1].Zero()
+	BatchInverse(in)
+	for i := range in {
+		if !in[i].Equal(&expected[i]) {
+			t.Fatal("batch inversion failed", i)
+		}
+	}
+	BatchInverse(nil)
+}
+
+func TestFpNormalizeBatch(t *testing.T) {
+	n := fuz + 3
+	in := make([]Fp, n)
+	in[1].one()
+	in[2].set(new(Fp).setBig(new(big.Int).Sub(modulus.big(), big.NewInt(1))))
+	for i := 3; i < n; i++ {
+		e, _ := new(Fp).rand(rand.Reader)
+		in[i].set(e)
+	}
+	expected := make([]Fp, n)
+	for i := range in {
+		fromMont(&expected[i], &in[i])
+	}
+	NormalizeBatchFp(in)
+	for i := range in {
+		if in[i] != expected[i] {
+			t.Fatal("batch normalization failed", i)
+		}
+	}
+}
+
+func TestFpExportedAPI(t *testing.T) {
+	p := modulus.big()
+	for i := 0; i < fuz; i++ {
+		a, _ := NewFp().Rand(rand.Reader)
+		b, _ := NewFp().Rand(rand.Reader)
+		aBig, bBig := a.Big(), b.Big()
+		c, z := NewFp(), new(big.Int)
+		c.Add(a, b)
+		if c.Big().Cmp(z.Mod(z.Add(aBig, bBig), p)) != 0 {
+			t.Fatal("add")
+		}
+		c.Sub(a, b)
+		if c.Big().Cmp(z.Mod(z.Sub(aBig, bBig), p)) != 0 {
+			t.Fatal("sub")
+		}
+		c.Double(a)
+		if c.Big().Cmp(z.Mod(z.Add(aBig, aBig), p)) != 0 {
+			t.Fatal("double")
+		}
+		c.Neg(a)
+		if c.Big().Cmp(z.Mod(z.Neg(aBig), p)) != 0 {
+			t.Fatal("neg")
+		}
+		c.Mul(a, b)
+		if c.Big().Cmp(z.Mod(z.Mul(aBig, bBig), p)) != 0 {
+			t.Fatal("mul")
+		}
+		c.Square(a)
+		if c.Big().Cmp(z.Mod(z.Mul(aBig, aBig), p)) != 0 {
+			t.Fatal("square")
+		}
+		if !c.Inverse(a) || c.Big().Cmp(z.ModInverse(aBig, p)) != 0 {
+			t.Fatal("inverse")
+		}
+		if c.Inverse(NewFp()) || !c.IsZero() {
+			t.Fatal("inversion of zero must be rejected")
+		}
+		if !c.InverseVarTime(a) || c.Big().Cmp(z.ModInverse(aBig, p)) != 0 {
+			t.Fatal("variable time inverse")
+		}
+		if c.InverseVarTime(NewFp()) || !c.IsZero() {
+			t.Fatal("variable time inversion of zero must be rejected")
+		}
+		c.Exp(a, bBig)
+		if c.Big().Cmp(z.Exp(aBig, bBig, p)) != 0 {
+			t.Fatal("exp")
+		}
+		aa := NewFp()
+		aa.Square(a)
+		if !aa.IsSquare() || !c.Sqrt(aa) {
+			t.Fatal("a^2 must be a square")
+		}
+		c.Square(c)
+		if !c.Equal(aa) {
+			t.Fatal("sqrt(a^2)^2 == a^2")
+		}
+		if (big.Jacobi(aBig, p) == 1) != a.IsSquare() {
+			t.Fatal("is square")
+		}
+
+		d, err := NewFp().SetBytes(a.Bytes())
+		if err != nil || !d.Equal(a) {
+			t.Fatal("bytes round trip")
+		}
+		d, err = NewFp().SetBig(aBig)
+		if err != nil || !d.Equal(a) {
+			t.Fatal("big round trip")
+		}
+		le := a.BytesLE()
+		be := a.Bytes()
+		for j := range le {
+			if le[j] != be[fpByteSize-1-j] {
+				t.Fatal("little endian bytes must be reversed big endian bytes")
+			}
+		}
+		d, err = NewFp().SetBytesLE(le)
+		if err != nil || !d.Equal(a) {
+			t.Fatal("little endian bytes round trip")
+		}
+	}
+	if !NewFp().SetUint64(1).IsOne() || !NewFp().One().IsOne() || !NewFp().IsZero() {
+		t.Fatal("constants")
+	}
+	if NewFp().SetUint64(7).Big().Int64() != 7 {
+		t.Fatal("set uint64")
+	}
+	for _, n := range []int64{0, 1, -1, 7, -7, math.MaxInt64, math.MinInt64} {
+		want, _ := NewFp().SetBig(new(big.Int).Mod(big.NewInt(n), p))
+		if !NewFp().SetInt64(n).Equal(want) {
+			t.Fatal("set int64", n)
+		}
+	}
+	for _, a := range []*big.Int{
+		big.NewInt(-1),
+		new(big.Int).Neg(p),
+		new(big.Int).Add(p, big.NewInt(5)),
+		new(big.Int).Lsh(p, 700),
+		new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(3), 1000)),
+	} {
+		want := new(big.Int).Mod(a, p)
+		if NewFp().SetBigReduce(a).Big().Cmp(want) != 0 {
+			t.Fatal("set big reduce", a)
+		}
+	}
+	if c := NewFp(); !c.Sqrt(NewFp()) || !c.IsZero() {
+		t.Fatal("sqrt of zero")
+	}
+	if _, err := NewFp().SetBig(p); err != ErrNonCanonical {
+		t.Fatal("non canonical big must be rejected")
+	}
+	if _, err := NewFp().SetBytes(modulus.bytes()); err != ErrNonCanonical {
+		t.Fatal("non canonical bytes must be rejected")
+	}
+	pLE := modulus.bytes()
+	reverseBytes(pLE)
+	if _, err := NewFp().SetBytesLE(pLE); err != ErrNonCanonical {
+		t.Fatal("non canonical little endian bytes must be rejected")
+	}
+	if _, err := NewFp().SetBytesLE(make([]byte, 49)); err != ErrInvalidLength {
+		t.Fatal("little endian input of wrong length must be rejected")
+	}
+	if _, err := NewFp().SetBytes(make([]byte, 47)); err != ErrInvalidLength {
+		t.Fatal("short input must be rejected")
+	}
+}
+
+func TestFpAssignOperations(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := NewFp().Rand(rand.Reader)
+		b, _ := NewFp().Rand(rand.Reader)
+		c, d := NewFp(), NewFp()
+		c.Add(a, b)
+		if d.Set(a).AddAssign(b); !d.Equal(c) {
+			t.Fatal("add assign")
+		}
+		c.Sub(a, b)
+		if d.Set(a).SubAssign(b); !d.Equal(c) {
+			t.Fatal("sub assign")
+		}
+		c.Mul(a, b)
+		if d.Set(a).MulAssign(b); !d.Equal(c) {
+			t.Fatal("mul assign")
+		}
+		c.Square(a)
+		if d.Set(a).SquareAssign(); !d.Equal(c) {
+			t.Fatal("square assign")
+		}
+	}
+	a, _ := NewFp().Rand(rand.Reader)
+	acc := NewFp().One()
+	if n := testing.AllocsPerRun(10, func() {
+		// loop body updating an accumulator in place
+		acc.MulAssign(a)
+		acc.AddAssign(a)
+		acc.SubAssign(a)
+		acc.SquareAssign()
+	}); n != 0 {
+		t.Fatal("assign operations must not allocate", n)
+	}
+}
+
+func TestFpConstantTimeSelect(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := NewFp().Rand(rand.Reader)
+		b, _ := NewFp().Rand(rand.Reader)
+		if a.EqualCT(a) != 1 || a.EqualCT(new(Fp).Set(a)) != 1 || a.EqualCT(b) != 0 {
+			t.Fatal("constant time equality")
+		}
+		c := new(Fp).Set(a)
+		c[fpNumberOfLimbs-1] ^= 1 << 63
+		if a.EqualCT(c) != 0 {
+			t.Fatal("difference in top bit must be detected")
+		}
+		c.Select(1, a, b)
+		if !c.Equal(a) {
+			t.Fatal("select a")
+		}
+		c.Select(0, a, b)
+		if !c.Equal(b) {
+			t.Fatal("select b")
+		}
+		// aliased arguments
+		c.Set(a).Select(0, c, b)
+		if !c.Equal(b) {
+			t.Fatal("select with aliased argument")
+		}
+		c.Set(a).CMov(b, 0)
+		if !c.Equal(a) {
+			t.Fatal("cmov must keep value if cond is zero")
+		}
+		c.CMov(b, -1)
+		if !c.Equal(b) {
+			t.Fatal("cmov must move if cond is non zero")
+		}
+	}
+}
+
+func TestFpSetBytesWide(t *testing.T) {
+	p := modulus.big()
+	for _, size := range []int{64, 96} {
+		inputs := [][]byte{make([]byte, size), bytes.Repeat([]byte{0xff}, size)}
+		for i := 0; i < fuz; i++ {
+			in := make([]byte, size)
+			_, _ = rand.Read(in)
+			inputs = append(inputs, in)
+		}
+		for _, in := range inputs {
+			e, err := NewFp().SetBytesWide(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := new(big.Int).Mod(new(big.Int).SetBytes(in), p)
+			if e.Big().Cmp(expected) != 0 {
+				t.Fatal("wide reduction failed", size)
+			}
+		}
+	}
+	if _, err := NewFp().SetBytesWide(make([]byte, 48)); err != ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+}
+
+func TestSqrtFp(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := NewFp().Rand(rand.Reader)
+		aa := NewFp()
+		aa.Square(a)
+		r, ok := SqrtFp(aa)
+		if !ok {
+			t.Fatal("square must have a root")
+		}
+		r.Square(r)
+		if !r.Equal(aa) {
+			t.Fatal("bad square root")
+		}
+		r, ok = SqrtFp(a)
+		if ok != a.IsSquare() || (!ok && r != nil) {
+			t.Fatal("quadratic residuosity mismatch")
+		}
+	}
+}
+
+func TestIsQuadraticResidue(t *testing.T) {
+	if IsQuadraticResidue(NewFp()) || IsQuadraticResidueFp2(new(Fp2)) {
+		t.Fatal("zero is not a quadratic residue")
+	}
+	if IsQuadraticResidue(nonResidue1) || IsQuadraticResidueFp2(nonResidue2) {
+		t.Fatal("non residue")
+	}
+	if !IsQuadraticResidue(new(Fp).One()) || !IsQuadraticResidueFp2(new(Fp2).One()) {
+		t.Fatal("one is a quadratic residue")
+	}
+	f := newFp2()
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp).rand(rand.Reader)
+		before := *a
+		if IsQuadraticResidue(a) != sqrt(new(Fp), a) {
+			t.Fatal("quadratic residuosity mismatch")
+		}
+		if *a != before {
+			t.Fatal("input must not be modified")
+		}
+		square(a, a)
+		if !IsQuadraticResidue(a) {
+			t.Fatal("square is a quadratic residue")
+		}
+		b, _ := new(fe2).rand(rand.Reader)
+		if IsQuadraticResidueFp2(b) != !f.isQuadraticNonResidue(b) {
+			t.Fatal("quadratic residuosity mismatch in fp2")
+		}
+		f.square(b, b)
+		if !IsQuadraticResidueFp2(b) {
+			t.Fatal("square is a quadratic residue in fp2")
+		}
+	}
+}
+
 func TestFpSquareRoot(t *testing.T) {
-	if sqrt(new(Fe), nonResidue1) {
+	if sqrt(new(Fp), nonResidue1) {
 		t.Fatal("non residue cannot have a sqrt")
 	}
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		r0, r1 := new(Fe), new(Fe)
+		a, _ := new(Fp).rand(rand.Reader)
+		r0, r1 := new(Fp), new(Fp)
 		d0 := sqrt(r0, a)
 		d1 := _sqrt(r1, a)
 		if d0 != d1 {
@@ -473,22 +955,22 @@ func TestFpNonResidue(t *testing.T) {
 	if !isQuadraticNonResidue(nonResidue1) {
 		t.Fatal("element is quadratic non residue, 1")
 	}
-	if isQuadraticNonResidue(new(Fe).one()) {
+	if isQuadraticNonResidue(new(Fp).one()) {
 		t.Fatal("one is not quadratic non residue")
 	}
-	if !isQuadraticNonResidue(new(Fe).zero()) {
+	if !isQuadraticNonResidue(new(Fp).zero()) {
 		t.Fatal("should accept zero as quadratic non residue")
 	}
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
+		a, _ := new(Fp).rand(rand.Reader)
 		square(a, a)
 		if isQuadraticNonResidue(a) {
 			t.Fatal("element is not quadratic non residue")
 		}
 	}
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		if !sqrt(new(Fe), a) {
+		a, _ := new(Fp).rand(rand.Reader)
+		if !sqrt(new(Fp), a) {
 			if !isQuadraticNonResidue(a) {
 				t.Fatal("element is quadratic non residue, 2", i)
 			}
@@ -500,7 +982,7 @@ func TestFpNonResidue(t *testing.T) {
 
 func TestWFp(t *testing.T) {
 	w := new(wfe)
-	a := new(Fe)
+	a := new(Fp)
 	fromWide(a, w)
 	if !a.isZero() {
 		t.Fatal("expect zero")
@@ -519,10 +1001,10 @@ func TestWFp(t *testing.T) {
 
 func TestWFpAddition(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a, _ := new(Fe).rand(rand.Reader)
-		b, _ := new(Fe).rand(rand.Reader)
+		a, _ := new(Fp).rand(rand.Reader)
+		b, _ := new(Fp).rand(rand.Reader)
 		w0, w1 := new(wfe), new(wfe)
-		c0, c1 := new(Fe), new(Fe)
+		c0, c1 := new(Fp), new(Fp)
 
 		wmul(w0, a, b)
 		w1.set(w0)
@@ -554,8 +1036,8 @@ func TestWFpAddition(t *testing.T) {
 			t.Fatal("doubling failed")
 		}
 
-		wmul(w0, a, &Fe{10001})
-		wmul(w1, a, &Fe{10000})
+		wmul(w0, a, &Fp{10001})
+		wmul(w1, a, &Fp{10000})
 		w2 := new(wfe)
 		wsub(w2, w0, w1)
 		lwsub(w0, w0, w1)
@@ -570,8 +1052,8 @@ func TestWFpAddition(t *testing.T) {
 			t.Fatal("subtraction failed")
 		}
 
-		wmul(w0, a, &Fe{10001})
-		wmul(w1, a, &Fe{10000})
+		wmul(w0, a, &Fp{10001})
+		wmul(w1, a, &Fp{10000})
 		wsub(w0, w1, w0)
 		fromWide(c0, w0)
 
@@ -586,13 +1068,13 @@ func TestWFpAddition(t *testing.T) {
 
 func TestWFpMultiplication(t *testing.T) {
 	for i := 0; i < fuz; i++ {
-		a0, _ := new(Fe).rand(rand.Reader)
-		b0, _ := new(Fe).rand(rand.Reader)
-		a1, _ := new(Fe).rand(rand.Reader)
-		b1, _ := new(Fe).rand(rand.Reader)
+		a0, _ := new(Fp).rand(rand.Reader)
+		b0, _ := new(Fp).rand(rand.Reader)
+		a1, _ := new(Fp).rand(rand.Reader)
+		b1, _ := new(Fp).rand(rand.Reader)
 		w0, w1, w2, w3 := new(wfe), new(wfe), new(wfe), new(wfe)
-		c0, c1 := new(Fe), new(Fe)
-		r0, r1 := new(Fe), new(Fe)
+		c0, c1 := new(Fp), new(Fp)
+		r0, r1 := new(Fp), new(Fp)
 
 		wmul(w0, a0, b0)
 		fromWide(r0, w0)
@@ -1195,6 +1677,40 @@ func TestWFp2MulByNonResidue(t *testing.T) {
 	}
 }
 
+func TestWFp2ConjugateAndNorm(t *testing.T) {
+	f := newFp2()
+	a, b, c0, c1 := new(fe2), new(fe2), new(fe2), new(fe2)
+	w0, w1 := new(wfe2), new(wfe2)
+	n0, n1 := new(Fp), new(Fp)
+	wn := new(wfe)
+	for i := 0; i < fuz; i++ {
+		_, _ = a.rand(rand.Reader)
+		_, _ = b.rand(rand.Reader)
+
+		wfp2Mul(w0, a, b)
+		wfp2Conjugate(w1, w0)
+		c0.fromWide(w0)
+		fp2Conjugate(c0, c0)
+		c1.fromWide(w1)
+		if !c0.equal(c1) {
+			t.Fatal("wide conjugate failed")
+		}
+
+		f.norm(n0, a)
+		f.wnorm(wn, a)
+		fromWide(n1, wn)
+		if !n0.equal(n1) {
+			t.Fatal("wide norm failed")
+		}
+		square(n1, &a[0])
+		mul(&c0[0], &a[1], &a[1])
+		addAssign(n1, &c0[0])
+		if !n0.equal(n1) {
+			t.Fatal("norm failed")
+		}
+	}
+}
+
 func TestFp6Serialization(t *testing.T) {
 	f := newFp6(nil)
 	for i := 0; i < fuz; i++ {
@@ -1921,6 +2437,55 @@ func TestFrobeniusMapping6(t *testing.T) {
 	}
 }
 
+func TestFp12Compression(t *testing.T) {
+	e, gt := NewEngine(), NewGT()
+	for i := 0; i < fuz; i++ {
+		a := e.AddPair(NewG1().randCorrect(), NewG2().randCorrect()).Result()
+		c, b := new(fe12c), new(fe12)
+		e.fp12.compress(c, a)
+		e.fp12.decompress(b, c)
+		if !b.equal(a) {
+			t.Fatal("decompression failed")
+		}
+		for j := 0; j < 8; j++ {
+			e.fp12.cyclotomicSquare(a)
+			e.fp12.compressedSquare(c)
+		}
+		e.fp12.decompress(b, c)
+		if !b.equal(a) {
+			t.Fatal("compressed squaring must agree with cyclotomic squaring")
+		}
+		b, err := gt.FromCompressedBytes(gt.ToCompressedBytes(a))
+		if err != nil || !b.equal(a) {
+			t.Fatal("compressed serialization")
+		}
+		bits := []int{0, 3, 4, 70, 71, 200}
+		s := new(big.Int)
+		for _, i := range bits {
+			s.SetBit(s, i, 1)
+		}
+		e.fp12.cyclotomicExp(b, a, s)
+		c2 := new(fe12)
+		e.fp12.cyclotomicExpBits(c2, a, bits)
+		if !c2.equal(b) {
+			t.Fatal("exponentiation with compressed squaring failed")
+		}
+	}
+	one := new(E).One()
+	b, err := gt.FromCompressedBytes(gt.ToCompressedBytes(one))
+	if err != nil || !b.isOne() {
+		t.Fatal("compressed serialization of one")
+	}
+	in := gt.ToCompressedBytes(one)
+	in[len(in)-1] = 1
+	if _, err := gt.FromCompressedBytes(in); err == nil {
+		t.Fatal("element out of subgroup must be rejected")
+	}
+	if _, err := gt.FromCompressedBytes(in[1:]); err != ErrInvalidLength {
+		t.Fatal("short input must be rejected")
+	}
+}
+
 func TestFrobeniusMapping12(t *testing.T) {
 	{
 		f := newFp2()
@@ -2034,42 +2599,54 @@ func TestFp4MultiplicationCross(t *testing.T) {
 }
 
 func BenchmarkFpMul(t *testing.B) {
-	a, _ := new(Fe).rand(rand.Reader)
-	b, _ := new(Fe).rand(rand.Reader)
-	c := new(Fe)
+	a, _ := new(Fp).rand(rand.Reader)
+	b, _ := new(Fp).rand(rand.Reader)
+	c := new(Fp)
 	t.ResetTimer()
 	for i := 0; i < t.N; i++ {
 		mul(c, a, b)
 	}
 }
 
-func (Fe *wfe) bytes() []byte {
+func BenchmarkFpNormalizeBatch(t *testing.B) {
+	in := make([]Fp, 1024)
+	for i := range in {
+		e, _ := new(Fp).rand(rand.Reader)
+		in[i].set(e)
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		NormalizeBatchFp(in)
+	}
+}
+
+func (Fp *wfe) bytes() []byte {
 	out := make([]byte, fpByteSize*2)
 	var a int
 	for i := 0; i < 2*fpNumberOfLimbs; i++ {
 		a = fpByteSize*2 - i*8
-		out[a-1] = byte(Fe[i])
-		out[a-2] = byte(Fe[i] >> 8)
-		out[a-3] = byte(Fe[i] >> 16)
-		out[a-4] = byte(Fe[i] >> 24)
-		out[a-5] = byte(Fe[i] >> 32)
-		out[a-6] = byte(Fe[i] >> 40)
-		out[a-7] = byte(Fe[i] >> 48)
-		out[a-8] = byte(Fe[i] >> 56)
+		out[a-1] = byte(Fp[i])
+		out[a-2] = byte(Fp[i] >> 8)
+		out[a-3] = byte(Fp[i] >> 16)
+		out[a-4] = byte(Fp[i] >> 24)
+		out[a-5] = byte(Fp[i] >> 32)
+		out[a-6] = byte(Fp[i] >> 40)
+		out[a-7] = byte(Fp[i] >> 48)
+		out[a-8] = byte(Fp[i] >> 56)
 	}
 	return out
 }
 
-func (Fe *wfe) equal(fe2 *wfe) bool {
-	return fe2[0] == Fe[0] && fe2[1] == Fe[1] && fe2[2] == Fe[2] && fe2[3] == Fe[3] && fe2[4] == Fe[4] && fe2[5] == Fe[5] && fe2[6] == Fe[6] && fe2[7] == Fe[7] && fe2[8] == Fe[8] && fe2[9] == Fe[9] && fe2[10] == Fe[10] && fe2[11] == Fe[11]
+func (Fp *wfe) equal(fe2 *wfe) bool {
+	return fe2[0] == Fp[0] && fe2[1] == Fp[1] && fe2[2] == Fp[2] && fe2[3] == Fp[3] && fe2[4] == Fp[4] && fe2[5] == Fp[5] && fe2[6] == Fp[6] && fe2[7] == Fp[7] && fe2[8] == Fp[8] && fe2[9] == Fp[9] && fe2[10] == Fp[10] && fe2[11] == Fp[11]
 }
 
-func (Fe *wfe2) equal(fe2 *wfe2) bool {
-	return Fe[0].equal(&fe2[0]) && Fe[1].equal(&fe2[1])
+func (Fp *wfe2) equal(fe2 *wfe2) bool {
+	return Fp[0].equal(&fe2[0]) && Fp[1].equal(&fe2[1])
 }
 
 func _fp2MulByNonResidue(c, a *fe2) {
-	t0 := &Fe{}
+	t0 := &Fp{}
 	add(t0, &a[0], &a[1])
 	sub(&c[0], &a[0], &a[1])
 	c[1].set(t0)
@@ -2114,7 +2691,7 @@ func _wfp2MulByNonResidue(c, a *wfe2) {
 
 func _wfp2Mul(c *wfe2, a, b *fe2) {
 	wt0, wt1 := new(wfe), new(wfe)
-	t0, t1 := new(Fe), new(Fe)
+	t0, t1 := new(Fp), new(Fp)
 	wmul(wt0, &a[0], &b[0]) // a0b0
 	wmul(wt1, &a[1], &b[1]) // a1b1
 	wsub(&c[0], wt0, wt1)   // c0 = a0b0 - a1b1
@@ -2126,7 +2703,7 @@ func _wfp2Mul(c *wfe2, a, b *fe2) {
 }
 
 func _wfp2Square(c *wfe2, a *fe2) {
-	t0, t1, t2 := new(Fe), new(Fe), new(Fe)
+	t0, t1, t2 := new(Fp), new(Fp), new(Fp)
 	ladd(t0, &a[0], &a[1]) // (a0 + a1)
 	sub(t1, &a[0], &a[1])  // (a0 - a1)
 	ldouble(t2, &a[0])     // 2a0
@@ -2236,3 +2813,66 @@ func (e *fp12) _fp4Square(c0, c1, a0, a1 *fe2) {
 	fp2SubAssign(t[2], t[0])    // (a0 + a1)^2 - a0^2
 	fp2Sub(c1, t[2], t[1])      // (a0 + a1)^2 - a0^2 - a1^2
 }
+
+func BenchmarkFp2Mul(t *testing.B) {
+	f := newFp2()
+	a, _ := new(fe2).rand(rand.Reader)
+	b, _ := new(fe2).rand(rand.Reader)
+	c := new(fe2)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		f.mul(c, a, b)
+	}
+}
+
+func BenchmarkFp2Square(t *testing.B) {
+	f := newFp2()
+	a, _ := new(fe2).rand(rand.Reader)
+	c := new(fe2)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		f.square(c, a)
+	}
+}
+
+func BenchmarkFp6Square(t *testing.B) {
+	f := newFp6(nil)
+	a, _ := new(fe6).rand(rand.Reader)
+	c := new(fe6)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		f.square(c, a)
+	}
+}
+
+func BenchmarkFp12Square(t *testing.B) {
+	f := newFp12(nil)
+	a, _ := new(fe12).rand(rand.Reader)
+	c := new(fe12)
+	t.Run("complex", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			f.square(c, a)
+		}
+	})
+	t.Run("cyclotomic", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			f.cyclotomicSquare(c)
+		}
+	})
+	t.Run("compressed", func(t *testing.B) {
+		z := new(fe12c)
+		f.compress(z, a)
+		for i := 0; i < t.N; i++ {
+			f.compressedSquare(z)
+		}
+	})
+}
+
+func BenchmarkFpExp(t *testing.B) {
+	a, _ := new(Fp).rand(rand.Reader)
+	c := new(Fp)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		exp(c, a, pMinus1Over2)
+	}
+}
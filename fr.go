@@ -28,6 +28,20 @@ func (e *Fr) Rand(r io.Reader) (*Fr, error) {
 	return e, nil
 }
 
+// RandNonZero sets the scalar to a uniformly random non zero value, for
+// blinding factors and batch verification coefficients, where a zero scalar
+// would cancel a term.
+func (e *Fr) RandNonZero(r io.Reader) (*Fr, error) {
+	for {
+		if _, err := e.Rand(r); err != nil {
+			return nil, err
+		}
+		if !e.IsZero() {
+			return e, nil
+		}
+	}
+}
+
 func (e *Fr) Set(e2 *Fr) *Fr {
 	e[0] = e2[0]
 	e[1] = e2[1]
@@ -65,6 +79,28 @@ func (e *Fr) RedFromBytes(in []byte) *Fr {
 	return e
 }
 
+// SetBytesMod interprets input as a big endian integer of any length and sets
+// the scalar to its value reduced modulo q. Input is consumed in 31 byte chunks
+// which are always below q, so that each step is a single Montgomery
+// multiplication by 2^248 followed by a modular addition.
+func (e *Fr) SetBytesMod(in []byte) *Fr {
+	const chunkSize = 31
+	acc, c := new(Fr), new(Fr)
+	for off := len(in) % chunkSize; off <= len(in); off += chunkSize {
+		from := off - chunkSize
+		if from < 0 {
+			from = 0
+		}
+		c.Zero()
+		for i, j := off-1, 0; i >= from; i, j = i-1, j+1 {
+			c[j/8] |= uint64(in[i]) << (8 * uint(j%8))
+		}
+		acc.RedMul(acc, qr248)
+		acc.Add(acc, c)
+	}
+	return e.Set(acc)
+}
+
 func (e *Fr) fromBytes(in []byte) *Fr {
 	u := new(big.Int).SetBytes(in)
 	_ = e.fromBig(u)
@@ -170,6 +206,21 @@ func (e *Fr) Cmp(e1 *Fr) int {
 	return 0
 }
 
+// CmpCT compares two scalars limb by limb without early exit.
+// Returns 1 if e > e1, -1 if e < e1 and 0 if they are equal.
+func (e *Fr) CmpCT(e1 *Fr) int {
+	var lt, gt uint64
+	_, lt = bits.Sub64(e[0], e1[0], 0)
+	_, lt = bits.Sub64(e[1], e1[1], lt)
+	_, lt = bits.Sub64(e[2], e1[2], lt)
+	_, lt = bits.Sub64(e[3], e1[3], lt)
+	_, gt = bits.Sub64(e1[0], e[0], 0)
+	_, gt = bits.Sub64(e1[1], e[1], gt)
+	_, gt = bits.Sub64(e1[2], e[2], gt)
+	_, gt = bits.Sub64(e1[3], e[3], gt)
+	return int(gt) - int(lt)
+}
+
 func (e *Fr) sliceUint64(from int) uint64 {
 	if from < 64 {
 		return e[0]>>from | e[1]<<(64-from)
@@ -283,6 +334,76 @@ func (e *Fr) Exp(a *Fr, ee *big.Int) {
 
 }
 
+// IsSquare returns true if the element is a quadratic residue. Result is the
+// same in both standard and Montgomery form since qr is a square.
+func (e *Fr) IsSquare() bool {
+	if e.IsZero() {
+		return true
+	}
+	t := new(Fr).Set(e)
+	t.RedExp(t, frLegendreExp)
+	return t.IsRedOne()
+}
+
+// Sqrt sets the receiver to a square root of a and returns true if a is a
+// quadratic residue. Otherwise receiver is unchanged and false is returned.
+func (e *Fr) Sqrt(a *Fr) bool {
+	t := new(Fr).Set(a)
+	t.toMont()
+	if !t.RedSqrt(t) {
+		return false
+	}
+	t.fromMont()
+	e.Set(t)
+	return true
+}
+
+// RedSqrt is Sqrt for elements in Montgomery form. It implements
+// Tonelli-Shanks algorithm.
+func (e *Fr) RedSqrt(a *Fr) bool {
+	if a.IsZero() {
+		e.Zero()
+		return true
+	}
+	w, x, b, z := new(Fr), new(Fr), new(Fr), new(Fr).Set(frRootOfUnity)
+	// x = a^((t + 1) / 2), b = a^t
+	w.RedExp(a, frSqrtExp)
+	x.RedMul(a, w)
+	b.RedMul(x, w)
+	m := frTwoAdicity
+	t := new(Fr)
+	for !b.IsRedOne() {
+		// Find least i such that b^(2^i) = 1
+		i := 0
+		for t.Set(b); !t.IsRedOne(); i++ {
+			t.RedSquare(t)
+		}
+		if i == m {
+			return false
+		}
+		t.Set(z)
+		for j := 0; j < m-i-1; j++ {
+			t.RedSquare(t)
+		}
+		z.RedSquare(t)
+		x.RedMul(x, t)
+		b.RedMul(b, z)
+		m = i
+	}
+	e.Set(x)
+	return true
+}
+
+// NormalizeBatchFr converts scalars from Montgomery form to standard form in
+// place. It is the batch counterpart of FromRed for exporting large witness or
+// commitment vectors.
+func NormalizeBatchFr(in []Fr) {
+	one := &Fr{1}
+	for i := range in {
+		in[i].RedMul(&in[i], one)
+	}
+}
+
 func RedInverseBatchFr(in []Fr) {
 	inverseBatchFr(in, func(a, b *Fr) { a.RedInverse(b) })
 }
@@ -339,71 +460,61 @@ func inverseBatchFr(in []Fr, invFn func(out *Fr, in *Fr)) {
 	}
 }
 
+// Inverse sets e = a^-1 in constant time with safegcd. Inverse of zero is
+// zero.
 func (e *Fr) Inverse(a *Fr) {
-	e.Set(a).toMont()
-	e.RedInverse(e)
-	e.fromMont()
+	frSafegcd.inverse(e[:], a[:])
 }
 
-func (e *Fr) RedInverse(ei *Fr) {
-	if ei.IsZero() {
-		e.Zero()
-		return
-	}
-	u := new(Fr).Set(&q)
-	v := new(Fr).Set(ei)
-	s := &Fr{1}
-	r := &Fr{0}
-	var k int
-	var z uint64
-	var found = false
-	// Phase 1
-	for i := 0; i < fourWordBitSize*2; i++ {
-		if v.IsZero() {
-			found = true
-			break
-		}
-		if u.isEven() {
-			u.div2()
-			s.mul2()
-		} else if v.isEven() {
-			v.div2()
-			z += r.mul2()
-		} else if u.Cmp(v) == 1 {
-			lsubAssignFR(u, v)
-			u.div2()
-			laddAssignFR(r, s)
-			s.mul2()
-		} else {
-			lsubAssignFR(v, u)
-			v.div2()
-			laddAssignFR(s, r)
-			z += r.mul2()
-		}
-		k += 1
-	}
-
-	if !found {
+// InverseVarTime is Inverse in variable time with safegcd, which is faster
+// on average. It must only be used for public values, such as challenges of
+// a verifier. It returns false if a is zero, in which case e is set to zero.
+func (e *Fr) InverseVarTime(a *Fr) bool {
+	if a.IsZero() {
 		e.Zero()
-		return
+		return false
 	}
+	frSafegcd.inverseVarTime(e[:], a[:])
+	return true
+}
 
-	if k < frBitSize || k > frBitSize+fourWordBitSize {
-		e.Zero()
-		return
-	}
+// RedInverse is Inverse of an element in Montgomery form.
+func (e *Fr) RedInverse(ei *Fr) {
+	e.Set(ei).fromMont()
+	frSafegcd.inverse(e[:], e[:])
+	e.toMont()
+}
 
-	if r.Cmp(&q) != -1 || z > 0 {
-		lsubAssignFR(r, &q)
-	}
-	u.Set(&q)
-	lsubAssignFR(u, r)
+// FrToFp embeds a scalar given in standard form into the base field.
+// Since q < p every scalar is a canonical base field element, so the integer
+// value is preserved and no reduction takes place. Result is in Montgomery form
+// as every other base field element.
+func FrToFp(a *Fr) *Fp {
+	c := &Fp{a[0], a[1], a[2], a[3]}
+	toMont(c, c)
+	return c
+}
 
-	// Phase 2
-	for i := k; i < 2*fourWordBitSize; i++ {
-		doubleFR(u, u)
+// FpToFrMod maps a base field element to a scalar in standard form by reducing
+// its integer value modulo q. Since p > q the mapping is not injective and
+// FrToFp(FpToFrMod(a)) equals a only if the integer value of a is less than q.
+func FpToFrMod(a *Fp) *Fr {
+	return new(Fr).SetBytesMod(toBytes(a))
+}
+
+// FpToFr maps a base field element to the scalar of the same integer value,
+// which is the inverse of FrToFp. It returns ErrNonCanonical if the value is
+// not less than q. Fiat-Shamir challenges derived from point coordinates
+// should use FpToFrMod instead, since coordinates are spread over the whole
+// base field and are almost never less than q.
+func FpToFr(a *Fp) (*Fr, error) {
+	t := new(Fp)
+	fromMont(t, a)
+	c := &Fr{t[0], t[1], t[2], t[3]}
+	if t[4] != 0 || t[5] != 0 || c.Cmp(&q) >= 0 {
+		return nil, ErrNonCanonical
 	}
-	e.Set(u)
+	return c, nil
 }
 
 func (ew *wideFr) mul(a, b *Fr) {
@@ -66,6 +66,61 @@ func TestFrSerialization(t *testing.T) {
 	}
 }
 
+func TestFrSetBytesMod(t *testing.T) {
+	for n := 0; n < 200; n++ {
+		in := make([]byte, n)
+		_, _ = rand.Read(in)
+		expected := new(big.Int).SetBytes(in)
+		expected.Mod(expected, qBig)
+		e := new(Fr).SetBytesMod(in)
+		if e.ToBig().Cmp(expected) != 0 {
+			t.Fatalf("modular reduction failed, length %d", n)
+		}
+	}
+	in := make([]byte, 100)
+	for i := range in {
+		in[i] = 0xff
+	}
+	expected := new(big.Int).SetBytes(in)
+	expected.Mod(expected, qBig)
+	if new(Fr).SetBytesMod(in).ToBig().Cmp(expected) != 0 {
+		t.Fatal("modular reduction failed, all ones")
+	}
+}
+
+func TestFrFpConversion(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		fe := FrToFp(a)
+		if ToBig(fe).Cmp(a.ToBig()) != 0 {
+			t.Fatal("scalar to base field conversion failed")
+		}
+		if !FpToFrMod(fe).Equal(a) {
+			t.Fatal("base field to scalar conversion failed")
+		}
+		if b, err := FpToFr(fe); err != nil || !b.Equal(a) {
+			t.Fatal("exact base field to scalar conversion failed")
+		}
+		fe, _ = new(Fp).rand(rand.Reader)
+		expected := ToBig(fe)
+		expected.Mod(expected, qBig)
+		if FpToFrMod(fe).ToBig().Cmp(expected) != 0 {
+			t.Fatal("base field to scalar conversion failed")
+		}
+		if _, err := FpToFr(fe); (err == nil) != (ToBig(fe).Cmp(qBig) < 0) {
+			t.Fatal("values not less than q must be rejected")
+		}
+	}
+	qMinusOne, _ := new(Fp).SetBig(new(big.Int).Sub(qBig, big.NewInt(1)))
+	if b, err := FpToFr(qMinusOne); err != nil || b.ToBig().Cmp(new(big.Int).Sub(qBig, big.NewInt(1))) != 0 {
+		t.Fatal("q - 1 must be accepted")
+	}
+	qFp, _ := new(Fp).SetBig(qBig)
+	if _, err := FpToFr(qFp); err != ErrNonCanonical {
+		t.Fatal("q must be rejected")
+	}
+}
+
 func TestFrSliceUint(t *testing.T) {
 	s, err := new(Fr).Rand(rand.Reader)
 	if err != nil {
@@ -114,6 +169,24 @@ func TestFrBitShift(t *testing.T) {
 	}
 }
 
+func TestFrComparison(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		if a.CmpCT(b) != a.Cmp(b) || b.CmpCT(a) != b.Cmp(a) {
+			t.Fatal("constant time comparison failed")
+		}
+		if a.CmpCT(a) != 0 {
+			t.Fatal("constant time comparison failed")
+		}
+		b.Set(a)
+		b[0] ^= 1
+		if a.CmpCT(b) != a.Cmp(b) {
+			t.Fatal("constant time comparison failed")
+		}
+	}
+}
+
 func TestFrAdditionCrossAgainstBigInt(t *testing.T) {
 	for i := 0; i < fuz; i++ {
 		a, _ := new(Fr).Rand(rand.Reader)
@@ -389,6 +462,15 @@ func TestFrInversion(t *testing.T) {
 		if !v.Equal(u) {
 			t.Fatal("a^(p-2) == a^-1")
 		}
+		if !v.InverseVarTime(a) || !v.Equal(u) {
+			t.Fatal("variable time inverse")
+		}
+		if !v.InverseVarTime(one) || !v.Equal(one) {
+			t.Fatal("variable time inverse of one")
+		}
+		if v.InverseVarTime(zero) || !v.IsZero() {
+			t.Fatal("variable time inverse of zero")
+		}
 	}
 }
 
@@ -415,3 +497,80 @@ func TestFnBatchInversion(t *testing.T) {
 		}
 	}
 }
+
+func TestFrSqrt(t *testing.T) {
+	// 7 is the smallest non residue
+	nonResidue := new(Fr).FromBytes([]byte{7})
+	if nonResidue.IsSquare() {
+		t.Fatal("7 must be a non residue")
+	}
+	r := new(Fr)
+	if r.Sqrt(nonResidue) {
+		t.Fatal("non residue must not have a square root")
+	}
+	zero := new(Fr).Zero()
+	if !zero.IsSquare() || !r.Sqrt(zero) || !r.IsZero() {
+		t.Fatal("sqrt(0) == 0")
+	}
+	root := new(Fr).Set(frRootOfUnity)
+	for i := 0; i < frTwoAdicity; i++ {
+		root.RedSquare(root)
+	}
+	if !root.IsRedOne() {
+		t.Fatal("bad root of unity")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fr).Rand(rand.Reader)
+		aa := new(Fr)
+		aa.Square(a)
+		if !aa.IsSquare() {
+			t.Fatal("a^2 must be a square")
+		}
+		if !r.Sqrt(aa) {
+			t.Fatal("a^2 must have a square root")
+		}
+		r.Square(r)
+		if !r.Equal(aa) {
+			t.Fatal("sqrt(a^2)^2 == a^2")
+		}
+		b := new(Fr)
+		b.Mul(aa, nonResidue)
+		if b.IsSquare() || r.Sqrt(b) {
+			t.Fatal("a^2 * 7 must be a non residue")
+		}
+		aa.toMont()
+		if !r.RedSqrt(aa) {
+			t.Fatal("a^2 must have a square root")
+		}
+		r.RedSquare(r)
+		if !r.Equal(aa) {
+			t.Fatal("sqrt(a^2)^2 == a^2")
+		}
+	}
+}
+
+func TestFrNormalizeBatch(t *testing.T) {
+	n := fuz + 3
+	in := make([]Fr, n)
+	in[1].RedOne()
+	in[2].Set(&q)
+	in[2][0]--
+	for i := 3; i < n; i++ {
+		e, _ := new(Fr).Rand(rand.Reader)
+		in[i].Set(e)
+	}
+	expected := make([]Fr, n)
+	for i := range in {
+		expected[i].Set(&in[i])
+		expected[i].FromRed()
+	}
+	NormalizeBatchFr(in)
+	for i := range in {
+		if !in[i].Equal(&expected[i]) {
+			t.Fatal("batch normalization failed", i)
+		}
+	}
+	if !in[1].IsOne() {
+		t.Fatal("one must be normalized to one")
+	}
+}
@@ -0,0 +1,241 @@
+//go:build gnark
+// +build gnark
+
+// Package gnark cross checks group and pairing outputs of bls12381 against
+// gnark-crypto on fuzzed inputs.
+//
+// It lives in its own module so that the main module does not depend on
+// gnark-crypto and keeps its minimum Go version. Run with:
+//
+//	go test -tags gnark -fuzz FuzzG1 ./...
+package gnark
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	gnark "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/gnarkcompat"
+)
+
+const domain = "BLS12381G2_XMD:SHA-256_SSWU_RO_TESTGEN"
+
+func seed(f *testing.F) {
+	f.Add([]byte{}, []byte{})
+	f.Add([]byte{1}, []byte{2})
+	f.Add(bytes.Repeat([]byte{0xff}, 32), bytes.Repeat([]byte{0xff}, 64))
+	f.Add(fr.Modulus().Bytes(), new(big.Int).Sub(fr.Modulus(), big.NewInt(1)).Bytes())
+}
+
+// scalars maps fuzzer input to the same scalar in both libraries.
+func scalars(in []byte) (*bls.Fr, *big.Int) {
+	s := new(bls.Fr).SetBytesMod(in)
+	return s, s.ToBig()
+}
+
+func FuzzG1(f *testing.F) {
+	seed(f)
+	_, _, g1Gen, _ := gnark.Generators()
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		g := bls.NewG1()
+		sa, ba := scalars(a)
+		sb, bb := scalars(b)
+
+		p := g.MulScalar(g.New(), g.One(), sa)
+		q := g.MulScalar(g.New(), g.One(), sb)
+		var gp, gq gnark.G1Affine
+		gp.ScalarMultiplication(&g1Gen, ba)
+		gq.ScalarMultiplication(&g1Gen, bb)
+		checkG1(t, "mul", g, p, &gp)
+		checkG1(t, "mul", g, q, &gq)
+
+		r := g.Add(g.New(), p, q)
+		var gr gnark.G1Affine
+		gr.Add(&gp, &gq)
+		checkG1(t, "add", g, r, &gr)
+
+		g.Double(r, p)
+		gr.Double(&gp)
+		checkG1(t, "double", g, r, &gr)
+
+		g.Sub(r, p, q)
+		gr.Sub(&gp, &gq)
+		checkG1(t, "sub", g, r, &gr)
+
+		g.MulScalar(r, p, sb)
+		gr.ScalarMultiplication(&gp, bb)
+		checkG1(t, "mul", g, r, &gr)
+	})
+}
+
+func FuzzG2(f *testing.F) {
+	seed(f)
+	_, _, _, g2Gen := gnark.Generators()
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		g := bls.NewG2()
+		sa, ba := scalars(a)
+		sb, bb := scalars(b)
+
+		p := g.MulScalar(g.New(), g.One(), sa)
+		q := g.MulScalar(g.New(), g.One(), sb)
+		var gp, gq gnark.G2Affine
+		gp.ScalarMultiplication(&g2Gen, ba)
+		gq.ScalarMultiplication(&g2Gen, bb)
+		checkG2(t, "mul", g, p, &gp)
+		checkG2(t, "mul", g, q, &gq)
+
+		r := g.Add(g.New(), p, q)
+		var gr gnark.G2Affine
+		gr.Add(&gp, &gq)
+		checkG2(t, "add", g, r, &gr)
+
+		g.Double(r, p)
+		gr.Double(&gp)
+		checkG2(t, "double", g, r, &gr)
+
+		g.Sub(r, p, q)
+		gr.Sub(&gp, &gq)
+		checkG2(t, "sub", g, r, &gr)
+
+		g.MulScalar(r, p, sb)
+		gr.ScalarMultiplication(&gp, bb)
+		checkG2(t, "mul", g, r, &gr)
+	})
+}
+
+func FuzzPairing(f *testing.F) {
+	seed(f)
+	_, _, g1Gen, g2Gen := gnark.Generators()
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		g1, g2 := bls.NewG1(), bls.NewG2()
+		sa, ba := scalars(a)
+		sb, bb := scalars(b)
+
+		p := g1.MulScalar(g1.New(), g1.One(), sa)
+		q := g2.MulScalar(g2.New(), g2.One(), sb)
+		var gp gnark.G1Affine
+		var gq gnark.G2Affine
+		gp.ScalarMultiplication(&g1Gen, ba)
+		gq.ScalarMultiplication(&g2Gen, bb)
+
+		e := bls.NewEngine()
+		e.AddPair(p, q)
+		expected, err := gnark.Pair([]gnark.G1Affine{gp}, []gnark.G2Affine{gq})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := expected.Bytes()
+		if got := e.GT().ToBytes(e.Result()); !bytes.Equal(got, want[:]) {
+			t.Fatalf("pairing mismatch\nhave %x\nwant %x", got, want)
+		}
+	})
+}
+
+func FuzzHashToCurve(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("abc"))
+	f.Add(bytes.Repeat([]byte{0x61}, 128))
+	f.Fuzz(func(t *testing.T, msg []byte) {
+		g1, g2 := bls.NewG1(), bls.NewG2()
+		p, err := g1.HashToCurve(msg, []byte(domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gp, err := gnark.HashToG1(msg, []byte(domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkG1(t, "hash", g1, p, &gp)
+
+		q, err := g2.HashToCurve(msg, []byte(domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gq, err := gnark.HashToG2(msg, []byte(domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkG2(t, "hash", g2, q, &gq)
+	})
+}
+
+func FuzzCompat(f *testing.F) {
+	seed(f)
+	_, _, g1Gen, g2Gen := gnark.Generators()
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		g1, g2 := bls.NewG1(), bls.NewG2()
+		sa, ba := scalars(a)
+		p := g1.MulScalar(g1.New(), g1.One(), sa)
+		q := g2.MulScalar(g2.New(), g2.One(), sa)
+		var gp gnark.G1Affine
+		var gq gnark.G2Affine
+		gp.ScalarMultiplication(&g1Gen, ba)
+		gq.ScalarMultiplication(&g2Gen, ba)
+
+		// gnark to bls12381
+		c1, u1 := gp.Bytes(), gp.RawBytes()
+		for _, in := range [][]byte{c1[:], u1[:]} {
+			r, err := gnarkcompat.G1FromBytes(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g1.Equal(r, p) {
+				t.Fatal("g1 conversion from gnark mismatch")
+			}
+		}
+		c2, u2 := gq.Bytes(), gq.RawBytes()
+		for _, in := range [][]byte{c2[:], u2[:]} {
+			r, err := gnarkcompat.G2FromBytes(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g2.Equal(r, q) {
+				t.Fatal("g2 conversion from gnark mismatch")
+			}
+		}
+
+		// bls12381 to gnark
+		var rp gnark.G1Affine
+		var rq gnark.G2Affine
+		raw1 := gnarkcompat.G1RawBytes(p)
+		if _, err := rp.SetBytes(raw1[:]); err != nil || !rp.Equal(&gp) {
+			t.Fatal("g1 conversion to gnark mismatch", err)
+		}
+		raw2 := gnarkcompat.G2RawBytes(q)
+		if _, err := rq.SetBytes(raw2[:]); err != nil || !rq.Equal(&gq) {
+			t.Fatal("g2 conversion to gnark mismatch", err)
+		}
+
+		var e fr.Element
+		e.SetBytes(b)
+		eb := e.Bytes()
+		s, err := gnarkcompat.FrFromBytes(eb[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb := gnarkcompat.FrBytes(s)
+		var e2 fr.Element
+		if err := e2.SetBytesCanonical(sb[:]); err != nil || !e2.Equal(&e) {
+			t.Fatal("scalar conversion mismatch", err)
+		}
+	})
+}
+
+func checkG1(t *testing.T, op string, g *bls.G1, p *bls.PointG1, expected *gnark.G1Affine) {
+	t.Helper()
+	want := expected.Bytes()
+	if got := g.ToCompressed(p); !bytes.Equal(got, want[:]) {
+		t.Fatalf("g1 %s mismatch\nhave %x\nwant %x", op, got, want)
+	}
+}
+
+func checkG2(t *testing.T, op string, g *bls.G2, p *bls.PointG2, expected *gnark.G2Affine) {
+	t.Helper()
+	want := expected.Bytes()
+	if got := g.ToCompressed(p); !bytes.Equal(got, want[:]) {
+		t.Fatalf("g2 %s mismatch\nhave %x\nwant %x", op, got, want)
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/kilic/bls12-381/fuzz/gnark
+
+go 1.18
+
+replace github.com/kilic/bls12-381 => ../..
+
+require (
+	github.com/consensys/gnark-crypto v0.12.1
+	github.com/kilic/bls12-381 v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/bits-and-blooms/bitset v1.7.0 // indirect
+	github.com/consensys/bavard v0.1.13 // indirect
+	github.com/mmcloughlin/addchain v0.4.0 // indirect
+	golang.org/x/sys v0.9.0 // indirect
+	rsc.io/tmplfunc v0.0.3 // indirect
+)
@@ -8,10 +8,15 @@ import (
 
 // PointG1 is type for point in G1 and used for both Affine and Jacobian point representation.
 // A point is accounted as in affine form if z is equal to one.
-type PointG1 [3]Fe
+type PointG1 [3]Fp
 
 var wnafMulWindowG1 uint = 5
 
+// g1SubgroupWNAF is the recoded form of z = (x^2 − 1)/3 used in subgroup check.
+const g1SubgroupWNAFWindow = 5
+
+var g1SubgroupWNAF = (&Fr{0x0000000055555555, 0x396c8c005555e156}).toWNAF(g1SubgroupWNAFWindow)
+
 func (p *PointG1) Set(p2 *PointG1) *PointG1 {
 	p[0].set(&p2[0])
 	p[1].set(&p2[1])
@@ -32,24 +37,28 @@ func (p *PointG1) IsAffine() bool {
 }
 
 type tempG1 struct {
-	t [9]*Fe
+	t [9]*Fp
 }
 
 // G1 is struct for G1 group.
 type G1 struct {
 	tempG1
+	multiExpNoGLV         bool
+	multiExpNoBatchAffine bool
+	multiExpMaxWindow     int
+	tracer                *Tracer
 }
 
 // NewG1 constructs a new G1 instance.
 func NewG1() *G1 {
 	t := newTempG1()
-	return &G1{t}
+	return &G1{tempG1: t}
 }
 
 func newTempG1() tempG1 {
-	t := [9]*Fe{}
+	t := [9]*Fp{}
 	for i := 0; i < 9; i++ {
-		t[i] = &Fe{}
+		t[i] = &Fp{}
 	}
 	return tempG1{t}
 }
@@ -64,41 +73,73 @@ func (g *G1) Q() *big.Int {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) FromUncompressed(uncompressed []byte) (*PointG1, error) {
-	if len(uncompressed) != 2*fpByteSize {
-		return nil, errors.New("input string length must be equal to 96 bytes")
+	p := new(PointG1)
+	if err := g.FromUncompressedInto(p, uncompressed); err != nil {
+		return nil, legacyError(err, errLength96, errCompressionFlagOff)
+	}
+	return p, nil
+}
+
+// FromUncompressedInto decodes 96 bytes uncompressed input into the point at first argument.
+// It follows the same rules with FromUncompressed without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G1) FromUncompressedInto(p *PointG1, uncompressed []byte) error {
+	if len(uncompressed) != G1UncompressedSize {
+		return ErrInvalidLength
 	}
-	var in [2 * fpByteSize]byte
-	copy(in[:], uncompressed[:2*fpByteSize])
-	if in[0]&(1<<7) != 0 {
-		return nil, errors.New("compression flag must be zero")
+	var in [G1UncompressedSize]byte
+	copy(in[:], uncompressed)
+	if in[0]&CompressionFlag != 0 {
+		return ErrCompressionFlag
 	}
-	if in[0]&(1<<5) != 0 {
-		return nil, errors.New("sort flag must be zero")
+	if in[0]&SignFlag != 0 {
+		return ErrSortFlag
 	}
-	if in[0]&(1<<6) != 0 {
+	if in[0]&InfinityFlag != 0 {
 		for i, v := range in {
-			if (i == 0 && v != 0x40) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
+			if (i == 0 && v != InfinityFlag) || (i != 0 && v != 0x00) {
+				return ErrInfinityEncoding
 			}
 		}
-		return g.Zero(), nil
+		p.Zero()
+		return nil
 	}
-	in[0] &= 0x1f
-	x, err := fromBytes(in[:fpByteSize])
-	if err != nil {
+	in[0] &^= FlagMask
+	if err := fromBytesInto(&p[0], in[:fpByteSize]); err != nil {
+		return err
+	}
+	if err := fromBytesInto(&p[1], in[fpByteSize:]); err != nil {
+		return err
+	}
+	p[2].one()
+	if !g.IsOnCurve(p) {
+		return ErrNotOnCurve
+	}
+	if !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// PointG1FromBig returns the G1 point with affine coordinates x and y, for
+// porting fixtures from scripts that work with integers. Coordinates must be
+// less than the modulus and the point must be on the curve and in the correct
+// subgroup. The point at infinity has no affine coordinates and is rejected.
+func PointG1FromBig(x, y *big.Int) (*PointG1, error) {
+	p := &PointG1{}
+	if _, err := p[0].SetBig(x); err != nil {
 		return nil, err
 	}
-	y, err := fromBytes(in[fpByteSize:])
-	if err != nil {
+	if _, err := p[1].SetBig(y); err != nil {
 		return nil, err
 	}
-	z := new(Fe).one()
-	p := &PointG1{*x, *y, *z}
+	p[2].one()
+	g := NewG1()
 	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
 	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+		return nil, ErrNotInSubgroup
 	}
 	return p, nil
 }
@@ -108,9 +149,9 @@ func (g *G1) FromUncompressed(uncompressed []byte) (*PointG1, error) {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) ToUncompressed(p *PointG1) []byte {
-	out := make([]byte, 2*fpByteSize)
+	out := make([]byte, G1UncompressedSize)
 	if g.IsZero(p) {
-		out[0] |= 1 << 6
+		out[0] |= InfinityFlag
 		return out
 	}
 	g.Affine(p)
@@ -124,46 +165,55 @@ func (g *G1) ToUncompressed(p *PointG1) []byte {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) FromCompressed(compressed []byte) (*PointG1, error) {
-	if len(compressed) != fpByteSize {
-		return nil, errors.New("input string length must be equal to 48 bytes")
+	p := new(PointG1)
+	if err := g.FromCompressedInto(p, compressed); err != nil {
+		return nil, legacyError(err, errLength48, errCompressionFlagSet)
+	}
+	return p, nil
+}
+
+// FromCompressedInto decodes 48 bytes compressed input into the point at first argument.
+// It follows the same rules with FromCompressed without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G1) FromCompressedInto(p *PointG1, compressed []byte) error {
+	if len(compressed) != G1CompressedSize {
+		return ErrInvalidLength
 	}
-	var in [fpByteSize]byte
-	copy(in[:], compressed[:])
-	if in[0]&(1<<7) == 0 {
-		return nil, errors.New("compression flag must be set")
+	var in [G1CompressedSize]byte
+	copy(in[:], compressed)
+	if in[0]&CompressionFlag == 0 {
+		return ErrCompressionFlag
 	}
-	if in[0]&(1<<6) != 0 {
-		// in[0] == (1 << 6) + (1 << 7)
+	if in[0]&InfinityFlag != 0 {
 		for i, v := range in {
-			if (i == 0 && v != 0xc0) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
+			if (i == 0 && v != CompressionFlag|InfinityFlag) || (i != 0 && v != 0x00) {
+				return ErrInfinityEncoding
 			}
 		}
-		return g.Zero(), nil
+		p.Zero()
+		return nil
 	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
-	x, err := fromBytes(in[:])
-	if err != nil {
-		return nil, err
+	a := in[0]&SignFlag != 0
+	in[0] &^= FlagMask
+	x, y := &p[0], &p[1]
+	if err := fromBytesInto(x, in[:]); err != nil {
+		return err
 	}
 	// solve curve equation
-	y := &Fe{}
 	square(y, x)
 	mul(y, y, x)
 	add(y, y, b)
 	if ok := sqrt(y, y); !ok {
-		return nil, errors.New("point is not on curve")
+		return ErrNotOnCurve
 	}
 	if y.signBE() == a {
 		neg(y, y)
 	}
-	z := new(Fe).one()
-	p := &PointG1{*x, *y, *z}
+	p[2].one()
 	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+		return ErrNotInSubgroup
 	}
-	return p, nil
+	return nil
 }
 
 // ToCompressed given a G1 point returns bytes in compressed form of the point.
@@ -171,18 +221,107 @@ func (g *G1) FromCompressed(compressed []byte) (*PointG1, error) {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G1) ToCompressed(p *PointG1) []byte {
-	out := make([]byte, fpByteSize)
+	out := make([]byte, G1CompressedSize)
 	g.Affine(p)
+	g.encodeCompressed(out, p)
+	return out
+}
+
+// ToCompressedBatch serializes given points in compressed form.
+// Points are normalized in place with a single inversion, see EnsureAffine.
+// Returned encodings share one underlying buffer.
+func (g *G1) ToCompressedBatch(points []*PointG1) [][]byte {
+	g.EnsureAffine(points...)
+	buf := make([]byte, len(points)*G1CompressedSize)
+	out := make([][]byte, len(points))
+	for i := 0; i < len(points); i++ {
+		out[i] = buf[i*G1CompressedSize : (i+1)*G1CompressedSize]
+		g.encodeCompressed(out[i], points[i])
+	}
+	return out
+}
+
+// encodeCompressed writes compressed encoding of a point given in affine form.
+func (g *G1) encodeCompressed(out []byte, p *PointG1) {
 	if g.IsZero(p) {
-		out[0] |= 1 << 6
+		out[0] |= InfinityFlag
 	} else {
 		copy(out[:], toBytes(&p[0]))
 		if !p[1].signBE() {
-			out[0] |= 1 << 5
+			out[0] |= SignFlag
 		}
 	}
-	out[0] |= 1 << 7
-	return out
+	out[0] |= CompressionFlag
+}
+
+// FromCompressedFormat decodes a compressed point serialized in given format.
+// Point is checked to be on curve and in correct subgroup.
+func (g *G1) FromCompressedFormat(compressed []byte, format PointFormat) (*PointG1, error) {
+	switch format {
+	case FormatZcash:
+		return g.FromCompressed(compressed)
+	case FormatMCL:
+		p := new(PointG1)
+		if err := g.fromCompressedMCL(p, compressed); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ToCompressedFormat serializes the point in compressed form of given format.
+func (g *G1) ToCompressedFormat(p *PointG1, format PointFormat) ([]byte, error) {
+	switch format {
+	case FormatZcash:
+		return g.ToCompressed(p), nil
+	case FormatMCL:
+		out := make([]byte, G1CompressedSize)
+		g.Affine(p)
+		if !g.IsZero(p) {
+			copy(out, toBytes(&p[0]))
+			reverseBytes(out)
+			if p[1].Sgn0() == 1 {
+				out[G1CompressedSize-1] |= mclOddFlag
+			}
+		}
+		return out, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+func (g *G1) fromCompressedMCL(p *PointG1, compressed []byte) error {
+	if len(compressed) != G1CompressedSize {
+		return ErrInvalidLength
+	}
+	var in [G1CompressedSize]byte
+	copy(in[:], compressed)
+	if isAllZero(in[:]) {
+		p.Zero()
+		return nil
+	}
+	odd := in[G1CompressedSize-1]&mclOddFlag != 0
+	in[G1CompressedSize-1] &^= mclOddFlag
+	reverseBytes(in[:])
+	x, y := &p[0], &p[1]
+	if err := fromBytesInto(x, in[:]); err != nil {
+		return err
+	}
+	// solve curve equation
+	square(y, x)
+	mul(y, y, x)
+	add(y, y, b)
+	if ok := sqrt(y, y); !ok {
+		return ErrNotOnCurve
+	}
+	if (y.Sgn0() == 1) != odd {
+		neg(y, y)
+	}
+	p[2].one()
+	if !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
 }
 
 func (g *G1) fromBytesUnchecked(in []byte) (*PointG1, error) {
@@ -194,7 +333,7 @@ func (g *G1) fromBytesUnchecked(in []byte) (*PointG1, error) {
 	if err != nil {
 		return nil, err
 	}
-	p2 := new(Fe).one()
+	p2 := new(Fp).one()
 	return &PointG1{*p0, *p1, *p2}, nil
 }
 
@@ -202,33 +341,42 @@ func (g *G1) fromBytesUnchecked(in []byte) (*PointG1, error) {
 // Input string is expected to be equal to 96 bytes and concatenation of x and y cooridanates.
 // (0, 0) is considered as infinity.
 func (g *G1) FromBytes(in []byte) (*PointG1, error) {
-	if len(in) != 2*fpByteSize {
-		return nil, errors.New("input string length must be equal to 96 bytes")
+	p := new(PointG1)
+	if err := g.FromBytesInto(p, in); err != nil {
+		return nil, legacyError(err, errLength96, ErrCompressionFlag)
 	}
-	p0, err := fromBytes(in[:fpByteSize])
-	if err != nil {
-		return nil, err
+	return p, nil
+}
+
+// FromBytesInto decodes 96 bytes input into the point at first argument.
+// It follows the same rules with FromBytes without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G1) FromBytesInto(p *PointG1, in []byte) error {
+	if len(in) != G1UncompressedSize {
+		return ErrInvalidLength
 	}
-	p1, err := fromBytes(in[fpByteSize:])
-	if err != nil {
-		return nil, err
+	if err := fromBytesInto(&p[0], in[:fpByteSize]); err != nil {
+		return err
+	}
+	if err := fromBytesInto(&p[1], in[fpByteSize:]); err != nil {
+		return err
 	}
 	// check if given input points to infinity
-	if p0.isZero() && p1.isZero() {
-		return g.Zero(), nil
+	if p[0].isZero() && p[1].isZero() {
+		p.Zero()
+		return nil
 	}
-	p2 := new(Fe).one()
-	p := &PointG1{*p0, *p1, *p2}
+	p[2].one()
 	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+		return ErrNotOnCurve
 	}
-	return p, nil
+	return nil
 }
 
 // ToBytes serializes a point into bytes in uncompressed form.
 // ToBytes returns (0, 0) if point is infinity.
 func (g *G1) ToBytes(p *PointG1) []byte {
-	out := make([]byte, 2*fpByteSize)
+	out := make([]byte, G1UncompressedSize)
 	if g.IsZero(p) {
 		return out
 	}
@@ -286,24 +434,34 @@ func (g *G1) InCorrectSubgroup(p *PointG1) bool {
 	// S. Bowe
 	// https://eprint.iacr.org/2019/814.pdf
 
-	mulZ := func(p *PointG1) {
-		// z = [(x^2 − 1)/3]
-		z := &Fr{0x0000000055555555, 0x396c8c005555e156}
-		e := z.toWNAF(wnafMulWindowG1)
-		g.wnafMul(p, p, e)
-	}
-
 	// [(x^2 − 1)/3](2σ(P) − P − σ^2(P)) − σ^2(P) ?= O
-	t0 := g.New().Set(p)
-	g.glvEndomorphism(t0, t0)
-	t1 := g.New().Set(t0)     // σ(P)
-	g.glvEndomorphism(t0, t0) // σ^2(P)
-	g.Double(t1, t1)          // 2σ(P)
-	g.Sub(t1, t1, p)          // 2σ(P) − P
-	g.Sub(t1, t1, t0)         // 2σ(P) − P − σ^2(P)
-	mulZ(t1)                  // [(x^2 − 1)/3](2σ(P) − P − σ^2(P))
-	g.Sub(t1, t1, t0)         // [(x^2 − 1)/3](2σ(P) − P − σ^2(P)) − σ^2(P)
-	return g.IsZero(t1)
+	var t0, t1 PointG1
+	t0.Set(p)
+	g.glvEndomorphism(&t0, &t0)
+	t1.Set(&t0)                                                     // σ(P)
+	g.glvEndomorphism(&t0, &t0)                                     // σ^2(P)
+	g.Double(&t1, &t1)                                              // 2σ(P)
+	g.Sub(&t1, &t1, p)                                              // 2σ(P) − P
+	g.Sub(&t1, &t1, &t0)                                            // 2σ(P) − P − σ^2(P)
+	g.wnafMulWindow(&t1, &t1, g1SubgroupWNAF, g1SubgroupWNAFWindow) // [(x^2 − 1)/3](2σ(P) − P − σ^2(P))
+	g.Sub(&t1, &t1, &t0)                                            // [(x^2 − 1)/3](2σ(P) − P − σ^2(P)) − σ^2(P)
+	return g.IsZero(&t1)
+}
+
+// InCorrectSubgroupBatch checks whether given points are in correct subgroup using all available CPUs.
+// Returns nil if all points are valid, otherwise returns a *BatchError carrying indices of invalid points.
+// Points are not modified.
+func (g *G1) InCorrectSubgroupBatch(points []*PointG1) error {
+	invalid := parallelCheck(len(points), func() func(int) bool {
+		g := NewG1()
+		return func(i int) bool {
+			return g.InCorrectSubgroup(points[i])
+		}
+	})
+	if len(invalid) != 0 {
+		return &BatchError{ErrNotInSubgroup, invalid}
+	}
+	return nil
 }
 
 // IsOnCurve checks a G1 point is on curve.
@@ -357,7 +515,7 @@ func (g *G1) affine(r, p *PointG1) *PointG1 {
 
 // AffineBatch given multiple of points returns affine representations
 func (g *G1) AffineBatch(p []*PointG1) {
-	inverses := make([]Fe, len(p))
+	inverses := make([]Fp, len(p))
 	for i := 0; i < len(p); i++ {
 		inverses[i].set(&p[i][2])
 	}
@@ -374,8 +532,54 @@ func (g *G1) AffineBatch(p []*PointG1) {
 	}
 }
 
+// AffineChecked normalizes the given point to affine form in place. Unlike
+// Affine it returns ErrZeroInversion if the z coordinate is zero, since the
+// point at infinity has no affine representation. Callers expecting finite
+// points use it to notice corrupted or degenerate inputs.
+func (g *G1) AffineChecked(p *PointG1) error {
+	if g.IsZero(p) {
+		return ErrZeroInversion
+	}
+	g.affine(p, p)
+	return nil
+}
+
+// AffineBatchChecked normalizes given points to affine form in place with a
+// single batch inversion. Points at infinity are left as is and reported with
+// a *BatchError wrapping ErrZeroInversion.
+func (g *G1) AffineBatchChecked(p []*PointG1) error {
+	var bad []int
+	for i := range p {
+		if g.IsZero(p[i]) {
+			bad = append(bad, i)
+		}
+	}
+	g.AffineBatch(p)
+	if len(bad) != 0 {
+		return &BatchError{Err: ErrZeroInversion, Indices: bad}
+	}
+	return nil
+}
+
+// EnsureAffine normalizes given points to affine form in place.
+// Points that are already affine or at infinity are left as is and the rest share a single batch inversion.
+// Serializers do not invert Z coordinate of an affine point, so repeated encoding of normalized points is cheap.
+func (g *G1) EnsureAffine(points ...*PointG1) {
+	pending := make([]*PointG1, 0, len(points))
+	for _, p := range points {
+		if !g.IsZero(p) && !g.IsAffine(p) {
+			pending = append(pending, p)
+		}
+	}
+	g.AffineBatch(pending)
+}
+
 // Add adds two G1 points p1, p2 and assigns the result to point at first argument.
 func (g *G1) Add(r, p1, p2 *PointG1) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.add", func() []byte { return g.ToUncompressed(g.Add(r, p1, p2)) }, g.ToUncompressed(p1), g.ToUncompressed(p2))
+		return r
+	}
 
 	// http://www.hyperelliptic.org/EFD/gp/auto-shortw-jacobian-0.html#addition-add-2007-bl
 	if g.IsZero(p1) {
@@ -473,6 +677,10 @@ func (g *G1) AddMixed(r, p1, p2 *PointG1) *PointG1 {
 
 // Double doubles a G1 point p and assigns the result to the point at first argument.
 func (g *G1) Double(r, p *PointG1) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.double", func() []byte { return g.ToUncompressed(g.Double(r, p)) }, g.ToUncompressed(p))
+		return r
+	}
 	// http://www.hyperelliptic.org/EFD/gp/auto-shortw-jacobian-0.html#doubling-dbl-2009-l
 	if g.IsZero(p) {
 		return r.Zero()
@@ -505,6 +713,10 @@ func (g *G1) Double(r, p *PointG1) *PointG1 {
 
 // Neg negates a G1 point p and assigns the result to the point at first argument.
 func (g *G1) Neg(r, p *PointG1) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.neg", func() []byte { return g.ToUncompressed(g.Neg(r, p)) }, g.ToUncompressed(p))
+		return r
+	}
 	r[0].set(&p[0])
 	r[2].set(&p[2])
 	neg(&r[1], &p[1])
@@ -513,6 +725,10 @@ func (g *G1) Neg(r, p *PointG1) *PointG1 {
 
 // Sub subtracts two G1 points p1, p2 and assigns the result to point at first argument.
 func (g *G1) Sub(c, a, b *PointG1) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.sub", func() []byte { return g.ToUncompressed(g.Sub(c, a, b)) }, g.ToUncompressed(a), g.ToUncompressed(b))
+		return c
+	}
 	d := &PointG1{}
 	g.Neg(d, b)
 	g.Add(c, a, d)
@@ -521,6 +737,10 @@ func (g *G1) Sub(c, a, b *PointG1) *PointG1 {
 
 // MulScalar multiplies a point by given scalar value and assigns the result to point at first argument.
 func (g *G1) MulScalar(r, p *PointG1, e *Fr) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.mul_scalar", func() []byte { return g.ToUncompressed(g.MulScalar(r, p, e)) }, g.ToUncompressed(p), e.ToBytes())
+		return r
+	}
 	return g.glvMulFr(r, p, e)
 }
 
@@ -564,38 +784,46 @@ func (g *G1) wnafMulBig(r, p *PointG1, e *big.Int) *PointG1 {
 }
 
 func (g *G1) wnafMul(c, p *PointG1, wnaf nafNumber) *PointG1 {
+	return g.wnafMulWindow(c, p, wnaf, wnafMulWindowG1)
+}
 
-	l := (1 << (wnafMulWindowG1 - 1))
+func (g *G1) wnafMulWindow(c, p *PointG1, wnaf nafNumber, w uint) *PointG1 {
 
-	twoP, acc := g.New(), new(PointG1).Set(p)
-	g.Double(twoP, p)
-	g.Affine(twoP)
+	l := (1 << (w - 1))
+
+	var twoP, acc, q PointG1
+	acc.Set(p)
+	g.Double(&twoP, p)
+	g.Affine(&twoP)
 
 	// table = {p, 3p, 5p, ..., -p, -3p, -5p}
-	table := make([]*PointG1, l*2)
-	table[0], table[l] = g.New(), g.New()
+	// table lives in stack unless window is larger than the default one
+	var buf [1 << g1SubgroupWNAFWindow]PointG1
+	table := buf[:]
+	if l*2 > len(buf) {
+		table = make([]PointG1, l*2)
+	}
 	table[0].Set(p)
-	g.Neg(table[l], table[0])
+	g.Neg(&table[l], &table[0])
 
 	for i := 1; i < l; i++ {
-		g.AddMixed(acc, acc, twoP)
-		table[i], table[i+l] = g.New(), g.New()
-		table[i].Set(acc)
-		g.Neg(table[i+l], table[i])
+		g.AddMixed(&acc, &acc, &twoP)
+		table[i].Set(&acc)
+		g.Neg(&table[i+l], &table[i])
 	}
 
-	q := g.Zero()
+	q.Zero()
 	for i := len(wnaf) - 1; i >= 0; i-- {
 		if wnaf[i] > 0 {
-			g.Add(q, q, table[wnaf[i]>>1])
+			g.Add(&q, &q, &table[wnaf[i]>>1])
 		} else if wnaf[i] < 0 {
-			g.Add(q, q, table[((-wnaf[i])>>1)+l])
+			g.Add(&q, &q, &table[((-wnaf[i])>>1)+l])
 		}
 		if i != 0 {
-			g.Double(q, q)
+			g.Double(&q, &q)
 		}
 	}
-	return c.Set(q)
+	return c.Set(&q)
 }
 
 func (g *G1) glvMulFr(r, p *PointG1, e *Fr) *PointG1 {
@@ -607,31 +835,42 @@ func (g *G1) glvMulBig(r, p *PointG1, e *big.Int) *PointG1 {
 }
 
 func (g *G1) glvMul(r, p0 *PointG1, v glvVector) *PointG1 {
+	naf1, naf2 := v.wnaf(glvMulWindowG1)
+	tableK1, tableK2 := newGLVTablesG1()
+	return g.glvMulNAF(r, p0, naf1, naf2, tableK1, tableK2)
+}
 
-	w := glvMulWindowG1
-	l := 1 << (w - 1)
+// newGLVTablesG1 allocates precomputation tables of GLV multiplication.
+func newGLVTablesG1() ([]*PointG1, []*PointG1) {
+	l := 1 << (glvMulWindowG1 - 1)
+	tableK1, tableK2 := make([]*PointG1, l), make([]*PointG1, l)
+	for i := 0; i < l; i++ {
+		tableK1[i], tableK2[i] = new(PointG1), new(PointG1)
+	}
+	return tableK1, tableK2
+}
+
+// glvMulNAF multiplies with recoded GLV decomposition of a scalar. Tables are
+// overwritten and can be reused across calls.
+func (g *G1) glvMulNAF(r, p0 *PointG1, naf1, naf2 nafNumber, tableK1, tableK2 []*PointG1) *PointG1 {
+
+	l := len(tableK1)
 
 	// prepare tables
 	// tableK1 = {P, 3P, 5P, ...}
 	// tableK2 = {λP, 3λP, 5λP, ...}
-	tableK1, tableK2 := make([]*PointG1, l), make([]*PointG1, l)
 	double := g.New()
 	g.Double(double, p0)
 	g.affine(double, double)
-	tableK1[0] = new(PointG1)
 	tableK1[0].Set(p0)
 	for i := 1; i < l; i++ {
-		tableK1[i] = new(PointG1)
 		g.AddMixed(tableK1[i], tableK1[i-1], double)
 	}
 	g.AffineBatch(tableK1)
 	for i := 0; i < l; i++ {
-		tableK2[i] = new(PointG1)
 		g.glvEndomorphism(tableK2[i], tableK1[i])
 	}
 
-	// recode small scalars
-	naf1, naf2 := v.wnaf(w)
 	lenNAF1, lenNAF2 := len(naf1), len(naf2)
 	lenNAF := lenNAF1
 	if lenNAF2 > lenNAF {
@@ -670,6 +909,102 @@ func (g *G1) glvMul(r, p0 *PointG1, v glvVector) *PointG1 {
 	return r.Set(acc)
 }
 
+// MulScalarBatch multiplies all points by the same scalar in place, such as
+// when re-randomizing a commitment vector. Scalar decomposition and recoding
+// are done once and shared across points, results are in affine form.
+func (g *G1) MulScalarBatch(points []PointG1, s *Fr) {
+	naf1, naf2 := new(glvVectorFr).new(s).wnaf(glvMulWindowG1)
+	tableK1, tableK2 := newGLVTablesG1()
+	ptrs := make([]*PointG1, len(points))
+	for i := range points {
+		g.glvMulNAF(&points[i], &points[i], naf1, naf2, tableK1, tableK2)
+		ptrs[i] = &points[i]
+	}
+	g.AffineBatch(ptrs)
+}
+
+// LinearComb2 calculates r = a * p + b * q with Shamir's trick, where both
+// multiplications share a single chain of doublings. It is faster than two
+// scalar multiplications followed by an addition, such as in Schnorr like
+// verification equations.
+func (g *G1) LinearComb2(r *PointG1, a *Fr, p *PointG1, b *Fr, q *PointG1) *PointG1 {
+	return g.straussMul(r, []*PointG1{p, q}, []*Fr{a, b}, true)
+}
+
+// straussMul calculates multi exponentiation with interleaved wNAF
+// multiplications, where all additions are done on a single chain of
+// doublings. If glv is set every scalar is split into two half length
+// scalars, which halves the chain but requires points to be in the subgroup.
+// Precomputation tables of all points are normalized with a single inversion.
+func (g *G1) straussMul(r *PointG1, points []*PointG1, scalars []*Fr, glv bool) *PointG1 {
+	w := glvMulWindowG1
+	l := 1 << (w - 1)
+	k := 1
+	if glv {
+		k = 2
+	}
+	n := len(points)
+	// naf of k*i+j is added from the table at block k*i+j
+	storage := make([]PointG1, k*n*l)
+	table := make([]*PointG1, k*n*l)
+	for i := range table {
+		table[i] = &storage[i]
+	}
+	nafs := make([]nafNumber, k*n)
+	bases := make([]*PointG1, 0, n*l)
+	double := g.New()
+	for i := 0; i < n; i++ {
+		// {P, 3P, 5P, ...}
+		t := table[k*i*l : (k*i+1)*l]
+		g.Double(double, points[i])
+		t[0].Set(points[i])
+		for j := 1; j < l; j++ {
+			g.Add(t[j], t[j-1], double)
+		}
+		bases = append(bases, t...)
+		if glv {
+			nafs[2*i], nafs[2*i+1] = new(glvVectorFr).new(scalars[i]).wnaf(w)
+		} else {
+			nafs[i] = scalars[i].toWNAF(w)
+		}
+	}
+	g.AffineBatch(bases)
+	if glv {
+		for i := 0; i < n; i++ {
+			// {λP, 3λP, 5λP, ...}
+			for j := 0; j < l; j++ {
+				g.glvEndomorphism(table[(2*i+1)*l+j], table[2*i*l+j])
+			}
+		}
+	}
+	lenNAF := 0
+	for i := range nafs {
+		if len(nafs[i]) > lenNAF {
+			lenNAF = len(nafs[i])
+		}
+	}
+
+	acc, p1 := g.New(), g.New()
+	for i := lenNAF - 1; i >= 0; i-- {
+		for t := range nafs {
+			if i >= len(nafs[t]) || nafs[t][i] == 0 {
+				continue
+			}
+			naf := nafs[t][i]
+			if naf > 0 {
+				g.AddMixed(acc, acc, table[t*l+naf>>1])
+			} else {
+				g.Neg(p1, table[t*l+(-naf)>>1])
+				g.AddMixed(acc, acc, p1)
+			}
+		}
+		if i != 0 {
+			g.Double(acc, acc)
+		}
+	}
+	return r.Set(acc)
+}
+
 // MultiExpBig calculates multi exponentiation. Scalar values are received as big.Int type.
 // Given pairs of G1 point and scalar values `(P_0, e_0), (P_1, e_1), ... (P_n, e_n)`,
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`.
@@ -723,22 +1058,109 @@ func (g *G1) MultiExpBig(r *PointG1, points []*PointG1, scalars []*big.Int) (*Po
 // MultiExp calculates multi exponentiation. Given pairs of G1 point and scalar values `(P_0, e_0), (P_1, e_1), ... (P_n, e_n)`,
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`. Length of points and scalars are expected to be equal,
 // otherwise an error is returned. Result is assigned to point at first argument.
+// Up to a few dozen terms interleaved wNAF multiplication is used, where
+// overhead of Pippenger buckets would dominate. From a few hundred terms
+// buckets are accumulated with batched affine additions.
 func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, error) {
+	if g.tracer != nil {
+		var res *PointG1
+		var err error
+		g.traced("g1.multi_exp", func() []byte {
+			if res, err = g.MultiExp(r, points, scalars); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(res)
+		}, g.tracePoints(points), traceScalars(scalars))
+		return res, err
+	}
 	if len(points) != len(scalars) {
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
 
+	if len(points) <= multiExpStraussThresholdG1 {
+		return g.straussMul(r, points, scalars, false), nil
+	}
+
 	g.AffineBatch(points)
 
-	c := 3
-	if len(scalars) >= 32 {
-		c = int(math.Ceil(math.Log(float64(len(scalars)))))
+	if !g.multiExpNoGLV {
+		points, scalars = g.glvSplit(points, scalars)
+		return g.pippenger(r, points, scalars, 128), nil
 	}
+	return g.pippenger(r, points, scalars, frBitSize), nil
+}
 
-	bucketSize := (1 << c) - 1
-	windows := make([]*PointG1, 255/c+1)
-	bucket := make([]PointG1, bucketSize)
+// SetMultiExpGLV enables or disables GLV decomposition of scalars in
+// MultiExp, which is enabled by default. With GLV every term is split into
+// two terms with half length scalars, so that number of bucket windows is
+// halved. Points are then expected to be in the subgroup.
+func (g *G1) SetMultiExpGLV(enabled bool) {
+	g.multiExpNoGLV = !enabled
+}
 
+// SetMultiExpMaxWindow bounds bit length of Pippenger windows used by
+// MultiExp and NewMSMAccumulator, trading speed for memory. Buckets take
+// 2^(c-1) points for each window of c bits, and by default window size grows
+// as natural logarithm of the number of terms. Zero or negative values restore
+// the default.
+func (g *G1) SetMultiExpMaxWindow(c int) {
+	g.multiExpMaxWindow = c
+}
+
+// SetMultiExpBatchAffine enables or disables batched affine additions in
+// MultiExp, which are enabled by default. Batched additions are faster but keep
+// buckets of all windows together with negations of all points in memory,
+// while otherwise buckets of a single window are kept at a time. Accumulators
+// always use batched additions.
+func (g *G1) SetMultiExpBatchAffine(enabled bool) {
+	g.multiExpNoBatchAffine = !enabled
+}
+
+// glvSplit maps affine terms e * P to k1 * P + k2 * λP where k1 and k2 are
+// at most 128 bits. Signs of the decomposition are moved to points.
+func (g *G1) glvSplit(points []*PointG1, scalars []*Fr) ([]*PointG1, []*Fr) {
+	n := len(points)
+	storage := make([]PointG1, 2*n)
+	splitPoints, splitScalars := make([]*PointG1, 2*n), make([]*Fr, 2*n)
+	for i := 0; i < n; i++ {
+		v := new(glvVectorFr).new(scalars[i])
+		p1, p2 := &storage[2*i], &storage[2*i+1]
+		p1.Set(points[i])
+		g.glvEndomorphism(p2, points[i])
+		if v.neg1 {
+			g.Neg(p1, p1)
+		}
+		if !v.neg2 {
+			g.Neg(p2, p2)
+		}
+		splitPoints[2*i], splitPoints[2*i+1] = p1, p2
+		splitScalars[2*i], splitScalars[2*i+1] = v.k1, v.k2
+	}
+	return splitPoints, splitScalars
+}
+
+// pippenger calculates multi exponentiation of affine points with bucket
+// method, where scalars are at most given number of bits.
+// Window digits are signed so that a window needs half as many buckets.
+func (g *G1) pippenger(r *PointG1, points []*PointG1, scalars []*Fr, bits int) *PointG1 {
+	c := g.multiExpWindow(len(scalars))
+	bucketSize := 1 << (c - 1)
+	windows := make([]*PointG1, bits/c+1)
+	digits := signedWindows(scalars, c, len(windows))
+
+	if len(scalars) >= multiExpBatchAffineThresholdG1 && !g.multiExpNoBatchAffine {
+		// Buckets of all windows are filled in a single pass so that rounds
+		// of batched additions are large and rarely hit the same bucket.
+		a := g.newBatchAffine(len(windows), bucketSize)
+		a.accumulate(points, digits)
+		for j := 0; j < len(windows); j++ {
+			windows[j] = a.window(j)
+		}
+		return g.combineWindows(r, windows, c)
+	}
+
+	bucket := make([]PointG1, bucketSize)
+	t := g.New()
 	for j := 0; j < len(windows); j++ {
 
 		for i := 0; i < bucketSize; i++ {
@@ -746,9 +1168,11 @@ func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, e
 		}
 
 		for i := 0; i < len(scalars); i++ {
-			index := bucketSize & int(scalars[i].sliceUint64(c*j))
-			if index != 0 {
+			index := digits[i*len(windows)+j]
+			if index > 0 {
 				g.AddMixed(&bucket[index-1], &bucket[index-1], points[i])
+			} else if index < 0 {
+				g.AddMixed(&bucket[-index-1], &bucket[-index-1], g.Neg(t, points[i]))
 			}
 		}
 
@@ -757,9 +1181,32 @@ func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, e
 			g.Add(sum, sum, &bucket[i])
 			g.Add(acc, acc, sum)
 		}
-		windows[j] = g.New().Set(acc)
+		windows[j] = acc
 	}
+	return g.combineWindows(r, windows, c)
+}
+
+// multiExpWindowG1 returns bit length of Pippenger windows for given number
+// of terms.
+func multiExpWindowG1(n int) int {
+	if n < 32 {
+		return 3
+	}
+	return int(math.Ceil(math.Log(float64(n))))
+}
+
+// multiExpWindow returns bit length of Pippenger windows for given number of
+// terms bounded as set by SetMultiExpMaxWindow.
+func (g *G1) multiExpWindow(n int) int {
+	c := multiExpWindowG1(n)
+	if g.multiExpMaxWindow > 0 && c > g.multiExpMaxWindow {
+		c = g.multiExpMaxWindow
+	}
+	return c
+}
 
+// combineWindows calculates sum of 2^(c*i) * windows[i].
+func (g *G1) combineWindows(r *PointG1, windows []*PointG1, c int) *PointG1 {
 	g.AffineBatch(windows)
 
 	acc := g.New()
@@ -769,10 +1216,273 @@ func (g *G1) MultiExp(r *PointG1, points []*PointG1, scalars []*Fr) (*PointG1, e
 		}
 		g.AddMixed(acc, acc, windows[i])
 	}
-	return r.Set(acc), nil
+	return r.Set(acc)
+}
+
+// batchAffineG1 accumulates points into affine buckets. Instead of adding a
+// point into its bucket right away, additions are collected and resolved
+// together so that all slope denominators of a round share one inversion.
+// An affine addition then costs about six multiplications against eleven of
+// a mixed addition. A bucket takes at most one addition per round, further
+// additions to a busy bucket wait in a queue for the next round. Once the
+// queue is full, as happens when many digits fall into a few buckets,
+// additions go to Jacobian overflow buckets with mixed addition instead.
+type batchAffineG1 struct {
+	g          *G1
+	windows    int
+	bucketSize int
+	buckets    []PointG1
+	overflow   []PointG1
+	// neg holds negations of input points for negative window digits.
+	neg    []PointG1
+	busy   []bool
+	index  []int
+	points []*PointG1
+	denoms []Fp
+	queueB []int
+	queueP []*PointG1
+}
+
+func (g *G1) newBatchAffine(windows, bucketSize int) *batchAffineG1 {
+	n := windows * bucketSize
+	size := n / 4
+	if size > multiExpBatchAffineMaxRound {
+		size = multiExpBatchAffineMaxRound
+	}
+	a := &batchAffineG1{
+		g:          g,
+		windows:    windows,
+		bucketSize: bucketSize,
+		buckets:    make([]PointG1, n),
+		busy:       make([]bool, n),
+		index:      make([]int, 0, size),
+		points:     make([]*PointG1, 0, size),
+		denoms:     make([]Fp, size),
+		queueB:     make([]int, 0, size),
+		queueP:     make([]*PointG1, 0, size),
+	}
+	a.reset()
+	return a
+}
+
+// reset empties all buckets.
+func (a *batchAffineG1) reset() {
+	for i := range a.buckets {
+		a.buckets[i].Zero()
+	}
+	a.overflow = nil
+}
+
+// accumulate adds affine points into buckets of their signed window digits.
+// Digits are laid out as returned by signedWindows.
+func (a *batchAffineG1) accumulate(points []*PointG1, digits []int) {
+	if cap(a.neg) < len(points) {
+		a.neg = make([]PointG1, len(points))
+	}
+	a.neg = a.neg[:len(points)]
+	for i := range points {
+		a.g.Neg(&a.neg[i], points[i])
+	}
+	w := a.windows
+	for i := range points {
+		for j := 0; j < w; j++ {
+			index := digits[i*w+j]
+			if index > 0 {
+				a.add(j*a.bucketSize+index-1, points[i])
+			} else if index < 0 {
+				a.add(j*a.bucketSize-index-1, &a.neg[i])
+			}
+		}
+	}
+	a.flush(true)
+	a.flush(false)
+	for k := range a.queueB {
+		a.addOverflow(a.queueB[k], a.queueP[k])
+	}
+	a.queueB, a.queueP = a.queueB[:0], a.queueP[:0]
+}
+
+// window returns sum of i * bucket[i-1] over buckets of j-th window.
+func (a *batchAffineG1) window(j int) *PointG1 {
+	g := a.g
+	from, to := j*a.bucketSize, (j+1)*a.bucketSize
+	acc, sum := g.New(), g.New()
+	for i := to - 1; i >= from; i-- {
+		g.AddMixed(sum, sum, &a.buckets[i])
+		if a.overflow != nil {
+			g.Add(sum, sum, &a.overflow[i])
+		}
+		g.Add(acc, acc, sum)
+	}
+	return acc
+}
+
+// add schedules addition of an affine point into given bucket.
+func (a *batchAffineG1) add(b int, p *PointG1) {
+	if a.g.IsZero(p) {
+		return
+	}
+	if !a.busy[b] && len(a.index) == cap(a.index) {
+		a.flush(true)
+	}
+	// draining the queue may fill the round again or take the bucket
+	if a.busy[b] || len(a.index) == cap(a.index) {
+		if len(a.queueB) == cap(a.queueB) {
+			a.addOverflow(b, p)
+			return
+		}
+		a.queueB, a.queueP = append(a.queueB, b), append(a.queueP, p)
+		return
+	}
+	a.schedule(b, p)
+}
+
+// schedule adds given point into the current round, bucket must not be busy.
+func (a *batchAffineG1) schedule(b int, p *PointG1) {
+	if a.g.IsZero(&a.buckets[b]) {
+		a.buckets[b].Set(p)
+		return
+	}
+	a.busy[b] = true
+	a.index, a.points = append(a.index, b), append(a.points, p)
+}
+
+func (a *batchAffineG1) addOverflow(b int, p *PointG1) {
+	if a.overflow == nil {
+		a.overflow = make([]PointG1, len(a.buckets))
+		for i := range a.overflow {
+			a.overflow[i].Zero()
+		}
+	}
+	a.g.AddMixed(&a.overflow[b], &a.overflow[b], p)
+}
+
+// flush resolves additions of the current round. If drain is set queued
+// additions are moved into the next round.
+func (a *batchAffineG1) flush(drain bool) {
+	t0, t1, t2 := a.g.t[0], a.g.t[1], a.g.t[2]
+	n := len(a.index)
+	for k := 0; k < n; k++ {
+		p1, p2 := &a.buckets[a.index[k]], a.points[k]
+		if !p1[0].equal(&p2[0]) {
+			sub(&a.denoms[k], &p2[0], &p1[0]) // x2 - x1
+		} else if p1[1].equal(&p2[1]) {
+			double(&a.denoms[k], &p1[1]) // 2y1
+		} else {
+			a.denoms[k].zero() // p1 == -p2
+		}
+	}
+	inverseBatch(a.denoms[:n])
+	for k := 0; k < n; k++ {
+		b := a.index[k]
+		p1, p2 := &a.buckets[b], a.points[k]
+		a.busy[b] = false
+		if a.denoms[k].isZero() {
+			p1.Zero()
+			continue
+		}
+		if !p1[0].equal(&p2[0]) {
+			sub(t0, &p2[1], &p1[1])
+		} else {
+			square(t0, &p1[0])
+			double(t1, t0)
+			add(t0, t1, t0)
+		}
+		mul(t0, t0, &a.denoms[k]) // λ
+		square(t1, t0)
+		sub(t1, t1, &p1[0])
+		sub(t1, t1, &p2[0]) // x3 = λ^2 - x1 - x2
+		sub(t2, &p1[0], t1)
+		mul(t2, t2, t0)
+		sub(&p1[1], t2, &p1[1]) // y3 = λ(x1 - x3) - y1
+		p1[0].set(t1)
+	}
+	a.index, a.points = a.index[:0], a.points[:0]
+	if !drain {
+		return
+	}
+
+	queueB, queueP := a.queueB, a.queueP
+	a.queueB, a.queueP = a.queueB[:0], a.queueP[:0]
+	for k := range queueB {
+		if len(a.index) == cap(a.index) || a.busy[queueB[k]] {
+			a.queueB, a.queueP = append(a.queueB, queueB[k]), append(a.queueP, queueP[k])
+			continue
+		}
+		a.schedule(queueB[k], queueP[k])
+	}
+}
+
+// MSMAccumulator calculates multi exponentiation of terms that are given in
+// chunks, such as when points of a large structured reference string are
+// streamed from disk. Only bucket state is kept between chunks, so memory use
+// depends on the window size but not on the number of terms.
+//
+// An accumulator uses temporaries of the G1 instance it is created from and
+// is not suitable for concurrent use either.
+type MSMAccumulator struct {
+	g     *G1
+	c     int
+	bits  int
+	glv   bool
+	batch *batchAffineG1
+}
+
+// maxMSMAccumulatorWindow bounds window size and therefore memory of an
+// accumulator, which holds 2^(c-1) buckets per window.
+const maxMSMAccumulatorWindow = 16
+
+// NewMSMAccumulator returns a new accumulator for about given number of terms
+// in total, which is used to choose the window size. GLV decomposition and
+// window size bound are applied as configured by SetMultiExpGLV and
+// SetMultiExpMaxWindow.
+func (g *G1) NewMSMAccumulator(size int) *MSMAccumulator {
+	bits, glv := frBitSize, !g.multiExpNoGLV
+	if glv {
+		bits, size = 128, 2*size
+	}
+	c := g.multiExpWindow(size)
+	if c > maxMSMAccumulatorWindow {
+		c = maxMSMAccumulatorWindow
+	}
+	return &MSMAccumulator{g, c, bits, glv, g.newBatchAffine(bits/c+1, 1<<(c-1))}
+}
+
+// Add accumulates a chunk of terms. Length of points and scalars are
+// expected to be equal, otherwise an error is returned. Points are converted
+// to affine form in place.
+func (m *MSMAccumulator) Add(points []*PointG1, scalars []*Fr) error {
+	if len(points) != len(scalars) {
+		return errors.New("point and scalar vectors should be in same length")
+	}
+	m.g.AffineBatch(points)
+	if m.glv {
+		points, scalars = m.g.glvSplit(points, scalars)
+	}
+	m.batch.accumulate(points, signedWindows(scalars, m.c, m.batch.windows))
+	return nil
+}
+
+// Result assigns sum of all terms added so far to the point at first
+// argument. Accumulator can still be extended after calling Result.
+func (m *MSMAccumulator) Result(r *PointG1) *PointG1 {
+	windows := make([]*PointG1, m.batch.windows)
+	for j := range windows {
+		windows[j] = m.batch.window(j)
+	}
+	return m.g.combineWindows(r, windows, m.c)
+}
+
+// Reset discards all terms added so far.
+func (m *MSMAccumulator) Reset() {
+	m.batch.reset()
 }
 
 func (g *G1) ClearCofactor(p *PointG1) *PointG1 {
+	if g.tracer != nil {
+		g.traced("g1.clear_cofactor", func() []byte { return g.ToUncompressed(g.ClearCofactor(p)) }, g.ToUncompressed(p))
+		return p
+	}
 	chain := func(p0 *PointG1, n int, p1 *PointG1) {
 		for i := 0; i < n; i++ {
 			g.Double(p0, p0)
@@ -793,34 +1503,56 @@ func (g *G1) ClearCofactor(p *PointG1) *PointG1 {
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
 // Input byte slice should be a valid field element, otherwise an error is returned.
+// Unlike EncodeToCurve and HashToCurve, the point at infinity is a valid output.
 func (g *G1) MapToCurve(in []byte) (*PointG1, error) {
 	u, err := fromBytes(in)
 	if err != nil {
 		return nil, err
 	}
-	x, y := swuMapG1(u)
-	isogenyMapG1(x, y)
-	one := new(Fe).one()
-	p := &PointG1{*x, *y, *one}
+	p := g.mapToCurve(g.New(), u)
 	g.ClearCofactor(p)
 	return g.Affine(p), nil
 }
 
+// mapToCurve maps a field element to a curve point with SSWU method and the
+// isogeny map. Output is not cofactor cleared.
+func (g *G1) mapToCurve(p *PointG1, u *Fp) *PointG1 {
+	x, y := swuMapG1(u)
+	if !isogenyMapG1(x, y) {
+		return p.Zero()
+	}
+	p[0].set(x)
+	p[1].set(y)
+	p[2].one()
+	return p
+}
+
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// ErrHashToInfinity is returned if the result is the point at infinity.
 func (g *G1) EncodeToCurve(msg, domain []byte) (*PointG1, error) {
+	if g.tracer != nil {
+		var p *PointG1
+		var err error
+		g.traced("g1.encode_to_curve", func() []byte {
+			if p, err = g.EncodeToCurve(msg, domain); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(p)
+		}, msg, domain)
+		return p, err
+	}
 	hashRes, err := HashToFpXMDSHA256(msg, domain, 1)
 	if err != nil {
 		return nil, err
 	}
-	u := hashRes[0]
-	x, y := swuMapG1(u)
-	isogenyMapG1(x, y)
-	one := new(Fe).one()
-	p := &PointG1{*x, *y, *one}
+	p := g.mapToCurve(g.New(), hashRes[0])
 	g.ClearCofactor(p)
+	if g.IsZero(p) {
+		return nil, ErrHashToInfinity
+	}
 	return g.Affine(p), nil
 }
 
@@ -828,19 +1560,36 @@ func (g *G1) EncodeToCurve(msg, domain []byte) (*PointG1, error) {
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// ErrHashToInfinity is returned if the result is the point at infinity.
 func (g *G1) HashToCurve(msg, domain []byte) (*PointG1, error) {
+	if g.tracer != nil {
+		var p *PointG1
+		var err error
+		g.traced("g1.hash_to_curve", func() []byte {
+			if p, err = g.HashToCurve(msg, domain); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(p)
+		}, msg, domain)
+		return p, err
+	}
 	hashRes, err := HashToFpXMDSHA256(msg, domain, 2)
 	if err != nil {
 		return nil, err
 	}
-	u0, u1 := hashRes[0], hashRes[1]
-	x0, y0 := swuMapG1(u0)
-	x1, y1 := swuMapG1(u1)
-	one := new(Fe).one()
-	p0, p1 := &PointG1{*x0, *y0, *one}, &PointG1{*x1, *y1, *one}
+	return g.hashToCurve(hashRes)
+}
+
+// hashToCurve maps two field elements to the curve and clears cofactor of
+// their sum.
+func (g *G1) hashToCurve(hashRes []*Fp) (*PointG1, error) {
+	// Both points are mapped to the curve before they are added since
+	// doubling formula does not hold for the isogenous curve.
+	p0, p1 := g.mapToCurve(g.New(), hashRes[0]), g.mapToCurve(g.New(), hashRes[1])
 	g.Add(p0, p0, p1)
-	g.Affine(p0)
-	isogenyMapG1(&p0[0], &p0[1])
 	g.ClearCofactor(p0)
+	if g.IsZero(p0) {
+		return nil, ErrHashToInfinity
+	}
 	return g.Affine(p0), nil
 }
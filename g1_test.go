@@ -15,16 +15,16 @@ func (g *G1) one() *PointG1 {
 
 func (g *G1) rand() *PointG1 {
 	p := &PointG1{}
-	z, _ := new(Fe).rand(rand.Reader)
-	z6, bz6 := new(Fe), new(Fe)
+	z, _ := new(Fp).rand(rand.Reader)
+	z6, bz6 := new(Fp), new(Fp)
 	square(z6, z)
 	square(z6, z6)
 	mul(z6, z6, z)
 	mul(z6, z6, z)
 	mul(bz6, z6, b)
 	for {
-		x, _ := new(Fe).rand(rand.Reader)
-		y := new(Fe)
+		x, _ := new(Fp).rand(rand.Reader)
+		y := new(Fp)
 		square(y, x)
 		mul(y, y, x)
 		add(y, y, bz6)
@@ -124,7 +124,7 @@ func TestG1IsOnCurve(t *testing.T) {
 	if !g.IsOnCurve(zero) {
 		t.Fatal("zero must be on curve")
 	}
-	one := new(Fe).one()
+	one := new(Fp).one()
 	p := &PointG1{*one, *one, *one}
 	if g.IsOnCurve(p) {
 		t.Fatal("(1, 1) is not on curve")
@@ -151,6 +151,82 @@ func TestG1BatchAffine(t *testing.T) {
 	}
 }
 
+func TestG1EnsureAffine(t *testing.T) {
+	n := 20
+	g := NewG1()
+	points := make([]*PointG1, n)
+	expected := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+		expected[i] = g.ToCompressed(g.New().Set(points[i]))
+	}
+	points[3] = g.Zero()
+	expected[3] = g.ToCompressed(g.Zero())
+	g.Affine(points[5])
+	g.EnsureAffine(points...)
+	for i := 0; i < n; i++ {
+		if !g.IsZero(points[i]) && !g.IsAffine(points[i]) {
+			t.Fatal("expect affine point")
+		}
+	}
+	out := g.ToCompressedBatch(points)
+	for i := 0; i < n; i++ {
+		if !bytes.Equal(out[i], expected[i]) {
+			t.Fatal("batch serialization failed")
+		}
+	}
+}
+
+func TestG1FromBig(t *testing.T) {
+	g := NewG1()
+	p := g.Affine(g.randCorrect())
+	x, y := p[0].Big(), p[1].Big()
+	q, err := PointG1FromBig(x, y)
+	if err != nil || !g.Equal(p, q) {
+		t.Fatal("point must be constructed", err)
+	}
+	if _, err := PointG1FromBig(x, new(big.Int).Add(y, big.NewInt(1))); err != ErrNotOnCurve {
+		t.Fatal("point must be on curve", err)
+	}
+	if _, err := PointG1FromBig(x, new(big.Int).Add(y, modulus.big())); err != ErrNonCanonical {
+		t.Fatal("coordinates must be canonical", err)
+	}
+	r := g.Affine(g.rand())
+	if _, err := PointG1FromBig(r[0].Big(), r[1].Big()); err != ErrNotInSubgroup {
+		t.Fatal("point must be in correct subgroup", err)
+	}
+	if _, err := PointG1FromBig(new(big.Int), new(big.Int)); err != ErrNotOnCurve {
+		t.Fatal("infinity must be rejected", err)
+	}
+}
+
+func TestG1AffineChecked(t *testing.T) {
+	g := NewG1()
+	p := g.rand()
+	expected := g.Affine(g.New().Set(p))
+	if err := g.AffineChecked(p); err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsAffine(p) || !g.Equal(p, expected) {
+		t.Fatal("point must be normalized")
+	}
+	if err := g.AffineChecked(g.Zero()); err != ErrZeroInversion {
+		t.Fatal("point at infinity must be rejected")
+	}
+	points := []*PointG1{g.rand(), g.Zero(), g.rand(), g.Zero()}
+	err := g.AffineBatchChecked(points)
+	batchErr, ok := err.(*BatchError)
+	if !ok || batchErr.Err != ErrZeroInversion || len(batchErr.Indices) != 2 || batchErr.Indices[0] != 1 || batchErr.Indices[1] != 3 {
+		t.Fatal("points at infinity must be reported", err)
+	}
+	if !g.IsAffine(points[0]) || !g.IsAffine(points[2]) || !g.IsZero(points[1]) {
+		t.Fatal("finite points must be normalized")
+	}
+	if err := g.AffineBatchChecked(points[:1]); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestG1AdditiveProperties(t *testing.T) {
 	g := NewG1()
 	t0, t1 := g.New(), g.New()
@@ -416,6 +492,283 @@ func TestG1MultiExpBig(t *testing.T) {
 	}
 }
 
+func TestG1MulScalarBatch(t *testing.T) {
+	g := NewG1()
+	n := 16
+	points := make([]PointG1, n)
+	for i := 1; i < n; i++ {
+		points[i].Set(g.randCorrect())
+	}
+	s, _ := new(Fr).Rand(rand.Reader)
+	expected := make([]PointG1, n)
+	for i := range points {
+		g.mulScalar(&expected[i], &points[i], s)
+	}
+	g.MulScalarBatch(points, s)
+	for i := range points {
+		if !g.Equal(&points[i], &expected[i]) {
+			t.Fatal("batch scalar multiplication failed", i)
+		}
+		if !g.IsZero(&points[i]) && !g.IsAffine(&points[i]) {
+			t.Fatal("results must be affine")
+		}
+	}
+}
+
+func TestG1LinearComb2(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < fuz; i++ {
+		p, q := g.randCorrect(), g.randCorrect()
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		expected, tmp := g.New(), g.New()
+		g.mulScalar(expected, p, a)
+		g.mulScalar(tmp, q, b)
+		g.Add(expected, expected, tmp)
+		if r := g.LinearComb2(g.New(), a, p, b, q); !g.Equal(r, expected) {
+			t.Fatal("linear combination failed")
+		}
+		// a * p + 0 * q
+		g.mulScalar(expected, p, a)
+		if r := g.LinearComb2(g.New(), a, p, new(Fr), q); !g.Equal(r, expected) {
+			t.Fatal("linear combination with zero scalar failed")
+		}
+		if r := g.LinearComb2(g.New(), a, p, b, g.Zero()); !g.Equal(r, expected) {
+			t.Fatal("linear combination with zero point failed")
+		}
+		// a * p - a * p
+		n := new(Fr)
+		n.Neg(a)
+		if r := g.LinearComb2(g.New(), a, p, n, p); !g.IsZero(r) {
+			t.Fatal("linear combination must be zero")
+		}
+	}
+}
+
+func TestG1MultiExpStrauss(t *testing.T) {
+	g := NewG1()
+	threshold := multiExpStraussThresholdG1
+	defer func() { multiExpStraussThresholdG1 = threshold }()
+	for n := 0; n <= threshold+1; n++ {
+		bases := make([]*PointG1, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.randCorrect()
+		}
+		multiExpStraussThresholdG1 = threshold
+		r0, _ := g.MultiExp(g.New(), bases, scalars)
+		multiExpStraussThresholdG1 = -1
+		r1, _ := g.MultiExp(g.New(), bases, scalars)
+		if !g.Equal(r0, r1) {
+			t.Fatal("interleaved and bucket multi exponentiation mismatch", n)
+		}
+	}
+}
+
+func TestG1MultiExpGLV(t *testing.T) {
+	g := NewG1()
+	for _, n := range []int{33, 100, 512} {
+		bases := make([]*PointG1, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.randCorrect()
+		}
+		// edge scalars
+		scalars[0].Zero()
+		scalars[1].One()
+		scalars[2].Neg(scalars[1])
+		g.SetMultiExpGLV(true)
+		r0, _ := g.MultiExp(g.New(), bases, scalars)
+		g.SetMultiExpGLV(false)
+		r1, _ := g.MultiExp(g.New(), bases, scalars)
+		if !g.Equal(r0, r1) {
+			t.Fatal("multi exponentiation with and without glv mismatch", n)
+		}
+	}
+}
+
+func TestG1MultiExpBatchAffine(t *testing.T) {
+	g := NewG1()
+	threshold := multiExpBatchAffineThresholdG1
+	defer func() { multiExpBatchAffineThresholdG1 = threshold }()
+	n := 300
+	bases := make([]*PointG1, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		bases[i] = g.randCorrect()
+	}
+	// doubling, cancellation and zero inside buckets
+	for i := 0; i < 20; i++ {
+		bases[i+20].Set(bases[i])
+		scalars[i+20].Set(scalars[i])
+		g.Neg(bases[i+40], bases[i])
+		scalars[i+40].Set(scalars[i])
+		bases[i+60].Zero()
+	}
+	same := make([]*Fr, n)
+	for i := range same {
+		same[i] = scalars[0]
+	}
+	for _, glv := range []bool{true, false} {
+		g.SetMultiExpGLV(glv)
+		for _, s := range [][]*Fr{scalars, same} {
+			multiExpBatchAffineThresholdG1 = 0
+			r0, _ := g.MultiExp(g.New(), bases, s)
+			multiExpBatchAffineThresholdG1 = n + 1
+			r1, _ := g.MultiExp(g.New(), bases, s)
+			if !g.Equal(r0, r1) {
+				t.Fatal("batch affine accumulation mismatch", glv)
+			}
+		}
+	}
+	g.SetMultiExpGLV(true)
+}
+
+func TestG1MultiExpStructuredScalars(t *testing.T) {
+	// d * sum(2^(c*j)) for j in [from, from+count) has digit d in count
+	// consecutive windows of c bits. Terms fill a batch affine round, are
+	// repeated until the queue is full and the last term then flushes the
+	// round, which refills it from the queue.
+	g := NewG1()
+	for _, v := range []struct {
+		n, c  int
+		glv   bool
+		terms [][3]int
+	}{
+		{64, 0, true, [][3]int{{1, 0, 13}, {2, 0, 13}, {3, 0, 13}, {4, 0, 13}, {5, 12, 13}, {6, 12, 13}, {7, 12, 13}, {8, 12, 13}, {9, 0, 13}}},
+		{128, 2, false, [][3]int{{1, 0, 16}, {2, 0, 16}, {1, 16, 16}, {2, 16, 16}, {1, 32, 16}}},
+	} {
+		g.SetMultiExpGLV(v.glv)
+		g.SetMultiExpMaxWindow(v.c)
+		c := uint(g.multiExpWindow(v.n))
+		if v.glv {
+			c = uint(g.multiExpWindow(2 * v.n))
+		}
+		last := len(v.terms) - 1
+		terms := append(append(append(v.terms, v.terms[:last]...), v.terms[:last]...), v.terms[last])
+		bases := make([]*PointG1, v.n)
+		scalars := make([]*Fr, v.n)
+		expected, tmp := g.New(), g.New()
+		for i := 0; i < v.n; i++ {
+			bases[i] = g.randAffine()
+			s := new(big.Int)
+			if i < len(terms) {
+				d, from, count := terms[i][0], terms[i][1], terms[i][2]
+				for j := from + count - 1; j >= from; j-- {
+					s.Lsh(s, c).Add(s, big.NewInt(int64(d)))
+				}
+				s.Lsh(s, c*uint(from))
+			}
+			scalars[i] = new(Fr).fromBig(s)
+			g.Add(expected, expected, g.MulScalar(tmp, bases[i], scalars[i]))
+		}
+		result, err := g.MultiExp(g.New(), bases, scalars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(expected, result) {
+			t.Fatal("structured scalars multi exponentiation mismatch", v.n)
+		}
+		acc := g.NewMSMAccumulator(v.n)
+		for _, chunk := range []int{v.n, 5} {
+			acc.Reset()
+			for i := 0; i < v.n; i += chunk {
+				j := i + chunk
+				if j > v.n {
+					j = v.n
+				}
+				if err := acc.Add(bases[i:j], scalars[i:j]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !g.Equal(expected, acc.Result(g.New())) {
+				t.Fatal("structured scalars accumulator mismatch", v.n, chunk)
+			}
+		}
+	}
+	g.SetMultiExpGLV(true)
+	g.SetMultiExpMaxWindow(0)
+}
+
+func TestG1MultiExpMemoryOptions(t *testing.T) {
+	g := NewG1()
+	n := 300
+	bases := make([]*PointG1, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		bases[i] = g.randCorrect()
+	}
+	expected, _ := g.MultiExp(g.New(), bases, scalars)
+	for _, c := range []int{1, 2, 5} {
+		g.SetMultiExpMaxWindow(c)
+		for _, batch := range []bool{true, false} {
+			g.SetMultiExpBatchAffine(batch)
+			r, _ := g.MultiExp(g.New(), bases, scalars)
+			if !g.Equal(r, expected) {
+				t.Fatal("bounded window multi exponentiation mismatch", c, batch)
+			}
+		}
+		acc := g.NewMSMAccumulator(n)
+		if acc.c > c {
+			t.Fatal("accumulator window must be bounded", c)
+		}
+		_ = acc.Add(bases, scalars)
+		if !g.Equal(acc.Result(g.New()), expected) {
+			t.Fatal("bounded window accumulator mismatch", c)
+		}
+	}
+	g.SetMultiExpMaxWindow(0)
+	g.SetMultiExpBatchAffine(true)
+}
+
+func TestG1MSMAccumulator(t *testing.T) {
+	g := NewG1()
+	n := 500
+	bases := make([]*PointG1, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		bases[i] = g.randCorrect()
+	}
+	expected, _ := g.MultiExp(g.New(), bases, scalars)
+	for _, glv := range []bool{true, false} {
+		g.SetMultiExpGLV(glv)
+		acc := g.NewMSMAccumulator(n)
+		if !g.IsZero(acc.Result(g.New())) {
+			t.Fatal("empty accumulator must be zero")
+		}
+		for _, chunk := range []int{1, 7, 50, 200} {
+			acc.Reset()
+			for i := 0; i < n; i += chunk {
+				j := i + chunk
+				if j > n {
+					j = n
+				}
+				if err := acc.Add(bases[i:j], scalars[i:j]); err != nil {
+					t.Fatal(err)
+				}
+				if j == n/2 {
+					partial, _ := g.MultiExp(g.New(), bases[:j], scalars[:j])
+					if !g.Equal(partial, acc.Result(g.New())) {
+						t.Fatal("partial result mismatch")
+					}
+				}
+			}
+			if !g.Equal(expected, acc.Result(g.New())) {
+				t.Fatal("streamed multi exponentiation mismatch", glv, chunk)
+			}
+		}
+		if err := acc.Add(bases[:1], scalars[:2]); err == nil {
+			t.Fatal("length mismatch must be rejected")
+		}
+	}
+	g.SetMultiExpGLV(true)
+}
+
 func TestG1MultiExp(t *testing.T) {
 	g := NewG1()
 	for n := 1; n < 1024+1; n = n * 2 {
@@ -456,6 +809,42 @@ func TestG1ClearCofactor(t *testing.T) {
 	}
 }
 
+func TestG1SubgroupCheckBatch(t *testing.T) {
+	g := NewG1()
+	n := 50
+	points := make([]*PointG1, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.randCorrect()
+	}
+	points[n/2] = g.Zero()
+	if err := g.InCorrectSubgroupBatch(points); err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{3, 17, n - 1}
+	for _, i := range expected {
+		points[i] = g.rand()
+	}
+	err := g.InCorrectSubgroupBatch(points)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatal("expect batch error")
+	}
+	if batchErr.Unwrap() != ErrNotInSubgroup || batchErr.First() != expected[0] {
+		t.Fatal("bad batch error", err)
+	}
+	if len(batchErr.Indices) != len(expected) {
+		t.Fatal("bad number of invalid points", batchErr.Indices)
+	}
+	for i := range expected {
+		if batchErr.Indices[i] != expected[i] {
+			t.Fatal("bad invalid indices", batchErr.Indices)
+		}
+	}
+	if err := g.InCorrectSubgroupBatch(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestG1MapToCurve(t *testing.T) {
 	for i, v := range []struct {
 		u        []byte
@@ -554,6 +943,45 @@ func TestG1EncodeToCurve(t *testing.T) {
 	}
 }
 
+func TestHasherG1(t *testing.T) {
+	g := NewG1()
+	domain := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	msg := make([]byte, 1000)
+	_, _ = rand.Read(msg)
+	h := NewHasherG1(domain)
+	for i := 0; i < len(msg); i += 37 {
+		end := i + 37
+		if end > len(msg) {
+			end = len(msg)
+		}
+		_, _ = h.Write(msg[i:end])
+		if i == 37*10 {
+			// sum must not change the state
+			if _, err := h.Sum(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	want, err := g.HashToCurve(msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		have, err := h.Sum()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(have, want) {
+			t.Fatal("streamed hash does not match")
+		}
+	}
+	h.Reset()
+	want, _ = g.HashToCurve(nil, domain)
+	if have, _ := h.Sum(); !g.Equal(have, want) {
+		t.Fatal("hash of empty message does not match after reset")
+	}
+}
+
 func TestG1HashToCurve(t *testing.T) {
 	domain := []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_TESTGEN")
 	for i, v := range []struct {
@@ -600,6 +1028,74 @@ func TestG1HashToCurve(t *testing.T) {
 	}
 }
 
+func TestG1SWUExceptionalCases(t *testing.T) {
+	g := NewG1()
+	params := swuParamsForG1
+	onIsogenousCurve := func(x, y *Fp) bool {
+		// y^2 == x^3 + a * x + b
+		l, r := new(Fp), new(Fp)
+		square(l, y)
+		square(r, x)
+		add(r, r, params.a)
+		mul(r, r, x)
+		add(r, r, params.b)
+		return l.equal(r)
+	}
+	// u = 0 and u^2 = -1 / z are the only inputs where tv1 denominator
+	// z^2 * u^4 + z * u^2 is zero, x1 must fall back to b / (z * a).
+	u0 := new(Fp)
+	u1 := new(Fp)
+	inverse(u1, params.z)
+	neg(u1, u1)
+	if !sqrt(u1, u1) {
+		t.Fatal("-1 / z is expected to be a square")
+	}
+	u2 := new(Fp)
+	neg(u2, u1)
+	for i, u := range []*Fp{u0, u1, u2} {
+		x, y := swuMapG1(u)
+		if !onIsogenousCurve(x, y) {
+			t.Fatal("swu output is not on isogenous curve", i)
+		}
+		xExpected := new(Fp)
+		mul(xExpected, params.z, params.a)
+		inverse(xExpected, xExpected)
+		mul(xExpected, xExpected, params.b)
+		if !x.equal(xExpected) {
+			t.Fatal("bad exceptional case x", i)
+		}
+		if y.Sgn0() != u.Sgn0() {
+			t.Fatal("bad sign of y", i)
+		}
+		p := g.mapToCurve(g.New(), u)
+		if !g.IsOnCurve(p) {
+			t.Fatal("mapped point is not on curve", i)
+		}
+		g.ClearCofactor(p)
+		if g.IsZero(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("cofactor cleared point is not valid", i)
+		}
+	}
+	x1, y1 := swuMapG1(u1)
+	x2, y2 := swuMapG1(u2)
+	neg(y2, y2)
+	if !x1.equal(x2) || !y1.equal(y2) {
+		t.Fatal("u and -u must map to inverse points")
+	}
+	// Sum of inverse points must be infinity and sum of equal points must
+	// be doubling on the target curve.
+	p1, p2 := g.mapToCurve(g.New(), u1), g.mapToCurve(g.New(), u2)
+	if !g.IsZero(g.Add(g.New(), p1, p2)) {
+		t.Fatal("expected point at infinity")
+	}
+	r0, r1 := g.Add(g.New(), p1, p1), g.Double(g.New(), p1)
+	g.ClearCofactor(r0)
+	g.ClearCofactor(r1)
+	if !g.Equal(r0, r1) {
+		t.Fatal("addition of equal points must double on target curve")
+	}
+}
+
 func BenchmarkG1Add(t *testing.B) {
 	g := NewG1()
 	a, b, c := g.rand(), g.rand(), PointG1{}
@@ -609,6 +1105,47 @@ func BenchmarkG1Add(t *testing.B) {
 	}
 }
 
+func BenchmarkG1MulScalarBatch(t *testing.B) {
+	g := NewG1()
+	points := make([]PointG1, 256)
+	for i := range points {
+		points[i].Set(g.randCorrect())
+	}
+	s, _ := new(Fr).Rand(rand.Reader)
+	t.Run("MulScalar", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			for j := range points {
+				g.MulScalar(&points[j], &points[j], s)
+			}
+		}
+	})
+	t.Run("MulScalarBatch", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.MulScalarBatch(points, s)
+		}
+	})
+}
+
+func BenchmarkG1LinearComb2(t *testing.B) {
+	g := NewG1()
+	p, q, r := g.randCorrect(), g.randCorrect(), g.New()
+	a, _ := new(Fr).Rand(rand.Reader)
+	b, _ := new(Fr).Rand(rand.Reader)
+	t.Run("MulScalar", func(t *testing.B) {
+		tmp := g.New()
+		for i := 0; i < t.N; i++ {
+			g.MulScalar(r, p, a)
+			g.MulScalar(tmp, q, b)
+			g.Add(r, r, tmp)
+		}
+	})
+	t.Run("LinearComb2", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g.LinearComb2(r, a, p, b, q)
+		}
+	})
+}
+
 func BenchmarkG1MulWNAF(t *testing.B) {
 	g := NewG1()
 	p := new(PointG1).Set(&g1One)
@@ -683,7 +1220,7 @@ func BenchmarkG1MultiExp(t *testing.B) {
 		}
 		return bases, scalars
 	}
-	for _, i := range []int{2, 10, 100, 1000} {
+	for _, i := range []int{2, 10, 16, 32, 100, 1000} {
 		t.Run(fmt.Sprint(i), func(t *testing.B) {
 			bases, scalars := v(i)
 			result := g.New()
@@ -693,6 +1230,18 @@ func BenchmarkG1MultiExp(t *testing.B) {
 			}
 		})
 	}
+	g.SetMultiExpGLV(false)
+	defer g.SetMultiExpGLV(true)
+	for _, i := range []int{100, 1000} {
+		t.Run(fmt.Sprintf("%d, no glv", i), func(t *testing.B) {
+			bases, scalars := v(i)
+			result := g.New()
+			t.ResetTimer()
+			for i := 0; i < t.N; i++ {
+				_, _ = g.MultiExp(result, bases, scalars)
+			}
+		})
+	}
 }
 
 func BenchmarkG1ClearCofactor(t *testing.B) {
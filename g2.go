@@ -40,6 +40,8 @@ type tempG2 struct {
 type G2 struct {
 	f *fp2
 	tempG2
+	multiExpMaxWindow int
+	tracer            *Tracer
 }
 
 // NewG2 constructs a new G2 instance.
@@ -52,7 +54,7 @@ func newG2(f *fp2) *G2 {
 		f = newFp2()
 	}
 	t := newTempG2()
-	return &G2{f, t}
+	return &G2{f: f, tempG2: t}
 }
 
 func newTempG2() tempG2 {
@@ -73,41 +75,74 @@ func (g *G2) Q() *big.Int {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) FromUncompressed(uncompressed []byte) (*PointG2, error) {
-	if len(uncompressed) != 4*fpByteSize {
-		return nil, errors.New("input string length must be equal to 192 bytes")
+	p := new(PointG2)
+	if err := g.FromUncompressedInto(p, uncompressed); err != nil {
+		return nil, legacyError(err, errLength192, errCompressionFlagOff)
 	}
-	var in [4 * fpByteSize]byte
-	copy(in[:], uncompressed[:4*fpByteSize])
-	if in[0]&(1<<7) != 0 {
-		return nil, errors.New("compression flag must be zero")
+	return p, nil
+}
+
+// FromUncompressedInto decodes 192 bytes uncompressed input into the point at first argument.
+// It follows the same rules with FromUncompressed without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G2) FromUncompressedInto(p *PointG2, uncompressed []byte) error {
+	if len(uncompressed) != G2UncompressedSize {
+		return ErrInvalidLength
 	}
-	if in[0]&(1<<5) != 0 {
-		return nil, errors.New("sort flag must be zero")
+	var in [G2UncompressedSize]byte
+	copy(in[:], uncompressed)
+	if in[0]&CompressionFlag != 0 {
+		return ErrCompressionFlag
 	}
-	if in[0]&(1<<6) != 0 {
+	if in[0]&SignFlag != 0 {
+		return ErrSortFlag
+	}
+	if in[0]&InfinityFlag != 0 {
 		for i, v := range in {
-			if (i == 0 && v != 0x40) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
+			if (i == 0 && v != InfinityFlag) || (i != 0 && v != 0x00) {
+				return ErrInfinityEncoding
 			}
 		}
-		return g.Zero(), nil
+		p.Zero()
+		return nil
 	}
-	in[0] &= 0x1f
-	x, err := g.f.fromBytes(in[:2*fpByteSize])
-	if err != nil {
-		return nil, err
+	in[0] &^= FlagMask
+	if err := g.f.fromBytesInto(&p[0], in[:2*fpByteSize]); err != nil {
+		return err
 	}
-	y, err := g.f.fromBytes(in[2*fpByteSize:])
-	if err != nil {
-		return nil, err
+	if err := g.f.fromBytesInto(&p[1], in[2*fpByteSize:]); err != nil {
+		return err
+	}
+	p[2].one()
+	if !g.IsOnCurve(p) {
+		return ErrNotOnCurve
 	}
-	z := new(fe2).one()
-	p := &PointG2{*x, *y, *z}
+	if !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+// PointG2FromBig returns the G2 point with affine coordinates x = x0 + x1 * u
+// and y = y0 + y1 * u, for porting fixtures from scripts that work with
+// integers. Coordinates must be less than the modulus and the point must be
+// on the curve and in the correct subgroup. The point at infinity has no
+// affine coordinates and is rejected.
+func PointG2FromBig(x0, x1, y0, y1 *big.Int) (*PointG2, error) {
+	p := &PointG2{}
+	coords := []*big.Int{x0, x1, y0, y1}
+	for i, c := range []*Fp{&p[0][0], &p[0][1], &p[1][0], &p[1][1]} {
+		if _, err := c.SetBig(coords[i]); err != nil {
+			return nil, err
+		}
+	}
+	p[2].one()
+	g := NewG2()
 	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
 	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+		return nil, ErrNotInSubgroup
 	}
 	return p, nil
 }
@@ -117,10 +152,10 @@ func (g *G2) FromUncompressed(uncompressed []byte) (*PointG2, error) {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) ToUncompressed(p *PointG2) []byte {
-	out := make([]byte, 4*fpByteSize)
+	out := make([]byte, G2UncompressedSize)
 	g.Affine(p)
 	if g.IsZero(p) {
-		out[0] |= 1 << 6
+		out[0] |= InfinityFlag
 		return out
 	}
 	copy(out[:2*fpByteSize], g.f.toBytes(&p[0]))
@@ -133,46 +168,55 @@ func (g *G2) ToUncompressed(p *PointG2) []byte {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) FromCompressed(compressed []byte) (*PointG2, error) {
-	if len(compressed) != 2*fpByteSize {
-		return nil, errors.New("input string length must be equal to 96 bytes")
+	p := new(PointG2)
+	if err := g.FromCompressedInto(p, compressed); err != nil {
+		return nil, legacyError(err, errLength96, errCompressionFlagSet)
+	}
+	return p, nil
+}
+
+// FromCompressedInto decodes 96 bytes compressed input into the point at first argument.
+// It follows the same rules with FromCompressed without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G2) FromCompressedInto(p *PointG2, compressed []byte) error {
+	if len(compressed) != G2CompressedSize {
+		return ErrInvalidLength
 	}
-	var in [2 * fpByteSize]byte
-	copy(in[:], compressed[:])
-	if in[0]&(1<<7) == 0 {
-		return nil, errors.New("compression flag must be set")
+	var in [G2CompressedSize]byte
+	copy(in[:], compressed)
+	if in[0]&CompressionFlag == 0 {
+		return ErrCompressionFlag
 	}
-	if in[0]&(1<<6) != 0 {
-		// in[0] == (1 << 6) + (1 << 7)
+	if in[0]&InfinityFlag != 0 {
 		for i, v := range in {
-			if (i == 0 && v != 0xc0) || (i != 0 && v != 0x00) {
-				return nil, errors.New("input string must be zero when infinity flag is set")
+			if (i == 0 && v != CompressionFlag|InfinityFlag) || (i != 0 && v != 0x00) {
+				return ErrInfinityEncoding
 			}
 		}
-		return g.Zero(), nil
+		p.Zero()
+		return nil
 	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
-	x, err := g.f.fromBytes(in[:])
-	if err != nil {
-		return nil, err
+	a := in[0]&SignFlag != 0
+	in[0] &^= FlagMask
+	x, y := &p[0], &p[1]
+	if err := g.f.fromBytesInto(x, in[:]); err != nil {
+		return err
 	}
 	// solve curve equation
-	y := &fe2{}
 	g.f.square(y, x)
 	g.f.mul(y, y, x)
 	fp2Add(y, y, b2)
 	if ok := g.f.sqrt(y, y); !ok {
-		return nil, errors.New("point is not on curve")
+		return ErrNotOnCurve
 	}
 	if y.signBE() == a {
 		fp2Neg(y, y)
 	}
-	z := new(fe2).one()
-	p := &PointG2{*x, *y, *z}
+	p[2].one()
 	if !g.InCorrectSubgroup(p) {
-		return nil, errors.New("point is not on correct subgroup")
+		return ErrNotInSubgroup
 	}
-	return p, nil
+	return nil
 }
 
 // ToCompressed given a G2 point returns bytes in compressed form of the point.
@@ -180,18 +224,114 @@ func (g *G2) FromCompressed(compressed []byte) (*PointG2, error) {
 // https://github.com/zcash/librustzcash/blob/master/pairing/src/bls12_381/README.md#serialization
 // https://docs.rs/bls12_381/0.1.1/bls12_381/notes/serialization/index.html
 func (g *G2) ToCompressed(p *PointG2) []byte {
-	out := make([]byte, 2*fpByteSize)
+	out := make([]byte, G2CompressedSize)
 	g.Affine(p)
+	g.encodeCompressed(out, p)
+	return out
+}
+
+// ToCompressedBatch serializes given points in compressed form.
+// Points are normalized in place with a single inversion, see EnsureAffine.
+// Returned encodings share one underlying buffer.
+func (g *G2) ToCompressedBatch(points []*PointG2) [][]byte {
+	g.EnsureAffine(points...)
+	buf := make([]byte, len(points)*G2CompressedSize)
+	out := make([][]byte, len(points))
+	for i := 0; i < len(points); i++ {
+		out[i] = buf[i*G2CompressedSize : (i+1)*G2CompressedSize]
+		g.encodeCompressed(out[i], points[i])
+	}
+	return out
+}
+
+// encodeCompressed writes compressed encoding of a point given in affine form.
+func (g *G2) encodeCompressed(out []byte, p *PointG2) {
 	if g.IsZero(p) {
-		out[0] |= 1 << 6
+		out[0] |= InfinityFlag
 	} else {
 		copy(out[:], g.f.toBytes(&p[0]))
 		if !p[1].signBE() {
-			out[0] |= 1 << 5
+			out[0] |= SignFlag
 		}
 	}
-	out[0] |= 1 << 7
-	return out
+	out[0] |= CompressionFlag
+}
+
+// FromCompressedFormat decodes a compressed point serialized in given format.
+// Point is checked to be on curve and in correct subgroup.
+func (g *G2) FromCompressedFormat(compressed []byte, format PointFormat) (*PointG2, error) {
+	switch format {
+	case FormatZcash:
+		return g.FromCompressed(compressed)
+	case FormatMCL:
+		p := new(PointG2)
+		if err := g.fromCompressedMCL(p, compressed); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ToCompressedFormat serializes the point in compressed form of given format.
+func (g *G2) ToCompressedFormat(p *PointG2, format PointFormat) ([]byte, error) {
+	switch format {
+	case FormatZcash:
+		return g.ToCompressed(p), nil
+	case FormatMCL:
+		out := make([]byte, G2CompressedSize)
+		g.Affine(p)
+		if !g.IsZero(p) {
+			copy(out[:fpByteSize], toBytes(&p[0][0]))
+			copy(out[fpByteSize:], toBytes(&p[0][1]))
+			reverseBytes(out[:fpByteSize])
+			reverseBytes(out[fpByteSize:])
+			// mcl takes parity of c0 rather than sgn0
+			if p[1][0].Sgn0() == 1 {
+				out[G2CompressedSize-1] |= mclOddFlag
+			}
+		}
+		return out, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+func (g *G2) fromCompressedMCL(p *PointG2, compressed []byte) error {
+	if len(compressed) != G2CompressedSize {
+		return ErrInvalidLength
+	}
+	var in [G2CompressedSize]byte
+	copy(in[:], compressed)
+	if isAllZero(in[:]) {
+		p.Zero()
+		return nil
+	}
+	odd := in[G2CompressedSize-1]&mclOddFlag != 0
+	in[G2CompressedSize-1] &^= mclOddFlag
+	reverseBytes(in[:fpByteSize])
+	reverseBytes(in[fpByteSize:])
+	x, y := &p[0], &p[1]
+	if err := fromBytesInto(&x[0], in[:fpByteSize]); err != nil {
+		return err
+	}
+	if err := fromBytesInto(&x[1], in[fpByteSize:]); err != nil {
+		return err
+	}
+	// solve curve equation
+	g.f.square(y, x)
+	g.f.mul(y, y, x)
+	fp2Add(y, y, b2)
+	if ok := g.f.sqrt(y, y); !ok {
+		return ErrNotOnCurve
+	}
+	if (y[0].Sgn0() == 1) != odd {
+		fp2Neg(y, y)
+	}
+	p[2].one()
+	if !g.InCorrectSubgroup(p) {
+		return ErrNotInSubgroup
+	}
+	return nil
 }
 
 func (g *G2) fromBytesUnchecked(in []byte) (*PointG2, error) {
@@ -211,33 +351,42 @@ func (g *G2) fromBytesUnchecked(in []byte) (*PointG2, error) {
 // Input string expected to be 192 bytes and concatenation of x and y values
 // Point (0, 0) is considered as infinity.
 func (g *G2) FromBytes(in []byte) (*PointG2, error) {
-	if len(in) != 4*fpByteSize {
-		return nil, errors.New("input string length must be equal to 192 bytes")
+	p := new(PointG2)
+	if err := g.FromBytesInto(p, in); err != nil {
+		return nil, legacyError(err, errLength192, ErrCompressionFlag)
 	}
-	p0, err := g.f.fromBytes(in[:2*fpByteSize])
-	if err != nil {
-		return nil, err
+	return p, nil
+}
+
+// FromBytesInto decodes 192 bytes input into the point at first argument.
+// It follows the same rules with FromBytes without allocating.
+// Content of the point is undefined if an error is returned.
+func (g *G2) FromBytesInto(p *PointG2, in []byte) error {
+	if len(in) != G2UncompressedSize {
+		return ErrInvalidLength
 	}
-	p1, err := g.f.fromBytes(in[2*fpByteSize:])
-	if err != nil {
-		return nil, err
+	if err := g.f.fromBytesInto(&p[0], in[:2*fpByteSize]); err != nil {
+		return err
+	}
+	if err := g.f.fromBytesInto(&p[1], in[2*fpByteSize:]); err != nil {
+		return err
 	}
 	// check if given input points to infinity
-	if p0.isZero() && p1.isZero() {
-		return g.Zero(), nil
+	if p[0].isZero() && p[1].isZero() {
+		p.Zero()
+		return nil
 	}
-	p2 := new(fe2).one()
-	p := &PointG2{*p0, *p1, *p2}
+	p[2].one()
 	if !g.IsOnCurve(p) {
-		return nil, errors.New("point is not on curve")
+		return ErrNotOnCurve
 	}
-	return p, nil
+	return nil
 }
 
 // ToBytes serializes a point into bytes in uncompressed form,
 // returns (0, 0) if point is infinity.
 func (g *G2) ToBytes(p *PointG2) []byte {
-	out := make([]byte, 4*fpByteSize)
+	out := make([]byte, G2UncompressedSize)
 	if g.IsZero(p) {
 		return out
 	}
@@ -247,6 +396,38 @@ func (g *G2) ToBytes(p *PointG2) []byte {
 	return out
 }
 
+// FromBytesOrder decodes 192 bytes input as FromBytes with coefficients of x
+// and y in given order, such as Fp2C0C1 order of Ethereum precompiles.
+func (g *G2) FromBytesOrder(in []byte, order Fp2Order) (*PointG2, error) {
+	switch order {
+	case Fp2C1C0:
+		return g.FromBytes(in)
+	case Fp2C0C1:
+		if len(in) != G2UncompressedSize {
+			return nil, ErrInvalidLength
+		}
+		b := make([]byte, len(in))
+		copy(b, in)
+		swapFp2Order(b)
+		return g.FromBytes(b)
+	}
+	return nil, ErrUnknownOrder
+}
+
+// ToBytesOrder serializes the point as ToBytes with coefficients of x and y in
+// given order.
+func (g *G2) ToBytesOrder(p *PointG2, order Fp2Order) ([]byte, error) {
+	switch order {
+	case Fp2C1C0:
+		return g.ToBytes(p), nil
+	case Fp2C0C1:
+		out := g.ToBytes(p)
+		swapFp2Order(out)
+		return out, nil
+	}
+	return nil, ErrUnknownOrder
+}
+
 // New creates a new G2 Point which is equal to zero in other words point at infinity.
 func (g *G2) New() *PointG2 {
 	return new(PointG2).Zero()
@@ -288,6 +469,22 @@ func (g *G2) Equal(p1, p2 *PointG2) bool {
 	return t[0].equal(t[1]) && t[2].equal(t[3])
 }
 
+// InCorrectSubgroupBatch checks whether given points are in correct subgroup using all available CPUs.
+// Returns nil if all points are valid, otherwise returns a *BatchError carrying indices of invalid points.
+// Points are not modified.
+func (g *G2) InCorrectSubgroupBatch(points []*PointG2) error {
+	invalid := parallelCheck(len(points), func() func(int) bool {
+		g := NewG2()
+		return func(i int) bool {
+			return g.InCorrectSubgroup(points[i])
+		}
+	})
+	if len(invalid) != 0 {
+		return &BatchError{ErrNotInSubgroup, invalid}
+	}
+	return nil
+}
+
 // IsOnCurve checks a G2 point is on curve.
 func (g *G2) IsOnCurve(p *PointG2) bool {
 	if g.IsZero(p) {
@@ -356,8 +553,54 @@ func (g *G2) AffineBatch(p []*PointG2) {
 	}
 }
 
+// AffineChecked normalizes the given point to affine form in place. Unlike
+// Affine it returns ErrZeroInversion if the z coordinate is zero, since the
+// point at infinity has no affine representation. Callers expecting finite
+// points use it to notice corrupted or degenerate inputs.
+func (g *G2) AffineChecked(p *PointG2) error {
+	if g.IsZero(p) {
+		return ErrZeroInversion
+	}
+	g.affine(p, p)
+	return nil
+}
+
+// AffineBatchChecked normalizes given points to affine form in place with a
+// single batch inversion. Points at infinity are left as is and reported with
+// a *BatchError wrapping ErrZeroInversion.
+func (g *G2) AffineBatchChecked(p []*PointG2) error {
+	var bad []int
+	for i := range p {
+		if g.IsZero(p[i]) {
+			bad = append(bad, i)
+		}
+	}
+	g.AffineBatch(p)
+	if len(bad) != 0 {
+		return &BatchError{Err: ErrZeroInversion, Indices: bad}
+	}
+	return nil
+}
+
+// EnsureAffine normalizes given points to affine form in place.
+// Points that are already affine or at infinity are left as is and the rest share a single batch inversion.
+// Serializers do not invert Z coordinate of an affine point, so repeated encoding of normalized points is cheap.
+func (g *G2) EnsureAffine(points ...*PointG2) {
+	pending := make([]*PointG2, 0, len(points))
+	for _, p := range points {
+		if !g.IsZero(p) && !g.IsAffine(p) {
+			pending = append(pending, p)
+		}
+	}
+	g.AffineBatch(pending)
+}
+
 // Add adds two G2 points p1, p2 and assigns the result to point at first argument.
 func (g *G2) Add(r, p1, p2 *PointG2) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.add", func() []byte { return g.ToUncompressed(g.Add(r, p1, p2)) }, g.ToUncompressed(p1), g.ToUncompressed(p2))
+		return r
+	}
 	// http://www.hyperelliptic.org/EFD/gp/auto-shortw-jacobian-0.html#addition-add-2007-bl
 	if g.IsZero(p1) {
 		return r.Set(p2)
@@ -454,6 +697,10 @@ func (g *G2) AddMixed(r, p1, p2 *PointG2) *PointG2 {
 
 // Double doubles a G2 point p and assigns the result to the point at first argument.
 func (g *G2) Double(r, p *PointG2) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.double", func() []byte { return g.ToUncompressed(g.Double(r, p)) }, g.ToUncompressed(p))
+		return r
+	}
 	// http://www.hyperelliptic.org/EFD/gp/auto-shortw-jacobian-0.html#doubling-dbl-2009-l
 	if g.IsZero(p) {
 		return r.Set(p)
@@ -486,6 +733,10 @@ func (g *G2) Double(r, p *PointG2) *PointG2 {
 
 // Neg negates a G2 point p and assigns the result to the point at first argument.
 func (g *G2) Neg(r, p *PointG2) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.neg", func() []byte { return g.ToUncompressed(g.Neg(r, p)) }, g.ToUncompressed(p))
+		return r
+	}
 	r[0].set(&p[0])
 	fp2Neg(&r[1], &p[1])
 	r[2].set(&p[2])
@@ -494,6 +745,10 @@ func (g *G2) Neg(r, p *PointG2) *PointG2 {
 
 // Sub subtracts two G2 points p1, p2 and assigns the result to point at first argument.
 func (g *G2) Sub(c, a, b *PointG2) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.sub", func() []byte { return g.ToUncompressed(g.Sub(c, a, b)) }, g.ToUncompressed(a), g.ToUncompressed(b))
+		return c
+	}
 	d := &PointG2{}
 	g.Neg(d, b)
 	g.Add(c, a, d)
@@ -502,6 +757,10 @@ func (g *G2) Sub(c, a, b *PointG2) *PointG2 {
 
 // MulScalar multiplies a point by given scalar value and assigns the result to point at first argument.
 func (g *G2) MulScalar(r, p *PointG2, e *Fr) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.mul_scalar", func() []byte { return g.ToUncompressed(g.MulScalar(r, p, e)) }, g.ToUncompressed(p), e.ToBytes())
+		return r
+	}
 	return g.glvMulFr(r, p, e)
 }
 
@@ -589,31 +848,42 @@ func (g *G2) glvMulBig(r, p *PointG2, e *big.Int) *PointG2 {
 }
 
 func (g *G2) glvMul(r, p0 *PointG2, v glvVector) *PointG2 {
+	naf1, naf2 := v.wnaf(glvMulWindowG2)
+	tableK1, tableK2 := newGLVTablesG2()
+	return g.glvMulNAF(r, p0, naf1, naf2, tableK1, tableK2)
+}
 
-	w := glvMulWindowG2
-	l := 1 << (w - 1)
+// newGLVTablesG2 allocates precomputation tables of GLV multiplication.
+func newGLVTablesG2() ([]*PointG2, []*PointG2) {
+	l := 1 << (glvMulWindowG2 - 1)
+	tableK1, tableK2 := make([]*PointG2, l), make([]*PointG2, l)
+	for i := 0; i < l; i++ {
+		tableK1[i], tableK2[i] = new(PointG2), new(PointG2)
+	}
+	return tableK1, tableK2
+}
+
+// glvMulNAF multiplies with recoded GLV decomposition of a scalar. Tables are
+// overwritten and can be reused across calls.
+func (g *G2) glvMulNAF(r, p0 *PointG2, naf1, naf2 nafNumber, tableK1, tableK2 []*PointG2) *PointG2 {
+
+	l := len(tableK1)
 
 	// prepare tables
 	// tableK1 = {P, 3P, 5P, ...}
 	// tableK2 = {λP, 3λP, 5λP, ...}
-	tableK1, tableK2 := make([]*PointG2, l), make([]*PointG2, l)
 	double := g.New()
 	g.Double(double, p0)
 	g.affine(double, double)
-	tableK1[0] = new(PointG2)
 	tableK1[0].Set(p0)
 	for i := 1; i < l; i++ {
-		tableK1[i] = new(PointG2)
 		g.AddMixed(tableK1[i], tableK1[i-1], double)
 	}
 	g.AffineBatch(tableK1)
 	for i := 0; i < l; i++ {
-		tableK2[i] = new(PointG2)
 		g.glvEndomorphism(tableK2[i], tableK1[i])
 	}
 
-	// recode small scalars
-	naf1, naf2 := v.wnaf(w)
 	lenNAF1, lenNAF2 := len(naf1), len(naf2)
 	lenNAF := lenNAF1
 	if lenNAF2 > lenNAF {
@@ -652,6 +922,102 @@ func (g *G2) glvMul(r, p0 *PointG2, v glvVector) *PointG2 {
 	return r.Set(acc)
 }
 
+// MulScalarBatch multiplies all points by the same scalar in place, such as
+// when re-randomizing a commitment vector. Scalar decomposition and recoding
+// are done once and shared across points, results are in affine form.
+func (g *G2) MulScalarBatch(points []PointG2, s *Fr) {
+	naf1, naf2 := new(glvVectorFr).new(s).wnaf(glvMulWindowG2)
+	tableK1, tableK2 := newGLVTablesG2()
+	ptrs := make([]*PointG2, len(points))
+	for i := range points {
+		g.glvMulNAF(&points[i], &points[i], naf1, naf2, tableK1, tableK2)
+		ptrs[i] = &points[i]
+	}
+	g.AffineBatch(ptrs)
+}
+
+// LinearComb2 calculates r = a * p + b * q with Shamir's trick, where both
+// multiplications share a single chain of doublings. It is faster than two
+// scalar multiplications followed by an addition, such as in Schnorr like
+// verification equations.
+func (g *G2) LinearComb2(r *PointG2, a *Fr, p *PointG2, b *Fr, q *PointG2) *PointG2 {
+	return g.straussMul(r, []*PointG2{p, q}, []*Fr{a, b}, true)
+}
+
+// straussMul calculates multi exponentiation with interleaved wNAF
+// multiplications, where all additions are done on a single chain of
+// doublings. If glv is set every scalar is split into two half length
+// scalars, which halves the chain but requires points to be in the subgroup.
+// Precomputation tables of all points are normalized with a single inversion.
+func (g *G2) straussMul(r *PointG2, points []*PointG2, scalars []*Fr, glv bool) *PointG2 {
+	w := glvMulWindowG2
+	l := 1 << (w - 1)
+	k := 1
+	if glv {
+		k = 2
+	}
+	n := len(points)
+	// naf of k*i+j is added from the table at block k*i+j
+	storage := make([]PointG2, k*n*l)
+	table := make([]*PointG2, k*n*l)
+	for i := range table {
+		table[i] = &storage[i]
+	}
+	nafs := make([]nafNumber, k*n)
+	bases := make([]*PointG2, 0, n*l)
+	double := g.New()
+	for i := 0; i < n; i++ {
+		// {P, 3P, 5P, ...}
+		t := table[k*i*l : (k*i+1)*l]
+		g.Double(double, points[i])
+		t[0].Set(points[i])
+		for j := 1; j < l; j++ {
+			g.Add(t[j], t[j-1], double)
+		}
+		bases = append(bases, t...)
+		if glv {
+			nafs[2*i], nafs[2*i+1] = new(glvVectorFr).new(scalars[i]).wnaf(w)
+		} else {
+			nafs[i] = scalars[i].toWNAF(w)
+		}
+	}
+	g.AffineBatch(bases)
+	if glv {
+		for i := 0; i < n; i++ {
+			// {λP, 3λP, 5λP, ...}
+			for j := 0; j < l; j++ {
+				g.glvEndomorphism(table[(2*i+1)*l+j], table[2*i*l+j])
+			}
+		}
+	}
+	lenNAF := 0
+	for i := range nafs {
+		if len(nafs[i]) > lenNAF {
+			lenNAF = len(nafs[i])
+		}
+	}
+
+	acc, p1 := g.New(), g.New()
+	for i := lenNAF - 1; i >= 0; i-- {
+		for t := range nafs {
+			if i >= len(nafs[t]) || nafs[t][i] == 0 {
+				continue
+			}
+			naf := nafs[t][i]
+			if naf > 0 {
+				g.AddMixed(acc, acc, table[t*l+naf>>1])
+			} else {
+				g.Neg(p1, table[t*l+(-naf)>>1])
+				g.AddMixed(acc, acc, p1)
+			}
+		}
+		if i != 0 {
+			g.Double(acc, acc)
+		}
+	}
+	return r.Set(acc)
+}
+
 // MultiExpBig calculates multi exponentiation. Scalar values are received as big.Int type.
 // Given pairs of G2 point and scalar values `(P_0, e_0), (P_1, e_1), ... (P_n, e_n)`,
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`.
@@ -705,21 +1071,44 @@ func (g *G2) MultiExpBig(r *PointG2, points []*PointG2, scalars []*big.Int) (*Po
 // MultiExp calculates multi exponentiation. Given pairs of G2 point and scalar values `(P_0, e_0), (P_1, e_1), ... (P_n, e_n)`,
 // calculates `r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n`. Length of points and scalars are expected to be equal,
 // otherwise an error is returned. Result is assigned to point at first argument.
+// Up to a few dozen terms interleaved wNAF multiplication is used, where
+// overhead of Pippenger buckets would dominate. Larger inputs use Pippenger
+// with signed window digits, so that a window needs half as many buckets.
 func (g *G2) MultiExp(r *PointG2, points []*PointG2, scalars []*Fr) (*PointG2, error) {
+	if g.tracer != nil {
+		var res *PointG2
+		var err error
+		g.traced("g2.multi_exp", func() []byte {
+			if res, err = g.MultiExp(r, points, scalars); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(res)
+		}, g.tracePoints(points), traceScalars(scalars))
+		return res, err
+	}
 	if len(points) != len(scalars) {
 		return nil, errors.New("point and scalar vectors should be in same length")
 	}
 
+	if len(points) <= multiExpStraussThresholdG2 {
+		return g.straussMul(r, points, scalars, false), nil
+	}
+
 	g.AffineBatch(points)
 
 	c := 3
 	if len(scalars) >= 32 {
 		c = int(math.Ceil(math.Log(float64(len(scalars)))))
 	}
+	if g.multiExpMaxWindow > 0 && c > g.multiExpMaxWindow {
+		c = g.multiExpMaxWindow
+	}
 
-	bucketSize := (1 << c) - 1
+	bucketSize := 1 << (c - 1)
 	windows := make([]*PointG2, 255/c+1)
 	bucket := make([]PointG2, bucketSize)
+	digits := signedWindows(scalars, c, len(windows))
+	t := g.New()
 
 	for j := 0; j < len(windows); j++ {
 
@@ -728,9 +1117,11 @@ func (g *G2) MultiExp(r *PointG2, points []*PointG2, scalars []*Fr) (*PointG2, e
 		}
 
 		for i := 0; i < len(scalars); i++ {
-			index := bucketSize & int(scalars[i].sliceUint64(c*j))
-			if index != 0 {
+			index := digits[i*len(windows)+j]
+			if index > 0 {
 				g.AddMixed(&bucket[index-1], &bucket[index-1], points[i])
+			} else if index < 0 {
+				g.AddMixed(&bucket[-index-1], &bucket[-index-1], g.Neg(t, points[i]))
 			}
 		}
 
@@ -754,6 +1145,14 @@ func (g *G2) MultiExp(r *PointG2, points []*PointG2, scalars []*Fr) (*PointG2, e
 	return r.Set(acc), nil
 }
 
+// SetMultiExpMaxWindow bounds bit length of Pippenger windows used by
+// MultiExp, trading speed for memory. Buckets of a window of c bits take
+// 2^(c-1) points, and by default window size grows as natural logarithm of the
+// number of terms. Zero or negative values restore the default.
+func (g *G2) SetMultiExpMaxWindow(c int) {
+	g.multiExpMaxWindow = c
+}
+
 // InCorrectSubgroup checks whether given point is in correct subgroup.
 func (g *G2) InCorrectSubgroup(p *PointG2) bool {
 
@@ -777,8 +1176,13 @@ func (g *G2) InCorrectSubgroup(p *PointG2) bool {
 	return g.IsZero(t0)
 }
 
-// ClearCofactor maps given a G2 point to correct subgroup
+// ClearCofactor maps given a G2 point to correct subgroup using the endomorphism
+// based method which is equivalent to multiplication by the effective cofactor.
 func (g *G2) ClearCofactor(p *PointG2) *PointG2 {
+	if g.tracer != nil {
+		g.traced("g2.clear_cofactor", func() []byte { return g.ToUncompressed(g.ClearCofactor(p)) }, g.ToUncompressed(p))
+		return p
+	}
 
 	// Efficient hash maps to G2 on BLS curves
 	// A. Budroni, F. Pintore
@@ -802,6 +1206,14 @@ func (g *G2) ClearCofactor(p *PointG2) *PointG2 {
 	return p.Set(t3)
 }
 
+// ClearCofactorNaive maps given a G2 point to correct subgroup by multiplying
+// it with the effective cofactor h_eff defined at draft-irtf-cfrg-hash-to-curve-06.
+// Result is equal to ClearCofactor output, but it is considerably slower.
+// It is provided to cross check the endomorphism based method.
+func (g *G2) ClearCofactorNaive(p *PointG2) *PointG2 {
+	return g.wnafMulBig(p, p, cofactorEFFG2)
+}
+
 func (g *G2) psi(p *PointG2) {
 	fp2Conjugate(&p[0], &p[0])
 	fp2Conjugate(&p[1], &p[1])
@@ -832,36 +1244,56 @@ func (g *G2) mulX(p *PointG2) {
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-05#section-6.6.2
 // Input byte slice should be a valid field element, otherwise an error is returned.
+// Unlike EncodeToCurve and HashToCurve, the point at infinity is a valid output.
 func (g *G2) MapToCurve(in []byte) (*PointG2, error) {
-	fp2 := g.f
-	u, err := fp2.fromBytes(in)
+	u, err := g.f.fromBytes(in)
 	if err != nil {
 		return nil, err
 	}
-	x, y := swuMapG2(fp2, u)
-	isogenyMapG2(fp2, x, y)
-	z := new(fe2).one()
-	q := &PointG2{*x, *y, *z}
+	q := g.mapToCurve(g.New(), u)
 	g.ClearCofactor(q)
 	return g.Affine(q), nil
 }
 
+// mapToCurve maps a field element to a curve point with SSWU method and the
+// isogeny map. Output is not cofactor cleared.
+func (g *G2) mapToCurve(q *PointG2, u *fe2) *PointG2 {
+	x, y := swuMapG2(g.f, u)
+	if !isogenyMapG2(g.f, x, y) {
+		return q.Zero()
+	}
+	q[0].set(x)
+	q[1].set(y)
+	q[2].one()
+	return q
+}
+
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// ErrHashToInfinity is returned if the result is the point at infinity.
 func (g *G2) EncodeToCurve(msg, domain []byte) (*PointG2, error) {
+	if g.tracer != nil {
+		var p *PointG2
+		var err error
+		g.traced("g2.encode_to_curve", func() []byte {
+			if p, err = g.EncodeToCurve(msg, domain); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(p)
+		}, msg, domain)
+		return p, err
+	}
 	hashRes, err := HashToFpXMDSHA256(msg, domain, 2)
 	if err != nil {
 		return nil, err
 	}
-	fp2 := g.f
-	u := &fe2{*hashRes[0], *hashRes[1]}
-	x, y := swuMapG2(fp2, u)
-	isogenyMapG2(fp2, x, y)
-	z := new(fe2).one()
-	q := &PointG2{*x, *y, *z}
+	q := g.mapToCurve(g.New(), &fe2{*hashRes[0], *hashRes[1]})
 	g.ClearCofactor(q)
+	if g.IsZero(q) {
+		return nil, ErrHashToInfinity
+	}
 	return g.Affine(q), nil
 }
 
@@ -869,21 +1301,37 @@ func (g *G2) EncodeToCurve(msg, domain []byte) (*PointG2, error) {
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// ErrHashToInfinity is returned if the result is the point at infinity.
 func (g *G2) HashToCurve(msg, domain []byte) (*PointG2, error) {
+	if g.tracer != nil {
+		var p *PointG2
+		var err error
+		g.traced("g2.hash_to_curve", func() []byte {
+			if p, err = g.HashToCurve(msg, domain); err != nil {
+				return nil
+			}
+			return g.ToUncompressed(p)
+		}, msg, domain)
+		return p, err
+	}
 	hashRes, err := HashToFpXMDSHA256(msg, domain, 4)
 	if err != nil {
 		return nil, err
 	}
-	fp2 := g.f
-	u0, u1 := &fe2{*hashRes[0], *hashRes[1]}, &fe2{*hashRes[2], *hashRes[3]}
-	x0, y0 := swuMapG2(fp2, u0)
-	x1, y1 := swuMapG2(fp2, u1)
-	z0 := new(fe2).one()
-	z1 := new(fe2).one()
-	p0, p1 := &PointG2{*x0, *y0, *z0}, &PointG2{*x1, *y1, *z1}
-	g.Add(p0, p0, p1)
-	g.Affine(p0)
-	isogenyMapG2(fp2, &p0[0], &p0[1])
-	g.ClearCofactor(p0)
-	return g.Affine(p0), nil
+	return g.hashToCurve(hashRes)
+}
+
+// hashToCurve maps two fp2 elements given by four field elements to the curve
+// and clears cofactor of their sum.
+func (g *G2) hashToCurve(hashRes []*Fp) (*PointG2, error) {
+	// Both points are mapped to the curve before they are added since
+	// doubling formula does not hold for the isogenous curve.
+	q0 := g.mapToCurve(g.New(), &fe2{*hashRes[0], *hashRes[1]})
+	q1 := g.mapToCurve(g.New(), &fe2{*hashRes[2], *hashRes[3]})
+	g.Add(q0, q0, q1)
+	g.ClearCofactor(q0)
+	if g.IsZero(q0) {
+		return nil, ErrHashToInfinity
+	}
+	return g.Affine(q0), nil
 }
@@ -151,6 +151,79 @@ func TestG2BatchAffine(t *testing.T) {
 	}
 }
 
+func TestG2EnsureAffine(t *testing.T) {
+	n := 20
+	g := NewG2()
+	points := make([]*PointG2, n)
+	expected := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+		expected[i] = g.ToCompressed(g.New().Set(points[i]))
+	}
+	points[3] = g.Zero()
+	expected[3] = g.ToCompressed(g.Zero())
+	g.Affine(points[5])
+	g.EnsureAffine(points...)
+	for i := 0; i < n; i++ {
+		if !g.IsZero(points[i]) && !g.IsAffine(points[i]) {
+			t.Fatal("expect affine point")
+		}
+	}
+	out := g.ToCompressedBatch(points)
+	for i := 0; i < n; i++ {
+		if !bytes.Equal(out[i], expected[i]) {
+			t.Fatal("batch serialization failed")
+		}
+	}
+}
+
+func TestG2FromBig(t *testing.T) {
+	g := NewG2()
+	p := g.Affine(g.randCorrect())
+	x0, x1, y0, y1 := p[0][0].Big(), p[0][1].Big(), p[1][0].Big(), p[1][1].Big()
+	q, err := PointG2FromBig(x0, x1, y0, y1)
+	if err != nil || !g.Equal(p, q) {
+		t.Fatal("point must be constructed", err)
+	}
+	if _, err := PointG2FromBig(x0, x1, y0, new(big.Int).Add(y1, big.NewInt(1))); err != ErrNotOnCurve {
+		t.Fatal("point must be on curve", err)
+	}
+	if _, err := PointG2FromBig(x0, new(big.Int).Add(x1, modulus.big()), y0, y1); err != ErrNonCanonical {
+		t.Fatal("coordinates must be canonical", err)
+	}
+	r := g.Affine(g.rand())
+	if _, err := PointG2FromBig(r[0][0].Big(), r[0][1].Big(), r[1][0].Big(), r[1][1].Big()); err != ErrNotInSubgroup {
+		t.Fatal("point must be in correct subgroup", err)
+	}
+}
+
+func TestG2AffineChecked(t *testing.T) {
+	g := NewG2()
+	p := g.rand()
+	expected := g.Affine(g.New().Set(p))
+	if err := g.AffineChecked(p); err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsAffine(p) || !g.Equal(p, expected) {
+		t.Fatal("point must be normalized")
+	}
+	if err := g.AffineChecked(g.Zero()); err != ErrZeroInversion {
+		t.Fatal("point at infinity must be rejected")
+	}
+	points := []*PointG2{g.rand(), g.Zero(), g.rand(), g.Zero()}
+	err := g.AffineBatchChecked(points)
+	batchErr, ok := err.(*BatchError)
+	if !ok || batchErr.Err != ErrZeroInversion || len(batchErr.Indices) != 2 || batchErr.Indices[0] != 1 || batchErr.Indices[1] != 3 {
+		t.Fatal("points at infinity must be reported", err)
+	}
+	if !g.IsAffine(points[0]) || !g.IsAffine(points[2]) || !g.IsZero(points[1]) {
+		t.Fatal("finite points must be normalized")
+	}
+	if err := g.AffineBatchChecked(points[:1]); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestG2AdditiveProperties(t *testing.T) {
 	g := NewG2()
 	t0, t1 := g.New(), g.New()
@@ -391,6 +464,99 @@ func TestG2MultiExpBigExpected(t *testing.T) {
 	}
 }
 
+func TestG2MulScalarBatch(t *testing.T) {
+	g := NewG2()
+	n := 16
+	points := make([]PointG2, n)
+	for i := 1; i < n; i++ {
+		points[i].Set(g.randCorrect())
+	}
+	s, _ := new(Fr).Rand(rand.Reader)
+	expected := make([]PointG2, n)
+	for i := range points {
+		g.mulScalar(&expected[i], &points[i], s)
+	}
+	g.MulScalarBatch(points, s)
+	for i := range points {
+		if !g.Equal(&points[i], &expected[i]) {
+			t.Fatal("batch scalar multiplication failed", i)
+		}
+		if !g.IsZero(&points[i]) && !g.IsAffine(&points[i]) {
+			t.Fatal("results must be affine")
+		}
+	}
+}
+
+func TestG2LinearComb2(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		p, q := g.randCorrect(), g.randCorrect()
+		a, _ := new(Fr).Rand(rand.Reader)
+		b, _ := new(Fr).Rand(rand.Reader)
+		expected, tmp := g.New(), g.New()
+		g.mulScalar(expected, p, a)
+		g.mulScalar(tmp, q, b)
+		g.Add(expected, expected, tmp)
+		if r := g.LinearComb2(g.New(), a, p, b, q); !g.Equal(r, expected) {
+			t.Fatal("linear combination failed")
+		}
+		// a * p + 0 * q
+		g.mulScalar(expected, p, a)
+		if r := g.LinearComb2(g.New(), a, p, new(Fr), q); !g.Equal(r, expected) {
+			t.Fatal("linear combination with zero scalar failed")
+		}
+		if r := g.LinearComb2(g.New(), a, p, b, g.Zero()); !g.Equal(r, expected) {
+			t.Fatal("linear combination with zero point failed")
+		}
+		// a * p - a * p
+		n := new(Fr)
+		n.Neg(a)
+		if r := g.LinearComb2(g.New(), a, p, n, p); !g.IsZero(r) {
+			t.Fatal("linear combination must be zero")
+		}
+	}
+}
+
+func TestG2MultiExpMaxWindow(t *testing.T) {
+	g := NewG2()
+	n := 100
+	bases := make([]*PointG2, n)
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+		bases[i] = g.randCorrect()
+	}
+	expected, _ := g.MultiExp(g.New(), bases, scalars)
+	for _, c := range []int{1, 2, 3} {
+		g.SetMultiExpMaxWindow(c)
+		r, _ := g.MultiExp(g.New(), bases, scalars)
+		if !g.Equal(r, expected) {
+			t.Fatal("bounded window multi exponentiation mismatch", c)
+		}
+	}
+}
+
+func TestG2MultiExpStrauss(t *testing.T) {
+	g := NewG2()
+	threshold := multiExpStraussThresholdG2
+	defer func() { multiExpStraussThresholdG2 = threshold }()
+	for n := 0; n <= threshold+1; n++ {
+		bases := make([]*PointG2, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i] = g.randCorrect()
+		}
+		multiExpStraussThresholdG2 = threshold
+		r0, _ := g.MultiExp(g.New(), bases, scalars)
+		multiExpStraussThresholdG2 = -1
+		r1, _ := g.MultiExp(g.New(), bases, scalars)
+		if !g.Equal(r0, r1) {
+			t.Fatal("interleaved and bucket multi exponentiation mismatch", n)
+		}
+	}
+}
+
 func TestG2MultiExp(t *testing.T) {
 	g := NewG2()
 	for n := 1; n < 1024+1; n = n * 2 {
@@ -457,6 +623,65 @@ func TestG2ClearCofactor(t *testing.T) {
 	}
 }
 
+func TestG2ClearCofactorEquivalence(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		p0 := g.rand()
+		p1 := g.New().Set(p0)
+		g.ClearCofactor(p0)
+		g.ClearCofactorNaive(p1)
+		if !g.Equal(p0, p1) {
+			t.Fatal("fast and naive cofactor clearing mismatch")
+		}
+	}
+	// Equivalence must also hold for points already in the subgroup and
+	// for the point at infinity.
+	p0, p1 := g.randCorrect(), g.New()
+	p1.Set(p0)
+	if !g.Equal(g.ClearCofactor(p0), g.ClearCofactorNaive(p1)) {
+		t.Fatal("fast and naive cofactor clearing mismatch")
+	}
+	if !g.IsZero(g.ClearCofactor(g.Zero())) || !g.IsZero(g.ClearCofactorNaive(g.Zero())) {
+		t.Fatal("cofactor clearing of infinity must be infinity")
+	}
+}
+
+func TestG2SubgroupCheckBatch(t *testing.T) {
+	g := NewG2()
+	n := 50
+	points := make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.randCorrect()
+	}
+	points[n/2] = g.Zero()
+	if err := g.InCorrectSubgroupBatch(points); err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{3, 17, n - 1}
+	for _, i := range expected {
+		points[i] = g.rand()
+	}
+	err := g.InCorrectSubgroupBatch(points)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatal("expect batch error")
+	}
+	if batchErr.Unwrap() != ErrNotInSubgroup || batchErr.First() != expected[0] {
+		t.Fatal("bad batch error", err)
+	}
+	if len(batchErr.Indices) != len(expected) {
+		t.Fatal("bad number of invalid points", batchErr.Indices)
+	}
+	for i := range expected {
+		if batchErr.Indices[i] != expected[i] {
+			t.Fatal("bad invalid indices", batchErr.Indices)
+		}
+	}
+	if err := g.InCorrectSubgroupBatch(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestG2MapToCurve(t *testing.T) {
 	for i, v := range []struct {
 		u        []byte
@@ -584,6 +809,45 @@ func TestG2EncodeToCurve(t *testing.T) {
 	}
 }
 
+func TestHasherG2(t *testing.T) {
+	g := NewG2()
+	domain := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	msg := make([]byte, 1000)
+	_, _ = rand.Read(msg)
+	h := NewHasherG2(domain)
+	for i := 0; i < len(msg); i += 37 {
+		end := i + 37
+		if end > len(msg) {
+			end = len(msg)
+		}
+		_, _ = h.Write(msg[i:end])
+		if i == 37*10 {
+			// sum must not change the state
+			if _, err := h.Sum(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	want, err := g.HashToCurve(msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		have, err := h.Sum()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(have, want) {
+			t.Fatal("streamed hash does not match")
+		}
+	}
+	h.Reset()
+	want, _ = g.HashToCurve(nil, domain)
+	if have, _ := h.Sum(); !g.Equal(have, want) {
+		t.Fatal("hash of empty message does not match after reset")
+	}
+}
+
 func TestG2HashToCurve(t *testing.T) {
 	domain := []byte("BLS12381G2_XMD:SHA-256_SSWU_RO_TESTGEN")
 	for i, v := range []struct {
@@ -638,6 +902,81 @@ func TestG2HashToCurve(t *testing.T) {
 	}
 }
 
+func TestG2SWUExceptionalCases(t *testing.T) {
+	g := NewG2()
+	e := g.f
+	params := swuParamsForG2
+	// u = 0 is the only input where tv1 denominator z^2 * u^4 + z * u^2
+	// is zero since -1 / z is not a square, x1 must fall back to b / (z * a).
+	t0 := e.new()
+	e.inverse(t0, params.z)
+	fp2Neg(t0, t0)
+	if !e.isQuadraticNonResidue(t0) {
+		t.Fatal("-1 / z is expected to be a non residue")
+	}
+	u := e.new()
+	x, y := swuMapG2(e, u)
+	l, r := e.new(), e.new()
+	e.square(l, y)
+	e.square(r, x)
+	fp2Add(r, r, params.a)
+	e.mul(r, r, x)
+	fp2Add(r, r, params.b)
+	if !l.equal(r) {
+		t.Fatal("swu output is not on isogenous curve")
+	}
+	xExpected := e.new()
+	e.mul(xExpected, params.z, params.a)
+	e.inverse(xExpected, xExpected)
+	e.mul(xExpected, xExpected, params.b)
+	if !x.equal(xExpected) {
+		t.Fatal("bad exceptional case x")
+	}
+	if y.Sgn0() != u.Sgn0() {
+		t.Fatal("bad sign of y")
+	}
+	q := g.mapToCurve(g.New(), u)
+	if !g.IsOnCurve(q) {
+		t.Fatal("mapped point is not on curve")
+	}
+	g.ClearCofactor(q)
+	if g.IsZero(q) || !g.InCorrectSubgroup(q) {
+		t.Fatal("cofactor cleared point is not valid")
+	}
+	// x denominator of the isogeny is (x - x_k)^2 where x_k is x coordinate
+	// of the kernel points.
+	c0, c1 := isogenyConstantsG2[1][0], isogenyConstantsG2[1][1]
+	d := e.new()
+	e.square(d, c1)
+	t0.set(c0)
+	fp2Double(t0, t0)
+	fp2Double(t0, t0)
+	if !d.equal(t0) {
+		t.Fatal("x denominator is expected to be a square")
+	}
+	xk := e.new()
+	fp2Double(xk, e.one())
+	e.inverse(xk, xk)
+	e.mul(xk, xk, c1)
+	fp2Neg(xk, xk)
+	if isogenyMapG2(e, xk, e.one()) {
+		t.Fatal("kernel of isogeny must map to infinity")
+	}
+	for i := 0; i < fuz; i++ {
+		u, _ := new(fe2).rand(rand.Reader)
+		uNeg := e.new()
+		fp2Neg(uNeg, u)
+		q0, q1 := g.mapToCurve(g.New(), u), g.mapToCurve(g.New(), uNeg)
+		if !g.IsZero(g.Add(g.New(), q0, q1)) {
+			t.Fatal("u and -u must map to inverse points")
+		}
+		r0, r1 := g.Add(g.New(), q0, q0), g.Double(g.New(), q0)
+		if !g.Equal(r0, r1) {
+			t.Fatal("addition of equal points must double on target curve")
+		}
+	}
+}
+
 func BenchmarkG2Add(t *testing.B) {
 	g2 := NewG2()
 	a, b, c := g2.rand(), g2.rand(), PointG2{}
@@ -742,6 +1081,15 @@ func BenchmarkG2ClearCofactor(t *testing.B) {
 	}
 }
 
+func BenchmarkG2ClearCofactorNaive(t *testing.B) {
+	g2 := NewG2()
+	a := g2.rand()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		g2.ClearCofactorNaive(a)
+	}
+}
+
 func BenchmarkG2SubgroupCheck(t *testing.B) {
 	g2 := NewG2()
 	a := g2.rand()
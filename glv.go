@@ -35,14 +35,26 @@ var halfRBig = bigFromHex("0x800000000000000000000000000000000000000000000000000
 var r128 = &Fr{0xffffffffffffffff, 0xffffffffffffffff, 0, 0}
 
 // glvPhi1 ^ 3 = 1
-var glvPhi1 = &Fe{0xcd03c9e48671f071, 0x5dab22461fcda5d2, 0x587042afd3851b95, 0x8eb60ebe01bacb9e, 0x03f97d6e83d050d2, 0x18f0206554638741}
+var glvPhi1 = &Fp{0xcd03c9e48671f071, 0x5dab22461fcda5d2, 0x587042afd3851b95, 0x8eb60ebe01bacb9e, 0x03f97d6e83d050d2, 0x18f0206554638741}
 
 // glvPhi2 ^ 3 = 1
-var glvPhi2 = &Fe{0x30f1361b798a64e8, 0xf3b8ddab7ece5a2a, 0x16a8ca3ac61577f7, 0xc26a2ff874fd029b, 0x3636b76660701c6e, 0x051ba4ab241b6160}
+var glvPhi2 = &Fp{0x30f1361b798a64e8, 0xf3b8ddab7ece5a2a, 0x16a8ca3ac61577f7, 0xc26a2ff874fd029b, 0x3636b76660701c6e, 0x051ba4ab241b6160}
 
 var glvMulWindowG1 uint = 4
 var glvMulWindowG2 uint = 4
 
+// MultiExp uses interleaved wNAF multiplication up to these many terms.
+var multiExpStraussThresholdG1 = 32
+var multiExpStraussThresholdG2 = 32
+
+// MultiExp accumulates Pippenger buckets with batched affine additions from
+// these many terms, where each round of additions is large enough to amortize
+// its inversion.
+var multiExpBatchAffineThresholdG1 = 128
+
+// multiExpBatchAffineMaxRound bounds number of additions resolved together.
+var multiExpBatchAffineMaxRound = 1024
+
 type glvVector interface {
 	wnaf(w uint) (nafNumber, nafNumber)
 }
@@ -168,7 +180,7 @@ func alpha2(m *Fr) *Fr {
 	return a.round()
 }
 
-func phi(a, b *Fe) {
+func phi(a, b *Fp) {
 	mul(a, b, glvPhi1)
 }
 
@@ -0,0 +1,114 @@
+// Package gnarkcompat converts points and scalars to and from byte encodings
+// of gnark-crypto BLS12-381 types, so values can be passed between the two
+// libraries without linking gnark-crypto.
+//
+// On gnark side G1Affine.Bytes, G1Affine.RawBytes, G2Affine.Bytes,
+// G2Affine.RawBytes and fr.Element.Bytes produce inputs of this package and
+// G1Affine.SetBytes, G2Affine.SetBytes and fr.Element.SetBytesCanonical
+// consume its outputs. Both libraries follow zcash point serialization.
+package gnarkcompat
+
+import (
+	"errors"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Sizes of gnark-crypto encodings in bytes.
+const (
+	SizeOfG1AffineCompressed   = bls.G1CompressedSize
+	SizeOfG1AffineUncompressed = bls.G1UncompressedSize
+	SizeOfG2AffineCompressed   = bls.G2CompressedSize
+	SizeOfG2AffineUncompressed = bls.G2UncompressedSize
+	SizeOfFrElement            = bls.FrSize
+)
+
+var ErrNonCanonicalScalar = errors.New("scalar must be less than group order")
+
+// G1FromBytes decodes output of G1Affine.Bytes or G1Affine.RawBytes. Point is
+// checked to be on curve and in correct subgroup.
+func G1FromBytes(in []byte) (*bls.PointG1, error) {
+	g := bls.NewG1()
+	p := g.New()
+	var err error
+	switch len(in) {
+	case SizeOfG1AffineCompressed:
+		err = g.FromCompressedInto(p, in)
+	case SizeOfG1AffineUncompressed:
+		err = g.FromUncompressedInto(p, in)
+	default:
+		err = bls.ErrInvalidLength
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// G1Bytes returns the point in G1Affine.Bytes encoding.
+func G1Bytes(p *bls.PointG1) [SizeOfG1AffineCompressed]byte {
+	var out [SizeOfG1AffineCompressed]byte
+	copy(out[:], bls.NewG1().ToCompressed(p))
+	return out
+}
+
+// G1RawBytes returns the point in G1Affine.RawBytes encoding.
+func G1RawBytes(p *bls.PointG1) [SizeOfG1AffineUncompressed]byte {
+	var out [SizeOfG1AffineUncompressed]byte
+	copy(out[:], bls.NewG1().ToUncompressed(p))
+	return out
+}
+
+// G2FromBytes decodes output of G2Affine.Bytes or G2Affine.RawBytes. Point is
+// checked to be on curve and in correct subgroup.
+func G2FromBytes(in []byte) (*bls.PointG2, error) {
+	g := bls.NewG2()
+	p := g.New()
+	var err error
+	switch len(in) {
+	case SizeOfG2AffineCompressed:
+		err = g.FromCompressedInto(p, in)
+	case SizeOfG2AffineUncompressed:
+		err = g.FromUncompressedInto(p, in)
+	default:
+		err = bls.ErrInvalidLength
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// G2Bytes returns the point in G2Affine.Bytes encoding.
+func G2Bytes(p *bls.PointG2) [SizeOfG2AffineCompressed]byte {
+	var out [SizeOfG2AffineCompressed]byte
+	copy(out[:], bls.NewG2().ToCompressed(p))
+	return out
+}
+
+// G2RawBytes returns the point in G2Affine.RawBytes encoding.
+func G2RawBytes(p *bls.PointG2) [SizeOfG2AffineUncompressed]byte {
+	var out [SizeOfG2AffineUncompressed]byte
+	copy(out[:], bls.NewG2().ToUncompressed(p))
+	return out
+}
+
+// FrFromBytes decodes output of fr.Element.Bytes. Non canonical inputs are
+// rejected.
+func FrFromBytes(in []byte) (*bls.Fr, error) {
+	if len(in) != SizeOfFrElement {
+		return nil, bls.ErrInvalidLength
+	}
+	if new(big.Int).SetBytes(in).Cmp(bls.NewG1().Q()) >= 0 {
+		return nil, ErrNonCanonicalScalar
+	}
+	return bls.NewFr().FromBytes(in), nil
+}
+
+// FrBytes returns the scalar in fr.Element.Bytes encoding.
+func FrBytes(e *bls.Fr) [SizeOfFrElement]byte {
+	var out [SizeOfFrElement]byte
+	copy(out[:], e.ToBytes())
+	return out
+}
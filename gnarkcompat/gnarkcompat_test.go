@@ -0,0 +1,66 @@
+package gnarkcompat
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestG1RoundTrip(t *testing.T) {
+	g := bls.NewG1()
+	s, _ := bls.NewFr().Rand(rand.Reader)
+	for _, p := range []*bls.PointG1{g.MulScalar(g.New(), g.One(), s), g.Zero()} {
+		c, u := G1Bytes(p), G1RawBytes(p)
+		for _, in := range [][]byte{c[:], u[:]} {
+			q, err := G1FromBytes(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(p, q) {
+				t.Fatal("bad g1 round trip")
+			}
+		}
+	}
+	if _, err := G1FromBytes(make([]byte, 47)); err != bls.ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+}
+
+func TestG2RoundTrip(t *testing.T) {
+	g := bls.NewG2()
+	s, _ := bls.NewFr().Rand(rand.Reader)
+	for _, p := range []*bls.PointG2{g.MulScalar(g.New(), g.One(), s), g.Zero()} {
+		c, u := G2Bytes(p), G2RawBytes(p)
+		for _, in := range [][]byte{c[:], u[:]} {
+			q, err := G2FromBytes(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(p, q) {
+				t.Fatal("bad g2 round trip")
+			}
+		}
+	}
+	if _, err := G2FromBytes(make([]byte, 95)); err != bls.ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+}
+
+func TestFrRoundTrip(t *testing.T) {
+	s, _ := bls.NewFr().Rand(rand.Reader)
+	b := FrBytes(s)
+	s2, err := FrFromBytes(b[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Equal(s2) {
+		t.Fatal("bad scalar round trip")
+	}
+	if _, err := FrFromBytes(bls.NewG1().Q().Bytes()); err != ErrNonCanonicalScalar {
+		t.Fatal("non canonical scalar must be rejected")
+	}
+	if _, err := FrFromBytes(b[1:]); err != bls.ErrInvalidLength {
+		t.Fatal("bad length must be rejected")
+	}
+}
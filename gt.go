@@ -20,8 +20,7 @@ func (e *E) Set(e2 *E) *E {
 
 // One sets a new target group element to one
 func (e *E) One() *E {
-	e = new(fe12).one()
-	return e
+	return e.one()
 }
 
 // IsOne returns true if given element equals to one
@@ -63,6 +62,41 @@ func (g *GT) ToBytes(e *E) []byte {
 	return g.fp12.toBytes(e)
 }
 
+// FromCompressedBytes expects 384 byte input produced by ToCompressedBytes and
+// returns target group element. It returns error if decompressed element is
+// not on correct subgroup.
+func (g *GT) FromCompressedBytes(in []byte) (*E, error) {
+	if len(in) != GTCompressedSize {
+		return nil, ErrInvalidLength
+	}
+	c := new(fe12c)
+	for i := range c {
+		if err := g.fp12.fp2().fromBytesInto(&c[3-i], in[i*2*fpByteSize:(i+1)*2*fpByteSize]); err != nil {
+			return nil, err
+		}
+	}
+	e := new(E)
+	g.fp12.decompress(e, c)
+	// inputs decompressed to one must be encodings of one
+	if !e[0][1].equal(&c[0]) || !e[1][2].equal(&c[3]) || !g.IsValid(e) {
+		return nil, errors.New("invalid element")
+	}
+	return e, nil
+}
+
+// ToCompressedBytes serializes target group element into 384 bytes keeping
+// four of six fp2 coefficients, as in Karabina compression. Other two are
+// recovered with an inversion on decoding.
+func (g *GT) ToCompressedBytes(e *E) []byte {
+	c := new(fe12c)
+	g.fp12.compress(c, e)
+	out := make([]byte, GTCompressedSize)
+	for i := range c {
+		copy(out[i*2*fpByteSize:], g.fp12.fp2().toBytes(&c[3-i]))
+	}
+	return out
+}
+
 // IsValid checks whether given target group element is in correct subgroup.
 func (g *GT) IsValid(e *E) bool {
 	r := g.New()
@@ -105,3 +139,13 @@ func (g *GT) Exp(c, a *E, s *big.Int) {
 func (g *GT) Inverse(c, a *E) {
 	g.fp12.inverse(c, a)
 }
+
+// Div divides an element `a` by `b` and assigns the result to the element in
+// first argument, so that verification equations e(P1, Q1) = e(P2, Q2) can be
+// written as a quotient. Elements must be in GT, where inverse of `b` is its
+// conjugate, so no field inversion takes place.
+func (g *GT) Div(c, a, b *E) {
+	t := new(E)
+	fp12Conjugate(t, b)
+	g.fp12.mul(c, a, t)
+}
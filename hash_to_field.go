@@ -3,16 +3,28 @@ package bls12381
 import (
 	"crypto/sha256"
 	"errors"
+	"hash"
 )
 
-func HashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*Fe, error) {
+// ErrHashToInfinity is returned by hash and encode to curve functions if the
+// output is the point at infinity, which happens with negligible probability.
+var ErrHashToInfinity = errors.New("hash to curve output is the point at infinity")
+
+func HashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*Fp, error) {
 	randBytes, err := expandMsgSHA256XMD(msg, domain, count*64)
 	if err != nil {
 		return nil, err
 	}
-	els := make([]*Fe, count)
+	return fpFromUniformBytes(randBytes, count)
+}
+
+// fpFromUniformBytes reduces consecutive 64 byte chunks of input to field
+// elements.
+func fpFromUniformBytes(randBytes []byte, count int) ([]*Fp, error) {
+	els := make([]*Fp, count)
 	for i := 0; i < count; i++ {
-		els[i], err = from64Bytes(randBytes[i*64 : (i+1)*64])
+		var err error
+		els[i], err = new(Fp).SetBytesWide(randBytes[i*64 : (i+1)*64])
 		if err != nil {
 			return nil, err
 		}
@@ -21,15 +33,28 @@ func HashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*Fe, error) {
 }
 
 func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
+	h := newXMDSHA256()
+	_, _ = h.Write(msg)
+	return finishXMDSHA256(h, domain, outLen)
+}
+
+// newXMDSHA256 returns the hash of b_0 with Z_pad written, so that message
+// can be written to it directly.
+func newXMDSHA256() hash.Hash {
 	h := sha256.New()
+	_, _ = h.Write(make([]byte, h.BlockSize()))
+	return h
+}
+
+// finishXMDSHA256 completes expand_message_xmd given the hash of b_0 with
+// Z_pad and message written. Hash is reused for the remaining blocks.
+func finishXMDSHA256(h hash.Hash, domain []byte, outLen int) ([]byte, error) {
 	domainLen := uint8(len(domain))
 	if domainLen > 255 {
 		return nil, errors.New("invalid domain length")
 	}
 	// DST_prime = DST || I2OSP(len(DST), 1)
 	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
-	_, _ = h.Write(make([]byte, h.BlockSize()))
-	_, _ = h.Write(msg)
 	_, _ = h.Write([]byte{uint8(outLen >> 8), uint8(outLen)})
 	_, _ = h.Write([]byte{0})
 	_, _ = h.Write(domain)
@@ -0,0 +1,96 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"hash"
+)
+
+// HasherG1 hashes a message written in parts to G1 with the same result as
+// G1.HashToCurve of the whole message, so that large messages need not be
+// buffered. Message is streamed into the first block of expand_message_xmd
+// and nothing else depends on its length. A hasher is not suitable for
+// concurrent use.
+type HasherG1 struct {
+	g      *G1
+	h      hash.Hash
+	domain []byte
+}
+
+// NewHasherG1 returns a hasher with given domain separation tag.
+func NewHasherG1(domain []byte) *HasherG1 {
+	return &HasherG1{NewG1(), newXMDSHA256(), append([]byte{}, domain...)}
+}
+
+// Write appends to the message. It never returns an error.
+func (h *HasherG1) Write(p []byte) (int, error) {
+	return h.h.Write(p)
+}
+
+// Reset discards the message written so far.
+func (h *HasherG1) Reset() {
+	h.h = newXMDSHA256()
+}
+
+// Sum returns hash of the message written so far. It does not change the
+// state of the hasher, so that more of the message can be written after.
+// ErrHashToInfinity is returned if the result is the point at infinity.
+func (h *HasherG1) Sum() (*PointG1, error) {
+	hashRes, err := sumXMDSHA256(h.h, h.domain, 2)
+	if err != nil {
+		return nil, err
+	}
+	return h.g.hashToCurve(hashRes)
+}
+
+// HasherG2 hashes a message written in parts to G2 with the same result as
+// G2.HashToCurve of the whole message, see HasherG1.
+type HasherG2 struct {
+	g      *G2
+	h      hash.Hash
+	domain []byte
+}
+
+// NewHasherG2 returns a hasher with given domain separation tag.
+func NewHasherG2(domain []byte) *HasherG2 {
+	return &HasherG2{NewG2(), newXMDSHA256(), append([]byte{}, domain...)}
+}
+
+// Write appends to the message. It never returns an error.
+func (h *HasherG2) Write(p []byte) (int, error) {
+	return h.h.Write(p)
+}
+
+// Reset discards the message written so far.
+func (h *HasherG2) Reset() {
+	h.h = newXMDSHA256()
+}
+
+// Sum returns hash of the message written so far. It does not change the
+// state of the hasher, so that more of the message can be written after.
+// ErrHashToInfinity is returned if the result is the point at infinity.
+func (h *HasherG2) Sum() (*PointG2, error) {
+	hashRes, err := sumXMDSHA256(h.h, h.domain, 4)
+	if err != nil {
+		return nil, err
+	}
+	return h.g.hashToCurve(hashRes)
+}
+
+// sumXMDSHA256 hashes to count field elements on a copy of the state of the
+// given b_0 hash.
+func sumXMDSHA256(h hash.Hash, domain []byte, count int) ([]*Fp, error) {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	c := sha256.New()
+	if err := c.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	randBytes, err := finishXMDSHA256(c, domain, count*64)
+	if err != nil {
+		return nil, err
+	}
+	return fpFromUniformBytes(randBytes, count)
+}
@@ -0,0 +1,27 @@
+// Package ssz implements merkleization of SimpleSerialize, shared by
+// packages that compute hash tree roots.
+package ssz
+
+import "crypto/sha256"
+
+// Merkleize packs input into zero padded 32 byte chunks and returns root of
+// the merkle tree with chunks as leaves. Number of leaves is padded to the
+// next power of two with zero chunks. It is the SSZ hash tree root of a fixed
+// size byte vector, and of a container given concatenated roots of its fields.
+func Merkleize(in []byte) [32]byte {
+	n := 1
+	for n*32 < len(in) {
+		n <<= 1
+	}
+	chunks := make([]byte, n*32)
+	copy(chunks, in)
+	for ; n > 1; n >>= 1 {
+		for i := 0; i < n/2; i++ {
+			h := sha256.Sum256(chunks[i*64 : (i+1)*64])
+			copy(chunks[i*32:], h[:])
+		}
+	}
+	var root [32]byte
+	copy(root[:], chunks[:32])
+	return root
+}
@@ -0,0 +1,28 @@
+package ssz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestMerkleize(t *testing.T) {
+	// Roots of zero subtrees of depth one and two.
+	zero1 := fromHex(t, "f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b")
+	zero2 := fromHex(t, "db56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71")
+	if root := Merkleize(make([]byte, 48)); !bytes.Equal(root[:], zero1) {
+		t.Fatal("bad root for zero Bytes48")
+	}
+	if root := Merkleize(make([]byte, 96)); !bytes.Equal(root[:], zero2) {
+		t.Fatal("bad root for zero Bytes96")
+	}
+}
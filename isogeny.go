@@ -1,8 +1,10 @@
 package bls12381
 
 // isogenyMapG1 applies 11-isogeny map for BLS12-381 G1 defined at draft-irtf-cfrg-hash-to-curve-06.
-func isogenyMapG1(x, y *Fe) {
-	xNum, xDen, yNum, yDen := new(Fe), new(Fe), new(Fe), new(Fe)
+// It returns false if the input is in the kernel of the isogeny, in which case
+// the image is the point at infinity and x, y are left unchanged.
+func isogenyMapG1(x, y *Fp) bool {
+	xNum, xDen, yNum, yDen := new(Fp), new(Fp), new(Fp), new(Fp)
 	xNum.set(isogenyConstansG1[0][15])
 	xDen.set(isogenyConstansG1[1][15])
 	yNum.set(isogenyConstansG1[2][15])
@@ -17,15 +19,21 @@ func isogenyMapG1(x, y *Fe) {
 		addAssign(yNum, isogenyConstansG1[2][i])
 		addAssign(yDen, isogenyConstansG1[3][i])
 	}
+	if xDen.isZero() || yDen.isZero() {
+		return false
+	}
 	inverse(xDen, xDen)
 	inverse(yDen, yDen)
 	mul(x, xNum, xDen)
 	mul(yNum, yNum, yDen)
 	mul(y, y, yNum)
+	return true
 }
 
 // isogenyMapG2 applies 3-isogeny map for BLS12-381 G2 defined at draft-irtf-cfrg-hash-to-curve-06.
-func isogenyMapG2(e *fp2, x, y *fe2) {
+// It returns false if the input is in the kernel of the isogeny, in which case
+// the image is the point at infinity and x, y are left unchanged.
+func isogenyMapG2(e *fp2, x, y *fe2) bool {
 	if e == nil {
 		e = newFp2()
 	}
@@ -57,14 +65,18 @@ func isogenyMapG2(e *fp2, x, y *fe2) {
 	fp2AddAssign(yNum, isogenyConstantsG2[2][0])
 	fp2AddAssign(yDen, isogenyConstantsG2[3][0])
 
+	if xDen.isZero() || yDen.isZero() {
+		return false
+	}
 	e.inverse(xDen, xDen)
 	e.inverse(yDen, yDen)
 	e.mul(x, xNum, xDen)
 	e.mulAssign(yNum, yDen)
 	e.mulAssign(y, yNum)
+	return true
 }
 
-var isogenyConstansG1 = [4][16]*Fe{
+var isogenyConstansG1 = [4][16]*Fp{
 	{
 		{0x4d18b6f3af00131c, 0x19fa219793fee28c, 0x3f2885f1467f19ae, 0x23dcea34f2ffb304, 0xd15b58d2ffc00054, 0x0913be200a20bef4},
 		{0x898985385cdbbd8b, 0x3c79e43cc7d966aa, 0x1597e193f4cd233a, 0x8637ef1e4d6623ad, 0x11b22deed20d827b, 0x07097bc5998784ad},
@@ -0,0 +1,160 @@
+package bls12381
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// Field elements, scalars and points are encoded in JSON as hex strings with
+// 0x prefix of their canonical byte encodings, same as their String output.
+// Points are in compressed form. Decoders accept strings with or without the
+// prefix and apply the same validation as byte decoders. JSON null leaves the
+// value unchanged.
+
+var errJSONString = errors.New("hex string expected")
+
+func marshalHexJSON(b []byte) ([]byte, error) {
+	return json.Marshal("0x" + hex.EncodeToString(b))
+}
+
+// unmarshalHexJSON returns bytes of a JSON hex string, or nil for JSON null.
+func unmarshalHexJSON(in []byte) ([]byte, error) {
+	if bytes.Equal(in, []byte("null")) {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(in, &s); err != nil {
+		return nil, errJSONString
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, errJSONString
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	return b, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Fp) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(e.Bytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Fp) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	return e.UnmarshalBinary(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Fp2) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(newFp2().toBytes(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Fp2) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	return e.UnmarshalBinary(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Fp6) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(newFp6(nil).toBytes(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Fp6) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	return e.UnmarshalBinary(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Fp12) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(newFp12(nil).toBytes(e))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Like UnmarshalBinary it does
+// not check subgroup membership.
+func (e *Fp12) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	return e.UnmarshalBinary(b)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Fr) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(e.ToBytes())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Scalar must be 32 bytes and less
+// than the group order.
+func (e *Fr) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	if len(b) != FrSize {
+		return ErrInvalidLength
+	}
+	if new(big.Int).SetBytes(b).Cmp(qBig) >= 0 {
+		return ErrNonCanonical
+	}
+	e.FromBytes(b)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The point is not modified.
+func (p *PointG1) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(NewG1().ToCompressed(new(PointG1).Set(p)))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Point is validated as in
+// G1.FromCompressed.
+func (p *PointG1) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	q := new(PointG1)
+	if err := NewG1().FromCompressedInto(q, b); err != nil {
+		return err
+	}
+	p.Set(q)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The point is not modified.
+func (p *PointG2) MarshalJSON() ([]byte, error) {
+	return marshalHexJSON(NewG2().ToCompressed(new(PointG2).Set(p)))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Point is validated as in
+// G2.FromCompressed.
+func (p *PointG2) UnmarshalJSON(in []byte) error {
+	b, err := unmarshalHexJSON(in)
+	if err != nil || b == nil {
+		return err
+	}
+	q := new(PointG2)
+	if err := NewG2().FromCompressedInto(q, b); err != nil {
+		return err
+	}
+	p.Set(q)
+	return nil
+}
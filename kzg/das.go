@@ -0,0 +1,230 @@
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Data availability sampling helpers. Data is a vector of scalars which is
+// taken as evaluations of a polynomial over a multiplicative subgroup. Data is
+// extended with Reed-Solomon code by evaluating the same polynomial over a
+// subgroup of twice the size, so any half of the extended data is enough to
+// recover it. Each extended evaluation is a sample which can be checked
+// against the commitment with its own opening proof.
+
+// maxDomainLog is 2-adicity of the scalar field.
+const maxDomainLog = 32
+
+var (
+	ErrInvalidDomainSize  = errors.New("domain size must be a power of two not larger than 2^32")
+	ErrDomainSizeMismatch = errors.New("data size must be equal to domain size")
+	ErrInvalidSample      = errors.New("sample index is out of domain")
+)
+
+// rootOfUnityGenerator is the smallest multiplicative generator of the scalar
+// field.
+var rootOfUnityGenerator = bls.NewFr().FromBytes([]byte{7})
+
+// Domain is a multiplicative subgroup of scalar field of size power of two.
+type Domain struct {
+	// Roots holds powers of the generator of the subgroup.
+	Roots []bls.Fr
+	nInv  bls.Fr
+}
+
+// NewDomain returns subgroup of given size.
+func NewDomain(n int) (*Domain, error) {
+	if n < 1 || n&(n-1) != 0 || uint64(n) > 1<<maxDomainLog {
+		return nil, ErrInvalidDomainSize
+	}
+	e := bls.NewG1().Q()
+	e.Sub(e, big.NewInt(1))
+	e.Div(e, big.NewInt(int64(n)))
+	w := bls.NewFr()
+	w.Exp(rootOfUnityGenerator, e)
+	d := &Domain{Roots: make([]bls.Fr, n)}
+	d.Roots[0].One()
+	for i := 1; i < n; i++ {
+		d.Roots[i].Mul(&d.Roots[i-1], w)
+	}
+	d.nInv.FromBytes(big.NewInt(int64(n)).Bytes())
+	d.nInv.Inverse(&d.nInv)
+	return d, nil
+}
+
+// Size returns number of elements of the domain.
+func (d *Domain) Size() int {
+	return len(d.Roots)
+}
+
+// FFT evaluates polynomial with given coefficients over the domain.
+// Coefficients are zero padded to domain size.
+func (d *Domain) FFT(p Polynomial) ([]bls.Fr, error) {
+	if len(p) > d.Size() {
+		return nil, ErrPolynomialTooLarge
+	}
+	out := make([]bls.Fr, d.Size())
+	copy(out, p)
+	d.fft(out, false)
+	return out, nil
+}
+
+// IFFT interpolates the polynomial from its evaluations over the domain.
+func (d *Domain) IFFT(evaluations []bls.Fr) (Polynomial, error) {
+	if len(evaluations) != d.Size() {
+		return nil, ErrDomainSizeMismatch
+	}
+	out := make(Polynomial, d.Size())
+	copy(out, evaluations)
+	d.fft(out, true)
+	return out, nil
+}
+
+// fft is in place iterative radix-2 transform.
+func (d *Domain) fft(a []bls.Fr, inverse bool) {
+	n := len(a)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	t := bls.NewFr()
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size>>1, n/size
+		for start := 0; start < n; start += size {
+			for j := 0; j < half; j++ {
+				k := j * step
+				if inverse && k != 0 {
+					k = n - k
+				}
+				t.Mul(&d.Roots[k], &a[start+j+half])
+				a[start+j+half].Sub(&a[start+j], t)
+				a[start+j].Add(&a[start+j], t)
+			}
+		}
+	}
+	if inverse {
+		for i := range a {
+			a[i].Mul(&a[i], &d.nInv)
+		}
+	}
+}
+
+// ExtendData extends data of size n, taken as evaluations over the domain of
+// size n, to evaluations over the domain of size 2n. Extended data at even
+// indices is equal to the original data. It also returns the polynomial
+// interpolating the data.
+func ExtendData(data []bls.Fr) ([]bls.Fr, Polynomial, error) {
+	d, err := NewDomain(len(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := d.IFFT(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	extended, err := NewDomain(2 * len(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := extended.FFT(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, p, nil
+}
+
+// ExtendData2D extends a k x n matrix to a 2k x 2n matrix by extending each
+// row and then each column of the result.
+func ExtendData2D(data [][]bls.Fr) ([][]bls.Fr, error) {
+	k := len(data)
+	rows := make([][]bls.Fr, 2*k)
+	for i := 0; i < k; i++ {
+		row, _, err := ExtendData(data[i])
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = row
+	}
+	if k == 0 {
+		return rows, nil
+	}
+	n := len(rows[0])
+	for i := k; i < 2*k; i++ {
+		rows[i] = make([]bls.Fr, n)
+	}
+	column := make([]bls.Fr, k)
+	for j := 0; j < n; j++ {
+		for i := 0; i < k; i++ {
+			if len(rows[i]) != n {
+				return nil, ErrDomainSizeMismatch
+			}
+			column[i] = rows[i][j]
+		}
+		extended, _, err := ExtendData(column)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < 2*k; i++ {
+			rows[i][j] = extended[i]
+		}
+	}
+	// Rows are reordered so that extended original rows come first and rows
+	// at odd powers of the column domain follow.
+	out := make([][]bls.Fr, 2*k)
+	for i := 0; i < k; i++ {
+		out[i], out[k+i] = rows[2*i], rows[2*i+1]
+	}
+	return out, nil
+}
+
+// Sample is an evaluation of the extended data with its opening proof.
+type Sample struct {
+	Index int
+	Value bls.Fr
+	Proof bls.PointG1
+}
+
+// ProveSample returns sample at given index of the polynomial evaluated over
+// the extended domain.
+func (srs *SRS) ProveSample(p Polynomial, extended *Domain, index int) (*Sample, error) {
+	if index < 0 || index >= extended.Size() {
+		return nil, ErrInvalidSample
+	}
+	y, proof, err := srs.Open(p, &extended.Roots[index])
+	if err != nil {
+		return nil, err
+	}
+	return &Sample{index, *y, *proof}, nil
+}
+
+// ProveSamples returns all samples of the polynomial over the extended domain.
+// Each proof is computed independently, which takes quadratic time in domain
+// size and is meant for prototyping.
+func (srs *SRS) ProveSamples(p Polynomial, extended *Domain) ([]*Sample, error) {
+	samples := make([]*Sample, extended.Size())
+	for i := range samples {
+		s, err := srs.ProveSample(p, extended, i)
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = s
+	}
+	return samples, nil
+}
+
+// VerifySample returns true if the sample is a valid evaluation of the
+// committed polynomial over the extended domain.
+func (srs *SRS) VerifySample(commitment *bls.PointG1, extended *Domain, s *Sample) bool {
+	if s.Index < 0 || s.Index >= extended.Size() {
+		return false
+	}
+	return srs.Verify(commitment, &extended.Roots[s.Index], &s.Value, &s.Proof)
+}
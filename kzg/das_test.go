@@ -0,0 +1,190 @@
+package kzg
+
+import (
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func randData(t *testing.T, n int) []bls.Fr {
+	t.Helper()
+	return []bls.Fr(randPolynomial(t, n))
+}
+
+func TestDomain(t *testing.T) {
+	for _, n := range []int{-1, 0, 3, 24} {
+		if _, err := NewDomain(n); err != ErrInvalidDomainSize {
+			t.Fatal("bad domain size must be rejected", n)
+		}
+	}
+	d, err := NewDomain(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := bls.NewFr()
+	w.Mul(&d.Roots[15], &d.Roots[1])
+	if !w.IsOne() || d.Roots[8].IsOne() {
+		t.Fatal("generator must have order of domain size")
+	}
+}
+
+func TestFFT(t *testing.T) {
+	d, _ := NewDomain(32)
+	p := randPolynomial(t, 20)
+	evaluations, err := d.FFT(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range evaluations {
+		if !evaluations[i].Equal(p.Evaluate(&d.Roots[i])) {
+			t.Fatal("bad evaluation", i)
+		}
+	}
+	q, err := d.IFFT(evaluations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range q {
+		expected := bls.NewFr()
+		if i < len(p) {
+			expected.Set(&p[i])
+		}
+		if !q[i].Equal(expected) {
+			t.Fatal("bad interpolation", i)
+		}
+	}
+	if _, err := d.IFFT(evaluations[1:]); err != ErrDomainSizeMismatch {
+		t.Fatal("size mismatch must be rejected")
+	}
+}
+
+func TestExtendData(t *testing.T) {
+	data := randData(t, 16)
+	extended, p, err := ExtendData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extended) != 32 {
+		t.Fatal("bad extended size")
+	}
+	for i := range data {
+		if !extended[2*i].Equal(&data[i]) {
+			t.Fatal("extended data must contain original data", i)
+		}
+	}
+	// Odd half alone is enough to recover the polynomial.
+	d, _ := NewDomain(32)
+	shift := &d.Roots[1]
+	odd := make([]bls.Fr, 16)
+	for i := range odd {
+		odd[i] = extended[2*i+1]
+	}
+	small, _ := NewDomain(16)
+	q, err := small.IFFT(odd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// q(x) = p(shift * x)
+	s := bls.NewFr().One()
+	for i := range q {
+		c := bls.NewFr()
+		c.Mul(&p[i], s)
+		if !c.Equal(&q[i]) {
+			t.Fatal("recovery from odd half fails", i)
+		}
+		s.Mul(s, shift)
+	}
+}
+
+func TestExtendData2D(t *testing.T) {
+	k, n := 4, 8
+	data := make([][]bls.Fr, k)
+	for i := range data {
+		data[i] = randData(t, n)
+	}
+	extended, err := ExtendData2D(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extended) != 2*k {
+		t.Fatal("bad number of rows")
+	}
+	for i := range extended {
+		if len(extended[i]) != 2*n {
+			t.Fatal("bad number of columns")
+		}
+	}
+	for i := 0; i < k; i++ {
+		for j := 0; j < n; j++ {
+			if !extended[i][2*j].Equal(&data[i][j]) {
+				t.Fatal("extended data must contain original data", i, j)
+			}
+		}
+	}
+	// Every column and every row must be a codeword.
+	check := func(v []bls.Fr) bool {
+		d, _ := NewDomain(len(v))
+		p, _ := d.IFFT(v)
+		for i := len(v) / 2; i < len(v); i++ {
+			if !p[i].IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range extended {
+		if !check(extended[i]) {
+			t.Fatal("row is not a codeword", i)
+		}
+	}
+	for j := 0; j < 2*n; j++ {
+		column := make([]bls.Fr, 2*k)
+		for i := 0; i < k; i++ {
+			column[2*i], column[2*i+1] = extended[i][j], extended[k+i][j]
+		}
+		if !check(column) {
+			t.Fatal("column is not a codeword", j)
+		}
+	}
+	data[1] = data[1][1:]
+	if _, err := ExtendData2D(data); err == nil {
+		t.Fatal("ragged matrix must be rejected")
+	}
+}
+
+func TestSamples(t *testing.T) {
+	srs := testSRS(t, 8)
+	extended, p, err := ExtendData(randData(t, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := srs.Commit(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := NewDomain(16)
+	samples, err := srs.ProveSamples(p, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range samples {
+		if !s.Value.Equal(&extended[i]) {
+			t.Fatal("bad sample value", i)
+		}
+		if !srs.VerifySample(c, d, s) {
+			t.Fatal("sample must be valid", i)
+		}
+	}
+	forged := *samples[0]
+	forged.Index = 1
+	if srs.VerifySample(c, d, &forged) {
+		t.Fatal("sample at another index must be invalid")
+	}
+	forged.Index = 16
+	if srs.VerifySample(c, d, &forged) {
+		t.Fatal("sample out of domain must be invalid")
+	}
+	if _, err := srs.ProveSample(p, d, -1); err != ErrInvalidSample {
+		t.Fatal("sample out of domain must be rejected")
+	}
+}
@@ -0,0 +1,93 @@
+package kzg
+
+import (
+	"crypto/sha256"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+const equivalenceDomain = "BLS12381_KZG_EQUIVALENCE_"
+
+// EquivalenceProof shows that a KZG commitment and an alternative commitment,
+// such as a hash of the data, commit to the same polynomial. It is an opening
+// of the KZG commitment at a point derived from both commitments with
+// Fiat-Shamir heuristic. Verifier evaluates the polynomial through the
+// alternative commitment at the same point and compares it with Y.
+type EquivalenceProof struct {
+	Y     bls.Fr
+	Proof bls.PointG1
+}
+
+// EquivalenceChallenge derives evaluation point from the KZG commitment and
+// an alternative commitment.
+func EquivalenceChallenge(commitment *bls.PointG1, altCommitment []byte) *bls.Fr {
+	c := bls.NewG1().ToCompressed(commitment)
+	// Two hash outputs are reduced so that the challenge is close to uniform.
+	out := make([]byte, 0, 2*sha256.Size)
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write([]byte(equivalenceDomain))
+		h.Write([]byte{i})
+		h.Write(c)
+		h.Write(altCommitment)
+		out = h.Sum(out)
+	}
+	return bls.NewFr().SetBytesMod(out)
+}
+
+// ProveEquivalence returns the KZG commitment to the polynomial and a proof
+// of its equivalence to the alternative commitment.
+func (srs *SRS) ProveEquivalence(p Polynomial, altCommitment []byte) (*bls.PointG1, *EquivalenceProof, error) {
+	commitment, err := srs.Commit(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, proof, err := srs.Open(p, EquivalenceChallenge(commitment, altCommitment))
+	if err != nil {
+		return nil, nil, err
+	}
+	return commitment, &EquivalenceProof{*y, *proof}, nil
+}
+
+// VerifyEquivalence verifies the equivalence proof where evaluate evaluates
+// the polynomial behind the alternative commitment at the given point.
+func (srs *SRS) VerifyEquivalence(commitment *bls.PointG1, altCommitment []byte, evaluate func(z *bls.Fr) *bls.Fr, proof *EquivalenceProof) bool {
+	z := EquivalenceChallenge(commitment, altCommitment)
+	if !evaluate(z).Equal(&proof.Y) {
+		return false
+	}
+	return srs.Verify(commitment, z, &proof.Y, &proof.Proof)
+}
+
+// Bytes returns coefficients of the polynomial as concatenated 32 byte big
+// endian scalars.
+func (p Polynomial) Bytes() []byte {
+	out := make([]byte, 0, len(p)*bls.FrSize)
+	for i := range p {
+		out = append(out, p[i].ToBytes()...)
+	}
+	return out
+}
+
+// SHA256Commitment returns SHA-256 of the polynomial bytes as an alternative
+// commitment.
+func (p Polynomial) SHA256Commitment() []byte {
+	h := sha256.Sum256(p.Bytes())
+	return h[:]
+}
+
+// ProveSHA256Equivalence proves that the KZG commitment and SHA-256 of the
+// polynomial bytes commit to the same polynomial.
+func (srs *SRS) ProveSHA256Equivalence(p Polynomial) (*bls.PointG1, *EquivalenceProof, error) {
+	return srs.ProveEquivalence(p, p.SHA256Commitment())
+}
+
+// VerifySHA256Equivalence verifies that the KZG commitment commits to the
+// polynomial whose bytes hash to the given SHA-256 digest, where data is the
+// preimage held by the verifier.
+func (srs *SRS) VerifySHA256Equivalence(commitment *bls.PointG1, digest []byte, data Polynomial, proof *EquivalenceProof) bool {
+	if string(data.SHA256Commitment()) != string(digest) {
+		return false
+	}
+	return srs.VerifyEquivalence(commitment, digest, data.Evaluate, proof)
+}
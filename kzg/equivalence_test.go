@@ -0,0 +1,52 @@
+package kzg
+
+import (
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestSHA256Equivalence(t *testing.T) {
+	srs := testSRS(t, 32)
+	p := randPolynomial(t, 32)
+	c, proof, err := srs.ProveSHA256Equivalence(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := p.SHA256Commitment()
+	if !srs.VerifySHA256Equivalence(c, digest, p, proof) {
+		t.Fatal("equivalence proof must be valid")
+	}
+
+	other := randPolynomial(t, 32)
+	if srs.VerifySHA256Equivalence(c, other.SHA256Commitment(), other, proof) {
+		t.Fatal("proof must not be valid for another polynomial")
+	}
+	otherCommitment, err := srs.Commit(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srs.VerifySHA256Equivalence(otherCommitment, digest, p, proof) {
+		t.Fatal("proof must not be valid for another commitment")
+	}
+	// A proof for an honest opening at a point other than the challenge
+	// must be rejected.
+	z := bls.NewFr().One()
+	y, opening, _ := srs.Open(p, z)
+	if srs.VerifySHA256Equivalence(c, digest, p, &EquivalenceProof{*y, *opening}) {
+		t.Fatal("opening at arbitrary point must be rejected")
+	}
+}
+
+func TestEquivalenceChallenge(t *testing.T) {
+	g := bls.NewG1()
+	z0 := EquivalenceChallenge(g.One(), []byte{0})
+	z1 := EquivalenceChallenge(g.One(), []byte{1})
+	z2 := EquivalenceChallenge(g.Zero(), []byte{0})
+	if z0.Equal(z1) || z0.Equal(z2) {
+		t.Fatal("challenge must bind both commitments")
+	}
+	if !z0.Equal(EquivalenceChallenge(g.One(), []byte{0})) {
+		t.Fatal("challenge must be deterministic")
+	}
+}
@@ -0,0 +1,116 @@
+// Package kzg implements KZG polynomial commitments over BLS12-381 with
+// commitments and opening proofs in G1.
+//
+// Polynomial Commitments, A. Kate, G. M. Zaverucha, I. Goldberg
+// https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf
+package kzg
+
+import (
+	"errors"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var (
+	ErrPolynomialTooLarge = errors.New("polynomial degree exceeds setup size")
+	ErrEmptySetup         = errors.New("setup must have at least one power")
+)
+
+// SRS is a structured reference string holding powers of a secret tau.
+type SRS struct {
+	// G1 holds [tau^i]G1 for i in [0, n).
+	G1 []*bls.PointG1
+	// G2 holds [1]G2 and [tau]G2.
+	G2 [2]*bls.PointG2
+}
+
+// NewSRSInsecure generates a setup of size n from a known secret. It is only
+// suitable for tests since anyone who knows tau can forge opening proofs.
+func NewSRSInsecure(n int, tau *bls.Fr) (*SRS, error) {
+	if n < 1 {
+		return nil, ErrEmptySetup
+	}
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	srs := &SRS{G1: make([]*bls.PointG1, n)}
+	t := bls.NewFr().One()
+	for i := 0; i < n; i++ {
+		srs.G1[i] = g1.MulScalar(g1.New(), g1.One(), t)
+		t.Mul(t, tau)
+	}
+	g1.AffineBatch(srs.G1)
+	srs.G2[0] = g2.One()
+	srs.G2[1] = g2.Affine(g2.MulScalar(g2.New(), g2.One(), tau))
+	return srs, nil
+}
+
+// Size returns number of G1 powers, which bounds degree of committed
+// polynomials.
+func (srs *SRS) Size() int {
+	return len(srs.G1)
+}
+
+// Polynomial is a polynomial over scalar field in coefficient form where i-th
+// element is coefficient of x^i.
+type Polynomial []bls.Fr
+
+// Evaluate returns value of the polynomial at z.
+func (p Polynomial) Evaluate(z *bls.Fr) *bls.Fr {
+	y := bls.NewFr()
+	for i := len(p) - 1; i >= 0; i-- {
+		y.Mul(y, z)
+		y.Add(y, &p[i])
+	}
+	return y
+}
+
+// Commit returns commitment to the polynomial.
+func (srs *SRS) Commit(p Polynomial) (*bls.PointG1, error) {
+	if len(p) > srs.Size() {
+		return nil, ErrPolynomialTooLarge
+	}
+	g := bls.NewG1()
+	scalars := make([]*bls.Fr, len(p))
+	for i := range p {
+		scalars[i] = &p[i]
+	}
+	return g.MultiExp(g.New(), srs.G1[:len(p)], scalars)
+}
+
+// Open returns value of the polynomial at z and proof of the evaluation,
+// which is commitment to the quotient (p(x) - p(z)) / (x - z).
+func (srs *SRS) Open(p Polynomial, z *bls.Fr) (*bls.Fr, *bls.PointG1, error) {
+	if len(p) > srs.Size() {
+		return nil, nil, ErrPolynomialTooLarge
+	}
+	if len(p) < 2 {
+		return p.Evaluate(z), bls.NewG1().Zero(), nil
+	}
+	// Synthetic division by (x - z), remainder is p(z).
+	q := make(Polynomial, len(p)-1)
+	q[len(q)-1].Set(&p[len(p)-1])
+	t := bls.NewFr()
+	for i := len(q) - 1; i > 0; i-- {
+		t.Mul(&q[i], z)
+		q[i-1].Add(&p[i], t)
+	}
+	y := bls.NewFr()
+	y.Mul(&q[0], z)
+	y.Add(y, &p[0])
+	proof, err := srs.Commit(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return y, proof, nil
+}
+
+// Verify returns true if proof shows that polynomial committed in commitment
+// evaluates to y at z. It checks e(C - [y]G1, G2) == e(proof, [tau - z]G2).
+func (srs *SRS) Verify(commitment *bls.PointG1, z, y *bls.Fr, proof *bls.PointG1) bool {
+	e := bls.NewEngine()
+	g1, g2 := e.G1, e.G2
+	c := g1.MulScalar(g1.New(), g1.One(), y)
+	g1.Sub(c, commitment, c)
+	s := g2.MulScalar(g2.New(), srs.G2[0], z)
+	g2.Sub(s, srs.G2[1], s)
+	return e.AddPair(c, srs.G2[0]).AddPairInv(proof, s).Check()
+}
@@ -0,0 +1,79 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func randPolynomial(t *testing.T, n int) Polynomial {
+	t.Helper()
+	p := make(Polynomial, n)
+	for i := range p {
+		if _, err := p[i].Rand(rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p
+}
+
+func testSRS(t *testing.T, n int) *SRS {
+	t.Helper()
+	tau, _ := bls.NewFr().Rand(rand.Reader)
+	srs, err := NewSRSInsecure(n, tau)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srs
+}
+
+func TestCommitment(t *testing.T) {
+	tau, _ := bls.NewFr().Rand(rand.Reader)
+	srs, err := NewSRSInsecure(16, tau)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := randPolynomial(t, 16)
+	c, err := srs.Commit(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := bls.NewG1()
+	expected := g.MulScalar(g.New(), g.One(), p.Evaluate(tau))
+	if !g.Equal(c, expected) {
+		t.Fatal("commitment must be [p(tau)]G1")
+	}
+	if _, err := srs.Commit(randPolynomial(t, 17)); err != ErrPolynomialTooLarge {
+		t.Fatal("large polynomial must be rejected")
+	}
+	if _, err := NewSRSInsecure(0, tau); err != ErrEmptySetup {
+		t.Fatal("empty setup must be rejected")
+	}
+}
+
+func TestOpening(t *testing.T) {
+	srs := testSRS(t, 16)
+	for _, n := range []int{1, 2, 7, 16} {
+		p := randPolynomial(t, n)
+		c, err := srs.Commit(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		z, _ := bls.NewFr().Rand(rand.Reader)
+		y, proof, err := srs.Open(p, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !y.Equal(p.Evaluate(z)) {
+			t.Fatal("bad evaluation", n)
+		}
+		if !srs.Verify(c, z, y, proof) {
+			t.Fatal("opening must be valid", n)
+		}
+		y.Add(y, bls.NewFr().One())
+		if srs.Verify(c, z, y, proof) {
+			t.Fatal("opening with wrong value must be invalid", n)
+		}
+	}
+}
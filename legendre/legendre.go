@@ -0,0 +1,53 @@
+// Package legendre implements the Legendre pseudo random function over the
+// base field of BLS12-381.
+package legendre
+
+import (
+	bls "github.com/kilic/bls12-381"
+)
+
+// PRF is the Legendre pseudo random function over the base field as proposed
+// for proof of custody schemes. Output bit at x is derived from Legendre
+// symbol of key + x, following legendre_bit of the custody game
+// specification: it is 1 if key + x is a non zero quadratic residue and 0
+// otherwise.
+type PRF struct {
+	key bls.Fp
+}
+
+// NewPRF returns a Legendre PRF with given key, which is a 48 bytes big
+// endian field element less than the modulus.
+func NewPRF(key []byte) (*PRF, error) {
+	l := &PRF{}
+	if _, err := l.key.SetBytes(key); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Bit returns output bit of the PRF at x.
+func (l *PRF) Bit(x uint64) byte {
+	t := new(bls.Fp).SetUint64(x)
+	t.Add(t, &l.key)
+	if bls.IsQuadraticResidue(t) {
+		return 1
+	}
+	return 0
+}
+
+// Bits fills out with consecutive output bits starting from x. Bits are
+// packed least significant bit first, so bit i of byte j is output at
+// x + 8j + i.
+func (l *PRF) Bits(x uint64, out []byte) {
+	t, one := new(bls.Fp).SetUint64(x), new(bls.Fp).One()
+	t.Add(t, &l.key)
+	for j := range out {
+		out[j] = 0
+		for i := uint(0); i < 8; i++ {
+			if bls.IsQuadraticResidue(t) {
+				out[j] |= 1 << i
+			}
+			t.Add(t, one)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package legendre
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestPRF(t *testing.T) {
+	minusOne := new(bls.Fp).One()
+	minusOne.Neg(minusOne)
+	modulus := new(big.Int).Add(minusOne.Big(), big.NewInt(1))
+	for i := 0; i < 10; i++ {
+		k, _ := new(bls.Fp).Rand(rand.Reader)
+		key := k.Bytes()
+		l, err := NewPRF(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x := uint64(i) * 0x9e3779b97f4a7c15
+		out := make([]byte, 4)
+		l.Bits(x, out)
+		kBig := new(big.Int).SetBytes(key)
+		for j := 0; j < 32; j++ {
+			a := new(big.Int).Add(kBig, new(big.Int).SetUint64(x+uint64(j)))
+			a.Mod(a, modulus)
+			var expected byte
+			if big.Jacobi(a, modulus) == 1 {
+				expected = 1
+			}
+			if bit := l.Bit(x + uint64(j)); bit != expected {
+				t.Fatalf("bad bit at %d", j)
+			}
+			if (out[j/8]>>(uint(j)%8))&1 != expected {
+				t.Fatalf("bad packed bit at %d", j)
+			}
+		}
+	}
+	// key + x is zero, which is not a quadratic residue
+	for _, v := range []struct {
+		key *bls.Fp
+		x   uint64
+	}{{new(bls.Fp).Zero(), 0}, {minusOne, 1}} {
+		l, err := NewPRF(v.key.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Bit(v.x) != 0 {
+			t.Fatal("output must be zero at zero", v.x)
+		}
+		out := make([]byte, 1)
+		l.Bits(v.x, out)
+		if out[0]&1 != 0 {
+			t.Fatal("packed output must be zero at zero", v.x)
+		}
+		// 1 is a non zero quadratic residue
+		if l.Bit(v.x+1) != 1 || out[0]&2 == 0 {
+			t.Fatal("output must be one at one", v.x)
+		}
+	}
+	if _, err := NewPRF(modulus.Bytes()); err == nil {
+		t.Fatal("non canonical key must be rejected")
+	}
+}
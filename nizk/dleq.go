@@ -0,0 +1,156 @@
+package nizk
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+const dleqDomain = "BLS12381G1G2_DLEQ_"
+
+// DLEQSize is the size of an encoded proof of discrete logarithm equality.
+const DLEQSize = bls.G1CompressedSize + bls.G2CompressedSize + bls.FrSize
+
+// ErrBatchSize is returned when numbers of keys and proofs in a batch differ.
+var ErrBatchSize = errors.New("number of keys and proofs must be equal")
+
+// DLEQ is a proof that public keys X1 = x * G1 and X2 = x * G2 share the same
+// secret x, for keys exposed in both groups. It is a Schnorr proof over both
+// groups at once: with commitments R1 = k * G1 and R2 = k * G2 and challenge c
+// hashed from the keys and commitments, prover responds with z = k + c * x and
+// verifier checks z * G1 == R1 + c * X1 and z * G2 == R2 + c * X2. Unlike a
+// pairing check of the keys it also proves knowledge of x. Commitments are
+// kept in the proof rather than the challenge, so that proofs can be verified
+// in batch.
+type DLEQ struct {
+	Commitment1 bls.PointG1
+	Commitment2 bls.PointG2
+	Response    bls.Fr
+}
+
+// dleqChallenge derives challenge of the proof from the keys and the
+// commitments.
+func dleqChallenge(g1 *bls.G1, g2 *bls.G2, x1 *bls.PointG1, x2 *bls.PointG2, proof *DLEQ, context []byte) *bls.Fr {
+	return challenge(dleqDomain,
+		g1.ToCompressed(x1), g2.ToCompressed(x2),
+		g1.ToCompressed(&proof.Commitment1), g2.ToCompressed(&proof.Commitment2),
+		context)
+}
+
+// ProveDLEQ proves that keys x * G1 and x * G2 share the secret x. Context is
+// bound to the proof, so that a proof is only valid for the same context.
+func ProveDLEQ(r io.Reader, x *bls.Fr, context []byte) (*DLEQ, error) {
+	k, err := bls.NewFr().Rand(r)
+	if err != nil {
+		return nil, err
+	}
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	x1 := g1.MulScalar(g1.New(), g1.One(), x)
+	x2 := g2.MulScalar(g2.New(), g2.One(), x)
+	proof := &DLEQ{}
+	g1.Affine(g1.MulScalar(&proof.Commitment1, g1.One(), k))
+	g2.Affine(g2.MulScalar(&proof.Commitment2, g2.One(), k))
+	c := dleqChallenge(g1, g2, x1, x2, proof, context)
+	proof.Response.Mul(c, x)
+	proof.Response.Add(&proof.Response, k)
+	return proof, nil
+}
+
+// VerifyDLEQ returns true if the proof shows that keys x1 and x2 share the same
+// secret. Keys are expected to be in the prime order subgroups, as decoded
+// from compressed form.
+func VerifyDLEQ(x1 *bls.PointG1, x2 *bls.PointG2, proof *DLEQ, context []byte) bool {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	c := dleqChallenge(g1, g2, x1, x2, proof, context)
+	l1, r1 := g1.New(), g1.New()
+	g1.MulScalar(l1, g1.One(), &proof.Response)
+	g1.MulScalar(r1, x1, c)
+	g1.Add(r1, r1, &proof.Commitment1)
+	if !g1.Equal(l1, r1) {
+		return false
+	}
+	l2, r2 := g2.New(), g2.New()
+	g2.MulScalar(l2, g2.One(), &proof.Response)
+	g2.MulScalar(r2, x2, c)
+	g2.Add(r2, r2, &proof.Commitment2)
+	return g2.Equal(l2, r2)
+}
+
+// BatchVerifyDLEQ verifies proofs for pairs of keys x1[i] and x2[i] at once.
+// Verification equations are combined with random coefficients drawn from r
+// into one multi exponentiation in each group, so that a batch with an invalid
+// proof passes only with negligible probability. It returns ErrBatchSize if
+// lengths of inputs differ and an empty batch is valid.
+func BatchVerifyDLEQ(r io.Reader, x1 []*bls.PointG1, x2 []*bls.PointG2, proofs []*DLEQ, context []byte) (bool, error) {
+	n := len(proofs)
+	if len(x1) != n || len(x2) != n {
+		return false, ErrBatchSize
+	}
+	if n == 0 {
+		return true, nil
+	}
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	// sum(rho * z) * G - sum(rho * R) - sum(rho * c * X) == 0
+	points1 := make([]*bls.PointG1, 0, 2*n+1)
+	points2 := make([]*bls.PointG2, 0, 2*n+1)
+	scalars := make([]*bls.Fr, 0, 2*n+1)
+	points1 = append(points1, g1.One())
+	points2 = append(points2, g2.One())
+	z := bls.NewFr()
+	scalars = append(scalars, z)
+	for i, proof := range proofs {
+		rho, err := bls.NewFr().RandNonZero(r)
+		if err != nil {
+			return false, err
+		}
+		c := dleqChallenge(g1, g2, x1[i], x2[i], proof, context)
+		t := bls.NewFr()
+		t.Mul(rho, &proof.Response)
+		z.Add(z, t)
+		c.Mul(c, rho)
+		c.Neg(c)
+		rho.Neg(rho)
+		points1 = append(points1, &proof.Commitment1, x1[i])
+		points2 = append(points2, &proof.Commitment2, x2[i])
+		scalars = append(scalars, rho, c)
+	}
+	acc1, acc2 := g1.New(), g2.New()
+	if _, err := g1.MultiExp(acc1, points1, scalars); err != nil {
+		return false, err
+	}
+	if _, err := g2.MultiExp(acc2, points2, scalars); err != nil {
+		return false, err
+	}
+	return g1.IsZero(acc1) && g2.IsZero(acc2), nil
+}
+
+// Bytes returns compressed commitments in G1 and G2 followed by the response.
+func (proof *DLEQ) Bytes() []byte {
+	out := bls.NewG1().ToCompressed(&proof.Commitment1)
+	out = append(out, bls.NewG2().ToCompressed(&proof.Commitment2)...)
+	return append(out, proof.Response.ToBytes()...)
+}
+
+// DLEQFromBytes decodes a proof, commitments must be points in G1 and G2 and
+// response must be less than group order.
+func DLEQFromBytes(in []byte) (*DLEQ, error) {
+	if len(in) != DLEQSize {
+		return nil, ErrInvalidProof
+	}
+	proof := &DLEQ{}
+	if err := bls.NewG1().FromCompressedInto(&proof.Commitment1, in[:bls.G1CompressedSize]); err != nil {
+		return nil, err
+	}
+	in = in[bls.G1CompressedSize:]
+	if err := bls.NewG2().FromCompressedInto(&proof.Commitment2, in[:bls.G2CompressedSize]); err != nil {
+		return nil, err
+	}
+	response := in[bls.G2CompressedSize:]
+	if new(big.Int).SetBytes(response).Cmp(bls.NewG1().Q()) >= 0 {
+		return nil, ErrInvalidProof
+	}
+	proof.Response.FromBytes(response)
+	return proof, nil
+}
@@ -0,0 +1,89 @@
+package nizk
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func dleqKeys(x *bls.Fr) (*bls.PointG1, *bls.PointG2) {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	return g1.MulScalar(g1.New(), g1.One(), x), g2.MulScalar(g2.New(), g2.One(), x)
+}
+
+func TestDLEQ(t *testing.T) {
+	x, _ := bls.NewFr().Rand(rand.Reader)
+	x1, x2 := dleqKeys(x)
+	context := []byte("validator keys")
+	proof, err := ProveDLEQ(rand.Reader, x, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyDLEQ(x1, x2, proof, context) {
+		t.Fatal("proof must be valid")
+	}
+	if VerifyDLEQ(x1, x2, proof, []byte("other context")) {
+		t.Fatal("proof must be bound to its context")
+	}
+	y, _ := bls.NewFr().Rand(rand.Reader)
+	y1, y2 := dleqKeys(y)
+	if VerifyDLEQ(x1, y2, proof, context) || VerifyDLEQ(y1, x2, proof, context) {
+		t.Fatal("proof must be bound to both keys")
+	}
+	// keys of different secrets with a proof of either
+	other, _ := ProveDLEQ(rand.Reader, y, context)
+	if VerifyDLEQ(x1, y2, other, context) {
+		t.Fatal("keys of different secrets must not verify")
+	}
+
+	decoded, err := DLEQFromBytes(proof.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyDLEQ(x1, x2, decoded, context) {
+		t.Fatal("decoded proof must be valid")
+	}
+	if _, err := DLEQFromBytes(proof.Bytes()[1:]); err != ErrInvalidProof {
+		t.Fatal("short input must be rejected")
+	}
+	in := proof.Bytes()
+	for i := DLEQSize - bls.FrSize; i < DLEQSize; i++ {
+		in[i] = 0xff
+	}
+	if _, err := DLEQFromBytes(in); err != ErrInvalidProof {
+		t.Fatal("non canonical response must be rejected")
+	}
+}
+
+func TestBatchVerifyDLEQ(t *testing.T) {
+	n := 8
+	context := []byte("validator keys")
+	x1 := make([]*bls.PointG1, n)
+	x2 := make([]*bls.PointG2, n)
+	proofs := make([]*DLEQ, n)
+	for i := 0; i < n; i++ {
+		x, _ := bls.NewFr().Rand(rand.Reader)
+		x1[i], x2[i] = dleqKeys(x)
+		proofs[i], _ = ProveDLEQ(rand.Reader, x, context)
+	}
+	if ok, err := BatchVerifyDLEQ(rand.Reader, x1, x2, proofs, context); err != nil || !ok {
+		t.Fatal("batch must be valid", err)
+	}
+	if ok, _ := BatchVerifyDLEQ(rand.Reader, nil, nil, nil, context); !ok {
+		t.Fatal("empty batch must be valid")
+	}
+	if _, err := BatchVerifyDLEQ(rand.Reader, x1[1:], x2, proofs, context); err != ErrBatchSize {
+		t.Fatal("length mismatch must be rejected", err)
+	}
+	// swapped G2 keys of two members
+	x2[2], x2[5] = x2[5], x2[2]
+	if ok, _ := BatchVerifyDLEQ(rand.Reader, x1, x2, proofs, context); ok {
+		t.Fatal("batch with mismatched keys must be invalid")
+	}
+	x2[2], x2[5] = x2[5], x2[2]
+	proofs[3], proofs[4] = proofs[4], proofs[3]
+	if ok, _ := BatchVerifyDLEQ(rand.Reader, x1, x2, proofs, context); ok {
+		t.Fatal("batch with swapped proofs must be invalid")
+	}
+}
@@ -0,0 +1,110 @@
+// Package nizk implements non interactive proofs over BLS12-381 groups made
+// non interactive with Fiat-Shamir heuristic.
+package nizk
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+const pokeDomain = "BLS12381G1_POKE_"
+
+// PoKESize is the size of an encoded proof of knowledge of exponent.
+const PoKESize = bls.G1CompressedSize + bls.FrSize
+
+// ErrInvalidProof is returned when a proof can not be decoded.
+var ErrInvalidProof = errors.New("invalid proof encoding")
+
+// PoKE is a proof of knowledge of exponent x such that Y = x * U for public
+// base U and element Y in G1, as used for accumulator updates where U is the
+// old accumulator and Y is the new one.
+//
+// Wesolowski style proofs, where prover sends quotient of x by a challenge
+// prime and verifier checks the remainder, rely on order of the group being
+// unknown. In a group of known prime order the quotient can be computed
+// without the exponent, so the proof here is a Schnorr proof instead: with
+// commitment R = k * U and challenge c hashed from the statement and R,
+// prover responds with z = k + c * x and verifier checks z * U == R + c * Y.
+type PoKE struct {
+	Commitment bls.PointG1
+	Response   bls.Fr
+}
+
+// challenge hashes domain separation label of a proof type and given parts of
+// the statement and commitments into a challenge scalar. Two hash outputs are
+// reduced so that the challenge is close to uniform.
+func challenge(domain string, parts ...[]byte) *bls.Fr {
+	out := make([]byte, 0, 2*sha256.Size)
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write([]byte(domain))
+		h.Write([]byte{i})
+		for _, part := range parts {
+			h.Write(part)
+		}
+		out = h.Sum(out)
+	}
+	return bls.NewFr().SetBytesMod(out)
+}
+
+// pokeChallenge derives challenge of the proof from the statement and the
+// commitment.
+func pokeChallenge(g *bls.G1, u, y, commitment *bls.PointG1, context []byte) *bls.Fr {
+	return challenge(pokeDomain, g.ToCompressed(u), g.ToCompressed(y), g.ToCompressed(commitment), context)
+}
+
+// ProvePoKE proves knowledge of x where y = x * u. Context is bound to the
+// proof, so that a proof is only valid for the same context.
+func ProvePoKE(r io.Reader, u, y *bls.PointG1, x *bls.Fr, context []byte) (*PoKE, error) {
+	k, err := bls.NewFr().Rand(r)
+	if err != nil {
+		return nil, err
+	}
+	g := bls.NewG1()
+	proof := &PoKE{}
+	g.Affine(g.MulScalar(&proof.Commitment, u, k))
+	c := pokeChallenge(g, u, y, &proof.Commitment, context)
+	proof.Response.Mul(c, x)
+	proof.Response.Add(&proof.Response, k)
+	return proof, nil
+}
+
+// VerifyPoKE returns true if the proof shows knowledge of exponent of y in
+// base u.
+func VerifyPoKE(u, y *bls.PointG1, proof *PoKE, context []byte) bool {
+	g := bls.NewG1()
+	c := pokeChallenge(g, u, y, &proof.Commitment, context)
+	l, r := g.New(), g.New()
+	g.MulScalar(l, u, &proof.Response)
+	g.MulScalar(r, y, c)
+	g.Add(r, r, &proof.Commitment)
+	return g.Equal(l, r)
+}
+
+// Bytes returns compressed commitment followed by the response.
+func (proof *PoKE) Bytes() []byte {
+	out := bls.NewG1().ToCompressed(&proof.Commitment)
+	return append(out, proof.Response.ToBytes()...)
+}
+
+// PoKEFromBytes decodes a proof, commitment must be a point in G1 and
+// response must be less than group order.
+func PoKEFromBytes(in []byte) (*PoKE, error) {
+	if len(in) != PoKESize {
+		return nil, ErrInvalidProof
+	}
+	proof := &PoKE{}
+	if err := bls.NewG1().FromCompressedInto(&proof.Commitment, in[:bls.G1CompressedSize]); err != nil {
+		return nil, err
+	}
+	response := in[bls.G1CompressedSize:]
+	if new(big.Int).SetBytes(response).Cmp(bls.NewG1().Q()) >= 0 {
+		return nil, ErrInvalidProof
+	}
+	proof.Response.FromBytes(response)
+	return proof, nil
+}
@@ -0,0 +1,64 @@
+package nizk
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func randG1(t *testing.T) *bls.PointG1 {
+	g := bls.NewG1()
+	s, err := bls.NewFr().Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g.MulScalar(g.New(), g.One(), s)
+}
+
+func TestPoKE(t *testing.T) {
+	g := bls.NewG1()
+	u := randG1(t)
+	x, _ := bls.NewFr().Rand(rand.Reader)
+	y := g.MulScalar(g.New(), u, x)
+	context := []byte("accumulator update")
+	proof, err := ProvePoKE(rand.Reader, u, y, x, context)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyPoKE(u, y, proof, context) {
+		t.Fatal("proof must be valid")
+	}
+	if VerifyPoKE(u, y, proof, []byte("other context")) {
+		t.Fatal("proof must be bound to its context")
+	}
+	if VerifyPoKE(u, randG1(t), proof, context) {
+		t.Fatal("proof must be bound to the statement")
+	}
+	if VerifyPoKE(randG1(t), y, proof, context) {
+		t.Fatal("proof must be bound to the base")
+	}
+	// prover not knowing x
+	fake, _ := ProvePoKE(rand.Reader, u, y, bls.NewFr().One(), context)
+	if VerifyPoKE(u, y, fake, context) {
+		t.Fatal("proof with wrong exponent must be invalid")
+	}
+
+	decoded, err := PoKEFromBytes(proof.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyPoKE(u, y, decoded, context) {
+		t.Fatal("decoded proof must be valid")
+	}
+	if _, err := PoKEFromBytes(proof.Bytes()[1:]); err != ErrInvalidProof {
+		t.Fatal("short input must be rejected")
+	}
+	in := proof.Bytes()
+	for i := bls.G1CompressedSize; i < PoKESize; i++ {
+		in[i] = 0xff
+	}
+	if _, err := PoKEFromBytes(in); err != ErrInvalidProof {
+		t.Fatal("non canonical response must be rejected")
+	}
+}
@@ -16,7 +16,8 @@ type Engine struct {
 	fp12 *fp12
 	fp2  *fp2
 	pairingEngineTemp
-	pairs []pair
+	pairs  []pair
+	tracer *Tracer
 }
 
 // NewEngine creates new pairing engine insteace.
@@ -196,87 +197,24 @@ func (e *Engine) millerLoop(f *fe12) {
 	fp12Conjugate(f, f)
 }
 
+// xBits and xDropBits are positions of set bits of |x| = 15132376222941642752
+// and of |x| / 2.
+var (
+	xBits     = []int{16, 48, 57, 60, 62, 63}
+	xDropBits = []int{15, 47, 56, 59, 61, 62}
+)
+
 // exp raises element by x = -15132376222941642752
 func (e *Engine) exp(c, a *fe12) {
-	c.set(a)
-	e.fp12.cyclotomicSquare(c) // (a ^ 2)
-
-	// (a ^ (2 + 1)) ^ (2 ^ 2) = a ^ 12
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (12 + 1)) ^ (2 ^ 3) = a ^ 104
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (104 + 1)) ^ (2 ^ 9) = a ^ 53760
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	// (a ^ (53760 + 1)) ^ (2 ^ 32) = a ^ 230901736800256
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 32; i++ {
-		e.fp12.cyclotomicSquare(c)
-	}
-
-	// (a ^ (230901736800256 + 1)) ^ (2 ^ 16) = a ^ 15132376222941642752
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 16; i++ {
-		e.fp12.cyclotomicSquare(c)
-	}
-	// invert chain result since x is negative
+	e.fp12.cyclotomicExpBits(c, a, xBits)
+	// invert result since x is negative
 	fp12Conjugate(c, c)
 }
 
 // expDrop raises element by x = -15132376222941642752 / 2
 func (e *Engine) expDrop(c, a *fe12) {
-	c.set(a)
-	e.fp12.cyclotomicSquare(c) // (a ^ 2)
-
-	// (a ^ (2 + 1)) ^ (2 ^ 2) = a ^ 12
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (12 + 1)) ^ (2 ^ 3) = a ^ 104
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-
-	// (a ^ (104 + 1)) ^ (2 ^ 9) = a ^ 53760
-	e.fp12.mulAssign(c, a)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	e.fp12.cyclotomicSquare(c)
-	// (a ^ (53760 + 1)) ^ (2 ^ 32) = a ^ 230901736800256
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 32; i++ {
-		e.fp12.cyclotomicSquare(c)
-	}
-
-	// (a ^ (230901736800256 + 1)) ^ (2 ^ 16) = a ^ 15132376222941642752
-	e.fp12.mulAssign(c, a)
-	for i := 0; i < 15; i++ {
-		e.fp12.cyclotomicSquare(c)
-	}
-	// invert chain result since x is negative
+	e.fp12.cyclotomicExpBits(c, a, xDropBits)
+	// invert result since x is negative
 	fp12Conjugate(c, c)
 }
 
@@ -328,11 +266,38 @@ func (e *Engine) calculate() *fe12 {
 
 // Check computes pairing and checks if result is equal to one
 func (e *Engine) Check() bool {
+	if e.tracer != nil {
+		in := e.tracePairs()
+		r := e.calculate()
+		e.tracer.record("pairing.check", NewGT().ToBytes(r), in...)
+		return r.isOne()
+	}
 	return e.calculate().isOne()
 }
 
+// PairingsEqual returns true if e(p1, q1) = e(p2, q2). It checks
+// e(p1, q1) * e(-p2, q2) = 1, so both pairings share a Miller loop and a
+// single final exponentiation. Given points are not modified and pairs added
+// to the engine earlier are kept.
+func (e *Engine) PairingsEqual(p1 *PointG1, q1 *PointG2, p2 *PointG1, q2 *PointG2) bool {
+	pairs := e.pairs
+	e.pairs = nil
+	e.AddPair(e.G1.New().Set(p1), e.G2.New().Set(q1))
+	e.AddPairInv(p2, e.G2.New().Set(q2))
+	ok := e.Check()
+	e.pairs = pairs
+	return ok
+}
+
 // Result computes pairing and returns target group element as result.
 func (e *Engine) Result() *E {
+	if e.tracer != nil {
+		in := e.tracePairs()
+		r := e.calculate()
+		e.tracer.record("pairing", NewGT().ToBytes(r), in...)
+		e.Reset()
+		return r
+	}
 	r := e.calculate()
 	e.Reset()
 	return r
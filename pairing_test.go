@@ -1,6 +1,7 @@
 package bls12381
 
 import (
+	"crypto/rand"
 	"math/big"
 	"testing"
 )
@@ -217,6 +218,69 @@ func TestPairingEmpty(t *testing.T) {
 	}
 }
 
+func TestPairingsEqual(t *testing.T) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	a, _ := new(Fr).Rand(rand.Reader)
+	p, q := g1.randCorrect(), g2.randCorrect()
+	// jacobian inputs must not be normalized
+	ap := g1.Double(g1.New(), g1.MulScalar(g1.New(), p, a))
+	aq := g2.Double(g2.New(), g2.MulScalar(g2.New(), q, a))
+	apCopy, aqCopy := g1.New().Set(ap), g2.New().Set(aq)
+	// pending pairs must be kept
+	bls.AddPair(g1.One(), g2.One())
+	if !bls.PairingsEqual(ap, q, p, aq) {
+		t.Fatal("e(2aP, Q) = e(P, 2aQ)")
+	}
+	if bls.PairingsEqual(ap, q, p, q) {
+		t.Fatal("e(2aP, Q) != e(P, Q)")
+	}
+	if ap[2] != apCopy[2] || aq[2] != aqCopy[2] {
+		t.Fatal("points must not be modified")
+	}
+	if bls.Check() {
+		t.Fatal("pending pairs must be kept")
+	}
+	bls.Reset()
+	if !bls.PairingsEqual(g1.Zero(), q, p, g2.Zero()) {
+		t.Fatal("e(0, Q) = e(P, 0)")
+	}
+	if bls.PairingsEqual(g1.Zero(), q, p, q) {
+		t.Fatal("e(0, Q) != e(P, Q)")
+	}
+}
+
+func TestGTDivision(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	p, q := g1.randCorrect(), g2.randCorrect()
+	e1 := bls.AddPair(g1.New().Set(p), q).Result()
+	e2 := bls.AddPair(g1.Double(g1.New(), p), q).Result()
+	// e(2P, Q) / e(P, Q) = e(P, Q)
+	c := gt.New()
+	gt.Div(c, e2, e1)
+	if !c.Equal(e1) {
+		t.Fatal("bad division")
+	}
+	// e(P - 2P, Q) = e(P, Q) / e(2P, Q)
+	d := bls.AddPair(g1.Sub(g1.New(), p, g1.Double(g1.New(), p)), q).Result()
+	gt.Div(c, e1, e2)
+	if !c.Equal(d) {
+		t.Fatal("division must match pairing of difference")
+	}
+	inv := gt.New()
+	gt.Inverse(inv, e2)
+	gt.Mul(inv, e1, inv)
+	if !inv.Equal(c) {
+		t.Fatal("division must match multiplication by inverse")
+	}
+	// in place
+	gt.Div(e1, e1, e1)
+	if !e1.IsOne() {
+		t.Fatal("a / a must be one")
+	}
+}
+
 func BenchmarkPairing(t *testing.B) {
 	bls := NewEngine()
 	g1, g2, gt := bls.G1, bls.G2, bls.GT()
@@ -244,9 +308,13 @@ func BenchmarkFinalExp(t *testing.B) {
 	bls := NewEngine()
 	g1, g2, gt := bls.G1, bls.G2, bls.GT()
 	bls.AddPair(g1.One(), g2.One())
-	f := gt.New().one()
+	// final exponentiation of one skips decompression, so that input is
+	// taken from the miller loop
+	f, c := gt.New().one(), gt.New()
+	bls.millerLoop(f)
 	t.ResetTimer()
 	for i := 0; i < t.N; i++ {
-		bls.finalExp(f)
+		c.set(f)
+		bls.finalExp(c)
 	}
 }
@@ -0,0 +1,144 @@
+// Package pairingtest checks pairing equations in tests of protocols built on
+// this library, such as signature, commitment or proof verification
+// equations, and explains failures.
+//
+// An equation is a product of terms e(P, Q)^c expected to equal one. Check
+// verifies any number of equations at once with a single multi pairing over
+// a random linear combination of them. If the combination does not hold,
+// every equation is evaluated on its own and failing ones are reported by
+// name together with hints about their terms: a term equal to one, usually
+// a point left at infinity, and a single term whose removal or negated
+// exponent would balance the equation, which are common mistakes in a new
+// verification equation.
+package pairingtest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Term is e(P, Q)^Exp. Nil exponent is one. Name is used in failure reports.
+type Term struct {
+	Name string
+	P    *bls.PointG1
+	Q    *bls.PointG2
+	Exp  *bls.Fr
+}
+
+// Equation is a named product of terms expected to equal one.
+type Equation struct {
+	Name  string
+	Terms []Term
+}
+
+// Failure reports an equation that does not hold.
+type Failure struct {
+	Equation string
+	Hints    []string
+}
+
+// Error is returned by Check if some of the equations do not hold.
+type Error struct {
+	Failures []Failure
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	for i, f := range e.Failures {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "pairing equation %q does not hold", f.Equation)
+		for _, h := range f.Hints {
+			sb.WriteString("\n\t")
+			sb.WriteString(h)
+		}
+	}
+	return sb.String()
+}
+
+// Check returns nil if all equations hold, and *Error describing failing
+// equations otherwise.
+func Check(eqs ...*Equation) error {
+	engine := bls.NewEngine()
+	g := engine.G1
+	for _, eq := range eqs {
+		r, err := bls.NewFr().Rand(rand.Reader)
+		if err != nil {
+			return err
+		}
+		for _, t := range eq.Terms {
+			c := new(bls.Fr).Set(r)
+			if t.Exp != nil {
+				c.Mul(c, t.Exp)
+			}
+			engine.AddPair(g.MulScalar(g.New(), t.P, c), t.Q)
+		}
+	}
+	if engine.Check() {
+		return nil
+	}
+	failed := &Error{}
+	for _, eq := range eqs {
+		if f := diagnose(eq); f != nil {
+			failed.Failures = append(failed.Failures, *f)
+		}
+	}
+	return failed
+}
+
+// Assert fails the test with a report of failing equations unless all
+// equations hold.
+func Assert(t testing.TB, eqs ...*Equation) {
+	t.Helper()
+	if err := Check(eqs...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// diagnose evaluates terms of the equation separately and returns nil if the
+// equation holds.
+func diagnose(eq *Equation) *Failure {
+	engine, gt := bls.NewEngine(), bls.NewGT()
+	g := engine.G1
+	values := make([]*bls.E, len(eq.Terms))
+	product := gt.New()
+	for i, t := range eq.Terms {
+		p := g.New().Set(t.P)
+		if t.Exp != nil {
+			g.MulScalar(p, p, t.Exp)
+		}
+		values[i] = engine.AddPair(p, t.Q).Result()
+		gt.Mul(product, product, values[i])
+	}
+	if product.IsOne() {
+		return nil
+	}
+	f := &Failure{Equation: eq.Name}
+	inv, rest := gt.New(), gt.New()
+	for i, t := range eq.Terms {
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		if values[i].IsOne() {
+			f.Hints = append(f.Hints, fmt.Sprintf("term %s is one, a point or the exponent may be zero", name))
+			continue
+		}
+		gt.Inverse(inv, values[i])
+		gt.Mul(rest, product, inv)
+		if rest.IsOne() {
+			f.Hints = append(f.Hints, fmt.Sprintf("equation holds without term %s", name))
+			continue
+		}
+		gt.Mul(rest, rest, inv)
+		if rest.IsOne() {
+			f.Hints = append(f.Hints, fmt.Sprintf("equation holds with exponent of term %s negated", name))
+		}
+	}
+	return f
+}
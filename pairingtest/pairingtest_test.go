@@ -0,0 +1,106 @@
+package pairingtest
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// signatureEquation returns e(-G1, sig) * e(pk, H(m)) for pk = sk * G1 and
+// sig = sk' * H(m).
+func signatureEquation(t *testing.T, forge bool) *Equation {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	sk, err := bls.NewFr().Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := g2.HashToCurve([]byte("message"), []byte("pairingtest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := g1.MulScalar(g1.New(), g1.One(), sk)
+	if forge {
+		sk, _ = bls.NewFr().Rand(rand.Reader)
+	}
+	sig := g2.MulScalar(g2.New(), h, sk)
+	negG1 := g1.Neg(g1.New(), g1.One())
+	return &Equation{Name: "signature", Terms: []Term{
+		{Name: "generator", P: negG1, Q: sig},
+		{Name: "key", P: pk, Q: h},
+	}}
+}
+
+// scalarEquation returns e(G1, G2)^(a*b) * e(a * G1, b * G2)^-1.
+func scalarEquation(t *testing.T) (*Equation, *bls.Fr) {
+	g1, g2 := bls.NewG1(), bls.NewG2()
+	a, _ := bls.NewFr().Rand(rand.Reader)
+	b, _ := bls.NewFr().Rand(rand.Reader)
+	ab, minusOne := bls.NewFr(), bls.NewFr()
+	ab.Mul(a, b)
+	minusOne.Sub(minusOne, bls.NewFr().One())
+	return &Equation{Name: "product", Terms: []Term{
+		{Name: "ab", P: g1.One(), Q: g2.One(), Exp: ab},
+		{Name: "a,b", P: g1.MulScalar(g1.New(), g1.One(), a), Q: g2.MulScalar(g2.New(), g2.One(), b), Exp: minusOne},
+	}}, ab
+}
+
+func TestCheck(t *testing.T) {
+	product, _ := scalarEquation(t)
+	Assert(t, signatureEquation(t, false), product)
+	if err := Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckFailure(t *testing.T) {
+	product, _ := scalarEquation(t)
+	err := Check(signatureEquation(t, false), signatureEquation(t, true), product)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if len(e.Failures) != 1 || e.Failures[0].Equation != "signature" {
+		t.Fatalf("bad failures %v", e.Failures)
+	}
+	if !strings.Contains(e.Error(), `pairing equation "signature" does not hold`) {
+		t.Fatalf("bad error %q", e.Error())
+	}
+}
+
+func TestCheckHints(t *testing.T) {
+	g1 := bls.NewG1()
+	// extra term
+	eq := signatureEquation(t, false)
+	eq.Terms = append(eq.Terms, Term{Name: "extra", P: g1.One(), Q: bls.NewG2().One()})
+	assertHints(t, eq, "equation holds without term extra")
+	// term with wrong sign
+	eq, _ = scalarEquation(t)
+	eq.Terms[1].Exp = nil
+	assertHints(t, eq, "equation holds with exponent of term a,b negated")
+	// zero exponent
+	eq, _ = scalarEquation(t)
+	eq.Terms[0].Exp = bls.NewFr()
+	assertHints(t, eq, "term ab is one, a point or the exponent may be zero")
+	// point at infinity, unnamed term
+	eq = signatureEquation(t, false)
+	eq.Terms[1].Name = ""
+	eq.Terms[1].P = g1.Zero()
+	assertHints(t, eq, "term #1 is one, a point or the exponent may be zero")
+}
+
+func assertHints(t *testing.T, eq *Equation, hint string) {
+	t.Helper()
+	err := Check(eq)
+	e, ok := err.(*Error)
+	if !ok || len(e.Failures) != 1 {
+		t.Fatalf("expected a failure, got %v", err)
+	}
+	for _, h := range e.Failures[0].Hints {
+		if h == hint {
+			return
+		}
+	}
+	t.Fatalf("hint %q not found in %q", hint, e.Failures[0].Hints)
+}
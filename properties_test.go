@@ -0,0 +1,205 @@
+package bls12381
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// quickScalar, quickG1 and quickG2 implement quick.Generator so that
+// property tests receive uniformly random scalars and subgroup points.
+
+type quickScalar struct{ *Fr }
+
+func (quickScalar) Generate(r *rand.Rand, _ int) reflect.Value {
+	s, err := new(Fr).Rand(r)
+	if err != nil {
+		panic(err)
+	}
+	return reflect.ValueOf(quickScalar{s})
+}
+
+type quickG1 struct{ *PointG1 }
+
+func (quickG1) Generate(r *rand.Rand, _ int) reflect.Value {
+	s, _ := new(Fr).Rand(r)
+	g := NewG1()
+	return reflect.ValueOf(quickG1{g.MulScalar(g.New(), g.One(), s)})
+}
+
+type quickG2 struct{ *PointG2 }
+
+func (quickG2) Generate(r *rand.Rand, _ int) reflect.Value {
+	s, _ := new(Fr).Rand(r)
+	g := NewG2()
+	return reflect.ValueOf(quickG2{g.MulScalar(g.New(), g.One(), s)})
+}
+
+func quickConfig(n int) *quick.Config {
+	return &quick.Config{MaxCount: n}
+}
+
+func TestG1GroupLaws(t *testing.T) {
+	g := NewG1()
+	associativity := func(a, b, c quickG1) bool {
+		l, r := g.New(), g.New()
+		g.Add(l, a.PointG1, b.PointG1)
+		g.Add(l, l, c.PointG1)
+		g.Add(r, b.PointG1, c.PointG1)
+		g.Add(r, a.PointG1, r)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(associativity, quickConfig(100*fuz)); err != nil {
+		t.Fatal("(a + b) + c == a + (b + c)", err)
+	}
+	commutativity := func(a, b quickG1) bool {
+		l, r := g.New(), g.New()
+		g.Add(l, a.PointG1, b.PointG1)
+		g.Add(r, b.PointG1, a.PointG1)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(commutativity, quickConfig(100*fuz)); err != nil {
+		t.Fatal("a + b == b + a", err)
+	}
+	inverse := func(a quickG1) bool {
+		r := g.New()
+		g.Neg(r, a.PointG1)
+		g.Add(r, r, a.PointG1)
+		return g.IsZero(r)
+	}
+	if err := quick.Check(inverse, quickConfig(100*fuz)); err != nil {
+		t.Fatal("a + (-a) == 0", err)
+	}
+	distributivity := func(a, b quickG1, s quickScalar) bool {
+		l, r, t := g.New(), g.New(), g.New()
+		g.Add(l, a.PointG1, b.PointG1)
+		g.MulScalar(l, l, s.Fr)
+		g.MulScalar(r, a.PointG1, s.Fr)
+		g.MulScalar(t, b.PointG1, s.Fr)
+		g.Add(r, r, t)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(distributivity, quickConfig(10*fuz)); err != nil {
+		t.Fatal("s(a + b) == sa + sb", err)
+	}
+	scalarAddition := func(a quickG1, s0, s1 quickScalar) bool {
+		l, r, t := g.New(), g.New(), g.New()
+		s := new(Fr)
+		s.Add(s0.Fr, s1.Fr)
+		g.MulScalar(l, a.PointG1, s)
+		g.MulScalar(r, a.PointG1, s0.Fr)
+		g.MulScalar(t, a.PointG1, s1.Fr)
+		g.Add(r, r, t)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(scalarAddition, quickConfig(10*fuz)); err != nil {
+		t.Fatal("(s0 + s1)a == s0a + s1a", err)
+	}
+	scalarMultiplication := func(a quickG1, s0, s1 quickScalar) bool {
+		l, r := g.New(), g.New()
+		s := new(Fr)
+		s.Mul(s0.Fr, s1.Fr)
+		g.MulScalar(l, a.PointG1, s)
+		g.MulScalar(r, a.PointG1, s0.Fr)
+		g.MulScalar(r, r, s1.Fr)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(scalarMultiplication, quickConfig(10*fuz)); err != nil {
+		t.Fatal("(s0 * s1)a == s1(s0a)", err)
+	}
+}
+
+func TestG2GroupLaws(t *testing.T) {
+	g := NewG2()
+	associativity := func(a, b, c quickG2) bool {
+		l, r := g.New(), g.New()
+		g.Add(l, a.PointG2, b.PointG2)
+		g.Add(l, l, c.PointG2)
+		g.Add(r, b.PointG2, c.PointG2)
+		g.Add(r, a.PointG2, r)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(associativity, quickConfig(100*fuz)); err != nil {
+		t.Fatal("(a + b) + c == a + (b + c)", err)
+	}
+	commutativity := func(a, b quickG2) bool {
+		l, r := g.New(), g.New()
+		g.Add(l, a.PointG2, b.PointG2)
+		g.Add(r, b.PointG2, a.PointG2)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(commutativity, quickConfig(100*fuz)); err != nil {
+		t.Fatal("a + b == b + a", err)
+	}
+	inverse := func(a quickG2) bool {
+		r := g.New()
+		g.Neg(r, a.PointG2)
+		g.Add(r, r, a.PointG2)
+		return g.IsZero(r)
+	}
+	if err := quick.Check(inverse, quickConfig(100*fuz)); err != nil {
+		t.Fatal("a + (-a) == 0", err)
+	}
+	distributivity := func(a, b quickG2, s quickScalar) bool {
+		l, r, t := g.New(), g.New(), g.New()
+		g.Add(l, a.PointG2, b.PointG2)
+		g.MulScalar(l, l, s.Fr)
+		g.MulScalar(r, a.PointG2, s.Fr)
+		g.MulScalar(t, b.PointG2, s.Fr)
+		g.Add(r, r, t)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(distributivity, quickConfig(10*fuz)); err != nil {
+		t.Fatal("s(a + b) == sa + sb", err)
+	}
+	scalarAddition := func(a quickG2, s0, s1 quickScalar) bool {
+		l, r, t := g.New(), g.New(), g.New()
+		s := new(Fr)
+		s.Add(s0.Fr, s1.Fr)
+		g.MulScalar(l, a.PointG2, s)
+		g.MulScalar(r, a.PointG2, s0.Fr)
+		g.MulScalar(t, a.PointG2, s1.Fr)
+		g.Add(r, r, t)
+		return g.Equal(l, r)
+	}
+	if err := quick.Check(scalarAddition, quickConfig(10*fuz)); err != nil {
+		t.Fatal("(s0 + s1)a == s0a + s1a", err)
+	}
+}
+
+func TestPairingLaws(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	bilinearity := func(p quickG1, q quickG2, a, b quickScalar) bool {
+		e := NewEngine()
+		gt := e.GT()
+		ap := g1.MulScalar(g1.New(), p.PointG1, a.Fr)
+		bq := g2.MulScalar(g2.New(), q.PointG2, b.Fr)
+		l := e.AddPair(ap, bq).Result()
+		ab := new(Fr)
+		ab.Mul(a.Fr, b.Fr)
+		r := gt.New()
+		gt.Exp(r, e.Reset().AddPair(p.PointG1, q.PointG2).Result(), ab.ToBig())
+		return l.Equal(r)
+	}
+	if err := quick.Check(bilinearity, quickConfig(fuz)); err != nil {
+		t.Fatal("e(aP, bQ) == e(P, Q)^ab", err)
+	}
+	linearity := func(p0, p1 quickG1, q quickG2) bool {
+		e := NewEngine()
+		gt := e.GT()
+		l := e.AddPair(g1.Add(g1.New(), p0.PointG1, p1.PointG1), q.PointG2).Result()
+		r := gt.New()
+		gt.Mul(r, e.Reset().AddPair(p0.PointG1, q.PointG2).Result(), e.Reset().AddPair(p1.PointG1, q.PointG2).Result())
+		return l.Equal(r)
+	}
+	if err := quick.Check(linearity, quickConfig(fuz)); err != nil {
+		t.Fatal("e(P0 + P1, Q) == e(P0, Q)e(P1, Q)", err)
+	}
+	inverse := func(p quickG1, q quickG2) bool {
+		return NewEngine().AddPair(p.PointG1, q.PointG2).AddPairInv(p.PointG1, q.PointG2).Check()
+	}
+	if err := quick.Check(inverse, quickConfig(fuz)); err != nil {
+		t.Fatal("e(P, Q)e(-P, Q) == 1", err)
+	}
+}
@@ -0,0 +1,136 @@
+package bls12381
+
+import "encoding/binary"
+
+// Raw encodings copy internal representation of points, that is Jacobian
+// coordinates in Montgomery form as 64 bit little endian limbs. Encoding and
+// decoding need neither an inversion to move to affine form nor a square root
+// or a subgroup check, which makes them suitable for caches shared between
+// trusted processes. Decoders do not validate their input apart from its
+// length, so raw bytes must never be accepted from untrusted sources. Raw
+// encodings are not part of the wire format covered by EncodingVersion and
+// may change along with internal representation.
+
+// Raw encoded sizes in bytes.
+const (
+	G1RawSize = 3 * fpByteSize
+	G2RawSize = 6 * fpByteSize
+)
+
+func putRawFp(out []byte, e *Fp) {
+	for i := range e {
+		binary.LittleEndian.PutUint64(out[i*8:], e[i])
+	}
+}
+
+func getRawFp(e *Fp, in []byte) {
+	for i := range e {
+		e[i] = binary.LittleEndian.Uint64(in[i*8:])
+	}
+}
+
+// ToRawBytes returns raw encoding of the point.
+func (g *G1) ToRawBytes(p *PointG1) []byte {
+	out := make([]byte, G1RawSize)
+	for i := range p {
+		putRawFp(out[i*fpByteSize:], &p[i])
+	}
+	return out
+}
+
+// FromRawBytesInto decodes raw encoding of a point into the point at first
+// argument. Input is not validated.
+func (g *G1) FromRawBytesInto(p *PointG1, in []byte) error {
+	if len(in) != G1RawSize {
+		return ErrInvalidLength
+	}
+	for i := range p {
+		getRawFp(&p[i], in[i*fpByteSize:])
+	}
+	return nil
+}
+
+// ToRawBytes returns raw encoding of the point.
+func (g *G2) ToRawBytes(p *PointG2) []byte {
+	out := make([]byte, G2RawSize)
+	for i := range p {
+		putRawFp(out[2*i*fpByteSize:], &p[i][0])
+		putRawFp(out[(2*i+1)*fpByteSize:], &p[i][1])
+	}
+	return out
+}
+
+// FromRawBytesInto decodes raw encoding of a point into the point at first
+// argument. Input is not validated.
+func (g *G2) FromRawBytesInto(p *PointG2, in []byte) error {
+	if len(in) != G2RawSize {
+		return ErrInvalidLength
+	}
+	for i := range p {
+		getRawFp(&p[i][0], in[2*i*fpByteSize:])
+		getRawFp(&p[i][1], in[(2*i+1)*fpByteSize:])
+	}
+	return nil
+}
+
+// RawLimbs returns internal Montgomery form of the element, little endian
+// limbs of e * 2^384 mod p, for snapshots and for exchanging elements with
+// code using the same representation without conversion.
+func (e *Fp) RawLimbs() [fpNumberOfLimbs]uint64 {
+	return *e
+}
+
+// SetRawLimbs sets the element to limbs in Montgomery form as returned by
+// RawLimbs. Unlike raw byte decoders it returns ErrNonCanonical if limbs are
+// not less than the modulus, since arithmetic relies on reduced inputs.
+func (e *Fp) SetRawLimbs(limbs [fpNumberOfLimbs]uint64) (*Fp, error) {
+	t := Fp(limbs)
+	if !t.isValid() {
+		return nil, ErrNonCanonical
+	}
+	*e = t
+	return e, nil
+}
+
+// RawLimbs returns Jacobian coordinates of the point in Montgomery form, same
+// as ToRawBytes without the byte encoding.
+func (p *PointG1) RawLimbs() [3][fpNumberOfLimbs]uint64 {
+	return [3][fpNumberOfLimbs]uint64{p[0], p[1], p[2]}
+}
+
+// SetRawLimbs sets the point to coordinates as returned by RawLimbs. Each
+// coordinate is checked as in Fp.SetRawLimbs, and the point is left unchanged
+// on error. It is not checked to be on the curve or in the subgroup.
+func (p *PointG1) SetRawLimbs(limbs [3][fpNumberOfLimbs]uint64) (*PointG1, error) {
+	var t PointG1
+	for i := range limbs {
+		if _, err := t[i].SetRawLimbs(limbs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return p.Set(&t), nil
+}
+
+// RawLimbs returns Jacobian coordinates of the point in Montgomery form as
+// c0, c1 pairs, same as ToRawBytes without the byte encoding.
+func (p *PointG2) RawLimbs() [3][2][fpNumberOfLimbs]uint64 {
+	var out [3][2][fpNumberOfLimbs]uint64
+	for i := range p {
+		out[i] = [2][fpNumberOfLimbs]uint64{p[i][0], p[i][1]}
+	}
+	return out
+}
+
+// SetRawLimbs sets the point to coordinates as returned by RawLimbs, see
+// PointG1.SetRawLimbs.
+func (p *PointG2) SetRawLimbs(limbs [3][2][fpNumberOfLimbs]uint64) (*PointG2, error) {
+	var t PointG2
+	for i := range limbs {
+		for j := range limbs[i] {
+			if _, err := t[i][j].SetRawLimbs(limbs[i][j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.Set(&t), nil
+}
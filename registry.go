@@ -0,0 +1,187 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// Encoding serializes points and scalars in a named format. Decoders must
+// return only valid elements, that is points on the curve and in the correct
+// subgroup and scalars less than the group order.
+type Encoding interface {
+	// Name is the key of the encoding in registry.
+	Name() string
+	EncodeG1(p *PointG1) ([]byte, error)
+	DecodeG1(in []byte) (*PointG1, error)
+	EncodeG2(p *PointG2) ([]byte, error)
+	DecodeG2(in []byte) (*PointG2, error)
+	EncodeFr(s *Fr) ([]byte, error)
+	DecodeFr(in []byte) (*Fr, error)
+}
+
+// Names of built in encodings.
+const (
+	EncodingZcash = "zcash"
+	EncodingMCL   = "mcl"
+)
+
+var (
+	ErrDuplicateEncoding = errors.New("encoding is already registered")
+	ErrUnknownEncoding   = errors.New("unknown encoding")
+)
+
+var encodings = struct {
+	sync.RWMutex
+	m map[string]Encoding
+}{m: map[string]Encoding{
+	EncodingZcash: &formatEncoding{EncodingZcash, FormatZcash},
+	EncodingMCL:   &formatEncoding{EncodingMCL, FormatMCL},
+}}
+
+// RegisterEncoding adds the encoding to registry. Names are unique and built in
+// encodings can not be replaced.
+func RegisterEncoding(e Encoding) error {
+	encodings.Lock()
+	defer encodings.Unlock()
+	if _, ok := encodings.m[e.Name()]; ok {
+		return ErrDuplicateEncoding
+	}
+	encodings.m[e.Name()] = e
+	return nil
+}
+
+// LookupEncoding returns the encoding registered with given name.
+func LookupEncoding(name string) (Encoding, error) {
+	encodings.RLock()
+	defer encodings.RUnlock()
+	e, ok := encodings.m[name]
+	if !ok {
+		return nil, ErrUnknownEncoding
+	}
+	return e, nil
+}
+
+// Encodings returns names of registered encodings in sorted order.
+func Encodings() []string {
+	encodings.RLock()
+	defer encodings.RUnlock()
+	names := make([]string, 0, len(encodings.m))
+	for name := range encodings.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WrapEncoding returns an encoding that transforms output of the base encoding
+// with wrap and recovers it with unwrap before decoding, such as a bech32 or
+// base58 layer over compressed points. Decoded elements are validated by the
+// base encoding.
+func WrapEncoding(name string, base Encoding, wrap, unwrap func([]byte) ([]byte, error)) Encoding {
+	return &wrappedEncoding{name, base, wrap, unwrap}
+}
+
+// formatEncoding is compressed point encoding of a PointFormat. Scalars are 32
+// bytes in the byte order of the format.
+type formatEncoding struct {
+	name   string
+	format PointFormat
+}
+
+func (e *formatEncoding) Name() string {
+	return e.name
+}
+
+func (e *formatEncoding) EncodeG1(p *PointG1) ([]byte, error) {
+	return NewG1().ToCompressedFormat(new(PointG1).Set(p), e.format)
+}
+
+func (e *formatEncoding) DecodeG1(in []byte) (*PointG1, error) {
+	return NewG1().FromCompressedFormat(in, e.format)
+}
+
+func (e *formatEncoding) EncodeG2(p *PointG2) ([]byte, error) {
+	return NewG2().ToCompressedFormat(new(PointG2).Set(p), e.format)
+}
+
+func (e *formatEncoding) DecodeG2(in []byte) (*PointG2, error) {
+	return NewG2().FromCompressedFormat(in, e.format)
+}
+
+func (e *formatEncoding) EncodeFr(s *Fr) ([]byte, error) {
+	out := s.ToBytes()
+	if e.format == FormatMCL {
+		reverseBytes(out)
+	}
+	return out, nil
+}
+
+func (e *formatEncoding) DecodeFr(in []byte) (*Fr, error) {
+	if len(in) != FrSize {
+		return nil, ErrInvalidLength
+	}
+	b := make([]byte, FrSize)
+	copy(b, in)
+	if e.format == FormatMCL {
+		reverseBytes(b)
+	}
+	if new(big.Int).SetBytes(b).Cmp(qBig) >= 0 {
+		return nil, ErrNonCanonical
+	}
+	return NewFr().FromBytes(b), nil
+}
+
+type wrappedEncoding struct {
+	name         string
+	base         Encoding
+	wrap, unwrap func([]byte) ([]byte, error)
+}
+
+func (e *wrappedEncoding) Name() string {
+	return e.name
+}
+
+func (e *wrappedEncoding) encode(in []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(in)
+}
+
+func (e *wrappedEncoding) EncodeG1(p *PointG1) ([]byte, error) {
+	return e.encode(e.base.EncodeG1(p))
+}
+
+func (e *wrappedEncoding) DecodeG1(in []byte) (*PointG1, error) {
+	b, err := e.unwrap(in)
+	if err != nil {
+		return nil, err
+	}
+	return e.base.DecodeG1(b)
+}
+
+func (e *wrappedEncoding) EncodeG2(p *PointG2) ([]byte, error) {
+	return e.encode(e.base.EncodeG2(p))
+}
+
+func (e *wrappedEncoding) DecodeG2(in []byte) (*PointG2, error) {
+	b, err := e.unwrap(in)
+	if err != nil {
+		return nil, err
+	}
+	return e.base.DecodeG2(b)
+}
+
+func (e *wrappedEncoding) EncodeFr(s *Fr) ([]byte, error) {
+	return e.encode(e.base.EncodeFr(s))
+}
+
+func (e *wrappedEncoding) DecodeFr(in []byte) (*Fr, error) {
+	b, err := e.unwrap(in)
+	if err != nil {
+		return nil, err
+	}
+	return e.base.DecodeFr(b)
+}
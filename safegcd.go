@@ -0,0 +1,337 @@
+package bls12381
+
+import "math/bits"
+
+// Constant time modular inversion with Bernstein-Yang safegcd.
+//
+// Fast constant-time gcd computation and modular inversion, Bernstein and Yang
+// https://eprint.iacr.org/2019/266.pdf
+//
+// The implementation follows the signed 62 bit limb representation of
+// libsecp256k1 modinv64. Integers are little endian limbs where all limbs
+// but the last are in [0, 2^62) and the last one carries the sign. Divsteps
+// are computed in batches of 62 on the low bits of f and g, and the resulting
+// transition matrix is then applied to full length f, g, d and e. Iteration
+// count is fixed by the bound of Theorem 11.2 so that the running time does
+// not depend on the input.
+
+const (
+	safegcdLimbs = 7
+	safegcdMask  = (1 << 62) - 1
+)
+
+type signed62 [safegcdLimbs]int64
+
+// safegcdModulus holds a modulus with precomputed values for inversion.
+type safegcdModulus struct {
+	// n is number of signed 62 bit limbs in use.
+	n       int
+	modulus signed62
+	// inv62 is modulus^-1 mod 2^62.
+	inv62 uint64
+	// batches is number of 62 divstep batches to run.
+	batches int
+}
+
+// newSafegcdModulus precomputes inversion constants of an odd modulus given
+// in 64 bit little endian limbs.
+func newSafegcdModulus(m []uint64, bitLen int) *safegcdModulus {
+	n := (bitLen + 1 + 61) / 62
+	sm := &safegcdModulus{n: n, modulus: toSigned62(m, n)}
+	// Newton iteration for the inverse of m0 modulo 2^64.
+	m0 := m[0]
+	inv := m0
+	for i := 0; i < 5; i++ {
+		inv *= 2 - m0*inv
+	}
+	sm.inv62 = inv & safegcdMask
+	// Theorem 11.2, d >= 46
+	divsteps := (49*bitLen + 57) / 17
+	sm.batches = (divsteps + 61) / 62
+	return sm
+}
+
+func toSigned62(a []uint64, n int) signed62 {
+	var r signed62
+	for i := 0; i < n; i++ {
+		bit := 62 * i
+		w, s := bit/64, uint(bit%64)
+		var v uint64
+		if w < len(a) {
+			v = a[w] >> s
+			if s > 2 && w+1 < len(a) {
+				v |= a[w+1] << (64 - s)
+			}
+		}
+		r[i] = int64(v & safegcdMask)
+	}
+	return r
+}
+
+// fromSigned62 converts a normalized value in [0, modulus) into 64 bit limbs.
+func fromSigned62(out []uint64, a *signed62, n int) {
+	for i := range out {
+		out[i] = 0
+	}
+	for i := 0; i < n; i++ {
+		bit := 62 * i
+		w, s := bit/64, uint(bit%64)
+		v := uint64(a[i])
+		if w < len(out) {
+			out[w] |= v << s
+		}
+		if s > 2 && w+1 < len(out) {
+			out[w+1] |= v >> (64 - s)
+		}
+	}
+}
+
+// int128 is a two's complement signed 128 bit accumulator.
+type int128 struct {
+	hi, lo uint64
+}
+
+// mulAdd adds signed product a * b to the accumulator.
+func (c *int128) mulAdd(a, b int64) {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	hi -= uint64(a>>63)&uint64(b) + uint64(b>>63)&uint64(a)
+	var carry uint64
+	c.lo, carry = bits.Add64(c.lo, lo, 0)
+	c.hi += hi + carry
+}
+
+// shift62 arithmetically shifts the accumulator right by 62 bits.
+func (c *int128) shift62() {
+	c.lo = c.lo>>62 | c.hi<<2
+	c.hi = uint64(int64(c.hi) >> 62)
+}
+
+// transition is a 2x2 matrix of divsteps scaled by 2^62.
+type transition struct {
+	u, v, q, r int64
+}
+
+// divsteps62 runs 62 divsteps on low bits of f and g in constant time and
+// returns the new delta. f must be odd.
+func divsteps62(delta int64, f0, g0 uint64, t *transition) int64 {
+	u, v, q, r := uint64(1), uint64(0), uint64(0), uint64(1)
+	f, g := f0, g0
+	for i := 0; i < 62; i++ {
+		// swap if delta > 0 and g is odd
+		mask1 := uint64((-delta) >> 63)
+		mask2 := -(g & 1)
+		// conditionally negated f, u and v
+		x := (f ^ mask1) - mask1
+		y := (u ^ mask1) - mask1
+		z := (v ^ mask1) - mask1
+		// g, q, r += f, u, v if g is odd, subtracted if swapping
+		g += x & mask2
+		q += y & mask2
+		r += z & mask2
+		mask1 &= mask2
+		// delta = 1 - delta if swapping, otherwise delta = 1 + delta
+		delta = int64((uint64(delta)^mask1)-mask1) + 1
+		// f, u, v = g, q, r if swapping
+		f += g & mask1
+		u += q & mask1
+		v += r & mask1
+		g >>= 1
+		u <<= 1
+		v <<= 1
+	}
+	t.u, t.v, t.q, t.r = int64(u), int64(v), int64(q), int64(r)
+	return delta
+}
+
+// updateFG computes (f, g) = t * (f, g) / 2^62 on the lowest n limbs.
+func updateFG(f, g *signed62, t *transition, n int) {
+	var cf, cg int128
+	cf.mulAdd(t.u, f[0])
+	cf.mulAdd(t.v, g[0])
+	cg.mulAdd(t.q, f[0])
+	cg.mulAdd(t.r, g[0])
+	cf.shift62()
+	cg.shift62()
+	for i := 1; i < n; i++ {
+		cf.mulAdd(t.u, f[i])
+		cf.mulAdd(t.v, g[i])
+		cg.mulAdd(t.q, f[i])
+		cg.mulAdd(t.r, g[i])
+		f[i-1] = int64(cf.lo & safegcdMask)
+		g[i-1] = int64(cg.lo & safegcdMask)
+		cf.shift62()
+		cg.shift62()
+	}
+	f[n-1] = int64(cf.lo)
+	g[n-1] = int64(cg.lo)
+}
+
+// updateDE computes (d, e) = t * (d, e) / 2^62 mod modulus. Inputs are in
+// range (-2 modulus, modulus) and so are the outputs.
+func (m *safegcdModulus) updateDE(d, e *signed62, t *transition) {
+	n := m.n
+	sd, se := d[n-1]>>63, e[n-1]>>63
+	md := (t.u & sd) + (t.v & se)
+	me := (t.q & sd) + (t.r & se)
+	var cd, ce int128
+	cd.mulAdd(t.u, d[0])
+	cd.mulAdd(t.v, e[0])
+	ce.mulAdd(t.q, d[0])
+	ce.mulAdd(t.r, e[0])
+	// choose md and me so that low 62 bits of the sums vanish
+	md -= int64((m.inv62*cd.lo + uint64(md)) & safegcdMask)
+	me -= int64((m.inv62*ce.lo + uint64(me)) & safegcdMask)
+	cd.mulAdd(m.modulus[0], md)
+	ce.mulAdd(m.modulus[0], me)
+	cd.shift62()
+	ce.shift62()
+	for i := 1; i < n; i++ {
+		cd.mulAdd(t.u, d[i])
+		cd.mulAdd(t.v, e[i])
+		ce.mulAdd(t.q, d[i])
+		ce.mulAdd(t.r, e[i])
+		cd.mulAdd(m.modulus[i], md)
+		ce.mulAdd(m.modulus[i], me)
+		d[i-1] = int64(cd.lo & safegcdMask)
+		e[i-1] = int64(ce.lo & safegcdMask)
+		cd.shift62()
+		ce.shift62()
+	}
+	d[n-1] = int64(cd.lo)
+	e[n-1] = int64(ce.lo)
+}
+
+// normalize maps a value in (-2 modulus, modulus) to [0, modulus), negating
+// it first if sign is negative.
+func (m *safegcdModulus) normalize(r *signed62, sign int64) {
+	n := m.n
+	cond := r[n-1] >> 63
+	for i := 0; i < n; i++ {
+		r[i] += m.modulus[i] & cond
+	}
+	neg := sign >> 63
+	for i := 0; i < n; i++ {
+		r[i] = (r[i] ^ neg) - neg
+	}
+	for i := 0; i < n-1; i++ {
+		r[i+1] += r[i] >> 62
+		r[i] &= safegcdMask
+	}
+	cond = r[n-1] >> 63
+	for i := 0; i < n; i++ {
+		r[i] += m.modulus[i] & cond
+	}
+	for i := 0; i < n-1; i++ {
+		r[i+1] += r[i] >> 62
+		r[i] &= safegcdMask
+	}
+}
+
+// inverse computes a^-1 mod modulus in constant time, where a is given in 64
+// bit little endian limbs and is less than the modulus. Inverse of zero is
+// zero.
+func (m *safegcdModulus) inverse(out, a []uint64) {
+	var d, e signed62
+	e[0] = 1
+	f, g := m.modulus, toSigned62(a, m.n)
+	t := new(transition)
+	delta := int64(1)
+	for i := 0; i < m.batches; i++ {
+		delta = divsteps62(delta, uint64(f[0]), uint64(g[0]), t)
+		m.updateDE(&d, &e, t)
+		updateFG(&f, &g, t, m.n)
+	}
+	// f is now ±1 and d * a = f
+	m.normalize(&d, f[m.n-1])
+	fromSigned62(out, &d, m.n)
+}
+
+// divsteps62Var runs 62 divsteps on low bits of f and g in variable time and
+// returns the new eta, which is -delta. Runs of zero low bits of g are
+// skipped at once and up to 6 bits of g are cancelled per step, following
+// modinv64_divsteps_62_var of libsecp256k1. f must be odd.
+func divsteps62Var(eta int64, f0, g0 uint64, t *transition) int64 {
+	u, v, q, r := uint64(1), uint64(0), uint64(0), uint64(1)
+	f, g := f0, g0
+	i := 62
+	for {
+		// sentinel bit counts zeros only up to i
+		zeros := bits.TrailingZeros64(g | (^uint64(0) << uint(i)))
+		g >>= uint(zeros)
+		u <<= uint(zeros)
+		v <<= uint(zeros)
+		eta -= int64(zeros)
+		i -= zeros
+		if i == 0 {
+			break
+		}
+		var m, w uint64
+		limit := int(eta) + 1
+		if eta < 0 {
+			// negate eta and replace f, g with g, -f
+			eta = -eta
+			f, g = g, -f
+			u, q = q, -u
+			v, r = r, -v
+			limit = int(eta) + 1
+			if limit > i {
+				limit = i
+			}
+			// cancel up to 6 bits of g
+			m = (^uint64(0) >> uint(64-limit)) & 63
+			w = (f * g * (f*f - 2)) & m
+		} else {
+			if limit > i {
+				limit = i
+			}
+			// cancel up to 4 bits of g
+			m = (^uint64(0) >> uint(64-limit)) & 15
+			w = f + (((f + 1) & 4) << 1)
+			w = (-w * g) & m
+		}
+		g += f * w
+		q += u * w
+		r += v * w
+	}
+	t.u, t.v, t.q, t.r = int64(u), int64(v), int64(q), int64(r)
+	return eta
+}
+
+// inverseVarTime is inverse in variable time. Instead of running the fixed
+// number of batches it stops as soon as g is zero, and it shortens f and g
+// as they shrink, following modinv64_var of libsecp256k1. a must not be zero.
+func (m *safegcdModulus) inverseVarTime(out, a []uint64) {
+	var d, e signed62
+	e[0] = 1
+	f, g := m.modulus, toSigned62(a, m.n)
+	t := new(transition)
+	eta := int64(-1)
+	n := m.n
+	for {
+		eta = divsteps62Var(eta, uint64(f[0]), uint64(g[0]), t)
+		m.updateDE(&d, &e, t)
+		updateFG(&f, &g, t, n)
+		if g[0] == 0 {
+			var cond int64
+			for j := 1; j < n; j++ {
+				cond |= g[j]
+			}
+			if cond == 0 {
+				break
+			}
+		}
+		// drop the top limb if it is 0 or -1 in both f and g
+		fn, gn := f[n-1], g[n-1]
+		cond := int64(n-2) >> 63
+		cond |= fn ^ (fn >> 63)
+		cond |= gn ^ (gn >> 63)
+		if cond == 0 {
+			f[n-2] |= int64(uint64(fn) << 62)
+			g[n-2] |= int64(uint64(gn) << 62)
+			n--
+		}
+	}
+	// f is now ±1 and d * a = f
+	m.normalize(&d, f[n-1])
+	fromSigned62(out, &d, m.n)
+}
@@ -0,0 +1,106 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestSafegcdInverse(t *testing.T) {
+	for _, m := range []struct {
+		name    string
+		limbs   []uint64
+		big     *big.Int
+		bitLen  int
+		modulus *safegcdModulus
+	}{
+		{"fr", q[:], qBig, frBitSize, frSafegcd},
+		{"fp", modulus[:], modulus.big(), fpBitSize, fpSafegcd},
+	} {
+		n := len(m.limbs)
+		toBig := func(a []uint64) *big.Int {
+			r := new(big.Int)
+			for i := n - 1; i >= 0; i-- {
+				r.Lsh(r, 64)
+				r.Or(r, new(big.Int).SetUint64(a[i]))
+			}
+			return r
+		}
+		fromBig := func(a *big.Int) []uint64 {
+			r := make([]uint64, n)
+			for i, w := range new(big.Int).Set(a).Bits() {
+				r[i] = uint64(w)
+			}
+			return r
+		}
+		one := big.NewInt(1)
+		inputs := []*big.Int{
+			one,
+			big.NewInt(2),
+			new(big.Int).Sub(m.big, one),
+			new(big.Int).Rsh(m.big, 1),
+			new(big.Int).Sub(new(big.Int).Lsh(one, uint(m.bitLen-1)), one),
+		}
+		for i := 0; i < fuz; i++ {
+			a, _ := rand.Int(rand.Reader, m.big)
+			inputs = append(inputs, a)
+		}
+		out := make([]uint64, n)
+		for _, a := range inputs {
+			if a.Sign() == 0 {
+				continue
+			}
+			m.modulus.inverse(out, fromBig(a))
+			if toBig(out).Cmp(new(big.Int).ModInverse(a, m.big)) != 0 {
+				t.Fatal(m.name, "inverse mismatch", a)
+			}
+			m.modulus.inverseVarTime(out, fromBig(a))
+			if toBig(out).Cmp(new(big.Int).ModInverse(a, m.big)) != 0 {
+				t.Fatal(m.name, "variable time inverse mismatch", a)
+			}
+		}
+		// in place
+		a := fromBig(inputs[len(inputs)-1])
+		m.modulus.inverse(a, a)
+		m.modulus.inverse(a, a)
+		if toBig(a).Cmp(inputs[len(inputs)-1]) != 0 {
+			t.Fatal(m.name, "double inversion must be identity")
+		}
+		m.modulus.inverse(out, make([]uint64, n))
+		if toBig(out).Sign() != 0 {
+			t.Fatal(m.name, "inverse of zero must be zero")
+		}
+	}
+}
+
+func BenchmarkSafegcdInverse(t *testing.B) {
+	a, _ := new(Fr).Rand(rand.Reader)
+	b, _ := new(Fp).Rand(rand.Reader)
+	c, d := new(Fr), new(Fp)
+	t.Run("fr", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			c.Inverse(a)
+		}
+	})
+	t.Run("fp", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			inverse(d, b)
+		}
+	})
+}
+
+func BenchmarkInverseVarTime(t *testing.B) {
+	a, _ := new(Fr).Rand(rand.Reader)
+	b, _ := new(Fp).Rand(rand.Reader)
+	c, d := new(Fr), new(Fp)
+	t.Run("fr", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			c.InverseVarTime(a)
+		}
+	})
+	t.Run("fp", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			d.InverseVarTime(b)
+		}
+	})
+}
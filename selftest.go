@@ -0,0 +1,224 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// Algorithm identifiers of primitives implemented by this package. Hashing
+// identifiers are suite identifiers of RFC 9380.
+const (
+	AlgorithmFp            = "BLS12381_FP"
+	AlgorithmFr            = "BLS12381_FR"
+	AlgorithmSerialization = "BLS12381_ZCASH_SERIALIZATION"
+	AlgorithmHashToG1      = "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	AlgorithmEncodeToG1    = "BLS12381G1_XMD:SHA-256_SSWU_NU_"
+	AlgorithmHashToG2      = "BLS12381G2_XMD:SHA-256_SSWU_RO_"
+	AlgorithmEncodeToG2    = "BLS12381G2_XMD:SHA-256_SSWU_NU_"
+	AlgorithmPairing       = "BLS12381_OPTIMAL_ATE"
+)
+
+// Algorithm describes an implemented algorithm and the version of the
+// specification it follows.
+type Algorithm struct {
+	ID   string
+	Spec string
+}
+
+// Algorithms returns algorithms implemented by this package.
+func Algorithms() []Algorithm {
+	return []Algorithm{
+		{AlgorithmFp, "base field arithmetic modulo p"},
+		{AlgorithmFr, "scalar field arithmetic modulo r"},
+		{AlgorithmSerialization, "zkcrypto pairing serialization, encoding version " + strconv.Itoa(EncodingVersion)},
+		{AlgorithmHashToG1, "RFC 9380"},
+		{AlgorithmEncodeToG1, "RFC 9380"},
+		{AlgorithmHashToG2, "RFC 9380"},
+		{AlgorithmEncodeToG2, "RFC 9380"},
+		{AlgorithmPairing, "optimal ate pairing with final exponentiation, draft-irtf-cfrg-pairing-friendly-curves"},
+	}
+}
+
+// Known answers of the self test.
+const (
+	katG1 = "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	katG2 = "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+	// SHA-256 of encoded e(g1, g2)
+	katPairingHash = "300e47c99502f3af33ad2080847d528cabd90365a90ab98bc174565c27928591"
+	// empty message hashed with TESTGEN domains of draft 07 of hash to curve,
+	// which uses the same mappings as RFC 9380
+	katHashToG1   = "0576730ab036cbac1d95b38dca905586f28d0a59048db4e8778782d89bff856ddef89277ead5a21e2975c4a6e3d8c79e1273e568bebf1864393c517f999b87c1eaa1b8432f95aea8160cd981b5b05d8cd4a7cf00103b6ef87f728e4b547dd7ae"
+	katEncodeToG1 = "1223effdbb2d38152495a864d78eee14cb0992d89a241707abb03819a91a6d2fd65854ab9a69e9aacb0cbebfd490732c0f925d61e0b235ecd945cbf0309291878df0d06e5d80d6b84aa4ff3e00633b26f9a7cb3523ef737d90e6d71e8b98b2d5"
+	katHashToG2   = "0fbdae26f9f9586a46d4b0b70390d09064ef2afe5c99348438a3c7d9756471e015cb534204c1b6824617a85024c772dc0a650bd36ae7455cb3fe5d8bb1310594551456f5c6593aec9ee0c03d2f6cb693bd2c5e99d4e23cbaec767609314f51d302e5cf8f9b7348428cc9e66b9a9b36fe45ba0b0a146290c3a68d92895b1af0e1f2d9f889fb412670ae8478d8abd4c5aa0d8d49e7737d8f9fc5cef7c4b8817633103faf2613016cb86a1f3fc29968fe2413e232d9208d2d74a89bf7a48ac36f83"
+	katEncodeToG2 = "0d4333b77becbf9f9dfa3ca928002233d1ecc854b1447e5a71f751c9042d000f42db91c1d6649a5e0ad22bd7bf7398b8027e4bfada0b47f9f07e04aec463c7371e68f2fd0c738cd517932ea3801a35acf09db018deda57387b0f270f7a219e4d0cc76dc777ea0d447e02a41004f37a0a7b1fafb6746884e8d9fc276716ccf47e4e0899548a2ec71c2bdf1a2a50e876db053674cba9ef516ddc218fedb37324e6c47de27f88ab7ef123b006127d738293c0277187f7e2f80a299a24d84ed03da7"
+	katA          = "0x0bd2c2e0b0a6a4ae874fd2ac8600e6d1a715f8ad1b6efc2cdbce7ca32c4590b1d22c4b8d5e7152ab266b693bc7cbb2f"
+	katB          = "0x160a2fc9f3d3dbc5cf2077e2ae1e4853bda1f828ad8393c9911f6f50bad33f8237a2d13399aac36cd4bc2027b62d81ea"
+)
+
+// KnownAnswerTest is an embedded test comparing outputs of an algorithm with
+// known answers.
+type KnownAnswerTest struct {
+	Name      string
+	Algorithm string
+	run       func() bool
+}
+
+// Run returns an error if the algorithm does not produce the known answer.
+func (t *KnownAnswerTest) Run() error {
+	if !t.run() {
+		return errors.New("known answer test failed: " + t.Name)
+	}
+	return nil
+}
+
+// KnownAnswerTests returns known answer tests embedded in this package.
+func KnownAnswerTests() []KnownAnswerTest {
+	return []KnownAnswerTest{
+		{"base field", AlgorithmFp, katFp},
+		{"scalar field", AlgorithmFr, katFr},
+		{"serialization", AlgorithmSerialization, katSerialization},
+		{"hash to G1", AlgorithmHashToG1, func() bool {
+			return katHashG1(NewG1().HashToCurve, "BLS12381G1_XMD:SHA-256_SSWU_RO_TESTGEN", katHashToG1)
+		}},
+		{"encode to G1", AlgorithmEncodeToG1, func() bool {
+			return katHashG1(NewG1().EncodeToCurve, "BLS12381G1_XMD:SHA-256_SSWU_NU_TESTGEN", katEncodeToG1)
+		}},
+		{"hash to G2", AlgorithmHashToG2, func() bool {
+			return katHashG2(NewG2().HashToCurve, "BLS12381G2_XMD:SHA-256_SSWU_RO_TESTGEN", katHashToG2)
+		}},
+		{"encode to G2", AlgorithmEncodeToG2, func() bool {
+			return katHashG2(NewG2().EncodeToCurve, "BLS12381G2_XMD:SHA-256_SSWU_NU_TESTGEN", katEncodeToG2)
+		}},
+		{"pairing", AlgorithmPairing, katPairing},
+	}
+}
+
+// SelfTest runs all known answer tests. It is meant to be run once at start
+// up to detect miscompiled or corrupted builds, such as on less common
+// architectures, and returns the error of the first failing test.
+func SelfTest() error {
+	tests := KnownAnswerTests()
+	for i := range tests {
+		if err := tests[i].Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func katFp() bool {
+	p := modulus.big()
+	aBig, bBig := bigFromHex(katA), bigFromHex(katB)
+	a, err := fromBig(aBig)
+	if err != nil {
+		return false
+	}
+	b, err := fromBig(bBig)
+	if err != nil {
+		return false
+	}
+	c, z := new(Fp), new(big.Int)
+	add(c, a, b)
+	if ToBig(c).Cmp(z.Mod(z.Add(aBig, bBig), p)) != 0 {
+		return false
+	}
+	sub(c, a, b)
+	if ToBig(c).Cmp(z.Mod(z.Sub(aBig, bBig), p)) != 0 {
+		return false
+	}
+	mul(c, a, b)
+	if ToBig(c).Cmp(z.Mod(z.Mul(aBig, bBig), p)) != 0 {
+		return false
+	}
+	inverse(c, a)
+	if ToBig(c).Cmp(z.ModInverse(aBig, p)) != 0 {
+		return false
+	}
+	square(c, a)
+	if !sqrt(c, c) || !(ToBig(c).Cmp(aBig) == 0 || ToBig(c).Cmp(z.Sub(p, aBig)) == 0) {
+		return false
+	}
+	return true
+}
+
+func katFr() bool {
+	aBig, bBig := bigFromHex(katA), bigFromHex(katB)
+	aBig.Mod(aBig, qBig)
+	bBig.Mod(bBig, qBig)
+	a, b, c, z := new(Fr).fromBig(aBig), new(Fr).fromBig(bBig), new(Fr), new(big.Int)
+	c.Add(a, b)
+	if c.ToBig().Cmp(z.Mod(z.Add(aBig, bBig), qBig)) != 0 {
+		return false
+	}
+	c.Mul(a, b)
+	if c.ToBig().Cmp(z.Mod(z.Mul(aBig, bBig), qBig)) != 0 {
+		return false
+	}
+	c.Inverse(a)
+	return c.ToBig().Cmp(z.ModInverse(aBig, qBig)) == 0
+}
+
+func katSerialization() bool {
+	g1, g2 := NewG1(), NewG2()
+	if hex.EncodeToString(g1.ToCompressed(g1.One())) != katG1 {
+		return false
+	}
+	if hex.EncodeToString(g2.ToCompressed(g2.One())) != katG2 {
+		return false
+	}
+	p := g1.MulScalar(g1.New(), g1.One(), new(Fr).setUint64(7))
+	if r, err := g1.FromCompressed(g1.ToCompressed(p)); err != nil || !g1.Equal(p, r) {
+		return false
+	}
+	if r, err := g1.FromUncompressed(g1.ToUncompressed(p)); err != nil || !g1.Equal(p, r) {
+		return false
+	}
+	q := g2.MulScalar(g2.New(), g2.One(), new(Fr).setUint64(7))
+	if r, err := g2.FromCompressed(g2.ToCompressed(q)); err != nil || !g2.Equal(q, r) {
+		return false
+	}
+	if r, err := g2.FromUncompressed(g2.ToUncompressed(q)); err != nil || !g2.Equal(q, r) {
+		return false
+	}
+	return true
+}
+
+func katHashG1(hash func(msg, domain []byte) (*PointG1, error), domain, expected string) bool {
+	p, err := hash([]byte{}, []byte(domain))
+	if err != nil {
+		return false
+	}
+	return hex.EncodeToString(NewG1().ToBytes(p)) == expected
+}
+
+func katHashG2(hash func(msg, domain []byte) (*PointG2, error), domain, expected string) bool {
+	p, err := hash([]byte{}, []byte(domain))
+	if err != nil {
+		return false
+	}
+	return hex.EncodeToString(NewG2().ToBytes(p)) == expected
+}
+
+func katPairing() bool {
+	e := NewEngine()
+	gt := e.GT()
+	base := e.AddPair(e.G1.One(), e.G2.One()).Result()
+	h := sha256.Sum256(gt.ToBytes(base))
+	expected, _ := hex.DecodeString(katPairingHash)
+	if !bytes.Equal(h[:], expected) {
+		return false
+	}
+	// e(2 * g1, 3 * g2) == e(6 * g1, g2) == e(g1, g2)^6
+	p := e.G1.MulScalar(e.G1.New(), e.G1.One(), new(Fr).setUint64(2))
+	q := e.G2.MulScalar(e.G2.New(), e.G2.One(), new(Fr).setUint64(3))
+	l := e.Reset().AddPair(p, q).Result()
+	e.G1.MulScalar(p, e.G1.One(), new(Fr).setUint64(6))
+	r := e.Reset().AddPair(p, e.G2.One()).Result()
+	c := gt.New()
+	gt.Exp(c, base, big.NewInt(6))
+	return !base.IsOne() && l.Equal(r) && l.Equal(c)
+}
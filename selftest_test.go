@@ -0,0 +1,32 @@
+package bls12381
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(); err != nil {
+		t.Fatal(err)
+	}
+	if bigFromHex(katA).Cmp(modulus.big()) >= 0 || bigFromHex(katB).Cmp(modulus.big()) >= 0 {
+		t.Fatal("self test inputs must be field elements")
+	}
+}
+
+func TestKnownAnswerTests(t *testing.T) {
+	ids := map[string]bool{}
+	for _, a := range Algorithms() {
+		ids[a.ID] = true
+	}
+	tests := KnownAnswerTests()
+	for i := range tests {
+		if !ids[tests[i].Algorithm] {
+			t.Fatal("unknown algorithm", tests[i].Algorithm)
+		}
+		if err := tests[i].Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	broken := KnownAnswerTest{"broken", AlgorithmFp, func() bool { return false }}
+	if err := broken.Run(); err == nil {
+		t.Fatal("failing test must return an error")
+	}
+}
@@ -0,0 +1,233 @@
+package sig
+
+import (
+	"errors"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var (
+	ErrParticipation = errors.New("participation length must be equal to number of keys")
+	ErrKeyIndex      = errors.New("key index out of range")
+)
+
+// Committee is an ordered list of public keys, such as a validator committee,
+// with cached prefix sums of the keys. Aggregate key of a participating subset
+// is recomputed from the prefix sums with two group additions per run of
+// consecutive participants, or from the keys directly if that takes fewer
+// additions, so that subsets with long runs of participants or absentees
+// cost far less than summing every participating key. Keys must come with
+// proofs of possession as for FastAggregateVerify. A committee is not
+// suitable for concurrent use.
+type Committee struct {
+	g      *bls.G1
+	pks    []*PublicKey
+	prefix []bls.PointG1
+}
+
+// NewCommittee returns a committee of given public keys.
+func NewCommittee(pks []*PublicKey) (*Committee, error) {
+	if len(pks) == 0 {
+		return nil, ErrNoInput
+	}
+	g := bls.NewG1()
+	// prefix[i] is sum of first i keys
+	prefix := make([]bls.PointG1, len(pks)+1)
+	for i, pk := range pks {
+		g.Add(&prefix[i+1], &prefix[i], &pk.p)
+	}
+	return &Committee{g, append([]*PublicKey{}, pks...), prefix}, nil
+}
+
+// Len returns the number of keys in the committee.
+func (c *Committee) Len() int {
+	return len(c.pks)
+}
+
+// AggregateSubset returns aggregate public key of committee members with
+// participation flag set. It returns ErrNoInput if no member participates.
+func (c *Committee) AggregateSubset(participation []bool) (*PublicKey, error) {
+	if len(participation) != len(c.pks) {
+		return nil, ErrParticipation
+	}
+	n, runs := 0, 0
+	for i, ok := range participation {
+		if ok {
+			n++
+			if i == 0 || !participation[i-1] {
+				runs++
+			}
+		}
+	}
+	if n == 0 {
+		return nil, ErrNoInput
+	}
+	g := c.g
+	agg := &PublicKey{}
+	if n <= 2*runs {
+		for i, ok := range participation {
+			if ok {
+				g.Add(&agg.p, &agg.p, &c.pks[i].p)
+			}
+		}
+		g.Affine(&agg.p)
+		return agg, nil
+	}
+	for i := 0; i < len(participation); {
+		if !participation[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(participation) && participation[i] {
+			i++
+		}
+		// keys in [start, i) are prefix[i] - prefix[start]
+		g.Add(&agg.p, &agg.p, &c.prefix[i])
+		g.Sub(&agg.p, &agg.p, &c.prefix[start])
+	}
+	g.Affine(&agg.p)
+	return agg, nil
+}
+
+// VerifySubset returns true if the aggregate signature is valid for the
+// message signed by committee members with participation flag set.
+func (sig *Signature) VerifySubset(c *Committee, participation []bool, msg []byte) bool {
+	agg, err := c.AggregateSubset(participation)
+	if err != nil {
+		return false
+	}
+	return verify(&agg.p, &sig.p, msg, []byte(DST))
+}
+
+// KeyTree keeps aggregates of an ordered list of public keys in a segment
+// tree, so that keys can be replaced or appended with O(log n) group
+// additions and aggregate of any range of keys is formed with O(log n) group
+// additions. Unlike Committee it suits lists that change between queries,
+// such as committees with rotating members. As for Committee keys must come
+// with proofs of possession, and a tree is not suitable for concurrent use.
+type KeyTree struct {
+	g *bls.G1
+	n int
+	// node i has children 2i and 2i + 1, leaves are at len(nodes) / 2 + i
+	nodes []bls.PointG1
+}
+
+// NewKeyTree returns a tree of given public keys, which can be empty.
+func NewKeyTree(pks []*PublicKey) *KeyTree {
+	t := &KeyTree{g: bls.NewG1()}
+	t.build(pks, len(pks))
+	return t
+}
+
+// build lays out leaves for at least size keys and sums inner nodes.
+func (t *KeyTree) build(pks []*PublicKey, size int) {
+	leaves := 1
+	for leaves < size {
+		leaves <<= 1
+	}
+	t.n = len(pks)
+	t.nodes = make([]bls.PointG1, 2*leaves)
+	for i, pk := range pks {
+		t.nodes[leaves+i].Set(&pk.p)
+	}
+	for i := leaves - 1; i > 0; i-- {
+		t.g.Add(&t.nodes[i], &t.nodes[2*i], &t.nodes[2*i+1])
+	}
+}
+
+// Len returns the number of keys in the tree.
+func (t *KeyTree) Len() int {
+	return t.n
+}
+
+// Key returns the key at index i.
+func (t *KeyTree) Key(i int) (*PublicKey, error) {
+	if i < 0 || i >= t.n {
+		return nil, ErrKeyIndex
+	}
+	pk := &PublicKey{}
+	pk.p.Set(&t.nodes[len(t.nodes)/2+i])
+	return pk, nil
+}
+
+// Set replaces the key at index i.
+func (t *KeyTree) Set(i int, pk *PublicKey) error {
+	if i < 0 || i >= t.n {
+		return ErrKeyIndex
+	}
+	j := len(t.nodes)/2 + i
+	t.nodes[j].Set(&pk.p)
+	for j >>= 1; j > 0; j >>= 1 {
+		t.g.Add(&t.nodes[j], &t.nodes[2*j], &t.nodes[2*j+1])
+	}
+	return nil
+}
+
+// Append adds the key to the end of the list. Capacity is doubled when the
+// tree is full, so appends take O(log n) additions amortized.
+func (t *KeyTree) Append(pk *PublicKey) {
+	if t.n == len(t.nodes)/2 {
+		pks := make([]*PublicKey, t.n)
+		for i := range pks {
+			pks[i], _ = t.Key(i)
+		}
+		t.build(pks, 2*t.n)
+	}
+	t.n++
+	_ = t.Set(t.n-1, pk)
+}
+
+// AggregateRange returns aggregate of keys with indices in [from, to).
+func (t *KeyTree) AggregateRange(from, to int) (*PublicKey, error) {
+	if from < 0 || to > t.n || from >= to {
+		return nil, ErrKeyIndex
+	}
+	agg := &PublicKey{}
+	t.addRange(&agg.p, from, to)
+	t.g.Affine(&agg.p)
+	return agg, nil
+}
+
+// addRange adds keys with indices in [from, to) to the accumulator.
+func (t *KeyTree) addRange(acc *bls.PointG1, from, to int) {
+	l, r := from+len(t.nodes)/2, to+len(t.nodes)/2
+	for ; l < r; l, r = l>>1, r>>1 {
+		if l&1 == 1 {
+			t.g.Add(acc, acc, &t.nodes[l])
+			l++
+		}
+		if r&1 == 1 {
+			r--
+			t.g.Add(acc, acc, &t.nodes[r])
+		}
+	}
+}
+
+// AggregateSubset returns aggregate public key of keys with participation
+// flag set, adding each run of consecutive participants as a range. It
+// returns ErrNoInput if no key participates.
+func (t *KeyTree) AggregateSubset(participation []bool) (*PublicKey, error) {
+	if len(participation) != t.n {
+		return nil, ErrParticipation
+	}
+	agg := &PublicKey{}
+	empty := true
+	for i := 0; i < len(participation); {
+		if !participation[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(participation) && participation[i] {
+			i++
+		}
+		t.addRange(&agg.p, start, i)
+		empty = false
+	}
+	if empty {
+		return nil, ErrNoInput
+	}
+	t.g.Affine(&agg.p)
+	return agg, nil
+}
@@ -0,0 +1,125 @@
+package sig
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func newTestCommittee(t testing.TB, n int) ([]*SecretKey, *Committee) {
+	sks := make([]*SecretKey, n)
+	pks := make([]*PublicKey, n)
+	for i := range sks {
+		sks[i], _ = GenerateKey(rand.Reader)
+		pks[i] = sks[i].PublicKey()
+	}
+	c, err := NewCommittee(pks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sks, c
+}
+
+func TestCommitteeSubset(t *testing.T) {
+	sks, c := newTestCommittee(t, 16)
+	msg := []byte("block root")
+	for _, participation := range [][]bool{
+		{true, true, true, true, true, true, true, true, true, true, true, true, true, true, true, true},
+		{true, false, true, false, true, false, true, false, true, false, true, false, true, false, true, false},
+		{false, true, true, true, true, true, true, false, false, true, true, true, true, true, true, true},
+		{false, false, false, false, false, false, false, false, false, false, false, false, false, false, false, true},
+	} {
+		var sigs []*Signature
+		var pks []*PublicKey
+		for i, ok := range participation {
+			if ok {
+				sig, _ := sks[i].Sign(msg)
+				sigs = append(sigs, sig)
+				pks = append(pks, sks[i].PublicKey())
+			}
+		}
+		want, _ := AggregatePublicKeys(pks...)
+		have, err := c.AggregateSubset(participation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.Equal(want) {
+			t.Fatal("bad subset aggregate", participation)
+		}
+		agg, _ := AggregateSignatures(sigs...)
+		if !agg.VerifySubset(c, participation, msg) {
+			t.Fatal("aggregate signature must be valid", participation)
+		}
+		participation[0] = !participation[0]
+		if agg.VerifySubset(c, participation, msg) {
+			t.Fatal("aggregate signature must be invalid for another subset")
+		}
+	}
+	if _, err := c.AggregateSubset(make([]bool, 16)); err != ErrNoInput {
+		t.Fatal("empty subset must be rejected")
+	}
+	if _, err := c.AggregateSubset(make([]bool, 15)); err != ErrParticipation {
+		t.Fatal("participation of wrong length must be rejected")
+	}
+}
+
+func BenchmarkCommitteeSubset(b *testing.B) {
+	_, c := newTestCommittee(b, 512)
+	participation := make([]bool, c.Len())
+	for i := range participation {
+		participation[i] = i%64 != 0
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.AggregateSubset(participation)
+	}
+}
+
+func TestKeyTree(t *testing.T) {
+	sks, c := newTestCommittee(t, 13)
+	tree := NewKeyTree(nil)
+	for _, sk := range sks {
+		tree.Append(sk.PublicKey())
+	}
+	if tree.Len() != len(sks) {
+		t.Fatal("bad length")
+	}
+	for from := 0; from < len(sks); from++ {
+		for to := from + 1; to <= len(sks); to++ {
+			participation := make([]bool, len(sks))
+			for i := from; i < to; i++ {
+				participation[i] = true
+			}
+			want, _ := c.AggregateSubset(participation)
+			have, err := tree.AggregateRange(from, to)
+			if err != nil || !have.Equal(want) {
+				t.Fatal("bad range aggregate", from, to)
+			}
+		}
+	}
+	participation := []bool{true, false, true, true, false, false, true, true, true, false, true, false, true}
+	want, _ := c.AggregateSubset(participation)
+	if have, err := tree.AggregateSubset(participation); err != nil || !have.Equal(want) {
+		t.Fatal("bad subset aggregate")
+	}
+	// rotate a member
+	sk, _ := GenerateKey(rand.Reader)
+	if err := tree.Set(2, sk.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+	want, _ = AggregatePublicKeys(sks[1].PublicKey(), sk.PublicKey(), sks[3].PublicKey())
+	if have, _ := tree.AggregateRange(1, 4); !have.Equal(want) {
+		t.Fatal("bad range aggregate after update")
+	}
+	if pk, _ := tree.Key(2); !pk.Equal(sk.PublicKey()) {
+		t.Fatal("bad key after update")
+	}
+	if err := tree.Set(13, sk.PublicKey()); err != ErrKeyIndex {
+		t.Fatal("index out of range must be rejected")
+	}
+	if _, err := tree.AggregateRange(3, 3); err != ErrKeyIndex {
+		t.Fatal("empty range must be rejected")
+	}
+	if _, err := tree.AggregateSubset(make([]bool, 13)); err != ErrNoInput {
+		t.Fatal("empty subset must be rejected")
+	}
+}
@@ -0,0 +1,61 @@
+package sig
+
+import "sync"
+
+// KeyStore caches decoded public keys by their compressed encoding, for
+// verifiers that see the same set of keys repeatedly, such as validator keys
+// across slots. Decoding a key costs a square root and a subgroup check,
+// which a cache hit replaces with a map lookup. Only valid keys are stored,
+// in affine form, and returned keys are shared between callers, so they must
+// not be modified. A store is safe for concurrent use.
+type KeyStore struct {
+	mu   sync.RWMutex
+	keys map[[PublicKeySize]byte]*PublicKey
+	max  int
+}
+
+// NewKeyStore returns a store holding up to max keys. When the store is full
+// an arbitrary key is evicted to make room. Zero max means no limit.
+func NewKeyStore(max int) *KeyStore {
+	return &KeyStore{keys: make(map[[PublicKeySize]byte]*PublicKey), max: max}
+}
+
+// PublicKey returns the public key of given compressed encoding, decoding and
+// validating it as PublicKeyFromBytes if it is not in the store yet.
+func (s *KeyStore) PublicKey(in []byte) (*PublicKey, error) {
+	if len(in) != PublicKeySize {
+		return PublicKeyFromBytes(in)
+	}
+	var k [PublicKeySize]byte
+	copy(k[:], in)
+	s.mu.RLock()
+	pk, ok := s.keys[k]
+	s.mu.RUnlock()
+	if ok {
+		return pk, nil
+	}
+	pk, err := PublicKeyFromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.keys[k]; ok {
+		return cached, nil
+	}
+	if s.max > 0 && len(s.keys) >= s.max {
+		for evict := range s.keys {
+			delete(s.keys, evict)
+			break
+		}
+	}
+	s.keys[k] = pk
+	return pk, nil
+}
+
+// Len returns the number of keys in the store.
+func (s *KeyStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.keys)
+}
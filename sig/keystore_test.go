@@ -0,0 +1,75 @@
+package sig
+
+import (
+	"crypto/rand"
+	"sync"
+	"testing"
+)
+
+func TestKeyStore(t *testing.T) {
+	s := NewKeyStore(0)
+	sk, _ := GenerateKey(rand.Reader)
+	enc := sk.PublicKey().Bytes()
+	pk, err := s.PublicKey(enc)
+	if err != nil || !pk.Equal(sk.PublicKey()) {
+		t.Fatal("key must be decoded", err)
+	}
+	if again, _ := s.PublicKey(enc); again != pk || s.Len() != 1 {
+		t.Fatal("key must be served from the store")
+	}
+	msg := []byte("message")
+	sig, _ := sk.Sign(msg)
+	if !sig.Verify(pk, msg) {
+		t.Fatal("cached key must verify")
+	}
+	infinity := make([]byte, PublicKeySize)
+	infinity[0] = 0xc0
+	if _, err := s.PublicKey(infinity); err != ErrInfinityKey {
+		t.Fatal("infinity key must be rejected", err)
+	}
+	if _, err := s.PublicKey(enc[1:]); err == nil {
+		t.Fatal("short input must be rejected")
+	}
+	if s.Len() != 1 {
+		t.Fatal("invalid keys must not be stored")
+	}
+}
+
+func TestKeyStoreLimit(t *testing.T) {
+	s := NewKeyStore(4)
+	keys := make([][]byte, 8)
+	for i := range keys {
+		sk, _ := GenerateKey(rand.Reader)
+		keys[i] = sk.PublicKey().Bytes()
+	}
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(enc []byte) {
+			defer wg.Done()
+			if _, err := s.PublicKey(enc); err != nil {
+				t.Error(err)
+			}
+		}(keys[i])
+	}
+	wg.Wait()
+	if s.Len() != 4 {
+		t.Fatal("store must not grow beyond its limit", s.Len())
+	}
+}
+
+func BenchmarkKeyStore(t *testing.B) {
+	sk, _ := GenerateKey(rand.Reader)
+	enc := sk.PublicKey().Bytes()
+	t.Run("decode", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			_, _ = PublicKeyFromBytes(enc)
+		}
+	})
+	t.Run("cached", func(t *testing.B) {
+		s := NewKeyStore(0)
+		for i := 0; i < t.N; i++ {
+			_, _ = s.PublicKey(enc)
+		}
+	})
+}
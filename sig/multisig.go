@@ -0,0 +1,128 @@
+package sig
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var (
+	ErrBitlist = errors.New("bitlist must end with a delimiter bit")
+	ErrOverlap = errors.New("participation of aggregated signatures must not overlap")
+)
+
+// MultiSignature is an aggregate signature of committee members with
+// participation flag set, such as an Ethereum attestation.
+//
+// Canonical encoding is the compressed signature followed by participation
+// flags as SSZ Bitlist, that is flags packed least significant bit first and
+// terminated by a single delimiter bit, so that the number of flags is
+// recovered from the last byte. A given multi signature has a single valid
+// encoding.
+type MultiSignature struct {
+	Participation []bool
+	Signature     *Signature
+}
+
+// Bytes returns canonical encoding of the multi signature.
+func (m *MultiSignature) Bytes() []byte {
+	n := len(m.Participation)
+	out := make([]byte, SignatureSize+n/8+1)
+	copy(out, m.Signature.Bytes())
+	bits := out[SignatureSize:]
+	for i, ok := range m.Participation {
+		if ok {
+			bits[i/8] |= 1 << uint(i%8)
+		}
+	}
+	bits[n/8] |= 1 << uint(n%8)
+	return out
+}
+
+// MultiSignatureFromBytes decodes canonical encoding of a multi signature.
+// Signature is checked as in SignatureFromBytes and ErrBitlist is returned if
+// participation flags are not terminated by a delimiter bit in the last byte.
+func MultiSignatureFromBytes(in []byte) (*MultiSignature, error) {
+	if len(in) <= SignatureSize {
+		return nil, bls.ErrInvalidLength
+	}
+	sig, err := SignatureFromBytes(in[:SignatureSize])
+	if err != nil {
+		return nil, err
+	}
+	bits := in[SignatureSize:]
+	last := bits[len(bits)-1]
+	if last == 0 {
+		return nil, ErrBitlist
+	}
+	// delimiter is the highest set bit of the last byte
+	n := 8 * (len(bits) - 1)
+	for last > 1 {
+		last >>= 1
+		n++
+	}
+	participation := make([]bool, n)
+	for i := range participation {
+		participation[i] = bits[i/8]&(1<<uint(i%8)) != 0
+	}
+	return &MultiSignature{participation, sig}, nil
+}
+
+// Verify returns true if the signature is valid for the message signed by
+// committee members with participation flag set.
+func (m *MultiSignature) Verify(c *Committee, msg []byte) bool {
+	return m.Signature.VerifySubset(c, m.Participation, msg)
+}
+
+// AggregateMultiSignatures combines multi signatures of the same committee
+// with disjoint participation into one. Since group addition commutes the
+// result does not depend on the order of inputs.
+func AggregateMultiSignatures(ms ...*MultiSignature) (*MultiSignature, error) {
+	if len(ms) == 0 {
+		return nil, ErrNoInput
+	}
+	participation := make([]bool, len(ms[0].Participation))
+	sigs := make([]*Signature, len(ms))
+	for i, m := range ms {
+		if len(m.Participation) != len(participation) {
+			return nil, ErrParticipation
+		}
+		for j, ok := range m.Participation {
+			if ok && participation[j] {
+				return nil, ErrOverlap
+			}
+			participation[j] = participation[j] || ok
+		}
+		sigs[i] = m.Signature
+	}
+	sig, err := AggregateSignatures(sigs...)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSignature{participation, sig}, nil
+}
+
+// SortMultiSignatures sorts multi signatures in canonical order, so that
+// lists of them are encoded identically by different implementations. Multi
+// signatures are ordered by number of participation flags, then by flags in
+// index order where the first differing flag that is set comes first, then
+// by compressed signature bytes.
+func SortMultiSignatures(ms []*MultiSignature) {
+	sort.SliceStable(ms, func(i, j int) bool {
+		return lessMultiSignature(ms[i], ms[j])
+	})
+}
+
+func lessMultiSignature(a, b *MultiSignature) bool {
+	if len(a.Participation) != len(b.Participation) {
+		return len(a.Participation) < len(b.Participation)
+	}
+	for i := range a.Participation {
+		if a.Participation[i] != b.Participation[i] {
+			return a.Participation[i]
+		}
+	}
+	return bytes.Compare(a.Signature.Bytes(), b.Signature.Bytes()) < 0
+}
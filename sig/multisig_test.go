@@ -0,0 +1,110 @@
+package sig
+
+import (
+	"bytes"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestMultiSignatureEncoding(t *testing.T) {
+	sks, c := newTestCommittee(t, 10)
+	msg := []byte("block root")
+	participation := []bool{true, false, false, true, true, false, false, false, false, true}
+	var sigs []*Signature
+	for i, ok := range participation {
+		if ok {
+			sig, _ := sks[i].Sign(msg)
+			sigs = append(sigs, sig)
+		}
+	}
+	agg, _ := AggregateSignatures(sigs...)
+	m := &MultiSignature{participation, agg}
+	enc := m.Bytes()
+	// 10 flags and the delimiter take two bytes
+	if len(enc) != SignatureSize+2 || enc[SignatureSize] != 0x19 || enc[SignatureSize+1] != 0x06 {
+		t.Fatalf("bad bitlist encoding %x", enc[SignatureSize:])
+	}
+	dec, err := MultiSignatureFromBytes(enc)
+	if err != nil || !dec.Signature.Equal(agg) || len(dec.Participation) != len(participation) {
+		t.Fatal("multi signature must round trip", err)
+	}
+	for i := range participation {
+		if dec.Participation[i] != participation[i] {
+			t.Fatal("participation must round trip")
+		}
+	}
+	if !dec.Verify(c, msg) {
+		t.Fatal("multi signature must verify")
+	}
+	// empty and full bytes of flags
+	for _, n := range []int{0, 8} {
+		m := &MultiSignature{make([]bool, n), agg}
+		dec, err := MultiSignatureFromBytes(m.Bytes())
+		if err != nil || len(dec.Participation) != n || len(m.Bytes()) != SignatureSize+n/8+1 {
+			t.Fatal("bad encoding of flags", n, err)
+		}
+	}
+	if _, err := MultiSignatureFromBytes(enc[:SignatureSize]); err != bls.ErrInvalidLength {
+		t.Fatal("missing bitlist must be rejected", err)
+	}
+	if _, err := MultiSignatureFromBytes(append(append([]byte{}, enc...), 0)); err != ErrBitlist {
+		t.Fatal("bitlist without delimiter must be rejected", err)
+	}
+}
+
+func TestAggregateMultiSignatures(t *testing.T) {
+	sks, c := newTestCommittee(t, 4)
+	msg := []byte("block root")
+	ms := make([]*MultiSignature, len(sks))
+	for i := range sks {
+		sig, _ := sks[i].Sign(msg)
+		ms[i] = &MultiSignature{make([]bool, len(sks)), sig}
+		ms[i].Participation[i] = true
+	}
+	a, err := AggregateMultiSignatures(ms[0], ms[2], ms[3])
+	if err != nil || !a.Verify(c, msg) {
+		t.Fatal("aggregate must verify", err)
+	}
+	b, _ := AggregateMultiSignatures(ms[3], ms[0], ms[2])
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Fatal("aggregate must not depend on order")
+	}
+	if _, err := AggregateMultiSignatures(a, ms[2]); err != ErrOverlap {
+		t.Fatal("overlapping participation must be rejected", err)
+	}
+	short := &MultiSignature{make([]bool, 3), ms[1].Signature}
+	if _, err := AggregateMultiSignatures(a, short); err != ErrParticipation {
+		t.Fatal("participation of different length must be rejected", err)
+	}
+	if _, err := AggregateMultiSignatures(); err != ErrNoInput {
+		t.Fatal("empty input must be rejected", err)
+	}
+}
+
+func TestSortMultiSignatures(t *testing.T) {
+	sks, _ := newTestCommittee(t, 3)
+	sig := func(i int) *Signature {
+		s, _ := sks[i].Sign([]byte("message"))
+		return s
+	}
+	list := []*MultiSignature{
+		{[]bool{false, true, true}, sig(0)},
+		{[]bool{true, false}, sig(1)},
+		{[]bool{true, false, false}, sig(2)},
+		{[]bool{false, true, false}, sig(0)},
+	}
+	want := [][]byte{list[1].Bytes(), list[2].Bytes(), list[0].Bytes(), list[3].Bytes()}
+	for _, perm := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}, {2, 0, 3, 1}} {
+		ms := make([]*MultiSignature, len(list))
+		for i, j := range perm {
+			ms[i] = list[j]
+		}
+		SortMultiSignatures(ms)
+		for i := range ms {
+			if !bytes.Equal(ms[i].Bytes(), want[i]) {
+				t.Fatal("bad canonical order", perm, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sig
+
+import bls "github.com/kilic/bls12-381"
+
+// In pre-hash mode messages are hashed to curve by their SHA-256 digest
+// under PrehashDST instead of directly under DST, for protocols that must
+// bound the input length of hashing to curve. Digests are 32 bytes and can be
+// computed by the caller with sha256.Sum256, or with sha256.New for messages
+// written in parts. Keys are same in both modes, but signatures of one mode
+// never verify in the other.
+
+// PrehashDST is the domain separation tag of the pre-hash mode. It differs
+// from DST so that a signature over SHA-256 digest of a message can not be
+// taken as a signature over a 32 byte message equal to the digest, or the
+// other way around.
+const PrehashDST = DST + prehashSuffix
+
+// prehashSuffix is appended to the tag of a ciphersuite for its pre-hash mode.
+const prehashSuffix = "PREHASH_SHA256_"
+
+// SignPrehashed signs the message given by its SHA-256 digest.
+func (sk *SecretKey) SignPrehashed(digest [32]byte) (*Signature, error) {
+	h, err := hashPrehashed(digest, []byte(PrehashDST))
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignHash(h), nil
+}
+
+// VerifyPrehashed returns true if the signature is valid for the message given
+// by its SHA-256 digest under the public key.
+func (sig *Signature) VerifyPrehashed(pk *PublicKey, digest [32]byte) bool {
+	h, err := hashPrehashed(digest, []byte(PrehashDST))
+	if err != nil {
+		return false
+	}
+	return verifyHashed(&pk.p, &sig.p, h)
+}
+
+// FastAggregateVerifyPrehashed is FastAggregateVerify in pre-hash mode.
+func (sig *Signature) FastAggregateVerifyPrehashed(pks []*PublicKey, digest [32]byte) bool {
+	agg, err := AggregatePublicKeys(pks...)
+	if err != nil {
+		return false
+	}
+	return sig.VerifyPrehashed(agg, digest)
+}
+
+func hashPrehashed(digest [32]byte, dst []byte) (*bls.PointG2, error) {
+	return bls.NewG2().HashToCurve(digest[:], dst)
+}
@@ -0,0 +1,38 @@
+package sig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestPrehashed(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	digest := sha256.Sum256(msg)
+	sig, err := sk.SignPrehashed(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.VerifyPrehashed(pk, digest) {
+		t.Fatal("signature must be valid")
+	}
+	if sig.VerifyPrehashed(pk, sha256.Sum256([]byte("other"))) {
+		t.Fatal("signature must be invalid for another message")
+	}
+	// modes are separated by domain
+	if sig.Verify(pk, digest[:]) {
+		t.Fatal("pre-hash signature must not verify as a signature over the digest")
+	}
+	plain, _ := sk.Sign(digest[:])
+	if plain.VerifyPrehashed(pk, digest) {
+		t.Fatal("signature over the digest must not verify in pre-hash mode")
+	}
+	sk2, _ := GenerateKey(rand.Reader)
+	sig2, _ := sk2.SignPrehashed(digest)
+	agg, _ := AggregateSignatures(sig, sig2)
+	if !agg.FastAggregateVerifyPrehashed([]*PublicKey{pk, sk2.PublicKey()}, digest) {
+		t.Fatal("aggregate signature must be valid")
+	}
+}
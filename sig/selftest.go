@@ -0,0 +1,46 @@
+package sig
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Ethereum consensus spec test vector.
+const (
+	selfTestSecretKey = "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"
+	selfTestPublicKey = "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+	selfTestSignature = "882730e5d03f6b42c3abc26d3372625034e1d871b65a8a6b900a56dae22da98abbe1b68f85e49fe7652a55ec3d0591c20767677e33e5cbb1207315c41a9ac03be39c2e7668edc043d6cb1d9fd93033caa8a1c5b0e84bedaeb6c64972503a43eb"
+)
+
+// SelfTest runs the self test of the core package followed by a known
+// answer signature round trip.
+func SelfTest() error {
+	if err := bls.SelfTest(); err != nil {
+		return err
+	}
+	if !selfTestSignatures() {
+		return errors.New("self test failed: signature")
+	}
+	return nil
+}
+
+func selfTestSignatures() bool {
+	in, _ := hex.DecodeString(selfTestSecretKey)
+	sk, err := SecretKeyFromBytes(in)
+	if err != nil {
+		return false
+	}
+	pk := sk.PublicKey().Bytes()
+	if hex.EncodeToString(pk) != selfTestPublicKey {
+		return false
+	}
+	msg := bytes.Repeat([]byte{0x56}, 32)
+	sig, err := sk.Sign(msg)
+	if err != nil || hex.EncodeToString(sig.Bytes()) != selfTestSignature {
+		return false
+	}
+	return VerifyBytes(pk, msg, sig.Bytes()) && !VerifyBytes(pk, msg[1:], sig.Bytes())
+}
@@ -0,0 +1,358 @@
+// Package sig implements BLS signatures over BLS12-381 in minimal public key
+// size variant, where public keys are in G1 and signatures are in G2.
+//
+// Signing uses the proof of possession ciphersuite of the irtf BLS signature
+// draft, which is the scheme used by Ethereum consensus layer.
+// https://tools.ietf.org/html/draft-irtf-cfrg-bls-signature-04
+package sig
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// DST is the domain separation tag used for hashing messages to G2.
+const DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// Encoded sizes in bytes.
+const (
+	SecretKeySize = bls.FrSize
+	PublicKeySize = bls.G1CompressedSize
+	SignatureSize = bls.G2CompressedSize
+)
+
+var (
+	ErrInvalidSecretKey = errors.New("secret key must be non zero and less than group order")
+	ErrInfinityKey      = errors.New("public key must not be the point at infinity")
+	ErrNoInput          = errors.New("nothing to aggregate")
+	ErrWeights          = errors.New("number of weights must be equal to number of inputs")
+	ErrZeroWeights      = errors.New("weights must not all be zero")
+)
+
+// SecretKey is a non zero scalar.
+type SecretKey struct {
+	s bls.Fr
+}
+
+// PublicKey is a G1 point which is not the point at infinity.
+type PublicKey struct {
+	p bls.PointG1
+}
+
+// Signature is a G2 point.
+type Signature struct {
+	p bls.PointG2
+}
+
+// GenerateKey returns a uniformly random secret key.
+func GenerateKey(r io.Reader) (*SecretKey, error) {
+	sk := &SecretKey{}
+	if _, err := sk.s.RandNonZero(r); err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// SecretKeyFromBytes decodes a 32 byte big endian secret key.
+func SecretKeyFromBytes(in []byte) (*SecretKey, error) {
+	if len(in) != SecretKeySize {
+		return nil, bls.ErrInvalidLength
+	}
+	s := new(big.Int).SetBytes(in)
+	if s.Sign() == 0 || s.Cmp(bls.NewG1().Q()) >= 0 {
+		return nil, ErrInvalidSecretKey
+	}
+	sk := &SecretKey{}
+	sk.s.FromBytes(in)
+	return sk, nil
+}
+
+// Bytes returns 32 byte big endian encoding of the secret key.
+func (sk *SecretKey) Bytes() []byte {
+	return sk.s.ToBytes()
+}
+
+// PublicKey derives the public key.
+func (sk *SecretKey) PublicKey() *PublicKey {
+	g := bls.NewG1()
+	pk := &PublicKey{}
+	g.Affine(g.MulScalar(&pk.p, g.One(), &sk.s))
+	return pk
+}
+
+// Sign signs the message.
+func (sk *SecretKey) Sign(msg []byte) (*Signature, error) {
+	g := bls.NewG2()
+	h, err := g.HashToCurve(msg, []byte(DST))
+	if err != nil {
+		return nil, err
+	}
+	sig := &Signature{}
+	g.Affine(g.MulScalar(&sig.p, h, &sk.s))
+	return sig, nil
+}
+
+// PublicKeyFromBytes decodes a compressed public key. Public key is checked to
+// be in correct subgroup and not to be the point at infinity.
+func PublicKeyFromBytes(in []byte) (*PublicKey, error) {
+	g := bls.NewG1()
+	pk := &PublicKey{}
+	if err := g.FromCompressedInto(&pk.p, in); err != nil {
+		return nil, err
+	}
+	if g.IsZero(&pk.p) {
+		return nil, ErrInfinityKey
+	}
+	return pk, nil
+}
+
+// Bytes returns compressed encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return bls.NewG1().ToCompressed(&pk.p)
+}
+
+// Point returns a copy of underlying G1 point.
+func (pk *PublicKey) Point() *bls.PointG1 {
+	return new(bls.PointG1).Set(&pk.p)
+}
+
+// Equal returns true if public keys are equal.
+func (pk *PublicKey) Equal(other *PublicKey) bool {
+	return bls.NewG1().Equal(&pk.p, &other.p)
+}
+
+// SignatureFromBytes decodes a compressed signature. Signature is checked to be
+// in correct subgroup.
+func SignatureFromBytes(in []byte) (*Signature, error) {
+	sig := &Signature{}
+	if err := bls.NewG2().FromCompressedInto(&sig.p, in); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Bytes returns compressed encoding of the signature.
+func (sig *Signature) Bytes() []byte {
+	return bls.NewG2().ToCompressed(&sig.p)
+}
+
+// Point returns a copy of underlying G2 point.
+func (sig *Signature) Point() *bls.PointG2 {
+	return new(bls.PointG2).Set(&sig.p)
+}
+
+// Equal returns true if signatures are equal.
+func (sig *Signature) Equal(other *Signature) bool {
+	return bls.NewG2().Equal(&sig.p, &other.p)
+}
+
+// Verify returns true if the signature is valid for the message under the
+// public key.
+func (sig *Signature) Verify(pk *PublicKey, msg []byte) bool {
+	return verify(&pk.p, &sig.p, msg, []byte(DST))
+}
+
+// AggregateSignatures sums signatures.
+func AggregateSignatures(sigs ...*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoInput
+	}
+	g := bls.NewG2()
+	agg := &Signature{}
+	for _, sig := range sigs {
+		g.Add(&agg.p, &agg.p, &sig.p)
+	}
+	g.Affine(&agg.p)
+	return agg, nil
+}
+
+// AggregatePublicKeys sums public keys.
+func AggregatePublicKeys(pks ...*PublicKey) (*PublicKey, error) {
+	if len(pks) == 0 {
+		return nil, ErrNoInput
+	}
+	g := bls.NewG1()
+	agg := &PublicKey{}
+	for _, pk := range pks {
+		g.Add(&agg.p, &agg.p, &pk.p)
+	}
+	g.Affine(&agg.p)
+	return agg, nil
+}
+
+// AggregatePublicKeysWeighted returns sum of public keys each multiplied by
+// its weight, such as stake of the signer, computed with a multi
+// exponentiation. It returns ErrZeroWeights if all weights are zero and
+// ErrInfinityKey if the weighted keys cancel out, since the point at infinity
+// is not a valid public key.
+func AggregatePublicKeysWeighted(pks []*PublicKey, weights []uint64) (*PublicKey, error) {
+	if err := checkWeights(len(pks), weights); err != nil {
+		return nil, err
+	}
+	points := make([]*bls.PointG1, len(pks))
+	for i, pk := range pks {
+		points[i] = pk.Point()
+	}
+	g := bls.NewG1()
+	agg := &PublicKey{}
+	if _, err := g.MultiExp(&agg.p, points, weightScalars(weights)); err != nil {
+		return nil, err
+	}
+	if g.IsZero(&agg.p) {
+		return nil, ErrInfinityKey
+	}
+	g.Affine(&agg.p)
+	return agg, nil
+}
+
+// AggregateSignaturesWeighted returns sum of signatures each multiplied by its
+// weight. Aggregate of signatures over the same message verifies under
+// AggregatePublicKeysWeighted of the keys with the same weights. It returns
+// ErrZeroWeights if all weights are zero.
+func AggregateSignaturesWeighted(sigs []*Signature, weights []uint64) (*Signature, error) {
+	if err := checkWeights(len(sigs), weights); err != nil {
+		return nil, err
+	}
+	points := make([]*bls.PointG2, len(sigs))
+	for i, sig := range sigs {
+		points[i] = sig.Point()
+	}
+	g := bls.NewG2()
+	agg := &Signature{}
+	if _, err := g.MultiExp(&agg.p, points, weightScalars(weights)); err != nil {
+		return nil, err
+	}
+	g.Affine(&agg.p)
+	return agg, nil
+}
+
+func checkWeights(n int, weights []uint64) error {
+	if n == 0 {
+		return ErrNoInput
+	}
+	if n != len(weights) {
+		return ErrWeights
+	}
+	for _, w := range weights {
+		if w != 0 {
+			return nil
+		}
+	}
+	return ErrZeroWeights
+}
+
+func weightScalars(weights []uint64) []*bls.Fr {
+	scalars := make([]*bls.Fr, len(weights))
+	var b [8]byte
+	for i, w := range weights {
+		binary.BigEndian.PutUint64(b[:], w)
+		scalars[i] = bls.NewFr().FromBytes(b[:])
+	}
+	return scalars
+}
+
+// FastAggregateVerify returns true if the aggregate signature is valid for
+// the message signed by all public keys. Public keys must come with proofs of
+// possession to prevent rogue key attacks.
+func (sig *Signature) FastAggregateVerify(pks []*PublicKey, msg []byte) bool {
+	agg, err := AggregatePublicKeys(pks...)
+	if err != nil {
+		return false
+	}
+	return verify(&agg.p, &sig.p, msg, []byte(DST))
+}
+
+// AggregateVerify returns true if the aggregate signature is valid for
+// messages each signed by the public key at the same index. Messages need not
+// be distinct, since public keys must come with proofs of possession.
+func (sig *Signature) AggregateVerify(pks []*PublicKey, msgs [][]byte) bool {
+	return aggregateVerify(pks, &sig.p, msgs, []byte(DST))
+}
+
+// HashMessage returns H(msg) in G2 under the signature domain. It is meant to
+// be computed once and passed to FastAggregateVerifyCached when the same
+// message is verified repeatedly.
+func HashMessage(msg []byte) (*bls.PointG2, error) {
+	return hashMessage(msg, []byte(DST))
+}
+
+func hashMessage(msg, dst []byte) (*bls.PointG2, error) {
+	g := bls.NewG2()
+	h, err := g.HashToCurve(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	return g.Affine(h), nil
+}
+
+// NewMessageHasher returns a hasher of messages to G2 under the signature
+// domain. Its sum equals to HashMessage of the message written, so that large
+// messages can be signed with SignHash and verified with VerifyHash without
+// being buffered.
+func NewMessageHasher() *bls.HasherG2 {
+	return bls.NewHasherG2([]byte(DST))
+}
+
+// SignHash signs the message given by its hash, which is expected from
+// HashMessage or NewMessageHasher.
+func (sk *SecretKey) SignHash(h *bls.PointG2) *Signature {
+	g := bls.NewG2()
+	sig := &Signature{}
+	g.Affine(g.MulScalar(&sig.p, h, &sk.s))
+	return sig
+}
+
+// VerifyHash is Verify with the message given by its hash.
+func (sig *Signature) VerifyHash(pk *PublicKey, h *bls.PointG2) bool {
+	return verifyHashed(&pk.p, &sig.p, h)
+}
+
+// FastAggregateVerifyCached is FastAggregateVerify with precomputed aggregate
+// public key and message hash, such as sync committee signatures where both
+// repeat across slots. Aggregate key is expected from AggregatePublicKeys and
+// message hash from HashMessage.
+func (sig *Signature) FastAggregateVerifyCached(aggPk *PublicKey, h *bls.PointG2) bool {
+	return verifyHashed(&aggPk.p, &sig.p, h)
+}
+
+// verify checks e(pk, H(msg)) == e(g1, sig) with message hashed under dst.
+func verify(pk *bls.PointG1, sig *bls.PointG2, msg, dst []byte) bool {
+	h, err := bls.NewG2().HashToCurve(msg, dst)
+	if err != nil {
+		return false
+	}
+	return verifyHashed(pk, sig, h)
+}
+
+// aggregateVerify checks product of e(pks[i], H(msgs[i])) == e(g1, sig) with
+// messages hashed under dst.
+func aggregateVerify(pks []*PublicKey, sig *bls.PointG2, msgs [][]byte, dst []byte) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	e := bls.NewEngine()
+	for i, pk := range pks {
+		if e.G1.IsZero(&pk.p) {
+			return false
+		}
+		h, err := e.G2.HashToCurve(msgs[i], dst)
+		if err != nil {
+			return false
+		}
+		e.AddPair(&pk.p, h)
+	}
+	return e.AddPairInv(e.G1.One(), sig).Check()
+}
+
+// verifyHashed checks e(pk, h) == e(g1, sig).
+func verifyHashed(pk *bls.PointG1, sig *bls.PointG2, h *bls.PointG2) bool {
+	e := bls.NewEngine()
+	if e.G1.IsZero(pk) {
+		return false
+	}
+	return e.AddPair(pk, h).AddPairInv(e.G1.One(), sig).Check()
+}
@@ -0,0 +1,303 @@
+package sig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSignVector(t *testing.T) {
+	// Ethereum consensus spec test vector.
+	sk, err := SecretKeyFromBytes(fromHex(t, "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := bytes.Repeat([]byte{0x56}, 32)
+	pk := sk.PublicKey()
+	if !bytes.Equal(pk.Bytes(), fromHex(t, "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a")) {
+		t.Fatal("bad public key")
+	}
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fromHex(t, "882730e5d03f6b42c3abc26d3372625034e1d871b65a8a6b900a56dae22da98abbe1b68f85e49fe7652a55ec3d0591c20767677e33e5cbb1207315c41a9ac03be39c2e7668edc043d6cb1d9fd93033caa8a1c5b0e84bedaeb6c64972503a43eb")
+	if !bytes.Equal(sig.Bytes(), expected) {
+		t.Fatal("bad signature")
+	}
+	if !sig.Verify(pk, msg) {
+		t.Fatal("signature must be valid")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	sk, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	sig, err := sk.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(pk, msg) {
+		t.Fatal("signature must be valid")
+	}
+	if sig.Verify(pk, []byte("other message")) {
+		t.Fatal("signature must not be valid for another message")
+	}
+	other, _ := GenerateKey(rand.Reader)
+	if sig.Verify(other.PublicKey(), msg) {
+		t.Fatal("signature must not be valid for another key")
+	}
+	if sig.Verify(&PublicKey{}, msg) {
+		t.Fatal("infinity public key must be rejected")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	sk2, err := SecretKeyFromBytes(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sk.Bytes(), sk2.Bytes()) {
+		t.Fatal("bad secret key encoding")
+	}
+	pk := sk.PublicKey()
+	pk2, err := PublicKeyFromBytes(pk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pk.Equal(pk2) {
+		t.Fatal("bad public key encoding")
+	}
+	sig, _ := sk.Sign([]byte("message"))
+	sig2, err := SignatureFromBytes(sig.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Equal(sig2) {
+		t.Fatal("bad signature encoding")
+	}
+
+	if _, err := SecretKeyFromBytes(make([]byte, SecretKeySize)); err != ErrInvalidSecretKey {
+		t.Fatal("zero secret key must be rejected")
+	}
+	if _, err := SecretKeyFromBytes(bls.NewG1().Q().Bytes()); err != ErrInvalidSecretKey {
+		t.Fatal("secret key equal to group order must be rejected")
+	}
+	if _, err := SecretKeyFromBytes(make([]byte, SecretKeySize-1)); err == nil {
+		t.Fatal("short secret key must be rejected")
+	}
+	g := bls.NewG1()
+	if _, err := PublicKeyFromBytes(g.ToCompressed(g.Zero())); err != ErrInfinityKey {
+		t.Fatal("infinity public key must be rejected")
+	}
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	n := 8
+	msg := []byte("message")
+	pks := make([]*PublicKey, n)
+	sigs := make([]*Signature, n)
+	for i := 0; i < n; i++ {
+		sk, _ := GenerateKey(rand.Reader)
+		pks[i] = sk.PublicKey()
+		sigs[i], _ = sk.Sign(msg)
+	}
+	agg, err := AggregateSignatures(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !agg.FastAggregateVerify(pks, msg) {
+		t.Fatal("aggregate signature must be valid")
+	}
+	if agg.FastAggregateVerify(pks[1:], msg) {
+		t.Fatal("aggregate signature must not be valid for a subset")
+	}
+	if agg.FastAggregateVerify(nil, msg) {
+		t.Fatal("empty public key set must be rejected")
+	}
+	if _, err := AggregateSignatures(); err != ErrNoInput {
+		t.Fatal("empty aggregation must fail")
+	}
+}
+
+func TestAggregateVerify(t *testing.T) {
+	n := 4
+	pks := make([]*PublicKey, n)
+	sigs := make([]*Signature, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		sk, _ := GenerateKey(rand.Reader)
+		pks[i] = sk.PublicKey()
+		msgs[i] = []byte{byte(i)}
+		sigs[i], _ = sk.Sign(msgs[i])
+	}
+	// messages may repeat in proof of possession suite
+	msgs = append(msgs, msgs[0])
+	sk, _ := GenerateKey(rand.Reader)
+	pks = append(pks, sk.PublicKey())
+	sig, _ := sk.Sign(msgs[0])
+	sigs = append(sigs, sig)
+	agg, _ := AggregateSignatures(sigs...)
+	if !agg.AggregateVerify(pks, msgs) {
+		t.Fatal("aggregate signature must be valid")
+	}
+	if agg.AggregateVerify(pks, [][]byte{msgs[1], msgs[0], msgs[2], msgs[3], msgs[4]}) {
+		t.Fatal("aggregate signature must not be valid for swapped messages")
+	}
+	if agg.AggregateVerify(pks[1:], msgs[1:]) || agg.AggregateVerify(pks, msgs[1:]) || agg.AggregateVerify(nil, nil) {
+		t.Fatal("aggregate signature must not be valid for other inputs")
+	}
+}
+
+func TestFastAggregateVerifyCached(t *testing.T) {
+	n := 8
+	msg := []byte("message")
+	pks := make([]*PublicKey, n)
+	sigs := make([]*Signature, n)
+	for i := 0; i < n; i++ {
+		sk, _ := GenerateKey(rand.Reader)
+		pks[i] = sk.PublicKey()
+		sigs[i], _ = sk.Sign(msg)
+	}
+	agg, _ := AggregateSignatures(sigs...)
+	aggPk, _ := AggregatePublicKeys(pks...)
+	h, err := HashMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !agg.FastAggregateVerifyCached(aggPk, h) {
+		t.Fatal("aggregate signature must be valid")
+	}
+	other, _ := HashMessage([]byte("other message"))
+	if agg.FastAggregateVerifyCached(aggPk, other) {
+		t.Fatal("aggregate signature must not be valid for another message")
+	}
+	subset, _ := AggregatePublicKeys(pks[1:]...)
+	if agg.FastAggregateVerifyCached(subset, h) {
+		t.Fatal("aggregate signature must not be valid for a subset")
+	}
+}
+
+func BenchmarkFastAggregateVerify(b *testing.B) {
+	n := 512
+	msg := []byte("message")
+	pks := make([]*PublicKey, n)
+	sigs := make([]*Signature, n)
+	for i := 0; i < n; i++ {
+		sk, _ := GenerateKey(rand.Reader)
+		pks[i] = sk.PublicKey()
+		sigs[i], _ = sk.Sign(msg)
+	}
+	agg, _ := AggregateSignatures(sigs...)
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			agg.FastAggregateVerify(pks, msg)
+		}
+	})
+	aggPk, _ := AggregatePublicKeys(pks...)
+	h, _ := HashMessage(msg)
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			agg.FastAggregateVerifyCached(aggPk, h)
+		}
+	})
+}
+
+func TestSignHash(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	msg := bytes.Repeat([]byte("large message "), 1<<12)
+	h := NewMessageHasher()
+	for i := 0; i < len(msg); i += 1000 {
+		end := i + 1000
+		if end > len(msg) {
+			end = len(msg)
+		}
+		_, _ = h.Write(msg[i:end])
+	}
+	hm, err := h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := sk.SignHash(hm)
+	expected, _ := sk.Sign(msg)
+	if !sig.Equal(expected) {
+		t.Fatal("signature of streamed message does not match")
+	}
+	if !sig.VerifyHash(pk, hm) || !sig.Verify(pk, msg) {
+		t.Fatal("signature must be valid")
+	}
+	if sig.VerifyHash(pk, bls.NewG2().One()) {
+		t.Fatal("signature must be invalid for another hash")
+	}
+}
+
+func TestWeightedAggregation(t *testing.T) {
+	msg := []byte("checkpoint")
+	weights := []uint64{32, 1, 0, 1 << 40, 7}
+	var pks []*PublicKey
+	var sigs []*Signature
+	for range weights {
+		sk, _ := GenerateKey(rand.Reader)
+		sig, _ := sk.Sign(msg)
+		pks = append(pks, sk.PublicKey())
+		sigs = append(sigs, sig)
+	}
+	aggPk, err := AggregatePublicKeysWeighted(pks, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := bls.NewG1()
+	want := g.Zero()
+	for i, pk := range pks {
+		g.Add(want, want, g.MulScalarBig(g.New(), pk.Point(), new(big.Int).SetUint64(weights[i])))
+	}
+	if !g.Equal(aggPk.Point(), want) {
+		t.Fatal("bad weighted public key aggregate")
+	}
+	aggSig, err := AggregateSignaturesWeighted(sigs, weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !aggSig.Verify(aggPk, msg) {
+		t.Fatal("weighted aggregate signature must be valid")
+	}
+	plain, _ := AggregatePublicKeys(pks...)
+	if aggSig.Verify(plain, msg) {
+		t.Fatal("weighted aggregate signature must be invalid under unweighted aggregate")
+	}
+	if _, err := AggregatePublicKeysWeighted(pks, weights[1:]); err != ErrWeights {
+		t.Fatal("weights of wrong length must be rejected")
+	}
+	zero := make([]uint64, len(weights))
+	if _, err := AggregatePublicKeysWeighted(pks, zero); err != ErrZeroWeights {
+		t.Fatal("zero weights must be rejected", err)
+	}
+	if _, err := AggregateSignaturesWeighted(sigs, zero); err != ErrZeroWeights {
+		t.Fatal("zero weights must be rejected", err)
+	}
+	// keys that cancel out
+	neg := &PublicKey{}
+	g.Neg(&neg.p, pks[0].Point())
+	if _, err := AggregatePublicKeysWeighted([]*PublicKey{pks[0], neg}, []uint64{3, 3}); err != ErrInfinityKey {
+		t.Fatal("infinity aggregate must be rejected", err)
+	}
+}
@@ -0,0 +1,13 @@
+package sig
+
+import "github.com/kilic/bls12-381/internal/ssz"
+
+// HashTreeRoot returns SSZ hash tree root of the public key as Bytes48.
+func (pk *PublicKey) HashTreeRoot() ([32]byte, error) {
+	return ssz.Merkleize(pk.Bytes()), nil
+}
+
+// HashTreeRoot returns SSZ hash tree root of the signature as Bytes96.
+func (sig *Signature) HashTreeRoot() ([32]byte, error) {
+	return ssz.Merkleize(sig.Bytes()), nil
+}
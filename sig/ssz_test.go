@@ -0,0 +1,33 @@
+package sig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestHashTreeRoot(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	root, err := pk.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := make([]byte, 64)
+	copy(chunks, pk.Bytes())
+	if root != sha256.Sum256(chunks) {
+		t.Fatal("bad public key root")
+	}
+
+	sig, _ := sk.Sign([]byte("message"))
+	root, err = sig.HashTreeRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks = make([]byte, 128)
+	copy(chunks, sig.Bytes())
+	left, right := sha256.Sum256(chunks[:64]), sha256.Sum256(chunks[64:])
+	if root != sha256.Sum256(append(left[:], right[:]...)) {
+		t.Fatal("bad signature root")
+	}
+}
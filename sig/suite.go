@@ -0,0 +1,160 @@
+package sig
+
+import (
+	"errors"
+	"fmt"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var ErrDST = errors.New("domain separation tag must be 1 to 255 bytes")
+
+var errAppID = errors.New("application id must be non empty printable ascii without dashes")
+
+// DeriveDST returns a domain separation tag of the signature ciphersuite bound
+// to an application, a protocol version and a chain, composed as
+// <appID>-V<version>-CHAIN<chainID>-with-<DST> after the tag format recommended
+// by RFC 9380 section 3.1. Signatures under tags of different chains or
+// versions never verify under each other, which prevents replay across forks
+// sharing keys. Application id must not contain dashes, so that distinct
+// parameters always give distinct tags.
+func DeriveDST(appID string, version uint8, chainID uint64) (string, error) {
+	if appID == "" {
+		return "", errAppID
+	}
+	for _, c := range appID {
+		if c < 0x21 || c > 0x7e || c == '-' {
+			return "", errAppID
+		}
+	}
+	dst := fmt.Sprintf("%s-V%02d-CHAIN%d-with-%s", appID, version, chainID, DST)
+	if len(dst) > 255 {
+		return "", ErrDST
+	}
+	return dst, nil
+}
+
+// Suite is the signature ciphersuite of package functions with another domain
+// separation tag, such as one given by DeriveDST. Each package function and
+// method that hashes messages has a Suite counterpart, so that no path falls
+// back to the default tag.
+type Suite struct {
+	dst        []byte
+	prehashDST []byte
+}
+
+// NewSuite returns a ciphersuite with given domain separation tag. Tag of its
+// pre-hash mode is the tag followed by the PREHASH_SHA256_ suffix of
+// PrehashDST, pre-hash methods fail if it is longer than 255 bytes.
+func NewSuite(dst string) (*Suite, error) {
+	if len(dst) == 0 || len(dst) > 255 {
+		return nil, ErrDST
+	}
+	s := &Suite{dst: []byte(dst)}
+	if len(dst)+len(prehashSuffix) <= 255 {
+		s.prehashDST = []byte(dst + prehashSuffix)
+	}
+	return s, nil
+}
+
+// DST returns domain separation tag of the ciphersuite.
+func (s *Suite) DST() string {
+	return string(s.dst)
+}
+
+// PrehashDST returns domain separation tag of the pre-hash mode of the
+// ciphersuite, empty if the tag would be too long.
+func (s *Suite) PrehashDST() string {
+	return string(s.prehashDST)
+}
+
+// HashMessage returns H(msg) in G2 under the domain of the ciphersuite, see
+// package HashMessage.
+func (s *Suite) HashMessage(msg []byte) (*bls.PointG2, error) {
+	return hashMessage(msg, s.dst)
+}
+
+// NewMessageHasher is package NewMessageHasher under the ciphersuite.
+func (s *Suite) NewMessageHasher() *bls.HasherG2 {
+	return bls.NewHasherG2(s.dst)
+}
+
+// Sign signs the message under the ciphersuite.
+func (s *Suite) Sign(sk *SecretKey, msg []byte) (*Signature, error) {
+	h, err := s.HashMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignHash(h), nil
+}
+
+// Verify returns true if the signature is valid for the message under the
+// public key and the ciphersuite.
+func (s *Suite) Verify(pk *PublicKey, sig *Signature, msg []byte) bool {
+	h, err := s.HashMessage(msg)
+	if err != nil {
+		return false
+	}
+	return sig.VerifyHash(pk, h)
+}
+
+// FastAggregateVerify is Signature.FastAggregateVerify under the ciphersuite.
+func (s *Suite) FastAggregateVerify(pks []*PublicKey, sig *Signature, msg []byte) bool {
+	agg, err := AggregatePublicKeys(pks...)
+	if err != nil {
+		return false
+	}
+	return s.Verify(agg, sig, msg)
+}
+
+// FastAggregateVerifyCached is Signature.FastAggregateVerifyCached with the
+// message hash expected from HashMessage of the ciphersuite.
+func (s *Suite) FastAggregateVerifyCached(aggPk *PublicKey, sig *Signature, h *bls.PointG2) bool {
+	return verifyHashed(&aggPk.p, &sig.p, h)
+}
+
+// AggregateVerify is Signature.AggregateVerify under the ciphersuite.
+func (s *Suite) AggregateVerify(pks []*PublicKey, sig *Signature, msgs [][]byte) bool {
+	return aggregateVerify(pks, &sig.p, msgs, s.dst)
+}
+
+// VerifyBytes is package VerifyBytes under the ciphersuite.
+func (s *Suite) VerifyBytes(pk, msg, sig []byte) bool {
+	return verifyBytes(pk, msg, sig, s.dst)
+}
+
+// SignPrehashed is SecretKey.SignPrehashed under the pre-hash tag of the
+// ciphersuite.
+func (s *Suite) SignPrehashed(sk *SecretKey, digest [32]byte) (*Signature, error) {
+	if s.prehashDST == nil {
+		return nil, ErrDST
+	}
+	h, err := hashPrehashed(digest, s.prehashDST)
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignHash(h), nil
+}
+
+// VerifyPrehashed is Signature.VerifyPrehashed under the pre-hash tag of the
+// ciphersuite.
+func (s *Suite) VerifyPrehashed(pk *PublicKey, sig *Signature, digest [32]byte) bool {
+	if s.prehashDST == nil {
+		return false
+	}
+	h, err := hashPrehashed(digest, s.prehashDST)
+	if err != nil {
+		return false
+	}
+	return verifyHashed(&pk.p, &sig.p, h)
+}
+
+// FastAggregateVerifyPrehashed is Signature.FastAggregateVerifyPrehashed
+// under the pre-hash tag of the ciphersuite.
+func (s *Suite) FastAggregateVerifyPrehashed(pks []*PublicKey, sig *Signature, digest [32]byte) bool {
+	agg, err := AggregatePublicKeys(pks...)
+	if err != nil {
+		return false
+	}
+	return s.VerifyPrehashed(agg, sig, digest)
+}
@@ -0,0 +1,115 @@
+package sig
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func TestDeriveDST(t *testing.T) {
+	dst, err := DeriveDST("EXAMPLE", 1, 5)
+	if err != nil || dst != "EXAMPLE-V01-CHAIN5-with-"+DST {
+		t.Fatal("bad tag", dst, err)
+	}
+	for _, appID := range []string{"", "a-b", "a b", "a\x00"} {
+		if _, err := DeriveDST(appID, 1, 1); err != errAppID {
+			t.Fatal("bad application id must be rejected", appID)
+		}
+	}
+	if _, err := DeriveDST(strings.Repeat("a", 255), 1, 1); err != ErrDST {
+		t.Fatal("long tag must be rejected", err)
+	}
+	if _, err := NewSuite(""); err != ErrDST {
+		t.Fatal("empty tag must be rejected", err)
+	}
+}
+
+func TestSuite(t *testing.T) {
+	dst1, _ := DeriveDST("EXAMPLE", 1, 1)
+	dst2, _ := DeriveDST("EXAMPLE", 1, 2)
+	s1, _ := NewSuite(dst1)
+	s2, _ := NewSuite(dst2)
+	if s1.DST() != dst1 {
+		t.Fatal("bad tag")
+	}
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	sig, err := s1.Sign(sk, msg)
+	if err != nil || !s1.Verify(pk, sig, msg) {
+		t.Fatal("signature must be valid under its suite", err)
+	}
+	if s2.Verify(pk, sig, msg) || sig.Verify(pk, msg) {
+		t.Fatal("signature must not be valid under another chain or the default suite")
+	}
+	def, _ := NewSuite(DST)
+	expected, _ := sk.Sign(msg)
+	if sig, _ := def.Sign(sk, msg); !sig.Equal(expected) {
+		t.Fatal("suite with default tag must match package functions")
+	}
+	other, _ := GenerateKey(rand.Reader)
+	sig2, _ := s1.Sign(other, msg)
+	agg, _ := AggregateSignatures(sig, sig2)
+	pks := []*PublicKey{pk, other.PublicKey()}
+	if !s1.FastAggregateVerify(pks, agg, msg) || s2.FastAggregateVerify(pks, agg, msg) {
+		t.Fatal("bad fast aggregate verification")
+	}
+}
+
+func TestSuitePaths(t *testing.T) {
+	dst, _ := DeriveDST("EXAMPLE", 1, 1)
+	s, _ := NewSuite(dst)
+	if s.PrehashDST() != dst+"PREHASH_SHA256_" {
+		t.Fatal("bad pre-hash tag")
+	}
+	def, _ := NewSuite(DST)
+	if def.PrehashDST() != PrehashDST {
+		t.Fatal("suite with default tag must have default pre-hash tag")
+	}
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	msg := []byte("message")
+	sig, _ := s.Sign(sk, msg)
+	defSig, _ := sk.Sign(msg)
+
+	if !s.VerifyBytes(pk.Bytes(), msg, sig.Bytes()) || s.VerifyBytes(pk.Bytes(), msg, defSig.Bytes()) {
+		t.Fatal("bad encoded verification")
+	}
+	if !s.AggregateVerify([]*PublicKey{pk}, sig, [][]byte{msg}) || s.AggregateVerify([]*PublicKey{pk}, defSig, [][]byte{msg}) {
+		t.Fatal("bad aggregate verification")
+	}
+	h := s.NewMessageHasher()
+	_, _ = h.Write(msg)
+	hm, _ := h.Sum()
+	expected, _ := s.HashMessage(msg)
+	if !bls.NewG2().Equal(hm, expected) {
+		t.Fatal("message hasher must hash under the suite")
+	}
+	if !s.FastAggregateVerifyCached(pk, sig, expected) || s.FastAggregateVerifyCached(pk, defSig, expected) {
+		t.Fatal("bad cached verification")
+	}
+
+	digest := sha256.Sum256(msg)
+	psig, err := s.SignPrehashed(sk, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defPsig, _ := sk.SignPrehashed(digest)
+	if !s.VerifyPrehashed(pk, psig, digest) || s.VerifyPrehashed(pk, defPsig, digest) || psig.VerifyPrehashed(pk, digest) {
+		t.Fatal("pre-hash signature must be valid only under its suite")
+	}
+	if !s.FastAggregateVerifyPrehashed([]*PublicKey{pk}, psig, digest) {
+		t.Fatal("bad pre-hash fast aggregate verification")
+	}
+	if sig, _ := def.SignPrehashed(sk, digest); !sig.Equal(defPsig) {
+		t.Fatal("suite with default tag must match package pre-hash mode")
+	}
+
+	long, _ := NewSuite(strings.Repeat("a", 250))
+	if _, err := long.SignPrehashed(sk, digest); err != ErrDST {
+		t.Fatal("pre-hash tag longer than 255 bytes must be rejected")
+	}
+}
@@ -0,0 +1,208 @@
+// Package testvectors provides known answer vectors for BLS signatures of
+// package sig, for reuse by downstream implementations and wrappers.
+//
+// The irtf BLS signature draft followed by package sig does not publish test
+// vectors for its ciphersuites, so vectors are taken from Ethereum consensus
+// spec bls tests, which use the proof of possession suite of package sig and
+// are independent of this implementation. Invalid inputs that the spec tests
+// do not cover are derived from a spec vector by changing one of its inputs
+// and are tagged as such. All values are hex encoded, points are in
+// compressed form.
+package testvectors
+
+// Sources of vectors.
+const (
+	SourceEthereum = "ethereum consensus spec tests"
+	SourceDerived  = "derived from an ethereum consensus spec test"
+)
+
+// KeyVector is a secret key with its public key.
+type KeyVector struct {
+	SecretKey string
+	PublicKey string
+}
+
+// SignVector is a signature of a message under a secret key.
+type SignVector struct {
+	SecretKey string
+	Message   string
+	Signature string
+	Source    string
+}
+
+// AggregateVector is aggregation of signatures.
+type AggregateVector struct {
+	Signatures []string
+	Aggregate  string
+	Source     string
+}
+
+// FastAggregateVerifyVector is a fast aggregate verification input with its
+// expected result.
+type FastAggregateVerifyVector struct {
+	PublicKeys []string
+	Message    string
+	Signature  string
+	Valid      bool
+	Source     string
+}
+
+// Suite holds vectors of a ciphersuite.
+type Suite struct {
+	Name                string
+	DST                 string
+	Keys                []KeyVector
+	Sign                []SignVector
+	Aggregate           []AggregateVector
+	FastAggregateVerify []FastAggregateVerifyVector
+}
+
+// Suites lists vectors of ciphersuites implemented by package sig, which is
+// the proof of possession suite with public keys in G1.
+var Suites = []Suite{
+	{
+		Name: "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+		DST:  "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+		Keys: []KeyVector{
+			{
+				SecretKey: "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+				PublicKey: "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+			},
+			{
+				SecretKey: "47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+				PublicKey: "b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+			},
+			{
+				SecretKey: "328388aff0d4a5b7dc9205abd374e7e98f3cd9f3418edb4eafda5fb16473d216",
+				PublicKey: "b53d21a4cfd562c469cc81514d4ce5a6b577d8403d32a394dc265dd190b47fa9f829fdd7963afdf972e5e77854051f6f",
+			},
+		},
+		Sign: []SignVector{
+			{
+				SecretKey: "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+				Message:   "0000000000000000000000000000000000000000000000000000000000000000",
+				Signature: "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+				Message:   "5656565656565656565656565656565656565656565656565656565656565656",
+				Signature: "882730e5d03f6b42c3abc26d3372625034e1d871b65a8a6b900a56dae22da98abbe1b68f85e49fe7652a55ec3d0591c20767677e33e5cbb1207315c41a9ac03be39c2e7668edc043d6cb1d9fd93033caa8a1c5b0e84bedaeb6c64972503a43eb",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "91347bccf740d859038fcdcaf233eeceb2a436bcaaee9b2aa3bfb70efe29dfb2677562ccbea1c8e061fb9971b0753c240622fab78489ce96768259fc01360346da5b9f579e5da0d941e4c6ba18a0e64906082375394f337fa1af2b7127b0d121",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+				Message:   "0000000000000000000000000000000000000000000000000000000000000000",
+				Signature: "b23c46be3a001c63ca711f87a005c200cc550b9429d5f4eb38d74322144f1b63926da3388979e5321012fb1a0526bcd100b5ef5fe72628ce4cd5e904aeaa3279527843fae5ca9ca675f4f51ed8f83bbf7155da9ecc9663100a885d5dc6df96d9",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+				Message:   "5656565656565656565656565656565656565656565656565656565656565656",
+				Signature: "af1390c3c47acdb37131a51216da683c509fce0e954328a59f93aebda7e4ff974ba208d9a4a2a2389f892a9d418d618418dd7f7a6bc7aa0da999a9d3a5b815bc085e14fd001f6a1948768a3f4afefc8b8240dda329f984cb345c6363272ba4fe",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "9674e2228034527f4c083206032b020310face156d4a4685e2fcaec2f6f3665aa635d90347b6ce124eb879266b1e801d185de36a0a289b85e9039662634f2eea1e02e670bc7ab849d006a70b2f93b84597558a05b879c8d445f387a5d5b653df",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "328388aff0d4a5b7dc9205abd374e7e98f3cd9f3418edb4eafda5fb16473d216",
+				Message:   "0000000000000000000000000000000000000000000000000000000000000000",
+				Signature: "948a7cb99f76d616c2c564ce9bf4a519f1bea6b0a624a02276443c245854219fabb8d4ce061d255af5330b078d5380681751aa7053da2c98bae898edc218c75f07e24d8802a17cd1f6833b71e58f5eb5b94208b4d0bb3848cecb075ea21be115",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "328388aff0d4a5b7dc9205abd374e7e98f3cd9f3418edb4eafda5fb16473d216",
+				Message:   "5656565656565656565656565656565656565656565656565656565656565656",
+				Signature: "a4efa926610b8bd1c8330c918b7a5e9bf374e53435ef8b7ec186abf62e1b1f65aeaaeb365677ac1d1172a1f5b44b4e6d022c252c58486c0a759fbdc7de15a756acc4d343064035667a594b4c2a6f0b0b421975977f297dba63ee2f63ffe47bb6",
+				Source:    SourceEthereum,
+			},
+			{
+				SecretKey: "328388aff0d4a5b7dc9205abd374e7e98f3cd9f3418edb4eafda5fb16473d216",
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "ae82747ddeefe4fd64cf9cedb9b04ae3e8a43420cd255e3c7cd06a8d88b7c7f8638543719981c5d16fa3527c468c25f0026704a6951bde891360c7e8d12ddee0559004ccdbe6046b55bae1b257ee97f7cdb955773d7cf29adf3ccbb9975e4eb9",
+				Source:    SourceEthereum,
+			},
+		},
+		Aggregate: []AggregateVector{
+			{
+				Signatures: []string{
+					"91347bccf740d859038fcdcaf233eeceb2a436bcaaee9b2aa3bfb70efe29dfb2677562ccbea1c8e061fb9971b0753c240622fab78489ce96768259fc01360346da5b9f579e5da0d941e4c6ba18a0e64906082375394f337fa1af2b7127b0d121",
+					"9674e2228034527f4c083206032b020310face156d4a4685e2fcaec2f6f3665aa635d90347b6ce124eb879266b1e801d185de36a0a289b85e9039662634f2eea1e02e670bc7ab849d006a70b2f93b84597558a05b879c8d445f387a5d5b653df",
+					"ae82747ddeefe4fd64cf9cedb9b04ae3e8a43420cd255e3c7cd06a8d88b7c7f8638543719981c5d16fa3527c468c25f0026704a6951bde891360c7e8d12ddee0559004ccdbe6046b55bae1b257ee97f7cdb955773d7cf29adf3ccbb9975e4eb9",
+				},
+				Aggregate: "9712c3edd73a209c742b8250759db12549b3eaf43b5ca61376d9f30e2747dbcf842d8b2ac0901d2a093713e20284a7670fcf6954e9ab93de991bb9b313e664785a075fc285806fa5224c82bde146561b446ccfc706a64b8579513cfc4ff1d930",
+				Source:    SourceEthereum,
+			},
+			{
+				Signatures: []string{
+					"b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+					"af1390c3c47acdb37131a51216da683c509fce0e954328a59f93aebda7e4ff974ba208d9a4a2a2389f892a9d418d618418dd7f7a6bc7aa0da999a9d3a5b815bc085e14fd001f6a1948768a3f4afefc8b8240dda329f984cb345c6363272ba4fe",
+					"ae82747ddeefe4fd64cf9cedb9b04ae3e8a43420cd255e3c7cd06a8d88b7c7f8638543719981c5d16fa3527c468c25f0026704a6951bde891360c7e8d12ddee0559004ccdbe6046b55bae1b257ee97f7cdb955773d7cf29adf3ccbb9975e4eb9",
+				},
+				Aggregate: "9104e74b9dfd3ad502f25d6a5ef57db0ed7d9a0e00f3500586d8ce44231212542fcfaf87840539b398bf07626705cf1105d246ca1062c6c2e1a53029a0f790ed5e3cb1f52f8234dc5144c45fc847c0cd37a92d68e7c5ba7c648a8a339f171244",
+				Source:    SourceEthereum,
+			},
+		},
+		FastAggregateVerify: []FastAggregateVerifyVector{
+			{
+				PublicKeys: []string{
+					"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+					"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+					"b53d21a4cfd562c469cc81514d4ce5a6b577d8403d32a394dc265dd190b47fa9f829fdd7963afdf972e5e77854051f6f",
+				},
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "9712c3edd73a209c742b8250759db12549b3eaf43b5ca61376d9f30e2747dbcf842d8b2ac0901d2a093713e20284a7670fcf6954e9ab93de991bb9b313e664785a075fc285806fa5224c82bde146561b446ccfc706a64b8579513cfc4ff1d930",
+				Valid:     true,
+				Source:    SourceEthereum,
+			},
+			{
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+				Valid:     false,
+				Source:    SourceEthereum,
+			},
+			{
+				PublicKeys: []string{
+					"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+					"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+					"b53d21a4cfd562c469cc81514d4ce5a6b577d8403d32a394dc265dd190b47fa9f829fdd7963afdf972e5e77854051f6f",
+				},
+				Message:   "5656565656565656565656565656565656565656565656565656565656565656",
+				Signature: "9712c3edd73a209c742b8250759db12549b3eaf43b5ca61376d9f30e2747dbcf842d8b2ac0901d2a093713e20284a7670fcf6954e9ab93de991bb9b313e664785a075fc285806fa5224c82bde146561b446ccfc706a64b8579513cfc4ff1d930",
+				Valid:     false,
+				Source:    SourceDerived,
+			},
+			{
+				PublicKeys: []string{
+					"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+					"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+				},
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "9712c3edd73a209c742b8250759db12549b3eaf43b5ca61376d9f30e2747dbcf842d8b2ac0901d2a093713e20284a7670fcf6954e9ab93de991bb9b313e664785a075fc285806fa5224c82bde146561b446ccfc706a64b8579513cfc4ff1d930",
+				Valid:     false,
+				Source:    SourceDerived,
+			},
+			{
+				PublicKeys: []string{
+					"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+					"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+					"b53d21a4cfd562c469cc81514d4ce5a6b577d8403d32a394dc265dd190b47fa9f829fdd7963afdf972e5e77854051f6f",
+				},
+				Message:   "abababababababababababababababababababababababababababababababab",
+				Signature: "91347bccf740d859038fcdcaf233eeceb2a436bcaaee9b2aa3bfb70efe29dfb2677562ccbea1c8e061fb9971b0753c240622fab78489ce96768259fc01360346da5b9f579e5da0d941e4c6ba18a0e64906082375394f337fa1af2b7127b0d121",
+				Valid:     false,
+				Source:    SourceDerived,
+			},
+		},
+	},
+}
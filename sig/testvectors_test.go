@@ -0,0 +1,80 @@
+package sig
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kilic/bls12-381/sig/testvectors"
+)
+
+func TestVectors(t *testing.T) {
+	for _, suite := range testvectors.Suites {
+		if suite.DST != DST {
+			t.Fatal("unknown suite", suite.Name)
+		}
+		for _, v := range suite.Keys {
+			sk, err := SecretKeyFromBytes(fromHex(t, v.SecretKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(sk.PublicKey().Bytes(), fromHex(t, v.PublicKey)) {
+				t.Fatal("bad public key", v.SecretKey)
+			}
+		}
+		for _, v := range suite.Sign {
+			sk, err := SecretKeyFromBytes(fromHex(t, v.SecretKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg := fromHex(t, v.Message)
+			sig, err := sk.Sign(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(sig.Bytes(), fromHex(t, v.Signature)) {
+				t.Fatal("bad signature", v.Source, v.SecretKey, v.Message)
+			}
+			if !sig.Verify(sk.PublicKey(), msg) {
+				t.Fatal("signature must be valid")
+			}
+		}
+		for _, v := range suite.Aggregate {
+			sigs := make([]*Signature, len(v.Signatures))
+			for i := range sigs {
+				sigs[i] = decodeSignature(t, v.Signatures[i])
+			}
+			agg, err := AggregateSignatures(sigs...)
+			if err != nil || !bytes.Equal(agg.Bytes(), fromHex(t, v.Aggregate)) {
+				t.Fatal("bad aggregate signature", v.Source)
+			}
+		}
+		for i, v := range suite.FastAggregateVerify {
+			sig := decodeSignature(t, v.Signature)
+			if sig.FastAggregateVerify(decodePublicKeys(t, v.PublicKeys), fromHex(t, v.Message)) != v.Valid {
+				t.Fatal("bad fast aggregate verification result", v.Source, i)
+			}
+		}
+	}
+}
+
+func decodeSignature(t *testing.T, s string) *Signature {
+	t.Helper()
+	sig, err := SignatureFromBytes(fromHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func decodePublicKeys(t *testing.T, in []string) []*PublicKey {
+	t.Helper()
+	pks := make([]*PublicKey, len(in))
+	for i := range in {
+		pk, err := PublicKeyFromBytes(fromHex(t, in[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks[i] = pk
+	}
+	return pks
+}
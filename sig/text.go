@@ -0,0 +1,223 @@
+package sig
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Default human readable parts of bech32m encoded keys and signatures.
+const (
+	PublicKeyHRP = "blspk"
+	SignatureHRP = "blssig"
+)
+
+var (
+	ErrInvalidText = errors.New("invalid text encoding")
+	ErrChecksum    = errors.New("invalid checksum")
+	ErrHRPMismatch = errors.New("unexpected human readable part")
+)
+
+// base64ChecksumSize is the number of digest bytes appended in Base64 format.
+const base64ChecksumSize = 4
+
+// TextFormat converts binary encodings of keys and signatures to printable
+// text with a checksum and back, for configuration files and command line
+// tools.
+type TextFormat interface {
+	Encode(in []byte) string
+	Decode(s string) ([]byte, error)
+}
+
+// Bech32m returns the bech32m format of BIP-350 with given human readable
+// part. Strings are lowercase. Length limit of 90 characters of BIP-173 is not
+// applied since encoded signatures are longer.
+func Bech32m(hrp string) TextFormat {
+	return bech32mFormat(strings.ToLower(hrp))
+}
+
+// Base64 is the standard padded base64 encoding of input followed by the
+// first four bytes of its sha256 digest.
+var Base64 TextFormat = base64Format{}
+
+// Text returns the public key in given text format.
+func (pk *PublicKey) Text(f TextFormat) string {
+	return f.Encode(pk.Bytes())
+}
+
+// PublicKeyFromText decodes a public key in given text format. Public key is
+// validated as in PublicKeyFromBytes.
+func PublicKeyFromText(s string, f TextFormat) (*PublicKey, error) {
+	in, err := f.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return PublicKeyFromBytes(in)
+}
+
+// Text returns the signature in given text format.
+func (sig *Signature) Text(f TextFormat) string {
+	return f.Encode(sig.Bytes())
+}
+
+// SignatureFromText decodes a signature in given text format. Signature is
+// validated as in SignatureFromBytes.
+func SignatureFromText(s string, f TextFormat) (*Signature, error) {
+	in, err := f.Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	return SignatureFromBytes(in)
+}
+
+type base64Format struct{}
+
+func (base64Format) Encode(in []byte) string {
+	h := sha256.Sum256(in)
+	return base64.StdEncoding.EncodeToString(append(append([]byte{}, in...), h[:base64ChecksumSize]...))
+}
+
+func (base64Format) Decode(s string) ([]byte, error) {
+	in, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(in) < base64ChecksumSize {
+		return nil, ErrInvalidText
+	}
+	n := len(in) - base64ChecksumSize
+	h := sha256.Sum256(in[:n])
+	for i := 0; i < base64ChecksumSize; i++ {
+		if h[i] != in[n+i] {
+			return nil, ErrChecksum
+		}
+	}
+	return in[:n], nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32mConst is the checksum constant of bech32m, which differs from
+// bech32 only in this value.
+const bech32mConst = 0x2bc830a3
+
+type bech32mFormat string
+
+func (hrp bech32mFormat) Encode(in []byte) string {
+	data := convertBits(in, 8, 5, true)
+	sum := bech32mChecksum(string(hrp), data)
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(data) + len(sum))
+	sb.WriteString(string(hrp))
+	sb.WriteByte('1')
+	for _, v := range append(data, sum...) {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String()
+}
+
+func (hrp bech32mFormat) Decode(s string) ([]byte, error) {
+	h, data, err := bech32mDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if h != string(hrp) {
+		return nil, ErrHRPMismatch
+	}
+	out := convertBits(data, 5, 8, false)
+	if out == nil {
+		return nil, ErrInvalidText
+	}
+	return out, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32mChecksum(hrp string, data []byte) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	pm := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ bech32mConst
+	out := make([]byte, 6)
+	for i := range out {
+		out[i] = byte(pm>>uint(5*(5-i))) & 31
+	}
+	return out
+}
+
+// bech32mDecode returns human readable part and 5 bit data of a bech32m
+// string with checksum removed.
+func bech32mDecode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, ErrInvalidText
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, ErrInvalidText
+	}
+	hrp := s[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, ErrInvalidText
+		}
+	}
+	data := make([]byte, len(s)-pos-1)
+	for i := range data {
+		v := strings.IndexByte(bech32Charset, s[pos+1+i])
+		if v < 0 {
+			return "", nil, ErrInvalidText
+		}
+		data[i] = byte(v)
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != bech32mConst {
+		return "", nil, ErrChecksum
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups input of given bit width into output groups. Without
+// padding it returns nil if remaining bits do not fit exactly.
+func convertBits(in []byte, from, to uint, pad bool) []byte {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<to - 1
+	out := make([]byte, 0, len(in)*int(from)/int(to)+1)
+	for _, v := range in {
+		acc = acc<<from | uint32(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package sig
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestBech32mVectors(t *testing.T) {
+	// BIP-350 test vectors
+	for _, s := range []string{
+		"A1LQFN3A",
+		"a1lqfn3a",
+		"an83characterlonghumanreadablepartthatcontainsthetheexcludedcharactersbioandnumber11sg7hg6",
+		"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx",
+		"11llllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllllludsr8",
+		"split1checkupstagehandshakeupstreamerranterredcaperredlc445v",
+		"?1v759aa",
+	} {
+		if _, _, err := bech32mDecode(s); err != nil {
+			t.Fatal("valid string is rejected", s, err)
+		}
+	}
+	for _, s := range []string{
+		"a12uel5l",  // bech32 checksum
+		"A1lqfn3a",  // mixed case
+		"a1lqfn3b",  // bad checksum
+		"1lqfn3a",   // empty hrp
+		"a1lqfn3",   // short checksum
+		"a1lqfnba3", // invalid character
+	} {
+		if _, _, err := bech32mDecode(s); err == nil {
+			t.Fatal("invalid string is accepted", s)
+		}
+	}
+}
+
+func TestTextFormats(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	pk := sk.PublicKey()
+	sig, _ := sk.Sign([]byte("msg"))
+	for _, c := range []struct {
+		pk, sig TextFormat
+	}{
+		{Bech32m(PublicKeyHRP), Bech32m(SignatureHRP)},
+		{Bech32m("TEST"), Bech32m("test")},
+		{Base64, Base64},
+	} {
+		s := pk.Text(c.pk)
+		pk2, err := PublicKeyFromText(s, c.pk)
+		if err != nil || !pk2.Equal(pk) {
+			t.Fatal("bad public key round trip", s, err)
+		}
+		s = sig.Text(c.sig)
+		sig2, err := SignatureFromText(s, c.sig)
+		if err != nil || !sig2.Equal(sig) {
+			t.Fatal("bad signature round trip", s, err)
+		}
+	}
+	s := pk.Text(Bech32m(PublicKeyHRP))
+	if !strings.HasPrefix(s, PublicKeyHRP+"1") {
+		t.Fatal("bad prefix", s)
+	}
+	if _, err := PublicKeyFromText(strings.ToUpper(s), Bech32m(PublicKeyHRP)); err != nil {
+		t.Fatal("uppercase string must be accepted")
+	}
+	if _, err := PublicKeyFromText(s, Bech32m(SignatureHRP)); err != ErrHRPMismatch {
+		t.Fatal("unexpected hrp must be rejected")
+	}
+	b := []byte(s)
+	b[len(b)-10] = bech32Charset[(strings.IndexByte(bech32Charset, b[len(b)-10])+1)%32]
+	if _, err := PublicKeyFromText(string(b), Bech32m(PublicKeyHRP)); err != ErrChecksum {
+		t.Fatal("corrupted string must be rejected", err)
+	}
+	raw, _ := base64.StdEncoding.DecodeString(pk.Text(Base64))
+	raw[0] ^= 1
+	if _, err := Base64.Decode(base64.StdEncoding.EncodeToString(raw)); err != ErrChecksum {
+		t.Fatal("corrupted base64 string must be rejected", err)
+	}
+}
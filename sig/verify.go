@@ -0,0 +1,49 @@
+package sig
+
+import (
+	"sync"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// verifier holds a pairing engine and point storage reused across calls of
+// VerifyBytes.
+type verifier struct {
+	e     *bls.Engine
+	pk    bls.PointG1
+	sig   bls.PointG2
+	negG1 bls.PointG1
+}
+
+var verifierPool = sync.Pool{New: func() interface{} {
+	v := &verifier{e: bls.NewEngine()}
+	v.e.G1.Neg(&v.negG1, v.e.G1.One())
+	return v
+}}
+
+// VerifyBytes verifies an encoded signature for the message under an encoded
+// public key. It is a convenience wrapper doing the same work as decoding both
+// with PublicKeyFromBytes and SignatureFromBytes followed by Verify, with
+// decoded points and the pairing engine taken from a pool to save
+// allocations. Cheap checks run first, so malformed inputs are rejected before
+// hashing the message.
+func VerifyBytes(pk, msg, sig []byte) bool {
+	return verifyBytes(pk, msg, sig, []byte(DST))
+}
+
+func verifyBytes(pk, msg, sig, dst []byte) bool {
+	v := verifierPool.Get().(*verifier)
+	defer verifierPool.Put(v)
+	e := v.e.Reset()
+	if err := e.G1.FromCompressedInto(&v.pk, pk); err != nil || e.G1.IsZero(&v.pk) {
+		return false
+	}
+	if err := e.G2.FromCompressedInto(&v.sig, sig); err != nil {
+		return false
+	}
+	h, err := e.G2.HashToCurve(msg, dst)
+	if err != nil {
+		return false
+	}
+	return e.AddPair(&v.pk, h).AddPair(&v.negG1, &v.sig).Check()
+}
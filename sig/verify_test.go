@@ -0,0 +1,60 @@
+package sig
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestVerifyBytes(t *testing.T) {
+	msg := []byte("message")
+	for i := 0; i < 4; i++ {
+		sk, _ := GenerateKey(rand.Reader)
+		sig, _ := sk.Sign(msg)
+		pk, s := sk.PublicKey().Bytes(), sig.Bytes()
+		if !VerifyBytes(pk, msg, s) {
+			t.Fatal("signature must be valid")
+		}
+		if VerifyBytes(pk, []byte("other message"), s) {
+			t.Fatal("signature must not be valid for another message")
+		}
+		other, _ := GenerateKey(rand.Reader)
+		if VerifyBytes(other.PublicKey().Bytes(), msg, s) {
+			t.Fatal("signature must not be valid under another key")
+		}
+		if VerifyBytes(pk[1:], msg, s) || VerifyBytes(pk, msg, s[1:]) {
+			t.Fatal("short input must be rejected")
+		}
+		bad := append([]byte{}, s...)
+		bad[10] ^= 1
+		if VerifyBytes(pk, msg, bad) {
+			t.Fatal("corrupt signature must be rejected")
+		}
+	}
+	infinity := make([]byte, PublicKeySize)
+	infinity[0] = 0xc0
+	sk, _ := GenerateKey(rand.Reader)
+	sig, _ := sk.Sign(msg)
+	if VerifyBytes(infinity, msg, sig.Bytes()) {
+		t.Fatal("infinity public key must be rejected")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	msg := []byte("message")
+	sk, _ := GenerateKey(rand.Reader)
+	sig, _ := sk.Sign(msg)
+	pk, s := sk.PublicKey().Bytes(), sig.Bytes()
+	b.Run("decoded", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p, _ := PublicKeyFromBytes(pk)
+			q, _ := SignatureFromBytes(s)
+			q.Verify(p, msg)
+		}
+	})
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			VerifyBytes(pk, msg, s)
+		}
+	})
+}
@@ -2,37 +2,37 @@ package bls12381
 
 // swuMapG1 is implementation of Simplified Shallue-van de Woestijne-Ulas Method
 // follows the implmentation at draft-irtf-cfrg-hash-to-curve-06.
-func swuMapG1(u *Fe) (*Fe, *Fe) {
+func swuMapG1(u *Fp) (*Fp, *Fp) {
 	var params = swuParamsForG1
-	var tv [4]*Fe
+	var tv [4]*Fp
 	for i := 0; i < 4; i++ {
-		tv[i] = new(Fe)
+		tv[i] = new(Fp)
 	}
 	square(tv[0], u)
 	mul(tv[0], tv[0], params.z)
 	square(tv[1], tv[0])
-	x1 := new(Fe)
+	x1 := new(Fp)
 	add(x1, tv[0], tv[1])
 	inverse(x1, x1)
 	e1 := x1.isZero()
-	one := new(Fe).one()
+	one := new(Fp).one()
 	add(x1, x1, one)
 	if e1 {
 		x1.set(params.zInv)
 	}
 	mul(x1, x1, params.minusBOverA)
-	gx1 := new(Fe)
+	gx1 := new(Fp)
 	square(gx1, x1)
 	add(gx1, gx1, params.a)
 	mul(gx1, gx1, x1)
 	add(gx1, gx1, params.b)
-	x2 := new(Fe)
+	x2 := new(Fp)
 	mul(x2, tv[0], x1)
 	mul(tv[1], tv[0], tv[1])
-	gx2 := new(Fe)
+	gx2 := new(Fp)
 	mul(gx2, gx1, tv[1])
 	e2 := !isQuadraticNonResidue(gx1)
-	x, y2 := new(Fe), new(Fe)
+	x, y2 := new(Fp), new(Fp)
 	if e2 {
 		x.set(x1)
 		y2.set(gx1)
@@ -40,9 +40,9 @@ func swuMapG1(u *Fe) (*Fe, *Fe) {
 		x.set(x2)
 		y2.set(gx2)
 	}
-	y := new(Fe)
+	y := new(Fp)
 	sqrt(y, y2)
-	if y.sign() != u.sign() {
+	if y.Sgn0() != u.Sgn0() {
 		neg(y, y)
 	}
 	return x, y
@@ -92,24 +92,24 @@ func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	}
 	y := e.new()
 	e.sqrtBLST(y, y2)
-	if y.sign() != u.sign() {
+	if y.Sgn0() != u.Sgn0() {
 		fp2Neg(y, y)
 	}
 	return x, y
 }
 
 var swuParamsForG1 = struct {
-	z           *Fe
-	zInv        *Fe
-	a           *Fe
-	b           *Fe
-	minusBOverA *Fe
+	z           *Fp
+	zInv        *Fp
+	a           *Fp
+	b           *Fp
+	minusBOverA *Fp
 }{
-	a:           &Fe{0x2f65aa0e9af5aa51, 0x86464c2d1e8416c3, 0xb85ce591b7bd31e2, 0x27e11c91b5f24e7c, 0x28376eda6bfc1835, 0x155455c3e5071d85},
-	b:           &Fe{0xfb996971fe22a1e0, 0x9aa93eb35b742d6f, 0x8c476013de99c5c4, 0x873e27c3a221e571, 0xca72b5e45a52d888, 0x06824061418a386b},
-	z:           &Fe{0x886c00000023ffdc, 0x0f70008d3090001d, 0x77672417ed5828c3, 0x9dac23e943dc1740, 0x50553f1b9c131521, 0x078c712fbe0ab6e8},
-	zInv:        &Fe{0x0e8a2e8ba2e83e10, 0x5b28ba2ca4d745d1, 0x678cd5473847377a, 0x4c506dd8a8076116, 0x9bcb227d79284139, 0x0e8d3154b0ba099a},
-	minusBOverA: &Fe{0x052583c93555a7fe, 0x3b40d72430f93c82, 0x1b75faa0105ec983, 0x2527e7dc63851767, 0x99fffd1f34fc181d, 0x097cab54770ca0d3},
+	a:           &Fp{0x2f65aa0e9af5aa51, 0x86464c2d1e8416c3, 0xb85ce591b7bd31e2, 0x27e11c91b5f24e7c, 0x28376eda6bfc1835, 0x155455c3e5071d85},
+	b:           &Fp{0xfb996971fe22a1e0, 0x9aa93eb35b742d6f, 0x8c476013de99c5c4, 0x873e27c3a221e571, 0xca72b5e45a52d888, 0x06824061418a386b},
+	z:           &Fp{0x886c00000023ffdc, 0x0f70008d3090001d, 0x77672417ed5828c3, 0x9dac23e943dc1740, 0x50553f1b9c131521, 0x078c712fbe0ab6e8},
+	zInv:        &Fp{0x0e8a2e8ba2e83e10, 0x5b28ba2ca4d745d1, 0x678cd5473847377a, 0x4c506dd8a8076116, 0x9bcb227d79284139, 0x0e8d3154b0ba099a},
+	minusBOverA: &Fp{0x052583c93555a7fe, 0x3b40d72430f93c82, 0x1b75faa0105ec983, 0x2527e7dc63851767, 0x99fffd1f34fc181d, 0x097cab54770ca0d3},
 }
 
 var swuParamsForG2 = struct {
@@ -120,23 +120,23 @@ var swuParamsForG2 = struct {
 	minusBOverA *fe2
 }{
 	a: &fe2{
-		Fe{0, 0, 0, 0, 0, 0},
-		Fe{0xe53a000003135242, 0x01080c0fdef80285, 0xe7889edbe340f6bd, 0x0b51375126310601, 0x02d6985717c744ab, 0x1220b4e979ea5467},
+		Fp{0, 0, 0, 0, 0, 0},
+		Fp{0xe53a000003135242, 0x01080c0fdef80285, 0xe7889edbe340f6bd, 0x0b51375126310601, 0x02d6985717c744ab, 0x1220b4e979ea5467},
 	},
 	b: &fe2{
-		Fe{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
-		Fe{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
+		Fp{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
+		Fp{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
 	},
 	z: &fe2{
-		Fe{0x87ebfffffff9555c, 0x656fffe5da8ffffa, 0x0fd0749345d33ad2, 0xd951e663066576f4, 0xde291a3d41e980d3, 0x0815664c7dfe040d},
-		Fe{0x43f5fffffffcaaae, 0x32b7fff2ed47fffd, 0x07e83a49a2e99d69, 0xeca8f3318332bb7a, 0xef148d1ea0f4c069, 0x040ab3263eff0206},
+		Fp{0x87ebfffffff9555c, 0x656fffe5da8ffffa, 0x0fd0749345d33ad2, 0xd951e663066576f4, 0xde291a3d41e980d3, 0x0815664c7dfe040d},
+		Fp{0x43f5fffffffcaaae, 0x32b7fff2ed47fffd, 0x07e83a49a2e99d69, 0xeca8f3318332bb7a, 0xef148d1ea0f4c069, 0x040ab3263eff0206},
 	},
 	zInv: &fe2{
-		Fe{0xacd0000000011110, 0x9dd9999dc88ccccd, 0xb5ca2ac9b76352bf, 0xf1b574bcf4bc90ce, 0x42dab41f28a77081, 0x132fc6ac14cd1e12},
-		Fe{0xe396ffffffff2223, 0x4fbf332fcd0d9998, 0x0c4bbd3c1aff4cc4, 0x6b9c91267926ca58, 0x29ae4da6aef7f496, 0x10692e942f195791},
+		Fp{0xacd0000000011110, 0x9dd9999dc88ccccd, 0xb5ca2ac9b76352bf, 0xf1b574bcf4bc90ce, 0x42dab41f28a77081, 0x132fc6ac14cd1e12},
+		Fp{0xe396ffffffff2223, 0x4fbf332fcd0d9998, 0x0c4bbd3c1aff4cc4, 0x6b9c91267926ca58, 0x29ae4da6aef7f496, 0x10692e942f195791},
 	},
 	minusBOverA: &fe2{
-		Fe{0x903c555555474fb3, 0x5f98cc95ce451105, 0x9f8e582eefe0fade, 0xc68946b6aebbd062, 0x467a4ad10ee6de53, 0x0e7146f483e23a05},
-		Fe{0x29c2aaaaaab85af8, 0xbf133368e30eeefa, 0xc7a27a7206cffb45, 0x9dee04ce44c9425c, 0x04a15ce53464ce83, 0x0b8fcaf5b59dac95},
+		Fp{0x903c555555474fb3, 0x5f98cc95ce451105, 0x9f8e582eefe0fade, 0xc68946b6aebbd062, 0x467a4ad10ee6de53, 0x0e7146f483e23a05},
+		Fp{0x29c2aaaaaab85af8, 0xbf133368e30eeefa, 0xc7a27a7206cffb45, 0x9dee04ce44c9425c, 0x04a15ce53464ce83, 0x0b8fcaf5b59dac95},
 	},
 }
@@ -0,0 +1,605 @@
+package bls12381
+
+import (
+	"io"
+	"math/big"
+)
+
+// Extension tower of the base field:
+//
+//	Fp2  = Fp[u] / (u^2 + 1)
+//	Fp6  = Fp2[v] / (v^3 - (u + 1))
+//	Fp12 = Fp6[w] / (w^2 - v)
+//
+// Elements are plain arrays of coefficients, lowest degree first, so they can
+// be built with composite literals such as Fp2{*c0, *c1}. Base field
+// coefficients follow Fp semantics and are kept in Montgomery form. Fp12 is
+// the same type as the target group element E.
+
+// Fp2 is an element of the quadratic extension c0 + c1*u.
+type Fp2 = fe2
+
+// Fp6 is an element of the cubic extension c0 + c1*v + c2*v^2.
+type Fp6 = fe6
+
+// Fp12 is an element of the quadratic extension c0 + c1*w.
+type Fp12 = fe12
+
+// Zero sets the element to zero.
+func (e *Fp2) Zero() *Fp2 {
+	return e.zero()
+}
+
+// One sets the element to one.
+func (e *Fp2) One() *Fp2 {
+	return e.one()
+}
+
+// Set copies given value into the destination.
+func (e *Fp2) Set(a *Fp2) *Fp2 {
+	return e.set(a)
+}
+
+// IsZero returns true if the element is zero.
+func (e *Fp2) IsZero() bool {
+	return e.isZero()
+}
+
+// IsOne returns true if the element is one.
+func (e *Fp2) IsOne() bool {
+	return e.isOne()
+}
+
+// Equal returns true if given two elements are equal.
+func (e *Fp2) Equal(a *Fp2) bool {
+	return e.equal(a)
+}
+
+// Sgn0 returns sgn0 of the element as defined in RFC 9380 section 4.1, that is
+// Sgn0 of c0, or of c1 if c0 is zero.
+func (e *Fp2) Sgn0() int {
+	sign0, sign1 := e[0].Sgn0(), e[1].Sgn0()
+	zero0 := 0
+	if e[0].isZero() {
+		zero0 = 1
+	}
+	return sign0 | (zero0 & sign1)
+}
+
+// Frobenius sets the element to a^p, which is the conjugate of `a`.
+func (e *Fp2) Frobenius(a *Fp2) *Fp2 {
+	fp2Conjugate(e, a)
+	return e
+}
+
+// Frobenius2 sets the element to a^(p^2), which equals to `a`.
+func (e *Fp2) Frobenius2(a *Fp2) *Fp2 {
+	return e.set(a)
+}
+
+// Frobenius3 sets the element to a^(p^3), which is the conjugate of `a`.
+func (e *Fp2) Frobenius3(a *Fp2) *Fp2 {
+	fp2Conjugate(e, a)
+	return e
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the encoding of
+// Tower.Fp2ToBytes.
+func (e *Fp2) MarshalBinary() ([]byte, error) {
+	return newFp2().toBytes(e), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Both coefficients
+// must be less than the modulus.
+func (e *Fp2) UnmarshalBinary(in []byte) error {
+	if len(in) != 2*fpByteSize {
+		return ErrInvalidLength
+	}
+	c := new(Fp2)
+	if err := newFp2().fromBytesInto(c, in); err != nil {
+		return err
+	}
+	e.set(c)
+	return nil
+}
+
+// Zero sets the element to zero.
+func (e *Fp6) Zero() *Fp6 {
+	return e.zero()
+}
+
+// One sets the element to one.
+func (e *Fp6) One() *Fp6 {
+	return e.one()
+}
+
+// Set copies given value into the destination.
+func (e *Fp6) Set(a *Fp6) *Fp6 {
+	return e.set(a)
+}
+
+// IsZero returns true if the element is zero.
+func (e *Fp6) IsZero() bool {
+	return e.isZero()
+}
+
+// IsOne returns true if the element is one.
+func (e *Fp6) IsOne() bool {
+	return e.isOne()
+}
+
+// Equal returns true if given two elements are equal.
+func (e *Fp6) Equal(a *Fp6) bool {
+	return e.equal(a)
+}
+
+// Frobenius sets the element to a^p. It allocates temporaries, Tower
+// provides the same map without allocation.
+func (e *Fp6) Frobenius(a *Fp6) *Fp6 {
+	newFp6(nil).frobeniusMap1(e.set(a))
+	return e
+}
+
+// Frobenius2 sets the element to a^(p^2).
+func (e *Fp6) Frobenius2(a *Fp6) *Fp6 {
+	newFp6(nil).frobeniusMap2(e.set(a))
+	return e
+}
+
+// Frobenius3 sets the element to a^(p^3).
+func (e *Fp6) Frobenius3(a *Fp6) *Fp6 {
+	newFp6(nil).frobeniusMap3(e.set(a))
+	return e
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the encoding of
+// Tower.Fp6ToBytes.
+func (e *Fp6) MarshalBinary() ([]byte, error) {
+	return newFp6(nil).toBytes(e), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. All coefficients
+// must be less than the modulus.
+func (e *Fp6) UnmarshalBinary(in []byte) error {
+	if len(in) != 6*fpByteSize {
+		return ErrInvalidLength
+	}
+	c, err := newFp6(nil).fromBytes(in)
+	if err != nil {
+		return err
+	}
+	e.set(c)
+	return nil
+}
+
+// Zero sets the element to zero.
+func (e *Fp12) Zero() *Fp12 {
+	return e.zero()
+}
+
+// IsZero returns true if the element is zero.
+func (e *Fp12) IsZero() bool {
+	return e.isZero()
+}
+
+// Frobenius sets the element to a^p. It allocates temporaries, Tower
+// provides the same map without allocation.
+func (e *Fp12) Frobenius(a *Fp12) *Fp12 {
+	newFp12(nil).frobeniusMap1(e.set(a))
+	return e
+}
+
+// Frobenius2 sets the element to a^(p^2).
+func (e *Fp12) Frobenius2(a *Fp12) *Fp12 {
+	newFp12(nil).frobeniusMap2(e.set(a))
+	return e
+}
+
+// Frobenius3 sets the element to a^(p^3).
+func (e *Fp12) Frobenius3(a *Fp12) *Fp12 {
+	newFp12(nil).frobeniusMap3(e.set(a))
+	return e
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with the encoding of
+// Tower.Fp12ToBytes, which is also GT.ToBytes encoding.
+func (e *Fp12) MarshalBinary() ([]byte, error) {
+	return newFp12(nil).toBytes(e), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. All coefficients
+// must be less than the modulus. Unlike GT.FromBytes it does not check
+// subgroup membership.
+func (e *Fp12) UnmarshalBinary(in []byte) error {
+	if len(in) != GTSize {
+		return ErrInvalidLength
+	}
+	c, err := newFp12(nil).fromBytes(in)
+	if err != nil {
+		return err
+	}
+	e.set(c)
+	return nil
+}
+
+// SqrtFp2 returns a square root of `a` and true if `a` is a quadratic
+// residue, otherwise it returns nil and false. Root is computed with the
+// complex method using two square roots in the base field.
+func SqrtFp2(a *Fp2) (*Fp2, bool) {
+	c := new(Fp2)
+	if !newFp2().sqrtComplex(c, a) {
+		return nil, false
+	}
+	return c, true
+}
+
+// SqrtFp2Sgn0 returns the square root of `a` whose sgn0 is equal to sign and
+// true if `a` is a quadratic residue, otherwise it returns nil and false.
+// sgn0 is the sign function of RFC 9380 section 4.1, parity of c0 or parity
+// of c1 if c0 is zero, as used to select y coordinates in hashing to G2, so
+// that the result does not depend on the square root algorithm. Sign is taken
+// modulo 2. Zero is its own only root and is returned for either sign.
+func SqrtFp2Sgn0(a *Fp2, sign int) (*Fp2, bool) {
+	c, ok := SqrtFp2(a)
+	if !ok {
+		return nil, false
+	}
+	if c.Sgn0() != sign&1 {
+		fp2Neg(c, c)
+	}
+	return c, true
+}
+
+// SqrtFp2Both returns both square roots of `a` and true if `a` is a quadratic
+// residue, otherwise it returns nils and false. First root has sgn0 of zero and
+// second is its negation, so the pair does not depend on the square root
+// algorithm. Roots are computed with Tonelli-Shanks algorithm, independently
+// of the complex method of SqrtFp2 used in G2 decompression, which is useful
+// for cross checking decompression and hashing to curve. Both roots of zero
+// are zero.
+func SqrtFp2Both(a *Fp2) (*Fp2, *Fp2, bool) {
+	r0, r1 := new(Fp2), new(Fp2)
+	if !newFp2().sqrtTonelliShanks(r0, a) {
+		return nil, nil, false
+	}
+	if r0.Sgn0() != 0 {
+		fp2Neg(r0, r0)
+	}
+	fp2Neg(r1, r0)
+	return r0, r1, true
+}
+
+// Tower is an instance of extension field arithmetic. Like group instances it
+// holds preallocated temporaries and _is not_ suitable for concurrent use.
+// Result arguments may alias inputs.
+type Tower struct {
+	fp12 *fp12
+}
+
+// NewTower constructs a new extension field instance.
+func NewTower() *Tower {
+	return &Tower{newFp12(nil)}
+}
+
+func (t *Tower) fp2() *fp2 {
+	return t.fp12.fp2()
+}
+
+func (t *Tower) fp6() *fp6 {
+	return t.fp12.fp6
+}
+
+// Fp2FromBytes decodes 96 bytes input as c1 || c0 in big endian.
+func (t *Tower) Fp2FromBytes(in []byte) (*Fp2, error) {
+	if len(in) != 2*fpByteSize {
+		return nil, ErrInvalidLength
+	}
+	return t.fp2().fromBytes(in)
+}
+
+// Fp2ToBytes encodes an element as c1 || c0 in big endian.
+func (t *Tower) Fp2ToBytes(a *Fp2) []byte {
+	return t.fp2().toBytes(a)
+}
+
+// Fp2FromBytesOrder decodes 96 bytes big endian input with coefficients in
+// given order.
+func (t *Tower) Fp2FromBytesOrder(in []byte, order Fp2Order) (*Fp2, error) {
+	switch order {
+	case Fp2C1C0:
+		return t.Fp2FromBytes(in)
+	case Fp2C0C1:
+		if len(in) != 2*fpByteSize {
+			return nil, ErrInvalidLength
+		}
+		b := make([]byte, len(in))
+		copy(b, in)
+		swapFp2Order(b)
+		return t.fp2().fromBytes(b)
+	}
+	return nil, ErrUnknownOrder
+}
+
+// Fp2ToBytesOrder encodes an element in big endian with coefficients in given
+// order.
+func (t *Tower) Fp2ToBytesOrder(a *Fp2, order Fp2Order) ([]byte, error) {
+	switch order {
+	case Fp2C1C0:
+		return t.Fp2ToBytes(a), nil
+	case Fp2C0C1:
+		out := t.Fp2ToBytes(a)
+		swapFp2Order(out)
+		return out, nil
+	}
+	return nil, ErrUnknownOrder
+}
+
+// Fp2Rand returns a uniformly random element.
+func (t *Tower) Fp2Rand(r io.Reader) (*Fp2, error) {
+	return new(Fp2).rand(r)
+}
+
+// Fp2RandNonZero returns a uniformly random non zero element.
+func (t *Tower) Fp2RandNonZero(r io.Reader) (*Fp2, error) {
+	e := new(Fp2)
+	for {
+		if _, err := e.rand(r); err != nil {
+			return nil, err
+		}
+		if !e.isZero() {
+			return e, nil
+		}
+	}
+}
+
+// Fp2Add adds `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Add(c, a, b *Fp2) {
+	fp2Add(c, a, b)
+}
+
+// Fp2Double doubles `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Double(c, a *Fp2) {
+	fp2Double(c, a)
+}
+
+// Fp2Halve halves `a` in constant time and assigns the result to the element
+// in first argument.
+func (t *Tower) Fp2Halve(c, a *Fp2) {
+	halve(&c[0], &a[0])
+	halve(&c[1], &a[1])
+}
+
+// Fp2Sub subtracts `b` from `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Sub(c, a, b *Fp2) {
+	fp2Sub(c, a, b)
+}
+
+// Fp2Neg negates `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Neg(c, a *Fp2) {
+	fp2Neg(c, a)
+}
+
+// Fp2Mul multiplies `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Mul(c, a, b *Fp2) {
+	t.fp2().mul(c, a, b)
+}
+
+// Fp2MulByFp multiplies `a` by a base field element `b` and assigns the result
+// to the element in first argument.
+func (t *Tower) Fp2MulByFp(c, a *Fp2, b *Fp) {
+	t.fp2().mul0(c, a, b)
+}
+
+// Fp2MulByNonResidue multiplies `a` by the cubic non residue u + 1 and
+// assigns the result to the element in first argument.
+func (t *Tower) Fp2MulByNonResidue(c, a *Fp2) {
+	mulByNonResidue(c, a)
+}
+
+// Fp2Square squares `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Square(c, a *Fp2) {
+	t.fp2().square(c, a)
+}
+
+// Fp2Inverse inverts `a` and assigns the result to the element in first
+// argument. Inverse of zero is zero.
+func (t *Tower) Fp2Inverse(c, a *Fp2) {
+	t.fp2().inverse(c, a)
+}
+
+// Fp2Exp exponents `a` by `s` and assigns the result to the element in first argument.
+func (t *Tower) Fp2Exp(c, a *Fp2, s *big.Int) {
+	t.fp2().exp(c, a, s)
+}
+
+// Fp2Sqrt assigns a square root of `a` to the element in first argument and
+// returns true. If `a` is not a square it returns false and leaves the
+// destination unchanged.
+func (t *Tower) Fp2Sqrt(c, a *Fp2) bool {
+	r := new(Fp2)
+	if !t.fp2().sqrtComplex(r, a) {
+		return false
+	}
+	c.set(r)
+	return true
+}
+
+// Fp2Conjugate assigns c0 - c1*u to the element in first argument.
+func (t *Tower) Fp2Conjugate(c, a *Fp2) {
+	fp2Conjugate(c, a)
+}
+
+// Fp2Norm assigns a0^2 + a1^2 to the base field element in first argument,
+// which is the product of `a` and its conjugate.
+func (t *Tower) Fp2Norm(c *Fp, a *Fp2) {
+	t.fp2().norm(c, a)
+}
+
+// Fp2Frobenius raises `a` to p^power and assigns the result to the element in
+// first argument.
+func (t *Tower) Fp2Frobenius(c, a *Fp2, power int) {
+	c.set(a)
+	t.fp2().frobeniusMap(c, power)
+}
+
+// Fp6FromBytes decodes 288 bytes input as c2 || c1 || c0, each coefficient
+// following Fp2 encoding.
+func (t *Tower) Fp6FromBytes(in []byte) (*Fp6, error) {
+	if len(in) != 6*fpByteSize {
+		return nil, ErrInvalidLength
+	}
+	return t.fp6().fromBytes(in)
+}
+
+// Fp6ToBytes encodes an element as c2 || c1 || c0.
+func (t *Tower) Fp6ToBytes(a *Fp6) []byte {
+	return t.fp6().toBytes(a)
+}
+
+// Fp6Rand returns a uniformly random element.
+func (t *Tower) Fp6Rand(r io.Reader) (*Fp6, error) {
+	return new(Fp6).rand(r)
+}
+
+// Fp6Add adds `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Add(c, a, b *Fp6) {
+	fp6Add(c, a, b)
+}
+
+// Fp6Double doubles `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Double(c, a *Fp6) {
+	fp6Double(c, a)
+}
+
+// Fp6Sub subtracts `b` from `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Sub(c, a, b *Fp6) {
+	fp6Sub(c, a, b)
+}
+
+// Fp6Neg negates `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Neg(c, a *Fp6) {
+	fp6Neg(c, a)
+}
+
+// Fp6Mul multiplies `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Mul(c, a, b *Fp6) {
+	t.fp6().mul(c, a, b)
+}
+
+// Fp6MulByFp2 multiplies `a` by an Fp2 element `b` and assigns the result to
+// the element in first argument.
+func (t *Tower) Fp6MulByFp2(c, a *Fp6, b *Fp2) {
+	t.fp6().mulByBaseField(c, a, b)
+}
+
+// Fp6MulByNonResidue multiplies `a` by the quadratic non residue v and assigns
+// the result to the element in first argument.
+func (t *Tower) Fp6MulByNonResidue(c, a *Fp6) {
+	t.fp6().mulByNonResidue(c, a)
+}
+
+// Fp6Square squares `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Square(c, a *Fp6) {
+	t.fp6().square(c, a)
+}
+
+// Fp6Inverse inverts `a` and assigns the result to the element in first
+// argument. Inverse of zero is zero.
+func (t *Tower) Fp6Inverse(c, a *Fp6) {
+	t.fp6().inverse(c, a)
+}
+
+// Fp6Exp exponents `a` by `s` and assigns the result to the element in first argument.
+func (t *Tower) Fp6Exp(c, a *Fp6, s *big.Int) {
+	t.fp6().exp(c, a, s)
+}
+
+// Fp6Frobenius raises `a` to p^power and assigns the result to the element in
+// first argument.
+func (t *Tower) Fp6Frobenius(c, a *Fp6, power int) {
+	c.set(a)
+	t.fp6().frobeniusMap(c, power)
+}
+
+// Fp12FromBytes decodes 576 bytes input as c1 || c0, each coefficient
+// following Fp6 encoding. Unlike GT.FromBytes it does not check subgroup
+// membership.
+func (t *Tower) Fp12FromBytes(in []byte) (*Fp12, error) {
+	if len(in) != GTSize {
+		return nil, ErrInvalidLength
+	}
+	return t.fp12.fromBytes(in)
+}
+
+// Fp12ToBytes encodes an element as c1 || c0.
+func (t *Tower) Fp12ToBytes(a *Fp12) []byte {
+	return t.fp12.toBytes(a)
+}
+
+// Fp12Rand returns a uniformly random element.
+func (t *Tower) Fp12Rand(r io.Reader) (*Fp12, error) {
+	return new(Fp12).rand(r)
+}
+
+// Fp12Add adds `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Add(c, a, b *Fp12) {
+	fp12Add(c, a, b)
+}
+
+// Fp12Double doubles `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Double(c, a *Fp12) {
+	fp12Double(c, a)
+}
+
+// Fp12Sub subtracts `b` from `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Sub(c, a, b *Fp12) {
+	fp12Sub(c, a, b)
+}
+
+// Fp12Neg negates `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Neg(c, a *Fp12) {
+	fp12Neg(c, a)
+}
+
+// Fp12Mul multiplies `a` and `b` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Mul(c, a, b *Fp12) {
+	t.fp12.mul(c, a, b)
+}
+
+// Fp12Square squares `a` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Square(c, a *Fp12) {
+	t.fp12.square(c, a)
+}
+
+// Fp12CyclotomicSquare squares `a` and assigns the result to the element in
+// first argument. `a` must be in the cyclotomic subgroup, that is the output
+// of the easy part of the final exponentiation, otherwise the result is
+// undefined.
+func (t *Tower) Fp12CyclotomicSquare(c, a *Fp12) {
+	c.set(a)
+	t.fp12.cyclotomicSquare(c)
+}
+
+// Fp12Inverse inverts `a` and assigns the result to the element in first
+// argument. Inverse of zero is zero.
+func (t *Tower) Fp12Inverse(c, a *Fp12) {
+	t.fp12.inverse(c, a)
+}
+
+// Fp12Exp exponents `a` by `s` and assigns the result to the element in first argument.
+func (t *Tower) Fp12Exp(c, a *Fp12, s *big.Int) {
+	t.fp12.exp(c, a, s)
+}
+
+// Fp12Conjugate assigns c0 - c1*w to the element in first argument, which is
+// `a` raised to p^6. In the cyclotomic subgroup it equals the inverse of `a`.
+func (t *Tower) Fp12Conjugate(c, a *Fp12) {
+	fp12Conjugate(c, a)
+}
+
+// Fp12Frobenius raises `a` to p^power and assigns the result to the element in
+// first argument.
+func (t *Tower) Fp12Frobenius(c, a *Fp12, power int) {
+	c.set(a)
+	t.fp12.frobeniusMap(c, power)
+}
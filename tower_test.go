@@ -0,0 +1,426 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"testing"
+)
+
+func TestTowerFp2(t *testing.T) {
+	tw := NewTower()
+	p := modulus.big()
+	for i := 0; i < fuz; i++ {
+		a, _ := tw.Fp2Rand(rand.Reader)
+		b, _ := tw.Fp2Rand(rand.Reader)
+		c, d := new(Fp2), new(Fp2)
+
+		// (a + b)(a - b) == a^2 - b^2
+		tw.Fp2Add(c, a, b)
+		tw.Fp2Sub(d, a, b)
+		tw.Fp2Mul(c, c, d)
+		tw.Fp2Square(d, a)
+		e := new(Fp2)
+		tw.Fp2Square(e, b)
+		tw.Fp2Sub(d, d, e)
+		if !c.Equal(d) {
+			t.Fatal("(a + b)(a - b) == a^2 - b^2")
+		}
+		tw.Fp2Inverse(c, a)
+		tw.Fp2Mul(c, c, a)
+		if !c.IsOne() {
+			t.Fatal("a * a^-1 == 1")
+		}
+		tw.Fp2Double(c, a)
+		tw.Fp2Neg(d, a)
+		tw.Fp2Add(c, c, d)
+		if !c.Equal(a) {
+			t.Fatal("2a - a == a")
+		}
+		c.Set(a)
+		tw.Fp2Halve(c, c)
+		tw.Fp2Add(d, c, c)
+		if !d.Equal(a) {
+			t.Fatal("a/2 + a/2 == a")
+		}
+		tw.Fp2Double(c, c)
+		if !c.Equal(a) {
+			t.Fatal("2(a/2) == a")
+		}
+		tw.Fp2Frobenius(c, a, 1)
+		tw.Fp2Exp(d, a, p)
+		if !c.Equal(d) {
+			t.Fatal("frobenius map must equal to exponentiation by p")
+		}
+		tw.Fp2Conjugate(d, a)
+		if !c.Equal(d) {
+			t.Fatal("frobenius map must equal to conjugation")
+		}
+		norm := new(Fp)
+		tw.Fp2Norm(norm, a)
+		tw.Fp2Conjugate(c, a)
+		tw.Fp2Mul(c, c, a)
+		if !c[0].Equal(norm) || !c[1].IsZero() {
+			t.Fatal("norm must equal to product with conjugate")
+		}
+		tw.Fp2Square(c, a)
+		if !tw.Fp2Sqrt(d, c) {
+			t.Fatal("a^2 must be a square")
+		}
+		tw.Fp2Square(d, d)
+		if !d.Equal(c) {
+			t.Fatal("sqrt(a^2)^2 == a^2")
+		}
+		tw.Fp2MulByNonResidue(c, a)
+		tw.Fp2Mul(d, a, &Fp2{*new(Fp).One(), *new(Fp).One()})
+		if !c.Equal(d) {
+			t.Fatal("non residue is u + 1")
+		}
+		tw.Fp2MulByFp(c, a, &b[0])
+		tw.Fp2Mul(d, a, &Fp2{b[0], Fp{}})
+		if !c.Equal(d) {
+			t.Fatal("multiplication by base field")
+		}
+		c, err := tw.Fp2FromBytes(tw.Fp2ToBytes(a))
+		if err != nil || !c.Equal(a) {
+			t.Fatal("serialization")
+		}
+	}
+	if _, err := tw.Fp2FromBytes(make([]byte, 95)); err != ErrInvalidLength {
+		t.Fatal("short input must be rejected")
+	}
+}
+
+func TestTowerFp6(t *testing.T) {
+	tw := NewTower()
+	p := modulus.big()
+	for i := 0; i < fuz; i++ {
+		a, _ := tw.Fp6Rand(rand.Reader)
+		b, _ := tw.Fp6Rand(rand.Reader)
+		c, d := new(Fp6), new(Fp6)
+
+		tw.Fp6Mul(c, a, b)
+		tw.Fp6Mul(d, b, a)
+		if !c.Equal(d) {
+			t.Fatal("ab == ba")
+		}
+		tw.Fp6Square(c, a)
+		tw.Fp6Mul(d, a, a)
+		if !c.Equal(d) {
+			t.Fatal("a^2 == aa")
+		}
+		tw.Fp6Inverse(c, a)
+		tw.Fp6Mul(c, c, a)
+		if !c.IsOne() {
+			t.Fatal("a * a^-1 == 1")
+		}
+		tw.Fp6Double(c, a)
+		tw.Fp6Sub(c, c, a)
+		tw.Fp6Neg(d, a)
+		tw.Fp6Add(c, c, d)
+		if !c.IsZero() {
+			t.Fatal("2a - a - a == 0")
+		}
+		tw.Fp6Frobenius(c, a, 1)
+		tw.Fp6Exp(d, a, p)
+		if !c.Equal(d) {
+			t.Fatal("frobenius map must equal to exponentiation by p")
+		}
+		tw.Fp6Frobenius(c, a, 6)
+		if !c.Equal(a) {
+			t.Fatal("frobenius map of power 6 is identity")
+		}
+		tw.Fp6MulByNonResidue(c, a)
+		tw.Fp6Mul(d, a, &Fp6{Fp2{}, *new(Fp2).One(), Fp2{}})
+		if !c.Equal(d) {
+			t.Fatal("non residue is v")
+		}
+		tw.Fp6MulByFp2(c, a, &b[0])
+		tw.Fp6Mul(d, a, &Fp6{b[0], Fp2{}, Fp2{}})
+		if !c.Equal(d) {
+			t.Fatal("multiplication by fp2")
+		}
+		c, err := tw.Fp6FromBytes(tw.Fp6ToBytes(a))
+		if err != nil || !c.Equal(a) {
+			t.Fatal("serialization")
+		}
+	}
+}
+
+func TestTowerFp12(t *testing.T) {
+	tw := NewTower()
+	p := modulus.big()
+	for i := 0; i < fuz; i++ {
+		a, _ := tw.Fp12Rand(rand.Reader)
+		b, _ := tw.Fp12Rand(rand.Reader)
+		c, d := new(Fp12), new(Fp12)
+
+		tw.Fp12Mul(c, a, b)
+		tw.Fp12Mul(d, b, a)
+		if !c.Equal(d) {
+			t.Fatal("ab == ba")
+		}
+		tw.Fp12Square(c, a)
+		tw.Fp12Mul(d, a, a)
+		if !c.Equal(d) {
+			t.Fatal("a^2 == aa")
+		}
+		tw.Fp12Inverse(c, a)
+		tw.Fp12Mul(c, c, a)
+		if !c.IsOne() {
+			t.Fatal("a * a^-1 == 1")
+		}
+		tw.Fp12Double(c, a)
+		tw.Fp12Sub(c, c, a)
+		tw.Fp12Neg(d, a)
+		tw.Fp12Add(c, c, d)
+		if !c.IsZero() {
+			t.Fatal("2a - a - a == 0")
+		}
+		for _, power := range []int{1, 2, 3, 5, 11} {
+			tw.Fp12Frobenius(c, a, power)
+			d.Set(a)
+			for j := 0; j < power; j++ {
+				tw.Fp12Exp(d, d, p)
+			}
+			if !c.Equal(d) {
+				t.Fatal("frobenius map must equal to exponentiation by p^power", power)
+			}
+		}
+		tw.Fp12Frobenius(c, a, 6)
+		tw.Fp12Conjugate(d, a)
+		if !c.Equal(d) {
+			t.Fatal("conjugate must equal to frobenius map of power 6")
+		}
+
+		// Easy part of the final exponentiation maps into the cyclotomic subgroup.
+		tw.Fp12Conjugate(c, a)
+		tw.Fp12Inverse(d, a)
+		tw.Fp12Mul(c, c, d)
+		tw.Fp12Frobenius(d, c, 2)
+		tw.Fp12Mul(c, c, d)
+		tw.Fp12CyclotomicSquare(d, c)
+		tw.Fp12Square(c, c)
+		if !c.Equal(d) {
+			t.Fatal("cyclotomic square")
+		}
+		tw.Fp12Conjugate(d, c)
+		tw.Fp12Mul(d, d, c)
+		if !d.IsOne() {
+			t.Fatal("conjugate is the inverse in cyclotomic subgroup")
+		}
+
+		in := tw.Fp12ToBytes(a)
+		c, err := tw.Fp12FromBytes(in)
+		if err != nil || !c.Equal(a) {
+			t.Fatal("serialization")
+		}
+		if !bytes.Equal(in, NewGT().ToBytes(a)) {
+			t.Fatal("encoding must match target group encoding")
+		}
+	}
+	if !new(Fp12).One().IsOne() {
+		t.Fatal("one")
+	}
+}
+
+func TestTowerFrobeniusMethods(t *testing.T) {
+	tw := NewTower()
+	for i := 0; i < fuz; i++ {
+		a2, _ := tw.Fp2Rand(rand.Reader)
+		a6, _ := tw.Fp6Rand(rand.Reader)
+		a12, _ := tw.Fp12Rand(rand.Reader)
+		c2, c6, c12 := new(Fp2), new(Fp6), new(Fp12)
+		for power, m := range []struct {
+			fp2  func(*Fp2) *Fp2
+			fp6  func(*Fp6) *Fp6
+			fp12 func(*Fp12) *Fp12
+		}{
+			{new(Fp2).Frobenius, new(Fp6).Frobenius, new(Fp12).Frobenius},
+			{new(Fp2).Frobenius2, new(Fp6).Frobenius2, new(Fp12).Frobenius2},
+			{new(Fp2).Frobenius3, new(Fp6).Frobenius3, new(Fp12).Frobenius3},
+		} {
+			tw.Fp2Frobenius(c2, a2, power+1)
+			if !m.fp2(a2).Equal(c2) {
+				t.Fatal("fp2 frobenius", power+1)
+			}
+			tw.Fp6Frobenius(c6, a6, power+1)
+			if !m.fp6(a6).Equal(c6) {
+				t.Fatal("fp6 frobenius", power+1)
+			}
+			tw.Fp12Frobenius(c12, a12, power+1)
+			if !m.fp12(a12).Equal(c12) {
+				t.Fatal("fp12 frobenius", power+1)
+			}
+		}
+		// in place
+		tw.Fp12Frobenius(c12, a12, 1)
+		if !a12.Frobenius(a12).Equal(c12) {
+			t.Fatal("fp12 frobenius in place")
+		}
+	}
+}
+
+func TestTowerBinaryMarshaling(t *testing.T) {
+	type wire struct {
+		A *Fp
+		B *Fp2
+		C *Fp6
+		D *Fp12
+	}
+	tw := NewTower()
+	a, _ := new(Fp).rand(rand.Reader)
+	b, _ := tw.Fp2Rand(rand.Reader)
+	c, _ := tw.Fp6Rand(rand.Reader)
+	d, _ := tw.Fp12Rand(rand.Reader)
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(&wire{a, b, c, d}); err != nil {
+		t.Fatal(err)
+	}
+	out := new(wire)
+	if err := gob.NewDecoder(buf).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.A.Equal(a) || !out.B.Equal(b) || !out.C.Equal(c) || !out.D.Equal(d) {
+		t.Fatal("bad gob round trip")
+	}
+	enc, _ := d.MarshalBinary()
+	if !bytes.Equal(enc, tw.Fp12ToBytes(d)) {
+		t.Fatal("binary encoding must match byte encoding")
+	}
+	copy(enc[GTSize-fpByteSize:], modulus.bytes())
+	if err := new(Fp12).UnmarshalBinary(enc); err != ErrNonCanonical {
+		t.Fatal("non canonical coefficient must be rejected", err)
+	}
+	for _, u := range []interface{ UnmarshalBinary([]byte) error }{new(Fp), new(Fp2), new(Fp6), new(Fp12)} {
+		if err := u.UnmarshalBinary(make([]byte, 1)); err != ErrInvalidLength {
+			t.Fatal("input of wrong length must be rejected")
+		}
+	}
+}
+
+func TestSqrtFp2(t *testing.T) {
+	f := newFp2()
+	zero, _ := SqrtFp2(new(Fp2))
+	if zero == nil || !zero.IsZero() {
+		t.Fatal("square root of zero")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp2).rand(rand.Reader)
+		real := &Fp2{a[0], Fp{}}
+		imaginary := &Fp2{Fp{}, a[1]}
+		for _, x := range []*Fp2{a, real, imaginary} {
+			aa := new(Fp2)
+			f.square(aa, x)
+			r, ok := SqrtFp2(aa)
+			if !ok {
+				t.Fatal("square must have a root")
+			}
+			f.square(r, r)
+			if !r.Equal(aa) {
+				t.Fatal("bad square root")
+			}
+		}
+		r, ok := SqrtFp2(a)
+		if ok != !f.isQuadraticNonResidue(a) || ok != f.sqrt(new(Fp2), a) {
+			t.Fatal("quadratic residuosity mismatch")
+		}
+		if !ok && r != nil {
+			t.Fatal("root of non residue must be nil")
+		}
+	}
+}
+
+func TestSqrtFp2Sgn0(t *testing.T) {
+	f := newFp2()
+	// roots of -1 are u, with odd c1, and -u
+	minusOne := new(Fp2)
+	fp2Neg(minusOne, f.one())
+	negOne := new(Fp)
+	neg(negOne, new(Fp).one())
+	for sign, c1 := range []*Fp{negOne, new(Fp).one()} {
+		r, ok := SqrtFp2Sgn0(minusOne, sign)
+		if !ok || !r[0].IsZero() || !r[1].Equal(c1) {
+			t.Fatal("bad root of minus one", sign)
+		}
+	}
+	for _, sign := range []int{0, 1} {
+		if r, ok := SqrtFp2Sgn0(new(Fp2), sign); !ok || !r.IsZero() {
+			t.Fatal("square root of zero must be zero")
+		}
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp2).rand(rand.Reader)
+		real := &Fp2{a[0], Fp{}}
+		imaginary := &Fp2{Fp{}, a[1]}
+		for _, x := range []*Fp2{a, real, imaginary} {
+			aa := new(Fp2)
+			f.square(aa, x)
+			for _, sign := range []int{0, 1, 2, -1} {
+				r, ok := SqrtFp2Sgn0(aa, sign)
+				if !ok || r.Sgn0() != sign&1 {
+					t.Fatal("root must have requested sign", sign)
+				}
+				f.square(r, r)
+				if !r.Equal(aa) {
+					t.Fatal("bad square root")
+				}
+			}
+		}
+		if _, ok := SqrtFp2Sgn0(a, 0); ok != !f.isQuadraticNonResidue(a) {
+			t.Fatal("quadratic residuosity mismatch")
+		}
+	}
+}
+
+func TestSqrtFp2Both(t *testing.T) {
+	f := newFp2()
+	if r0, r1, ok := SqrtFp2Both(new(Fp2)); !ok || !r0.IsZero() || !r1.IsZero() {
+		t.Fatal("square roots of zero must be zero")
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp2).rand(rand.Reader)
+		real := &Fp2{a[0], Fp{}}
+		imaginary := &Fp2{Fp{}, a[1]}
+		for _, x := range []*Fp2{a, real, imaginary} {
+			aa := new(Fp2)
+			f.square(aa, x)
+			r0, r1, ok := SqrtFp2Both(aa)
+			if !ok || r0.Sgn0() != 0 {
+				t.Fatal("first root must have zero sign")
+			}
+			if expected, _ := SqrtFp2Sgn0(aa, 0); !r0.Equal(expected) {
+				t.Fatal("roots must agree with the complex method")
+			}
+			neg := new(Fp2)
+			fp2Neg(neg, r0)
+			if !r1.Equal(neg) {
+				t.Fatal("second root must be negation of the first")
+			}
+			f.square(r1, r1)
+			if !r1.Equal(aa) {
+				t.Fatal("bad square root")
+			}
+		}
+		if _, _, ok := SqrtFp2Both(a); ok != !f.isQuadraticNonResidue(a) {
+			t.Fatal("quadratic residuosity mismatch")
+		}
+	}
+}
+
+func BenchmarkSqrtFp2(t *testing.B) {
+	f := newFp2()
+	a, _ := new(Fp2).rand(rand.Reader)
+	f.square(a, a)
+	c := new(Fp2)
+	t.Run("complex", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			f.sqrtComplex(c, a)
+		}
+	})
+	t.Run("exponentiation", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			f.sqrt(c, a)
+		}
+	})
+}
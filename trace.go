@@ -0,0 +1,139 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Tracer logs high level operations for debugging. Each record is a line
+//
+//	<sequence> <operation> in=<hash>,<hash>,... out=<hash>
+//
+// where hashes are first 8 bytes of SHA-256 of canonical encodings, that is
+// uncompressed points, 32 bytes big endian scalars and target group bytes.
+// Two implementations tracing the same computation produce the same lines up
+// to the first diverging operation, which helps tracking down consensus
+// mismatches. Operations called internally by a traced operation are not
+// recorded. A tracer can be shared by instances used from multiple goroutines,
+// although sequence numbers then follow the order records are written.
+type Tracer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	seq uint64
+	err error
+}
+
+// NewTracer returns a tracer writing records to w.
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// Err returns the first error encountered while writing records. Records are
+// dropped after a write error.
+func (t *Tracer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Tracer) record(op string, out []byte, in ...[]byte) {
+	hashes := make([]string, len(in))
+	for i := range in {
+		hashes[i] = traceHash(in[i])
+	}
+	outHash := "error"
+	if out != nil {
+		outHash = traceHash(out)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, "%d %s in=%s out=%s\n", t.seq, op, strings.Join(hashes, ","), outHash)
+	t.seq++
+}
+
+func traceHash(in []byte) string {
+	h := sha256.Sum256(in)
+	return hex.EncodeToString(h[:8])
+}
+
+// SetTracer enables tracing of Add, Double, Neg, Sub, MulScalar, MultiExp,
+// ClearCofactor, EncodeToCurve and HashToCurve. Nil disables tracing, which is
+// the default.
+func (g *G1) SetTracer(t *Tracer) {
+	g.tracer = t
+}
+
+// traced runs op with tracing disabled, so that nested operations are not
+// recorded, and records its output encoding. Nil output marks an error.
+func (g *G1) traced(name string, op func() []byte, in ...[]byte) {
+	t := g.tracer
+	g.tracer = nil
+	out := op()
+	g.tracer = t
+	t.record(name, out, in...)
+}
+
+func (g *G1) tracePoints(points []*PointG1) []byte {
+	out := make([]byte, 0, len(points)*G1UncompressedSize)
+	for _, p := range points {
+		out = append(out, g.ToUncompressed(p)...)
+	}
+	return out
+}
+
+// SetTracer enables tracing of Add, Double, Neg, Sub, MulScalar, MultiExp,
+// ClearCofactor, EncodeToCurve and HashToCurve. Nil disables tracing, which is
+// the default.
+func (g *G2) SetTracer(t *Tracer) {
+	g.tracer = t
+}
+
+// traced runs op with tracing disabled, so that nested operations are not
+// recorded, and records its output encoding. Nil output marks an error.
+func (g *G2) traced(name string, op func() []byte, in ...[]byte) {
+	t := g.tracer
+	g.tracer = nil
+	out := op()
+	g.tracer = t
+	t.record(name, out, in...)
+}
+
+func (g *G2) tracePoints(points []*PointG2) []byte {
+	out := make([]byte, 0, len(points)*G2UncompressedSize)
+	for _, p := range points {
+		out = append(out, g.ToUncompressed(p)...)
+	}
+	return out
+}
+
+func traceScalars(scalars []*Fr) []byte {
+	out := make([]byte, 0, len(scalars)*frByteSize)
+	for _, s := range scalars {
+		out = append(out, s.ToBytes()...)
+	}
+	return out
+}
+
+// SetTracer enables tracing of pairing computations by Check and Result, and
+// of operations of G1 and G2 instances of the engine. Inputs of a pairing
+// record are the added pairs. Nil disables tracing.
+func (e *Engine) SetTracer(t *Tracer) {
+	e.tracer = t
+	e.G1.SetTracer(t)
+	e.G2.SetTracer(t)
+}
+
+func (e *Engine) tracePairs() [][]byte {
+	in := make([][]byte, len(e.pairs))
+	for i, p := range e.pairs {
+		in[i] = append(e.G1.ToUncompressed(p.g1), e.G2.ToUncompressed(p.g2)...)
+	}
+	return in
+}
@@ -0,0 +1,66 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	run := func() string {
+		buf := new(bytes.Buffer)
+		tracer := NewTracer(buf)
+		e := NewEngine()
+		e.SetTracer(tracer)
+		g1, g2 := e.G1, e.G2
+		s := new(Fr).FromBytes([]byte{7})
+		p := g1.MulScalar(g1.New(), g1.One(), s)
+		g1.Add(p, p, g1.One())
+		q, _ := g2.HashToCurve([]byte("msg"), []byte("domain"))
+		_, _ = g1.MultiExp(g1.New(), []*PointG1{p, g1.One()}, []*Fr{s, s})
+		_, _ = g1.MultiExp(g1.New(), []*PointG1{p}, []*Fr{})
+		e.AddPair(p, q).Check()
+		return buf.String()
+	}
+	out := run()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	ops := []string{"g1.mul_scalar", "g1.add", "g2.hash_to_curve", "g1.multi_exp", "g1.multi_exp", "pairing.check"}
+	if len(lines) != len(ops) {
+		t.Fatal("nested operations must not be recorded", out)
+	}
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[1] != ops[i] || !strings.HasPrefix(fields[2], "in=") || !strings.HasPrefix(fields[3], "out=") {
+			t.Fatal("bad record", line)
+		}
+	}
+	if !strings.HasSuffix(lines[4], "out=error") {
+		t.Fatal("failing operation must be marked", lines[4])
+	}
+	g := NewG1()
+	expected := g.MulScalar(g.New(), g.One(), new(Fr).FromBytes([]byte{7}))
+	h := sha256.Sum256(g.ToUncompressed(expected))
+	if !strings.HasSuffix(lines[0], "out="+hex.EncodeToString(h[:8])) {
+		t.Fatal("output hash must be hash of canonical encoding", lines[0])
+	}
+	if out != run() {
+		t.Fatal("traces of same computation must be equal")
+	}
+
+	tracer := NewTracer(failingWriter{})
+	g.SetTracer(tracer)
+	g.Double(g.New(), g.One())
+	g.SetTracer(nil)
+	if tracer.Err() == nil {
+		t.Fatal("write error must be reported")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -2,6 +2,8 @@ package bls12381
 
 import (
 	"math/big"
+	"runtime"
+	"sync"
 )
 
 func bigFromHex(hex string) *big.Int {
@@ -11,3 +13,49 @@ func bigFromHex(hex string) *big.Int {
 	n, _ := new(big.Int).SetString(hex, 16)
 	return n
 }
+
+// reverseBytes reverses given slice in place.
+func reverseBytes(in []byte) {
+	for i, j := 0, len(in)-1; i < j; i, j = i+1, j-1 {
+		in[i], in[j] = in[j], in[i]
+	}
+}
+
+// isAllZero returns true if all bytes of the input are zero.
+func isAllZero(in []byte) bool {
+	var acc byte
+	for _, v := range in {
+		acc |= v
+	}
+	return acc == 0
+}
+
+// parallelCheck validates n elements on all available CPUs and returns indices
+// of elements failing the check in ascending order. newCheck is called once
+// per worker so that each worker can hold its own group instance.
+func parallelCheck(n int, newCheck func() func(i int) bool) []int {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	invalid := make([]bool, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			check := newCheck()
+			for i := from; i < to; i++ {
+				invalid[i] = !check(i)
+			}
+		}(w*n/workers, (w+1)*n/workers)
+	}
+	wg.Wait()
+	indices := []int{}
+	for i := 0; i < n; i++ {
+		if invalid[i] {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
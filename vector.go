@@ -0,0 +1,229 @@
+package bls12381
+
+import (
+	"errors"
+	"math"
+)
+
+// G1AffineVector is a structure of arrays container for affine G1 points.
+// X and Y coordinates are stored in two contiguous slices so that bulk operations
+// such as multi exponentiation walk memory linearly instead of chasing pointers.
+// Point at infinity is kept as (0, 0).
+type G1AffineVector struct {
+	x []Fp
+	y []Fp
+}
+
+// NewG1AffineVector returns a vector of n points where each point is at infinity.
+func NewG1AffineVector(n int) *G1AffineVector {
+	return &G1AffineVector{make([]Fp, n), make([]Fp, n)}
+}
+
+// AffineVector normalizes given points with a single batch inversion and returns them in vector form.
+// Given points are not modified.
+func (g *G1) AffineVector(points []PointG1) *G1AffineVector {
+	n := len(points)
+	v := NewG1AffineVector(n)
+	inverses := make([]Fp, n)
+	for i := 0; i < n; i++ {
+		inverses[i].set(&points[i][2])
+	}
+	inverseBatch(inverses)
+	t := g.t
+	for i := 0; i < n; i++ {
+		p := &points[i]
+		if g.IsZero(p) {
+			continue
+		}
+		if g.IsAffine(p) {
+			v.x[i].set(&p[0])
+			v.y[i].set(&p[1])
+			continue
+		}
+		square(t[1], &inverses[i])    // z^-2
+		mul(&v.x[i], &p[0], t[1])     // x = x * z^-2
+		mul(t[0], &inverses[i], t[1]) // z^-3
+		mul(&v.y[i], &p[1], t[0])     // y = y * z^-3
+	}
+	return v
+}
+
+// Len returns number of points in the vector.
+func (v *G1AffineVector) Len() int {
+	return len(v.x)
+}
+
+// IsZero returns true if point at given index is point at infinity.
+func (v *G1AffineVector) IsZero(i int) bool {
+	return v.x[i].isZero() && v.y[i].isZero()
+}
+
+// Get loads point at given index into the point at first argument.
+func (v *G1AffineVector) Get(r *PointG1, i int) *PointG1 {
+	if v.IsZero(i) {
+		return r.Zero()
+	}
+	r[0].set(&v.x[i])
+	r[1].set(&v.y[i])
+	r[2].one()
+	return r
+}
+
+// ToCompressedVector serializes point at given index of the vector in compressed form.
+// Coordinates are already affine so that no inversion takes place.
+func (g *G1) ToCompressedVector(v *G1AffineVector, i int) []byte {
+	out := make([]byte, G1CompressedSize)
+	p := new(PointG1)
+	g.encodeCompressed(out, v.Get(p, i))
+	return out
+}
+
+// Points returns points of the vector in affine form.
+func (v *G1AffineVector) Points() []PointG1 {
+	points := make([]PointG1, v.Len())
+	for i := 0; i < len(points); i++ {
+		v.Get(&points[i], i)
+	}
+	return points
+}
+
+// FrVector is a flat vector of scalars, limbs of consecutive scalars are adjacent in memory.
+type FrVector []Fr
+
+// NewFrVector copies given scalars into a new vector.
+func NewFrVector(scalars []Fr) FrVector {
+	v := make(FrVector, len(scalars))
+	copy(v, scalars)
+	return v
+}
+
+// FrVectorFromPointers gathers scalars given by reference into a new vector.
+func FrVectorFromPointers(scalars []*Fr) FrVector {
+	v := make(FrVector, len(scalars))
+	for i := 0; i < len(scalars); i++ {
+		v[i].Set(scalars[i])
+	}
+	return v
+}
+
+// Len returns number of scalars in the vector.
+func (v FrVector) Len() int {
+	return len(v)
+}
+
+// MultiExpVector calculates multi exponentiation for points and scalars given in vector form.
+// See MultiExp for details. Length of points and scalars are expected to be equal,
+// otherwise an error is returned. Result is assigned to point at first argument.
+func (g *G1) MultiExpVector(r *PointG1, points *G1AffineVector, scalars FrVector) (*PointG1, error) {
+	if points.Len() != scalars.Len() {
+		return nil, errors.New("point and scalar vectors should be in same length")
+	}
+
+	c := 3
+	if len(scalars) >= 32 {
+		c = int(math.Ceil(math.Log(float64(len(scalars)))))
+	}
+
+	bucketSize := (1 << c) - 1
+	windows := make([]*PointG1, 255/c+1)
+	bucket := make([]PointG1, bucketSize)
+	p := new(PointG1)
+
+	for j := 0; j < len(windows); j++ {
+
+		for i := 0; i < bucketSize; i++ {
+			bucket[i].Zero()
+		}
+
+		for i := 0; i < len(scalars); i++ {
+			index := bucketSize & int(scalars[i].sliceUint64(c*j))
+			if index != 0 && !points.IsZero(i) {
+				points.Get(p, i)
+				g.AddMixed(&bucket[index-1], &bucket[index-1], p)
+			}
+		}
+
+		acc, sum := g.New(), g.New()
+		for i := bucketSize - 1; i >= 0; i-- {
+			g.Add(sum, sum, &bucket[i])
+			g.Add(acc, acc, sum)
+		}
+		windows[j] = g.New().Set(acc)
+	}
+
+	g.AffineBatch(windows)
+
+	acc := g.New()
+	for i := len(windows) - 1; i >= 0; i-- {
+		for j := 0; j < c; j++ {
+			g.Double(acc, acc)
+		}
+		g.AddMixed(acc, acc, windows[i])
+	}
+	return r.Set(acc), nil
+}
+
+// ErrVectorLength is returned by slice arithmetic if slices differ in length.
+var ErrVectorLength = errors.New("vectors should be in same length")
+
+// Slice arithmetic applies field operations element wise to slices of base
+// field elements, four elements per iteration and without allocation. Result
+// slice may be the same as an input slice, but must not overlap it partially.
+
+// AddVec sets c[i] = a[i] + b[i].
+func AddVec(c, a, b []Fp) error {
+	n := len(c)
+	if len(a) != n || len(b) != n {
+		return ErrVectorLength
+	}
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		add(&c[i], &a[i], &b[i])
+		add(&c[i+1], &a[i+1], &b[i+1])
+		add(&c[i+2], &a[i+2], &b[i+2])
+		add(&c[i+3], &a[i+3], &b[i+3])
+	}
+	for ; i < n; i++ {
+		add(&c[i], &a[i], &b[i])
+	}
+	return nil
+}
+
+// MulVec sets c[i] = a[i] * b[i].
+func MulVec(c, a, b []Fp) error {
+	n := len(c)
+	if len(a) != n || len(b) != n {
+		return ErrVectorLength
+	}
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		mul(&c[i], &a[i], &b[i])
+		mul(&c[i+1], &a[i+1], &b[i+1])
+		mul(&c[i+2], &a[i+2], &b[i+2])
+		mul(&c[i+3], &a[i+3], &b[i+3])
+	}
+	for ; i < n; i++ {
+		mul(&c[i], &a[i], &b[i])
+	}
+	return nil
+}
+
+// ScalarMulVec sets c[i] = s * a[i]. s may be an element of either slice.
+func ScalarMulVec(c, a []Fp, s *Fp) error {
+	n := len(c)
+	if len(a) != n {
+		return ErrVectorLength
+	}
+	k := *s
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		mul(&c[i], &a[i], &k)
+		mul(&c[i+1], &a[i+1], &k)
+		mul(&c[i+2], &a[i+2], &k)
+		mul(&c[i+3], &a[i+3], &k)
+	}
+	for ; i < n; i++ {
+		mul(&c[i], &a[i], &k)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestG1AffineVector(t *testing.T) {
+	g := NewG1()
+	n := 20
+	points := make([]PointG1, n)
+	for i := 0; i < n; i++ {
+		points[i].Set(g.rand())
+	}
+	points[n/2].Zero()
+	points[n/3].Set(g.randAffine())
+	v := g.AffineVector(points)
+	if v.Len() != n {
+		t.Fatal("bad vector length")
+	}
+	if !v.IsZero(n / 2) {
+		t.Fatal("expect infinity")
+	}
+	out := v.Points()
+	for i := 0; i < n; i++ {
+		if !g.Equal(&points[i], &out[i]) {
+			t.Fatal("vector conversion failed")
+		}
+		if !g.IsZero(&out[i]) && !g.IsAffine(&out[i]) {
+			t.Fatal("expect affine point")
+		}
+	}
+}
+
+func TestG1AffineVectorSerialization(t *testing.T) {
+	g := NewG1()
+	n := 10
+	points := make([]PointG1, n)
+	for i := 0; i < n; i++ {
+		points[i].Set(g.randCorrect())
+	}
+	points[0].Zero()
+	v := g.AffineVector(points)
+	for i := 0; i < n; i++ {
+		if !bytes.Equal(g.ToCompressedVector(v, i), g.ToCompressed(&points[i])) {
+			t.Fatal("vector serialization failed")
+		}
+	}
+}
+
+func TestFrVector(t *testing.T) {
+	n := 20
+	scalars := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		scalars[i], _ = new(Fr).Rand(rand.Reader)
+	}
+	v := FrVectorFromPointers(scalars)
+	for i := 0; i < n; i++ {
+		if !v[i].Equal(scalars[i]) {
+			t.Fatal("vector conversion failed")
+		}
+	}
+	w := NewFrVector(v)
+	w[0].Zero()
+	if v[0].IsZero() {
+		t.Fatal("vector must be copied")
+	}
+}
+
+func TestG1MultiExpVector(t *testing.T) {
+	g := NewG1()
+	for n := 1; n < 1024+1; n = n * 4 {
+		bases := make([]PointG1, n)
+		scalars := make([]*Fr, n)
+		for i := 0; i < n; i++ {
+			scalars[i], _ = new(Fr).Rand(rand.Reader)
+			bases[i].Set(g.rand())
+		}
+		bases[0].Zero()
+		expected, tmp := g.New(), g.New()
+		for i := 0; i < n; i++ {
+			g.mulScalar(tmp, &bases[i], scalars[i])
+			g.Add(expected, expected, tmp)
+		}
+		result := g.New()
+		_, err := g.MultiExpVector(result, g.AffineVector(bases), FrVectorFromPointers(scalars))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(expected, result) {
+			t.Fatal("multi-exponentiation failed")
+		}
+	}
+	if _, err := g.MultiExpVector(g.New(), NewG1AffineVector(2), make(FrVector, 1)); err == nil {
+		t.Fatal("expect length mismatch error")
+	}
+}
+
+func BenchmarkG1MultiExpVector(t *testing.B) {
+	g := NewG1()
+	n := 1000
+	bases := make([]PointG1, n)
+	scalars := make(FrVector, n)
+	for i := 0; i < n; i++ {
+		_, _ = scalars[i].Rand(rand.Reader)
+		bases[i].Set(g.randAffine())
+	}
+	v := g.AffineVector(bases)
+	result := g.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_, _ = g.MultiExpVector(result, v, scalars)
+	}
+}
+
+func TestFpSliceArithmetic(t *testing.T) {
+	randSlice := func(n int) []Fp {
+		v := make([]Fp, n)
+		for i := range v {
+			_, _ = v[i].Rand(rand.Reader)
+		}
+		return v
+	}
+	for n := 0; n < 10; n++ {
+		a, b := randSlice(n), randSlice(n)
+		sum, prod, scaled := make([]Fp, n), make([]Fp, n), make([]Fp, n)
+		s, _ := new(Fp).Rand(rand.Reader)
+		if AddVec(sum, a, b) != nil || MulVec(prod, a, b) != nil || ScalarMulVec(scaled, a, s) != nil {
+			t.Fatal("slices of same length must be accepted")
+		}
+		for i := 0; i < n; i++ {
+			e := new(Fp)
+			if e.Add(&a[i], &b[i]); !e.Equal(&sum[i]) {
+				t.Fatal("bad addition", n, i)
+			}
+			if e.Mul(&a[i], &b[i]); !e.Equal(&prod[i]) {
+				t.Fatal("bad multiplication", n, i)
+			}
+			if e.Mul(&a[i], s); !e.Equal(&scaled[i]) {
+				t.Fatal("bad scalar multiplication", n, i)
+			}
+		}
+		// results in place
+		squares := make([]Fp, n)
+		for i := range a {
+			squares[i].Square(&a[i])
+		}
+		_ = AddVec(b, a, b)
+		_ = MulVec(a, a, a)
+		for i := 0; i < n; i++ {
+			if !b[i].Equal(&sum[i]) || !a[i].Equal(&squares[i]) {
+				t.Fatal("bad arithmetic in place", n, i)
+			}
+		}
+		if n > 0 {
+			// scalar is an element of the result slice
+			want := make([]Fp, n)
+			_ = ScalarMulVec(want, sum, new(Fp).Set(&sum[0]))
+			_ = ScalarMulVec(sum, sum, &sum[0])
+			for i := 0; i < n; i++ {
+				if !sum[i].Equal(&want[i]) {
+					t.Fatal("scalar must be read before the result is written", n, i)
+				}
+			}
+		}
+	}
+	a := make([]Fp, 3)
+	if AddVec(a, a, a[:2]) != ErrVectorLength || MulVec(a[:2], a, a) != ErrVectorLength || ScalarMulVec(a, a[:1], &a[0]) != ErrVectorLength {
+		t.Fatal("slices of different length must be rejected")
+	}
+	b := make([]Fp, 64)
+	if allocs := testing.AllocsPerRun(10, func() {
+		_ = AddVec(b, b, b)
+		_ = MulVec(b, b, b)
+		_ = ScalarMulVec(b, b, &b[0])
+	}); allocs != 0 {
+		t.Fatal("slice arithmetic must not allocate", allocs)
+	}
+}
+
+func BenchmarkMulVec(t *testing.B) {
+	n := 1024
+	a, b := make([]Fp, n), make([]Fp, n)
+	for i := 0; i < n; i++ {
+		_, _ = a[i].Rand(rand.Reader)
+		_, _ = b[i].Rand(rand.Reader)
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = MulVec(a, a, b)
+	}
+}
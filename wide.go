@@ -0,0 +1,81 @@
+package bls12381
+
+// WideFp is a double width unreduced product of two base field elements, for
+// building lazy reduced formulas such as sums of products outside of the
+// package. Products are accumulated in wide form with Add, Sub and Double and
+// reduced once with Fp.Reduce, which saves a Montgomery reduction per term
+// over multiplying and adding in Fp. Like Fp a wide element is kept in
+// Montgomery form, so it holds values less than p * 2^384 and the operations
+// below keep it in that range, which is what Reduce accepts.
+type WideFp = wfe
+
+// WideFp2 is a double width unreduced product of two Fp2 elements, with
+// coefficients following WideFp semantics.
+type WideFp2 = wfe2
+
+// Mul sets w = a * b without reduction.
+func (w *WideFp) Mul(a, b *Fp) {
+	wmul(w, a, b)
+}
+
+// Square sets w = a^2 without reduction.
+func (w *WideFp) Square(a *Fp) {
+	wmul(w, a, a)
+}
+
+// Add sets w = a + b.
+func (w *WideFp) Add(a, b *WideFp) {
+	wadd(w, a, b)
+}
+
+// Sub sets w = a - b.
+func (w *WideFp) Sub(a, b *WideFp) {
+	wsub(w, a, b)
+}
+
+// Double sets w = 2 * a.
+func (w *WideFp) Double(a *WideFp) {
+	wdouble(w, a)
+}
+
+// Reduce sets the element to Montgomery reduction of the wide element, that
+// is the sum of products accumulated in it.
+func (e *Fp) Reduce(w *WideFp) {
+	fromWide(e, w)
+}
+
+// Mul sets w = a * b without reduction.
+func (w *WideFp2) Mul(a, b *Fp2) {
+	wfp2Mul(w, a, b)
+}
+
+// Square sets w = a^2 without reduction.
+func (w *WideFp2) Square(a *Fp2) {
+	wfp2Square(w, a)
+}
+
+// Add sets w = a + b.
+func (w *WideFp2) Add(a, b *WideFp2) {
+	wfp2Add(w, a, b)
+}
+
+// Sub sets w = a - b.
+func (w *WideFp2) Sub(a, b *WideFp2) {
+	wfp2Sub(w, a, b)
+}
+
+// Double sets w = 2 * a.
+func (w *WideFp2) Double(a *WideFp2) {
+	wfp2Double(w, a)
+}
+
+// MulByNonResidue sets w = a * (1 + u).
+func (w *WideFp2) MulByNonResidue(a *WideFp2) {
+	wfp2MulByNonResidue(w, a)
+}
+
+// Reduce sets the element to Montgomery reduction of both coefficients of the
+// wide element.
+func (e *Fp2) Reduce(w *WideFp2) {
+	e.fromWide(w)
+}
@@ -0,0 +1,94 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestWideFp(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		a, b, c, d := new(Fp), new(Fp), new(Fp), new(Fp)
+		for _, e := range []*Fp{a, b, c, d} {
+			_, _ = e.Rand(rand.Reader)
+		}
+		// 2 * (a * b + c^2) - c * d
+		w, t0 := new(WideFp), new(WideFp)
+		w.Mul(a, b)
+		t0.Square(c)
+		w.Add(w, t0)
+		w.Double(w)
+		t0.Mul(c, d)
+		w.Sub(w, t0)
+		r := new(Fp)
+		r.Reduce(w)
+		expected, e0 := new(Fp), new(Fp)
+		expected.Mul(a, b)
+		e0.Square(c)
+		expected.Add(expected, e0)
+		expected.Double(expected)
+		e0.Mul(c, d)
+		expected.Sub(expected, e0)
+		if !r.Equal(expected) {
+			t.Fatal("bad lazy reduced sum of products")
+		}
+		// difference wraps around
+		t0.Sub(new(WideFp), w)
+		r.Reduce(t0)
+		expected.Neg(expected)
+		if !r.Equal(expected) {
+			t.Fatal("bad wide subtraction")
+		}
+	}
+}
+
+func TestWideFp2(t *testing.T) {
+	f := newFp2()
+	for i := 0; i < fuz; i++ {
+		a, _ := new(Fp2).rand(rand.Reader)
+		b, _ := new(Fp2).rand(rand.Reader)
+		c, _ := new(Fp2).rand(rand.Reader)
+		// (a * b - c^2) * (1 + u) doubled
+		w, t0 := new(WideFp2), new(WideFp2)
+		w.Mul(a, b)
+		t0.Square(c)
+		w.Sub(w, t0)
+		w.MulByNonResidue(w)
+		w.Double(w)
+		w.Add(w, t0)
+		r := new(Fp2)
+		r.Reduce(w)
+		expected, e0 := new(Fp2), new(Fp2)
+		f.mul(expected, a, b)
+		f.square(e0, c)
+		fp2Sub(expected, expected, e0)
+		mulByNonResidue(expected, expected)
+		fp2Double(expected, expected)
+		fp2Add(expected, expected, e0)
+		if !r.Equal(expected) {
+			t.Fatal("bad lazy reduced fp2 formula")
+		}
+	}
+}
+
+func BenchmarkWideFp(t *testing.B) {
+	a, _ := new(Fp).Rand(rand.Reader)
+	b, _ := new(Fp).Rand(rand.Reader)
+	r := new(Fp)
+	t.Run("reduced", func(t *testing.B) {
+		t0 := new(Fp)
+		for i := 0; i < t.N; i++ {
+			r.Mul(a, b)
+			t0.Mul(b, a)
+			r.Add(r, t0)
+		}
+	})
+	t.Run("lazy", func(t *testing.B) {
+		w, t0 := new(WideFp), new(WideFp)
+		for i := 0; i < t.N; i++ {
+			w.Mul(a, b)
+			t0.Mul(b, a)
+			w.Add(w, t0)
+			r.Reduce(w)
+		}
+	})
+}
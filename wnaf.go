@@ -108,3 +108,28 @@ func bigFromWNAF(naf nafNumber) *big.Int {
 	}
 	return acc
 }
+
+// signedWindows recodes scalars into signed digits of c bits in range
+// [-2^(c-1), 2^(c-1)] so that multi exponentiation needs only 2^(c-1)
+// buckets per window, negative digits are handled by negating the point.
+// Digits of i-th scalar are stored at [i*windows, (i+1)*windows) least
+// significant first. Scalars must be less than 2^(c*windows-1) so that the
+// last carry is absorbed.
+func signedWindows(scalars []*Fr, c, windows int) []int {
+	digits := make([]int, len(scalars)*windows)
+	mask, half, full := (1<<c)-1, 1<<(c-1), 1<<c
+	for i, s := range scalars {
+		carry := 0
+		d := digits[i*windows : (i+1)*windows]
+		for j := 0; j < windows; j++ {
+			v := int(s.sliceUint64(c*j))&mask + carry
+			carry = 0
+			if v > half {
+				v -= full
+				carry = 1
+			}
+			d[j] = v
+		}
+	}
+	return digits
+}
@@ -65,3 +65,32 @@ func TestFrWNAFCrossAgainstBig(t *testing.T) {
 		}
 	}
 }
+
+func TestSignedWindows(t *testing.T) {
+	qMinusOne := new(Fr)
+	qMinusOne.Neg(new(Fr).One())
+	ones := &Fr{^uint64(0), ^uint64(0), ^uint64(0), 0x7fffffffffffffff}
+	for c := 2; c <= 16; c++ {
+		windows := 255/c + 1
+		scalars := []*Fr{new(Fr), new(Fr).One(), qMinusOne, ones}
+		for i := 0; i < fuz; i++ {
+			s, _ := new(Fr).Rand(rand.Reader)
+			scalars = append(scalars, s)
+		}
+		digits := signedWindows(scalars, c, windows)
+		for i, s := range scalars {
+			acc := new(big.Int)
+			for j := windows - 1; j >= 0; j-- {
+				d := digits[i*windows+j]
+				if d > 1<<(c-1) || d < -(1<<(c-1)) {
+					t.Fatal("digit out of range", c, d)
+				}
+				acc.Lsh(acc, uint(c))
+				acc.Add(acc, big.NewInt(int64(d)))
+			}
+			if acc.Cmp(s.ToBig()) != 0 {
+				t.Fatal("bad recoding", c)
+			}
+		}
+	}
+}